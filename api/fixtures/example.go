@@ -65,6 +65,7 @@ type ExampleAWSOptions struct {
 	Zone                                   string
 	VPCID                                  string
 	SubnetID                               string
+	Zones                                  []ExampleAWSOptionsZones
 	SecurityGroupID                        string
 	InstanceProfile                        string
 	InstanceType                           string
@@ -75,6 +76,11 @@ type ExampleAWSOptions struct {
 	NodePoolManagementUserAccessKeySecret  string
 }
 
+type ExampleAWSOptionsZones struct {
+	Name     string
+	SubnetID string
+}
+
 func (o ExampleOptions) Resources() *ExampleResources {
 	namespace := &corev1.Namespace{
 		TypeMeta: metav1.TypeMeta{
@@ -285,6 +291,18 @@ aws_secret_access_key = %s
 					},
 				},
 			}
+			// Spread the NodePool across all zones when there are several.
+			if len(o.AWS.Zones) > 1 {
+				nodePool.Spec.Platform.AWS.Subnet = nil
+				for i := range o.AWS.Zones {
+					nodePool.Spec.Platform.AWS.Zones = append(nodePool.Spec.Platform.AWS.Zones, hyperv1.AWSNodePoolZone{
+						Name: o.AWS.Zones[i].Name,
+						Subnet: hyperv1.AWSResourceReference{
+							ID: &o.AWS.Zones[i].SubnetID,
+						},
+					})
+				}
+			}
 		}
 	}
 
@@ -0,0 +1,60 @@
+// Package inplaceupgrade defines the contract between the NodePool controller
+// and the in-place upgrader of the hosted-cluster-config-operator, which
+// updates the nodes of InPlace NodePools. The NodePool controller sets the
+// target config version and how to roll it out in annotations on the
+// MachineSet of a NodePool, and the in-place upgrader reports the progress of
+// each node in annotations on its Machine.
+package inplaceupgrade
+
+import (
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
+)
+
+const (
+	// MachineSetAnnotationTargetConfigVersion is the config version the nodes
+	// of the MachineSet are updated to.
+	MachineSetAnnotationTargetConfigVersion = "hypershift.openshift.io/nodePoolTargetConfigVersion"
+
+	// MachineSetAnnotationUpgradePayloadSecret is the name of the Secret in the
+	// namespace of the MachineSet holding the ignition payload of the target
+	// config version under the key PayloadSecretKey.
+	MachineSetAnnotationUpgradePayloadSecret = "hypershift.openshift.io/nodePoolUpgradePayloadSecret"
+
+	// MachineSetAnnotationUpgradeMaxUnavailable is how many nodes, or which
+	// percentage of the nodes, of the MachineSet are updated at the same time.
+	MachineSetAnnotationUpgradeMaxUnavailable = "hypershift.openshift.io/nodePoolUpgradeMaxUnavailable"
+
+	// MachineSetAnnotationMachineConfigDaemonImage is the image of the
+	// machine-config-daemon of the release the nodes are updated to.
+	MachineSetAnnotationMachineConfigDaemonImage = "hypershift.openshift.io/nodePoolMachineConfigDaemonImage"
+
+	// MachineAnnotationCurrentConfigVersion is the config version the node of
+	// the Machine runs.
+	MachineAnnotationCurrentConfigVersion = "hypershift.openshift.io/nodeCurrentConfigVersion"
+
+	// MachineAnnotationUpgradeState is the v1alpha1.NodeUpgradeState of the
+	// node of the Machine.
+	MachineAnnotationUpgradeState = "hypershift.openshift.io/nodeUpgradeState"
+
+	// MachineAnnotationUpgradeMessage explains the upgrade state of the node
+	// of the Machine.
+	MachineAnnotationUpgradeMessage = "hypershift.openshift.io/nodeUpgradeMessage"
+
+	// PayloadSecretKey is the key of the ignition payload in the upgrade
+	// payload Secret.
+	PayloadSecretKey = "payload"
+)
+
+// MachineConfigVersion returns the config version the node of a Machine runs.
+// Machines booted from the user data of the target config version run it,
+// the config version of any other Machine is reported by the in-place
+// upgrader. It is empty until the in-place upgrader has reported it.
+func MachineConfigVersion(machine *capiv1.Machine, targetDataSecretName, targetConfigVersion string) string {
+	if version, ok := machine.Annotations[MachineAnnotationCurrentConfigVersion]; ok {
+		return version
+	}
+	if machine.Spec.Bootstrap.DataSecretName != nil && *machine.Spec.Bootstrap.DataSecretName == targetDataSecretName {
+		return targetConfigVersion
+	}
+	return ""
+}
@@ -0,0 +1,39 @@
+// Package nodesync defines the contract between the NodePool controller and
+// the node controller of the hosted-cluster-config-operator, which keeps the
+// labels and taints of the nodes of a NodePool in sync with it. The NodePool
+// controller sets the node labels and taints of a NodePool in annotations on
+// its MachineDeployment or MachineSet, and the node controller records the
+// ones it applied in annotations on each node.
+package nodesync
+
+const (
+	// MachineSetAnnotationNodeLabels is the JSON encoded map of the node
+	// labels of the NodePool of a MachineDeployment or MachineSet.
+	MachineSetAnnotationNodeLabels = "hypershift.openshift.io/nodePoolNodeLabels"
+
+	// MachineSetAnnotationTaints is the JSON encoded list of the taints of
+	// the NodePool of a MachineDeployment or MachineSet.
+	MachineSetAnnotationTaints = "hypershift.openshift.io/nodePoolTaints"
+
+	// MachineSetAnnotationRegisteredNodeLabels is the JSON encoded map of the
+	// node labels new nodes of the NodePool register with. It's set on the
+	// token Secret when it's created and copied to the MachineDeployment or
+	// MachineSet, so the labels which are no longer part of the NodePool can
+	// be removed from nodes which weren't synced yet.
+	MachineSetAnnotationRegisteredNodeLabels = "hypershift.openshift.io/nodePoolRegisteredNodeLabels"
+
+	// MachineSetAnnotationRegisteredTaints is the JSON encoded list of the
+	// taints new nodes of the NodePool register with, like
+	// MachineSetAnnotationRegisteredNodeLabels.
+	MachineSetAnnotationRegisteredTaints = "hypershift.openshift.io/nodePoolRegisteredTaints"
+
+	// NodeAnnotationAppliedNodeLabels is the comma separated list of the keys
+	// of the NodePool node labels applied to a node, so the ones removed from
+	// the NodePool can be removed from the node. The labels a node registered
+	// with count as applied as well.
+	NodeAnnotationAppliedNodeLabels = "hypershift.openshift.io/nodePoolAppliedNodeLabels"
+
+	// NodeAnnotationAppliedTaints is the comma separated list of the NodePool
+	// taints applied to a node, each identified as key:effect.
+	NodeAnnotationAppliedTaints = "hypershift.openshift.io/nodePoolAppliedTaints"
+)
@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	SchemeBuilder.Register(&ClusterSizingConfiguration{})
+	SchemeBuilder.Register(&ClusterSizingConfigurationList{})
+}
+
+// ClusterSizingConfigurationName is the name of the sizing configuration the
+// hypershift-operator applies to hosted clusters.
+const ClusterSizingConfigurationName = "cluster"
+
+// ClusterSizingConfiguration classifies hosted clusters into sizes by their
+// number of nodes and sizes their control plane components accordingly. Only
+// the configuration named "cluster" is used.
+// +kubebuilder:resource:path=clustersizingconfigurations,scope=Cluster
+// +kubebuilder:storageversion
+// +kubebuilder:object:root=true
+type ClusterSizingConfiguration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterSizingConfigurationSpec `json:"spec,omitempty"`
+}
+
+// ClusterSizingConfigurationSpec defines the sizes of hosted clusters.
+type ClusterSizingConfigurationSpec struct {
+	// Sizes are the sizes hosted clusters are classified into. A cluster gets
+	// the largest size whose FromNodeCount does not exceed its number of
+	// nodes, or the smallest size if there is none.
+	// +listType=map
+	// +listMapKey=name
+	Sizes []ClusterSize `json:"sizes"`
+
+	// DownsizeMargin is how many nodes below the FromNodeCount of its size a
+	// cluster must shrink to before it is moved to a smaller size. It keeps
+	// clusters hovering around a threshold from flapping between sizes.
+	// Clusters growing into a larger size are moved right away.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DownsizeMargin int32 `json:"downsizeMargin,omitempty"`
+
+	// DownsizeDelay is how long the number of nodes of a cluster stays below
+	// the downsize margin of its size before the cluster is moved to a smaller
+	// size. Defaults to 30 minutes.
+	// +optional
+	DownsizeDelay *metav1.Duration `json:"downsizeDelay,omitempty"`
+}
+
+// ClusterSize is a size of hosted clusters.
+type ClusterSize struct {
+	// Name identifies the size, e.g. small, medium or large.
+	Name string `json:"name"`
+
+	// FromNodeCount is the smallest number of nodes of clusters of this size.
+	// +kubebuilder:validation:Minimum=0
+	FromNodeCount int32 `json:"fromNodeCount"`
+
+	// ComponentOverrides customize the control plane components of clusters
+	// of this size. Overrides of the HostedCluster take precedence over them.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ComponentOverrides []ControlPlaneComponentOverride `json:"componentOverrides,omitempty"`
+}
+
+// ClusterSizeStatus is the size a hosted cluster was classified into.
+type ClusterSizeStatus struct {
+	// Name is the name of the size.
+	Name string `json:"name"`
+
+	// NodeCount is the number of nodes of the cluster the last time its size
+	// was evaluated.
+	NodeCount int32 `json:"nodeCount"`
+
+	// LastTransitionTime is when the cluster was moved to the size.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// BelowThresholdSince is when the number of nodes dropped below the
+	// downsize margin of the size. It is unset while the number of nodes is
+	// above the margin. The cluster moves to a smaller size once it has been
+	// below the margin for the downsize delay.
+	// +optional
+	BelowThresholdSince *metav1.Time `json:"belowThresholdSince,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// ClusterSizingConfigurationList contains a list of ClusterSizingConfiguration
+type ClusterSizingConfigurationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterSizingConfiguration `json:"items"`
+}
@@ -73,6 +73,27 @@ type HostedControlPlaneSpec struct {
 	// +optional
 	AuditWebhook *corev1.LocalObjectReference `json:"auditWebhook,omitempty"`
 
+	// Audit configures the audit policy of the kube apiserver and where its
+	// audit log is shipped to. The policy, CA and credentials it references
+	// are copies the HostedCluster controller keeps in the control plane
+	// namespace.
+	// +optional
+	Audit *AuditSpec `json:"audit,omitempty"`
+
+	// ComponentOverrides customizes the resources, replicas and scheduling of
+	// individual control plane components.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ComponentOverrides []ControlPlaneComponentOverride `json:"componentOverrides,omitempty"`
+
+	// ImageOverrides replaces the images of individual components. They take
+	// precedence over the images of the release image.
+	// +optional
+	// +listType=map
+	// +listMapKey=component
+	ImageOverrides []ImageOverride `json:"imageOverrides,omitempty"`
+
 	// Etcd contains metadata about the etcd cluster the hypershift managed Openshift control plane components
 	// use to store data.
 	Etcd EtcdSpec `json:"etcd"`
@@ -85,6 +106,28 @@ type HostedControlPlaneSpec struct {
 	// ImageContentSources lists sources/repositories for the release-image content.
 	// +optional
 	ImageContentSources []ImageContentSource `json:"imageContentSources,omitempty"`
+
+	// SecretEncryption configures envelope encryption of resources at rest with
+	// an external key management service.
+	// +optional
+	SecretEncryption *SecretEncryptionSpec `json:"secretEncryption,omitempty"`
+
+	// SigningKeyOverlapDuration is how long the previous service account
+	// signing key remains trusted after a new key is introduced through
+	// SigningKey.
+	// +optional
+	SigningKeyOverlapDuration *metav1.Duration `json:"signingKeyOverlapDuration,omitempty"`
+
+	// CertificateRotation configures when the certificates and CAs of the
+	// control plane are renewed.
+	// +optional
+	CertificateRotation *CertificateRotationSpec `json:"certificateRotation,omitempty"`
+
+	// KeyAlgorithm is the algorithm of the keys generated for the certificates
+	// and CAs of the control plane.
+	// +kubebuilder:default=RSA
+	// +optional
+	KeyAlgorithm KeyAlgorithm `json:"keyAlgorithm,omitempty"`
 }
 
 type AvailabilityPolicy string
@@ -108,6 +151,29 @@ const (
 	InfrastructureReady         ConditionType = "InfrastructureReady"
 	ValidConfiguration          ConditionType = "ValidConfiguration"
 	ClusterVersionFailing       ConditionType = "ClusterVersionFailing"
+
+	// EtcdQuotaRisk indicates whether the database of a managed etcd member is
+	// approaching the backend quota, after which etcd only accepts reads and
+	// deletes.
+	EtcdQuotaRisk ConditionType = "EtcdQuotaRisk"
+
+	// CertificatesExpiring indicates whether a certificate or CA of the control
+	// plane expires within the expiry warning period, or has already expired.
+	CertificatesExpiring ConditionType = "CertificatesExpiring"
+
+	// KonnectivityAvailable indicates whether every konnectivity server is
+	// running and has agents connected to it.
+	KonnectivityAvailable ConditionType = "KonnectivityAvailable"
+
+	// EncryptionKeysAvailable indicates whether the keys resources are
+	// encrypted at rest with are available. Encryption isn't reconciled while
+	// they are lost.
+	EncryptionKeysAvailable ConditionType = "EncryptionKeysAvailable"
+
+	// ValidAuditPolicy indicates whether the custom audit policy is accepted
+	// by the kube apiserver. While it is invalid, the kube apiserver keeps
+	// auditing with its previous policy.
+	ValidAuditPolicy ConditionType = "ValidAuditPolicy"
 )
 
 // HostedControlPlaneStatus defines the observed state of HostedControlPlane
@@ -157,12 +223,106 @@ type HostedControlPlaneStatus struct {
 	// for this control plane.
 	KubeConfig *KubeconfigSecretRef `json:"kubeConfig,omitempty"`
 
+	// Etcd is the observed state of the managed etcd cluster databases
+	// +optional
+	Etcd *ManagedEtcdStatus `json:"etcd,omitempty"`
+
+	// Certificates reports when the certificate in each secret of the control
+	// plane PKI expires and will be renewed.
+	// +optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+
+	// KonnectivityImages are the images of the konnectivity server and agent
+	// and where they were taken from.
+	// +optional
+	KonnectivityImages *KonnectivityImagesStatus `json:"konnectivityImages,omitempty"`
+
+	// NodeCount is the number of nodes registered with the hosted cluster
+	// API server. It is unset until the nodes could be listed.
+	// +optional
+	NodeCount *int32 `json:"nodeCount,omitempty"`
+
 	// Condition contains details for one aspect of the current state of the HostedControlPlane.
 	// Current condition types are: "Available"
 	// +kubebuilder:validation:Required
 	Conditions []metav1.Condition `json:"conditions"`
 }
 
+// KonnectivityImagesStatus are the images of the konnectivity components.
+type KonnectivityImagesStatus struct {
+	// Server is the image of the konnectivity server.
+	Server ComponentImageStatus `json:"server"`
+
+	// Agent is the image of the konnectivity agents.
+	Agent ComponentImageStatus `json:"agent"`
+}
+
+// ComponentImageSource is where the image of a component was taken from.
+type ComponentImageSource string
+
+const (
+	// ImageOverrideSource is an image override of the HostedControlPlane.
+	ImageOverrideSource ComponentImageSource = "ImageOverride"
+	// ReleaseImageSource is the release image of the HostedControlPlane.
+	ReleaseImageSource ComponentImageSource = "ReleaseImage"
+	// OperatorDefaultSource is the default image of the control plane operator,
+	// used when the release image does not carry the component.
+	OperatorDefaultSource ComponentImageSource = "OperatorDefault"
+)
+
+// ComponentImageStatus is the image of a component.
+type ComponentImageStatus struct {
+	// Image is the pull spec of the image.
+	Image string `json:"image"`
+
+	// Source is where the image was taken from.
+	// +kubebuilder:validation:Enum=ImageOverride;ReleaseImage;OperatorDefault
+	Source ComponentImageSource `json:"source"`
+}
+
+// CertificateStatus is the validity of the certificate in a secret of the
+// control plane PKI.
+type CertificateStatus struct {
+	// Secret is the name of the secret holding the certificate.
+	Secret string `json:"secret"`
+
+	// NotAfter is when the certificate expires.
+	NotAfter metav1.Time `json:"notAfter"`
+
+	// RenewalTime is when the certificate will be renewed.
+	RenewalTime metav1.Time `json:"renewalTime"`
+}
+
+// ManagedEtcdStatus is the observed state of the databases of a managed etcd
+// cluster
+type ManagedEtcdStatus struct {
+	// QuotaBackendBytes is the size the database of a member may grow to before
+	// etcd stops accepting writes
+	QuotaBackendBytes int64 `json:"quotaBackendBytes"`
+
+	// Members are the members of the etcd cluster
+	// +optional
+	Members []ManagedEtcdMemberStatus `json:"members,omitempty"`
+}
+
+// ManagedEtcdMemberStatus is the observed state of the database of a member of
+// a managed etcd cluster
+type ManagedEtcdMemberStatus struct {
+	// Name is the name of the member
+	Name string `json:"name"`
+
+	// DBSizeBytes is the size of the database of the member, including the
+	// space freed by compaction which is only reclaimed by defragmentation
+	DBSizeBytes int64 `json:"dbSizeBytes"`
+
+	// DBSizeInUseBytes is the size of the database of the member in use
+	DBSizeInUseBytes int64 `json:"dbSizeInUseBytes"`
+
+	// LastDefragmentationTime is the last time the member was defragmented
+	// +optional
+	LastDefragmentationTime *metav1.Time `json:"lastDefragmentationTime,omitempty"`
+}
+
 type APIEndpoint struct {
 	// Host is the hostname on which the API server is serving.
 	Host string `json:"host"`
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 
@@ -20,22 +21,40 @@ const (
 	OauthLoginURLOverrideAnnotation           = "oauth.hypershift.openshift.io/login-url-override"
 	//KonnectivityServerImageAnnotation is a temporary annotation that allows the specification of the konnectivity server image.
 	//This will be removed when Konnectivity is added to the Openshift release payload
+	// Deprecated: use ImageOverrides with the konnectivity-server component instead.
 	KonnectivityServerImageAnnotation = "hypershift.openshift.io/konnectivity-server-image"
 	//KonnectivityAgentImageAnnotation is a temporary annotation that allows the specification of the konnectivity agent image.
 	//This will be removed when Konnectivity is added to the Openshift release payload
+	// Deprecated: use ImageOverrides with the konnectivity-agent component instead.
 	KonnectivityAgentImageAnnotation = "hypershift.openshift.io/konnectivity-agent-image"
 	// RestartDateAnnotation is a annotation that can be used to trigger a rolling restart of all components managed by hypershift.
 	// it is important in some situations like CA rotation where components need to be fully restarted to pick up new CAs. It's also
 	// important in some recovery situations where a fresh start of the component helps fix symptoms a user might be experiencing.
 	RestartDateAnnotation = "hypershift.openshift.io/restart-date"
+	// EncryptionKeyRotationAnnotation requests a new key for encrypting resources at rest whenever its value
+	// changes. Existing resources are migrated to the new key and older keys are removed afterwards.
+	EncryptionKeyRotationAnnotation = "hypershift.openshift.io/encryption-key-rotation"
+	// SkipImmutableFieldValidationAnnotation allows changes to HostedCluster fields which are otherwise immutable
+	// once the cluster has been created (networking CIDRs, platform type, infraID and base domain). It is meant as
+	// an escape hatch for emergencies only, since changing these fields usually breaks the guest cluster. It must be
+	// set in the same update as the change, and is removed once the change has been propagated to the control plane.
+	SkipImmutableFieldValidationAnnotation = "hypershift.openshift.io/skip-immutable-field-validation"
 	// ClusterAPIManagerImage is an annotation that allows the specification of the cluster api manager image.
 	// This is a temporary workaround necessary for compliance reasons on the IBM Cloud side:
 	// no images can be pulled from registries outside of IBM Cloud's official regional registries
+	// Deprecated: use ImageOverrides with the cluster-api component instead.
 	ClusterAPIManagerImage = "hypershift.openshift.io/capi-manager-image"
 	// ClusterAutoscalerImage is an annotation that allows the specification of the cluster autoscaler image.
 	// This is a temporary workaround necessary for compliance reasons on the IBM Cloud side:
 	//no images can be pulled from registries outside of IBM Cloud's official regional registries
+	// Deprecated: use ImageOverrides with the cluster-autoscaler component instead.
 	ClusterAutoscalerImage = "hypershift.openshift.io/cluster-autoscaler-image"
+	// AuditPolicyConfigMapKey is the key name in the Audit policy config map that stores the audit policy
+	AuditPolicyConfigMapKey = "policy.yaml"
+	// AuditLogCAConfigMapKey is the key name in the audit log CA config map that stores the PEM encoded CA bundle
+	AuditLogCAConfigMapKey = "ca.crt"
+	// AuditLogTokenSecretKey is the key name in the audit log credentials secret that stores the bearer token
+	AuditLogTokenSecretKey = "token"
 )
 
 // HostedClusterSpec defines the desired state of HostedCluster
@@ -60,10 +79,24 @@ type HostedClusterSpec struct {
 	// +optional
 	AuditWebhook *corev1.LocalObjectReference `json:"auditWebhook,omitempty"`
 
+	// Audit configures the audit policy of the kube apiserver and where its
+	// audit log is shipped to.
+	// +optional
+	Audit *AuditSpec `json:"audit,omitempty"`
+
 	// SigningKey is a reference to a Secret containing a single key "key"
 	// +optional
 	SigningKey corev1.LocalObjectReference `json:"signingKey,omitempty"`
 
+	// SigningKeyOverlapDuration is how long the previous service account
+	// signing key remains trusted after a new key is introduced through
+	// SigningKey. Tokens are signed with the new key right away, while tokens
+	// signed with the previous key keep being accepted and its public key keeps
+	// being published for OIDC discovery until the overlap ends. Defaults to
+	// 24 hours.
+	// +optional
+	SigningKeyOverlapDuration *metav1.Duration `json:"signingKeyOverlapDuration,omitempty"`
+
 	// +kubebuilder:default:="https://kubernetes.default.svc"
 	IssuerURL string `json:"issuerURL"`
 
@@ -97,6 +130,22 @@ type HostedClusterSpec struct {
 	// +optional
 	ControllerAvailabilityPolicy AvailabilityPolicy `json:"controllerAvailabilityPolicy,omitempty"`
 
+	// ComponentOverrides customizes the resources, replicas and scheduling of
+	// individual control plane components. They take precedence over the
+	// defaults of the component and over ControllerAvailabilityPolicy.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	ComponentOverrides []ControlPlaneComponentOverride `json:"componentOverrides,omitempty"`
+
+	// ImageOverrides replaces the images of individual components, e.g. to
+	// roll out a fix for a single component without a new release image.
+	// They take precedence over the images of the release image.
+	// +optional
+	// +listType=map
+	// +listMapKey=component
+	ImageOverrides []ImageOverride `json:"imageOverrides,omitempty"`
+
 	// Etcd contains metadata about the etcd cluster the hypershift managed Openshift control plane components
 	// use to store data. Changing the ManagementType for the etcd cluster is not supported after initial creation.
 	// +kubebuilder:validation:Optional
@@ -112,6 +161,366 @@ type HostedClusterSpec struct {
 	// ImageContentSources lists sources/repositories for the release-image content.
 	// +optional
 	ImageContentSources []ImageContentSource `json:"imageContentSources,omitempty"`
+
+	// SecretEncryption configures envelope encryption of resources at rest with
+	// an external key management service. When set, it takes precedence over
+	// the encryption type of the APIServer configuration.
+	// +optional
+	SecretEncryption *SecretEncryptionSpec `json:"secretEncryption,omitempty"`
+
+	// CertificateRotation configures when the certificates and CAs of the
+	// control plane are renewed.
+	// +optional
+	CertificateRotation *CertificateRotationSpec `json:"certificateRotation,omitempty"`
+
+	// KeyAlgorithm is the algorithm of the keys generated for the certificates
+	// and CAs of the control plane. Changing it renews the existing
+	// certificates with keys of the new algorithm. CAs are renewed like on
+	// their renewal time: the renewed CAs are trusted for the CA overlap
+	// duration before they sign certificates.
+	// +kubebuilder:default=RSA
+	// +optional
+	KeyAlgorithm KeyAlgorithm `json:"keyAlgorithm,omitempty"`
+}
+
+// KeyAlgorithm is the algorithm of a private key.
+// +kubebuilder:validation:Enum=RSA;ECDSAP256;ECDSAP384
+type KeyAlgorithm string
+
+const (
+	// RSAKeyAlgorithm generates 2048 bit RSA keys.
+	RSAKeyAlgorithm KeyAlgorithm = "RSA"
+
+	// ECDSAP256KeyAlgorithm generates ECDSA keys on the P-256 curve.
+	ECDSAP256KeyAlgorithm KeyAlgorithm = "ECDSAP256"
+
+	// ECDSAP384KeyAlgorithm generates ECDSA keys on the P-384 curve.
+	ECDSAP384KeyAlgorithm KeyAlgorithm = "ECDSAP384"
+)
+
+// CertificateRotationSpec configures the renewal of control plane
+// certificates.
+type CertificateRotationSpec struct {
+	// RenewalPercentage is the percentage of its lifetime after which a
+	// certificate or CA is renewed. Defaults to 80.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +optional
+	RenewalPercentage int32 `json:"renewalPercentage,omitempty"`
+
+	// CAOverlapDuration is how long a renewed CA is trusted before it signs
+	// certificates, so that clients pick it up first, and how long the CA it
+	// replaces remains trusted afterwards, so that components which haven't
+	// picked up certificates signed by the new CA keep working. Defaults to 7
+	// days.
+	// +optional
+	CAOverlapDuration *metav1.Duration `json:"caOverlapDuration,omitempty"`
+
+	// ExpiryWarningPeriod is how long before a certificate or CA expires the
+	// CertificatesExpiring condition is set. Defaults to 30 days.
+	// +optional
+	ExpiryWarningPeriod *metav1.Duration `json:"expiryWarningPeriod,omitempty"`
+}
+
+// SecretEncryptionSpec contains metadata about the encryption of resources at
+// rest.
+type SecretEncryptionSpec struct {
+	// KMS defines the key management service which encrypts the data
+	// encryption keys used to store resources.
+	// +optional
+	KMS *KMSSpec `json:"kms,omitempty"`
+}
+
+// KMSProvider is the type of key management service used for envelope
+// encryption.
+// +kubebuilder:validation:Enum=AWS
+type KMSProvider string
+
+const (
+	// AWSKMSProvider encrypts data encryption keys with an AWS KMS key.
+	AWSKMSProvider KMSProvider = "AWS"
+)
+
+// KMSSpec defines the key management service used for envelope encryption.
+type KMSSpec struct {
+	// Provider is the type of key management service.
+	// +unionDiscriminator
+	Provider KMSProvider `json:"provider"`
+
+	// AWS configures an AWS KMS key. It is required when Provider is AWS.
+	// +optional
+	AWS *AWSKMSSpec `json:"aws,omitempty"`
+}
+
+// AWSKMSSpec defines the AWS KMS key used for envelope encryption.
+type AWSKMSSpec struct {
+	// Region is the AWS region of the key.
+	Region string `json:"region"`
+
+	// KeyARN is the ARN of the symmetric KMS key.
+	KeyARN string `json:"keyARN"`
+
+	// Auth defines how the KMS plugin authenticates to AWS.
+	Auth AWSKMSAuthSpec `json:"auth"`
+}
+
+// AWSKMSAuthSpec defines the role the KMS plugin assumes to use the key. As
+// with the roles in AWSPlatformSpec, the role must trust the OIDC provider of
+// the hosted cluster's service account issuer; the plugin assumes it with a
+// token for the kube-system/kms-provider service account.
+type AWSKMSAuthSpec struct {
+	// RoleARN is the ARN of a role allowed to encrypt and decrypt with the key.
+	RoleARN string `json:"roleARN"`
+}
+
+// AuditSpec configures auditing of the kube apiserver.
+type AuditSpec struct {
+	// Policy is a reference to a ConfigMap containing an audit.k8s.io Policy
+	// under the key that corresponds to the constant AuditPolicyConfigMapKey.
+	// When set, it takes precedence over the audit profile of the APIServer
+	// configuration.
+	// +optional
+	Policy *corev1.LocalObjectReference `json:"policy,omitempty"`
+
+	// LogBackend ships the audit log of the kube apiserver to a sink.
+	// +optional
+	LogBackend *AuditLogBackendSpec `json:"logBackend,omitempty"`
+}
+
+// AuditLogBackendSpec configures the audit log file of the kube apiserver and
+// the sink a sidecar forwards its events to.
+type AuditLogBackendSpec struct {
+	// MaxSize is the size in megabytes the audit log file reaches before it is
+	// rotated. Defaults to 100.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxSize int32 `json:"maxSize,omitempty"`
+
+	// MaxBackups is the number of rotated audit log files kept next to the
+	// audit log. Defaults to 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxBackups int32 `json:"maxBackups,omitempty"`
+
+	// Sink is where audit events are forwarded to.
+	Sink AuditSinkSpec `json:"sink"`
+
+	// CA is a reference to a ConfigMap containing the PEM encoded CA bundle
+	// the sink is verified with, under the key that corresponds to the
+	// constant AuditLogCAConfigMapKey. Syslog sinks are reached over TLS when
+	// it is set. It is not supported by PersistentVolume sinks.
+	// +optional
+	CA *corev1.LocalObjectReference `json:"ca,omitempty"`
+
+	// Credentials is a reference to a Secret the forwarder authenticates to
+	// the sink with. HTTP sinks send the bearer token stored under the key
+	// that corresponds to the constant AuditLogTokenSecretKey. HTTP and TLS
+	// syslog sinks present the client certificate stored under the tls.crt
+	// and tls.key keys. It is not supported by PersistentVolume sinks.
+	// +optional
+	Credentials *corev1.LocalObjectReference `json:"credentials,omitempty"`
+}
+
+// AuditSinkType is the type of destination audit events are forwarded to.
+// +kubebuilder:validation:Enum=PersistentVolume;Syslog;HTTP
+type AuditSinkType string
+
+const (
+	// PersistentVolumeAuditSink appends audit events to files on a persistent
+	// volume of the control plane.
+	PersistentVolumeAuditSink AuditSinkType = "PersistentVolume"
+
+	// SyslogAuditSink sends audit events to a syslog server.
+	SyslogAuditSink AuditSinkType = "Syslog"
+
+	// HTTPAuditSink posts audit events to an HTTP endpoint.
+	HTTPAuditSink AuditSinkType = "HTTP"
+)
+
+// AuditSinkSpec defines the destination of audit events.
+type AuditSinkSpec struct {
+	// Type is the type of destination.
+	// +unionDiscriminator
+	Type AuditSinkType `json:"type"`
+
+	// PersistentVolume configures the volume audit events are written to. It
+	// is required when Type is PersistentVolume.
+	// +optional
+	PersistentVolume *AuditPersistentVolumeSinkSpec `json:"persistentVolume,omitempty"`
+
+	// Syslog configures the syslog server. It is required when Type is Syslog.
+	// +optional
+	Syslog *AuditSyslogSinkSpec `json:"syslog,omitempty"`
+
+	// HTTP configures the HTTP endpoint. It is required when Type is HTTP.
+	// +optional
+	HTTP *AuditHTTPSinkSpec `json:"http,omitempty"`
+}
+
+// AuditPersistentVolumeSinkSpec defines the claim created in the control plane
+// namespace for audit logs. Every kube apiserver replica writes daily files
+// to its own directory of the volume; the volume is mounted by all replicas,
+// so the storage class must support the ReadWriteMany access mode.
+type AuditPersistentVolumeSinkSpec struct {
+	// StorageClassName is the storage class of the claimed volume. It must
+	// support the ReadWriteMany access mode, so the default storage class of
+	// the management cluster is not used.
+	// +kubebuilder:validation:MinLength=1
+	StorageClassName string `json:"storageClassName"`
+
+	// Size is the requested size of the volume
+	Size resource.Quantity `json:"size"`
+}
+
+// AuditSyslogSinkSpec defines a syslog server.
+type AuditSyslogSinkSpec struct {
+	// Address is the host:port of the syslog server.
+	Address string `json:"address"`
+
+	// Protocol is the transport used to reach the server. It must be TCP when
+	// the log backend has a CA.
+	// +kubebuilder:validation:Enum=TCP;UDP
+	// +kubebuilder:default=TCP
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+}
+
+// AuditHTTPSinkSpec defines an HTTP endpoint. Audit events are posted in
+// batches as newline delimited JSON.
+type AuditHTTPSinkSpec struct {
+	// URL of the endpoint.
+	URL string `json:"url"`
+}
+
+// ControlPlaneComponentOverride customizes a control plane component.
+type ControlPlaneComponentOverride struct {
+	// Name is the name of the deployment or stateful set of the component in
+	// the control plane namespace, e.g. kube-apiserver or etcd.
+	Name string `json:"name"`
+
+	// Replicas is the number of replicas of the component. It is not supported
+	// for etcd, whose size follows ControllerAvailabilityPolicy.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources replaces the resource requirements of containers of the
+	// component.
+	// +optional
+	// +listType=map
+	// +listMapKey=container
+	Resources []ContainerResourcesOverride `json:"resources,omitempty"`
+
+	// NodeSelector restricts the nodes the component is scheduled to.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations are added to the tolerations of the component.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// PriorityClassName replaces the priority class of the component.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+}
+
+// EtcdComponentName is the name of the etcd component of the control plane.
+const EtcdComponentName = "etcd"
+
+// ControlPlaneComponents are the names of all the control plane components
+// which can be overridden.
+var ControlPlaneComponents = []string{
+	"cluster-policy-controller",
+	"cluster-version-operator",
+	EtcdComponentName,
+	"konnectivity-agent",
+	"konnectivity-server",
+	"kube-apiserver",
+	"kube-controller-manager",
+	"kube-scheduler",
+	"oauth-openshift",
+	"openshift-apiserver",
+	"openshift-controller-manager",
+	"openshift-oauth-apiserver",
+}
+
+// ContainerResourcesOverride defines the resource requirements of a container.
+type ContainerResourcesOverride struct {
+	// Container is the name of the container.
+	Container string `json:"container"`
+
+	// Resources are the resource requirements of the container.
+	Resources corev1.ResourceRequirements `json:"resources"`
+}
+
+// ImageOverrideComponent is a component whose image can be overridden. The
+// names match the component names of the release image where there is one.
+// +kubebuilder:validation:Enum=cli;cluster-api;cluster-api-provider-aws;cluster-autoscaler;cluster-config-operator;cluster-policy-controller;deployer;docker-builder;etcd;haproxy-router;hosted-cluster-config-operator;hyperkube;hypershift;konnectivity-agent;konnectivity-server;oauth-apiserver;oauth-server;openshift-apiserver;openshift-controller-manager;operator-lifecycle-manager;operator-registry
+type ImageOverrideComponent string
+
+const (
+	CLIImageComponent                         ImageOverrideComponent = "cli"
+	ClusterAPIImageComponent                  ImageOverrideComponent = "cluster-api"
+	ClusterAPIProviderAWSImageComponent       ImageOverrideComponent = "cluster-api-provider-aws"
+	ClusterAutoscalerImageComponent           ImageOverrideComponent = "cluster-autoscaler"
+	ClusterConfigOperatorImageComponent       ImageOverrideComponent = "cluster-config-operator"
+	ClusterPolicyControllerImageComponent     ImageOverrideComponent = "cluster-policy-controller"
+	DeployerImageComponent                    ImageOverrideComponent = "deployer"
+	DockerBuilderImageComponent               ImageOverrideComponent = "docker-builder"
+	EtcdImageComponent                        ImageOverrideComponent = "etcd"
+	HAProxyRouterImageComponent               ImageOverrideComponent = "haproxy-router"
+	HostedClusterConfigOperatorImageComponent ImageOverrideComponent = "hosted-cluster-config-operator"
+	HyperkubeImageComponent                   ImageOverrideComponent = "hyperkube"
+	HyperShiftImageComponent                  ImageOverrideComponent = "hypershift"
+	KonnectivityAgentImageComponent           ImageOverrideComponent = "konnectivity-agent"
+	KonnectivityServerImageComponent          ImageOverrideComponent = "konnectivity-server"
+	OAuthAPIServerImageComponent              ImageOverrideComponent = "oauth-apiserver"
+	OAuthServerImageComponent                 ImageOverrideComponent = "oauth-server"
+	OpenShiftAPIServerImageComponent          ImageOverrideComponent = "openshift-apiserver"
+	OpenShiftControllerManagerImageComponent  ImageOverrideComponent = "openshift-controller-manager"
+	OperatorLifecycleManagerImageComponent    ImageOverrideComponent = "operator-lifecycle-manager"
+	OperatorRegistryImageComponent            ImageOverrideComponent = "operator-registry"
+)
+
+// DefaultKonnectivityImage is the konnectivity server and agent image used for
+// release images which don't include the apiserver-network-proxy component,
+// such as 4.8 releases. It's pinned by digest so that every control plane
+// runs the same image and the image can be mirrored.
+const DefaultKonnectivityImage = "registry.ci.openshift.org/hypershift/apiserver-network-proxy@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+// ImageOverrideComponents are all the components whose image can be overridden.
+var ImageOverrideComponents = []ImageOverrideComponent{
+	CLIImageComponent,
+	ClusterAPIImageComponent,
+	ClusterAPIProviderAWSImageComponent,
+	ClusterAutoscalerImageComponent,
+	ClusterConfigOperatorImageComponent,
+	ClusterPolicyControllerImageComponent,
+	DeployerImageComponent,
+	DockerBuilderImageComponent,
+	EtcdImageComponent,
+	HAProxyRouterImageComponent,
+	HostedClusterConfigOperatorImageComponent,
+	HyperkubeImageComponent,
+	HyperShiftImageComponent,
+	KonnectivityAgentImageComponent,
+	KonnectivityServerImageComponent,
+	OAuthAPIServerImageComponent,
+	OAuthServerImageComponent,
+	OpenShiftAPIServerImageComponent,
+	OpenShiftControllerManagerImageComponent,
+	OperatorLifecycleManagerImageComponent,
+	OperatorRegistryImageComponent,
+}
+
+// ImageOverride replaces the image of a component.
+type ImageOverride struct {
+	// Component is the component whose image is replaced.
+	Component ImageOverrideComponent `json:"component"`
+
+	// Image is the pull spec of the image to use for the component.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
 }
 
 // ImageContentSource defines a list of sources/repositories that can be used to pull content.
@@ -377,8 +786,144 @@ type EtcdSpec struct {
 }
 
 type ManagedEtcdSpec struct {
+	// Storage configures the storage used by the etcd members. Members use
+	// ephemeral storage if unset. It is immutable.
+	// +optional
+	Storage *ManagedEtcdStorageSpec `json:"storage,omitempty"`
+
+	// Backup defines a policy for taking periodic snapshots of the etcd cluster
+	// +optional
+	Backup *EtcdBackupPolicy `json:"backup,omitempty"`
+
+	// Restore recreates the etcd cluster from a snapshot taken by the backup policy.
+	// The etcd cluster is restored once for every distinct snapshot name.
+	// Clusters created by previous versions with the etcd operator can't be
+	// restored, and the ValidConfiguration condition reports the ignored restore.
+	// +optional
+	Restore *EtcdRestoreSpec `json:"restore,omitempty"`
+}
+
+// ManagedEtcdStorageType is a type of storage for the members of a managed etcd cluster
+type ManagedEtcdStorageType string
+
+const (
+	// PersistentVolumeEtcdStorage uses a persistent volume claimed for every etcd member
+	PersistentVolumeEtcdStorage ManagedEtcdStorageType = "PersistentVolume"
+
+	// EphemeralEtcdStorage uses an emptyDir volume which is lost when the etcd member's pod is deleted
+	EphemeralEtcdStorage ManagedEtcdStorageType = "Ephemeral"
+)
+
+// DefaultPersistentVolumeEtcdStorageSize is the size of the volume claimed for
+// every etcd member when no size is specified
+const DefaultPersistentVolumeEtcdStorageSize = "4Gi"
+
+// ManagedEtcdStorageSpec describes the storage of the members of a managed etcd cluster
+type ManagedEtcdStorageSpec struct {
+	// Type is the type of storage used by the etcd members
+	// +unionDiscriminator
+	// +kubebuilder:validation:Enum=PersistentVolume;Ephemeral
+	Type ManagedEtcdStorageType `json:"type"`
+
+	// PersistentVolume configures the volume claimed for every etcd member
+	// +optional
+	PersistentVolume *PersistentVolumeEtcdStorageSpec `json:"persistentVolume,omitempty"`
+}
+
+// PersistentVolumeEtcdStorageSpec defines the volume claimed for every etcd member
+type PersistentVolumeEtcdStorageSpec struct {
+	// StorageClassName is the storage class of the claimed volumes. The default storage class is used if unset.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Size is the requested size of every volume. Defaults to 4Gi.
+	// +optional
+	Size *resource.Quantity `json:"size,omitempty"`
+}
+
+// EtcdBackupPolicy defines how often etcd snapshots are taken, how many are kept
+// and where they are stored
+type EtcdBackupPolicy struct {
+	// Schedule is the interval between two consecutive snapshots, for example 1h
+	Schedule metav1.Duration `json:"schedule"`
+
+	// MaxBackups is the number of snapshots to retain. Older snapshots are removed.
+	// Zero means snapshots are never removed.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxBackups int `json:"maxBackups,omitempty"`
+
+	// Destination is where the snapshots are stored
+	Destination EtcdBackupDestination `json:"destination"`
+}
+
+// EtcdBackupDestinationType is a type of storage for etcd snapshots
+type EtcdBackupDestinationType string
+
+const (
+	// PersistentVolumeEtcdBackupDestination stores snapshots on a persistent volume
+	// in the control plane namespace
+	PersistentVolumeEtcdBackupDestination EtcdBackupDestinationType = "PersistentVolume"
+
+	// S3EtcdBackupDestination stores snapshots in an S3 compatible bucket
+	S3EtcdBackupDestination EtcdBackupDestinationType = "S3"
+)
+
+// EtcdBackupDestination is where etcd snapshots are stored
+type EtcdBackupDestination struct {
+	// Type is the type of storage for the snapshots
+	// +unionDiscriminator
+	// +kubebuilder:validation:Enum=PersistentVolume;S3
+	Type EtcdBackupDestinationType `json:"type"`
 
-	//TODO: Ultimately backup policies, etc can be defined here.
+	// PersistentVolume stores snapshots on a persistent volume claimed in the control plane namespace
+	// +optional
+	PersistentVolume *EtcdBackupPersistentVolumeDestination `json:"persistentVolume,omitempty"`
+
+	// S3 stores snapshots in an S3 compatible bucket
+	// +optional
+	S3 *EtcdBackupS3Destination `json:"s3,omitempty"`
+}
+
+// EtcdBackupPersistentVolumeDestination defines the volume claimed for etcd snapshots
+type EtcdBackupPersistentVolumeDestination struct {
+	// StorageClassName is the storage class of the claimed volume. The default storage class is used if unset.
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
+
+	// Size is the requested size of the volume
+	Size resource.Quantity `json:"size"`
+}
+
+// EtcdBackupS3Destination defines an S3 compatible bucket for etcd snapshots
+type EtcdBackupS3Destination struct {
+	// Bucket is the name of the bucket
+	Bucket string `json:"bucket"`
+
+	// Prefix is prepended to the name of every snapshot stored in the bucket
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// Endpoint is the URL of an S3 compatible object store. AWS S3 is used if unset.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// ForcePathStyle forces path style bucket addressing, for object stores which
+	// don't support subdomain style addressing
+	// +optional
+	ForcePathStyle bool `json:"forcePathStyle,omitempty"`
+
+	// Credentials is a reference to a secret with the 'credentials' and 'config'
+	// AWS files used to access the bucket
+	Credentials corev1.LocalObjectReference `json:"credentials"`
+}
+
+// EtcdRestoreSpec identifies a snapshot to restore the etcd cluster from
+type EtcdRestoreSpec struct {
+	// SnapshotName is the name of the snapshot in the backup destination, relative
+	// to the bucket or to the root of the backup volume.
+	// +kubebuilder:validation:MinLength=1
+	SnapshotName string `json:"snapshotName"`
 }
 
 // UnmanagedEtcdSpec defines metadata that enables the Openshift controllers to connect to the external etcd cluster
@@ -418,6 +963,18 @@ const (
 	// ValidHostedClusterConfiguration indicates (if status is true) that the
 	// ClusterConfiguration specified for the HostedCluster is valid.
 	ValidHostedClusterConfiguration ConditionType = "ValidConfiguration"
+
+	// HostedClusterCertificatesExpiring mirrors the CertificatesExpiring
+	// condition of the HostedControlPlane.
+	HostedClusterCertificatesExpiring ConditionType = "CertificatesExpiring"
+
+	// HostedClusterKonnectivityAvailable mirrors the KonnectivityAvailable
+	// condition of the HostedControlPlane.
+	HostedClusterKonnectivityAvailable ConditionType = "KonnectivityAvailable"
+
+	// HostedClusterValidAuditPolicy mirrors the ValidAuditPolicy condition of
+	// the HostedControlPlane.
+	HostedClusterValidAuditPolicy ConditionType = "ValidAuditPolicy"
 )
 
 const (
@@ -429,9 +986,14 @@ const (
 	HostedClusterAsExpectedReason          = "HostedClusterAsExpected"
 	HostedClusterUnhealthyComponentsReason = "UnhealthyControlPlaneComponents"
 
-	UnmanagedEtcdStatusUnknownReason = "UnmanagedEtcdStatusUnknown"
-	UnmanagedEtcdMisconfiguredReason = "UnmanagedEtcdMisconfigured"
-	UnmanagedEtcdAsExpected          = "UnmanagedEtcdAsExpected"
+	UnmanagedEtcdStatusUnknownReason       = "UnmanagedEtcdStatusUnknown"
+	UnmanagedEtcdMisconfiguredReason       = "UnmanagedEtcdMisconfigured"
+	UnmanagedEtcdAsExpected                = "UnmanagedEtcdAsExpected"
+	UnmanagedEtcdEndpointUnreachableReason = "UnmanagedEtcdEndpointUnreachable"
+	UnmanagedEtcdTLSHandshakeFailedReason  = "UnmanagedEtcdTLSHandshakeFailed"
+	UnmanagedEtcdUnhealthyMemberReason     = "UnmanagedEtcdUnhealthyMember"
+
+	ImmutableFieldChangedReason = "ImmutableFieldChanged"
 )
 
 // HostedClusterStatus defines the observed state of HostedCluster
@@ -451,9 +1013,77 @@ type HostedClusterStatus struct {
 	// +optional
 	IgnitionEndpoint string `json:"ignitionEndpoint"`
 
+	// UnmanagedEtcd is the state of the user-managed etcd cluster observed the
+	// last time its endpoint was probed. It is only set for unmanaged etcd.
+	// +optional
+	UnmanagedEtcd *UnmanagedEtcdStatus `json:"unmanagedEtcd,omitempty"`
+
+	// Size is the size the cluster was classified into by the cluster sizing
+	// configuration. It is only set when a sizing configuration exists.
+	// +optional
+	Size *ClusterSizeStatus `json:"size,omitempty"`
+
+	// KonnectivityImages are the images of the konnectivity server and agent
+	// and where they were taken from, as reported by the HostedControlPlane.
+	// +optional
+	KonnectivityImages *KonnectivityImagesStatus `json:"konnectivityImages,omitempty"`
+
 	Conditions []metav1.Condition `json:"conditions"`
 }
 
+// UnmanagedEtcdStatus is the observed state of a user-managed etcd cluster
+type UnmanagedEtcdStatus struct {
+	// LastProbeTime is the last time the etcd endpoint was probed
+	LastProbeTime metav1.Time `json:"lastProbeTime"`
+
+	// Reachable is true if a TLS connection to the etcd endpoint could be
+	// established with the client certificate
+	Reachable bool `json:"reachable"`
+
+	// Healthy is true if the etcd endpoint and all the members of the cluster
+	// report that they are healthy
+	Healthy bool `json:"healthy"`
+
+	// Version is the etcd version of the member serving the endpoint
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Leader is the name of the member which is the current leader of the cluster
+	// +optional
+	Leader string `json:"leader,omitempty"`
+
+	// DBSizeBytes is the size of the database of the member serving the endpoint
+	// +optional
+	DBSizeBytes int64 `json:"dbSizeBytes,omitempty"`
+
+	// Members are the members of the etcd cluster
+	// +optional
+	Members []UnmanagedEtcdMemberStatus `json:"members,omitempty"`
+}
+
+// UnmanagedEtcdMemberStatus is the observed state of a member of a
+// user-managed etcd cluster
+type UnmanagedEtcdMemberStatus struct {
+	// ID is the hexadecimal ID of the member
+	ID string `json:"id"`
+
+	// Name is the name of the member. Members which haven't started yet have
+	// no name.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// ClientURLs are the URLs the member serves clients on
+	// +optional
+	ClientURLs []string `json:"clientURLs,omitempty"`
+
+	// Healthy is true if the member reports that it is healthy
+	Healthy bool `json:"healthy"`
+
+	// Message explains why the member is unhealthy
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // ClusterVersionStatus reports the status of the cluster versioning,
 // including any upgrades that are in progress. The current field will
 // be set to whichever version the cluster is reconciling to, and the
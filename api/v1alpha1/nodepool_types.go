@@ -59,10 +59,12 @@ type NodePoolSpec struct {
 	NodeCount *int32 `json:"nodeCount"`
 
 	// +kubebuilder:validation:Optional
-	// TODO (alberto): this ConfigMaps are meant to contain
-	// MachineConfig, KubeletConfig and ContainerRuntimeConfig but
-	// MCO only supports MachineConfig in bootstrap mode atm
-	// https://github.com/openshift/machine-config-operator/blob/9c6c2bfd7ed498bfbc296d530d1839bd6a177b0b/pkg/controller/bootstrap/bootstrap.go#L104-L119
+	// Config is a list of references to ConfigMaps containing a serialized
+	// MachineConfig, KubeletConfig or ContainerRuntimeConfig to be rendered
+	// into the ignition payload of the nodes of the NodePool.
+	// KubeletConfigs and ContainerRuntimeConfigs always apply to all the nodes
+	// of the NodePool, their machineConfigPoolSelector is ignored. They require
+	// a release image of at least 4.9.0.
 	// By contractual convention the ConfigMap structure is as follow:
 	// type: ConfigMap
 	//   data:
@@ -76,6 +78,20 @@ type NodePoolSpec struct {
 
 	Platform NodePoolPlatform `json:"platform"`
 
+	// NodeLabels are labels applied to the nodes of the NodePool when they
+	// register and kept in sync with the NodePool afterwards. Changes are
+	// applied to existing nodes right away without rolling them out. Labels
+	// of the kubernetes.io and k8s.io namespaces the kubelet can't set, e.g.
+	// node-role.kubernetes.io/infra, are applied once the nodes registered.
+	// +optional
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+
+	// Taints are taints applied to the nodes of the NodePool when they
+	// register and kept in sync with the NodePool afterwards. Changes are
+	// applied to existing nodes right away without rolling them out.
+	// +optional
+	Taints []Taint `json:"taints,omitempty"`
+
 	// Release specifies the release image to use for this NodePool
 	// For a nodePool a given version dictates the ignition config and
 	// an image artifact e.g an AMI in AWS.
@@ -85,6 +101,24 @@ type NodePoolSpec struct {
 	Release Release `json:"release"`
 }
 
+// Taint is a taint applied to the nodes of a NodePool.
+type Taint struct {
+	// Key is the taint key to be applied to a node.
+	// +kubebuilder:validation:Required
+	// +required
+	Key string `json:"key"`
+
+	// Value is the taint value corresponding to the taint key.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// Effect is the effect of the taint on pods that do not tolerate it.
+	// +kubebuilder:validation:Enum=NoSchedule;PreferNoSchedule;NoExecute
+	// +kubebuilder:validation:Required
+	// +required
+	Effect v1.TaintEffect `json:"effect"`
+}
+
 // NodePoolStatus defines the observed state of NodePool
 type NodePoolStatus struct {
 	// NodeCount is the most recently observed number of replicas.
@@ -102,6 +136,48 @@ type NodePoolStatus struct {
 	// an image artifact e.g an AMI in AWS.
 	// +kubebuilder:validation:Optional
 	Version string `json:"version,omitempty"`
+
+	// Nodes reports the progress of in-place upgrades on each node of the
+	// pool. It is only set for NodePools with the InPlace upgrade type.
+	// +optional
+	Nodes []NodePoolNodeStatus `json:"nodes,omitempty"`
+}
+
+// NodeUpgradeState is the state of the in-place upgrade of a node.
+type NodeUpgradeState string
+
+const (
+	// NodeUpgradeStateDone means the node runs the current config of the
+	// NodePool.
+	NodeUpgradeStateDone = NodeUpgradeState("Done")
+	// NodeUpgradeStatePending means the node waits for its turn to be updated.
+	NodeUpgradeStatePending = NodeUpgradeState("Pending")
+	// NodeUpgradeStateDraining means the node is cordoned and its pods are
+	// being evicted.
+	NodeUpgradeStateDraining = NodeUpgradeState("Draining")
+	// NodeUpgradeStateUpdating means the new config is being applied to the
+	// node, which reboots into it.
+	NodeUpgradeStateUpdating = NodeUpgradeState("Updating")
+	// NodeUpgradeStateDegraded means applying the new config to the node
+	// failed. The upgrade of the pool does not progress until it is resolved.
+	NodeUpgradeStateDegraded = NodeUpgradeState("Degraded")
+)
+
+// NodePoolNodeStatus is the in-place upgrade progress of a node.
+type NodePoolNodeStatus struct {
+	// Name is the name of the node.
+	Name string `json:"name"`
+
+	// ConfigVersion identifies the config and version the node runs.
+	// +optional
+	ConfigVersion string `json:"configVersion,omitempty"`
+
+	// State is the state of the upgrade of the node.
+	State NodeUpgradeState `json:"state"`
+
+	// Message describes why a node is degraded.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -135,7 +211,15 @@ type RollingUpdate struct {
 	MaxSurge       *intstr.IntOrString `json:"maxSurge,omitempty"`
 }
 
+// InPlaceUpgrade configures how existing nodes are updated to a new config
+// or version without replacing their machines.
 type InPlaceUpgrade struct {
+	// MaxUnavailable is the maximum number of nodes that are drained and
+	// updated at the same time, either as a number or as a percentage of the
+	// nodes of the pool. Nodes which are already unavailable count against it.
+	// Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
 type NodePoolManagement struct {
@@ -176,12 +260,69 @@ type AWSNodePoolPlatform struct {
 	// Subnet is the subnet to use for instances
 	// +optional
 	Subnet *AWSResourceReference `json:"subnet,omitempty"`
+	// Zones spreads the instances of the NodePool across availability zones.
+	// A MachineDeployment is created per zone, and the NodeCount and the
+	// AutoScaling min and max are distributed evenly across them. The
+	// AutoScaling min must be at least the number of zones. The
+	// MachineDeployments of removed zones, or of the Subnet, are scaled down
+	// once the ones of the current zones have all their replicas available.
+	// Zones can't be combined with Subnet nor with the InPlace upgrade type.
+	// +optional
+	Zones []AWSNodePoolZone `json:"zones,omitempty"`
 	// AMI is the image id to use
 	// +optional
 	AMI string `json:"ami,omitempty"`
 	// SecurityGroups is the set of security groups to associate with nodepool machines
 	// +optional
 	SecurityGroups []AWSResourceReference `json:"securityGroups,omitempty"`
+	// RootVolume specifies the root volume of the instances. The volume of
+	// the AMI is used when it is not set.
+	// +optional
+	RootVolume *Volume `json:"rootVolume,omitempty"`
+	// AdditionalVolumes are EBS volumes attached to the instances in addition
+	// to the root volume. Each of them requires a device name.
+	// +optional
+	AdditionalVolumes []Volume `json:"additionalVolumes,omitempty"`
+	// Tenancy indicates if the instances run on shared or single-tenant
+	// hardware.
+	// +kubebuilder:validation:Enum=default;dedicated;host
+	// +optional
+	Tenancy string `json:"tenancy,omitempty"`
+}
+
+// Volume encapsulates the configuration options of an EBS volume.
+type Volume struct {
+	// DeviceName is the device name of the volume, e.g. /dev/sdb. It is only
+	// used for additional volumes.
+	// +optional
+	DeviceName string `json:"deviceName,omitempty"`
+	// Size specifies the size (in Gi) of the volume.
+	// +kubebuilder:validation:Minimum=8
+	Size int64 `json:"size"`
+	// Type is the type of the volume.
+	// +kubebuilder:validation:Enum=standard;gp2;gp3;io1;io2;sc1;st1
+	// +optional
+	Type string `json:"type,omitempty"`
+	// IOPS is the number of IOPS provisioned for the volume. It is required
+	// for io1 and io2 volumes and only allowed for io1, io2 and gp3 volumes.
+	// +optional
+	IOPS int64 `json:"iops,omitempty"`
+	// Encrypted is whether the volume is encrypted.
+	// +optional
+	Encrypted bool `json:"encrypted,omitempty"`
+	// EncryptionKey is the KMS key ID or ARN used to encrypt the volume. The
+	// default AWS key is used when Encrypted is set and it is omitted.
+	// +optional
+	EncryptionKey string `json:"encryptionKey,omitempty"`
+}
+
+// AWSNodePoolZone is an availability zone the instances of a NodePool are
+// created in.
+type AWSNodePoolZone struct {
+	// Name is the name of the availability zone, e.g. us-east-1a.
+	Name string `json:"name"`
+	// Subnet is the subnet in the availability zone to use for instances.
+	Subnet AWSResourceReference `json:"subnet"`
 }
 
 // AWSResourceReference is a reference to a specific AWS resource by ID, ARN, or filters.
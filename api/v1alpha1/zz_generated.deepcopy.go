@@ -22,8 +22,8 @@ package v1alpha1
 
 import (
 	configv1 "github.com/openshift/api/config/v1"
-	"k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
@@ -88,6 +88,37 @@ func (in *AWSCloudProviderConfig) DeepCopy() *AWSCloudProviderConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSKMSAuthSpec) DeepCopyInto(out *AWSKMSAuthSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSKMSAuthSpec.
+func (in *AWSKMSAuthSpec) DeepCopy() *AWSKMSAuthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSKMSAuthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSKMSSpec) DeepCopyInto(out *AWSKMSSpec) {
+	*out = *in
+	out.Auth = in.Auth
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSKMSSpec.
+func (in *AWSKMSSpec) DeepCopy() *AWSKMSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSKMSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSNodePoolPlatform) DeepCopyInto(out *AWSNodePoolPlatform) {
 	*out = *in
@@ -96,6 +127,13 @@ func (in *AWSNodePoolPlatform) DeepCopyInto(out *AWSNodePoolPlatform) {
 		*out = new(AWSResourceReference)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Zones != nil {
+		in, out := &in.Zones, &out.Zones
+		*out = make([]AWSNodePoolZone, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.SecurityGroups != nil {
 		in, out := &in.SecurityGroups, &out.SecurityGroups
 		*out = make([]AWSResourceReference, len(*in))
@@ -103,6 +141,16 @@ func (in *AWSNodePoolPlatform) DeepCopyInto(out *AWSNodePoolPlatform) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RootVolume != nil {
+		in, out := &in.RootVolume, &out.RootVolume
+		*out = new(Volume)
+		**out = **in
+	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = make([]Volume, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSNodePoolPlatform.
@@ -115,6 +163,22 @@ func (in *AWSNodePoolPlatform) DeepCopy() *AWSNodePoolPlatform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AWSNodePoolZone) DeepCopyInto(out *AWSNodePoolZone) {
+	*out = *in
+	in.Subnet.DeepCopyInto(&out.Subnet)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AWSNodePoolZone.
+func (in *AWSNodePoolZone) DeepCopy() *AWSNodePoolZone {
+	if in == nil {
+		return nil
+	}
+	out := new(AWSNodePoolZone)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSPlatformSpec) DeepCopyInto(out *AWSPlatformSpec) {
 	*out = *in
@@ -209,6 +273,175 @@ func (in *AWSServiceEndpoint) DeepCopy() *AWSServiceEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditHTTPSinkSpec) DeepCopyInto(out *AuditHTTPSinkSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditHTTPSinkSpec.
+func (in *AuditHTTPSinkSpec) DeepCopy() *AuditHTTPSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditHTTPSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogBackendSpec) DeepCopyInto(out *AuditLogBackendSpec) {
+	*out = *in
+	in.Sink.DeepCopyInto(&out.Sink)
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogBackendSpec.
+func (in *AuditLogBackendSpec) DeepCopy() *AuditLogBackendSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogBackendSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditPersistentVolumeSinkSpec) DeepCopyInto(out *AuditPersistentVolumeSinkSpec) {
+	*out = *in
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditPersistentVolumeSinkSpec.
+func (in *AuditPersistentVolumeSinkSpec) DeepCopy() *AuditPersistentVolumeSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditPersistentVolumeSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSinkSpec) DeepCopyInto(out *AuditSinkSpec) {
+	*out = *in
+	if in.PersistentVolume != nil {
+		in, out := &in.PersistentVolume, &out.PersistentVolume
+		*out = new(AuditPersistentVolumeSinkSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Syslog != nil {
+		in, out := &in.Syslog, &out.Syslog
+		*out = new(AuditSyslogSinkSpec)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(AuditHTTPSinkSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSinkSpec.
+func (in *AuditSinkSpec) DeepCopy() *AuditSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSpec) DeepCopyInto(out *AuditSpec) {
+	*out = *in
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.LogBackend != nil {
+		in, out := &in.LogBackend, &out.LogBackend
+		*out = new(AuditLogBackendSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSpec.
+func (in *AuditSpec) DeepCopy() *AuditSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditSyslogSinkSpec) DeepCopyInto(out *AuditSyslogSinkSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditSyslogSinkSpec.
+func (in *AuditSyslogSinkSpec) DeepCopy() *AuditSyslogSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditSyslogSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRotationSpec) DeepCopyInto(out *CertificateRotationSpec) {
+	*out = *in
+	if in.CAOverlapDuration != nil {
+		in, out := &in.CAOverlapDuration, &out.CAOverlapDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ExpiryWarningPeriod != nil {
+		in, out := &in.ExpiryWarningPeriod, &out.ExpiryWarningPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRotationSpec.
+func (in *CertificateRotationSpec) DeepCopy() *CertificateRotationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRotationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	in.RenewalTime.DeepCopyInto(&out.RenewalTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterAutoscaling) DeepCopyInto(out *ClusterAutoscaling) {
 	*out = *in
@@ -244,12 +477,12 @@ func (in *ClusterConfiguration) DeepCopyInto(out *ClusterConfiguration) {
 	*out = *in
 	if in.SecretRefs != nil {
 		in, out := &in.SecretRefs, &out.SecretRefs
-		*out = make([]v1.LocalObjectReference, len(*in))
+		*out = make([]corev1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
 	if in.ConfigMapRefs != nil {
 		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
-		*out = make([]v1.LocalObjectReference, len(*in))
+		*out = make([]corev1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
 	if in.Items != nil {
@@ -281,50 +514,343 @@ func (in *ClusterNetworking) DeepCopyInto(out *ClusterNetworking) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNetworking.
-func (in *ClusterNetworking) DeepCopy() *ClusterNetworking {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterNetworking.
+func (in *ClusterNetworking) DeepCopy() *ClusterNetworking {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterNetworking)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSize) DeepCopyInto(out *ClusterSize) {
+	*out = *in
+	if in.ComponentOverrides != nil {
+		in, out := &in.ComponentOverrides, &out.ComponentOverrides
+		*out = make([]ControlPlaneComponentOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSize.
+func (in *ClusterSize) DeepCopy() *ClusterSize {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSize)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSizeStatus) DeepCopyInto(out *ClusterSizeStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.BelowThresholdSince != nil {
+		in, out := &in.BelowThresholdSince, &out.BelowThresholdSince
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSizeStatus.
+func (in *ClusterSizeStatus) DeepCopy() *ClusterSizeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSizeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSizingConfiguration) DeepCopyInto(out *ClusterSizingConfiguration) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSizingConfiguration.
+func (in *ClusterSizingConfiguration) DeepCopy() *ClusterSizingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSizingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSizingConfiguration) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSizingConfigurationList) DeepCopyInto(out *ClusterSizingConfigurationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterSizingConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSizingConfigurationList.
+func (in *ClusterSizingConfigurationList) DeepCopy() *ClusterSizingConfigurationList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSizingConfigurationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterSizingConfigurationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSizingConfigurationSpec) DeepCopyInto(out *ClusterSizingConfigurationSpec) {
+	*out = *in
+	if in.Sizes != nil {
+		in, out := &in.Sizes, &out.Sizes
+		*out = make([]ClusterSize, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DownsizeDelay != nil {
+		in, out := &in.DownsizeDelay, &out.DownsizeDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSizingConfigurationSpec.
+func (in *ClusterSizingConfigurationSpec) DeepCopy() *ClusterSizingConfigurationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSizingConfigurationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterVersionStatus) DeepCopyInto(out *ClusterVersionStatus) {
+	*out = *in
+	out.Desired = in.Desired
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]configv1.UpdateHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVersionStatus.
+func (in *ClusterVersionStatus) DeepCopy() *ClusterVersionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterVersionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentImageStatus) DeepCopyInto(out *ComponentImageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentImageStatus.
+func (in *ComponentImageStatus) DeepCopy() *ComponentImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerResourcesOverride) DeepCopyInto(out *ContainerResourcesOverride) {
+	*out = *in
+	in.Resources.DeepCopyInto(&out.Resources)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerResourcesOverride.
+func (in *ContainerResourcesOverride) DeepCopy() *ContainerResourcesOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerResourcesOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneComponentOverride) DeepCopyInto(out *ControlPlaneComponentOverride) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ContainerResourcesOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneComponentOverride.
+func (in *ControlPlaneComponentOverride) DeepCopy() *ControlPlaneComponentOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneComponentOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSSpec) DeepCopyInto(out *DNSSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSSpec.
+func (in *DNSSpec) DeepCopy() *DNSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupDestination) DeepCopyInto(out *EtcdBackupDestination) {
+	*out = *in
+	if in.PersistentVolume != nil {
+		in, out := &in.PersistentVolume, &out.PersistentVolume
+		*out = new(EtcdBackupPersistentVolumeDestination)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(EtcdBackupS3Destination)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupDestination.
+func (in *EtcdBackupDestination) DeepCopy() *EtcdBackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupPersistentVolumeDestination) DeepCopyInto(out *EtcdBackupPersistentVolumeDestination) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	out.Size = in.Size.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupPersistentVolumeDestination.
+func (in *EtcdBackupPersistentVolumeDestination) DeepCopy() *EtcdBackupPersistentVolumeDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdBackupPersistentVolumeDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdBackupPolicy) DeepCopyInto(out *EtcdBackupPolicy) {
+	*out = *in
+	out.Schedule = in.Schedule
+	in.Destination.DeepCopyInto(&out.Destination)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupPolicy.
+func (in *EtcdBackupPolicy) DeepCopy() *EtcdBackupPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterNetworking)
+	out := new(EtcdBackupPolicy)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterVersionStatus) DeepCopyInto(out *ClusterVersionStatus) {
+func (in *EtcdBackupS3Destination) DeepCopyInto(out *EtcdBackupS3Destination) {
 	*out = *in
-	out.Desired = in.Desired
-	if in.History != nil {
-		in, out := &in.History, &out.History
-		*out = make([]configv1.UpdateHistory, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.Credentials = in.Credentials
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterVersionStatus.
-func (in *ClusterVersionStatus) DeepCopy() *ClusterVersionStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdBackupS3Destination.
+func (in *EtcdBackupS3Destination) DeepCopy() *EtcdBackupS3Destination {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterVersionStatus)
+	out := new(EtcdBackupS3Destination)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DNSSpec) DeepCopyInto(out *DNSSpec) {
+func (in *EtcdRestoreSpec) DeepCopyInto(out *EtcdRestoreSpec) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSSpec.
-func (in *DNSSpec) DeepCopy() *DNSSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdRestoreSpec.
+func (in *EtcdRestoreSpec) DeepCopy() *EtcdRestoreSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(DNSSpec)
+	out := new(EtcdRestoreSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -335,7 +861,7 @@ func (in *EtcdSpec) DeepCopyInto(out *EtcdSpec) {
 	if in.Managed != nil {
 		in, out := &in.Managed, &out.Managed
 		*out = new(ManagedEtcdSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Unmanaged != nil {
 		in, out := &in.Unmanaged, &out.Unmanaged
@@ -456,10 +982,20 @@ func (in *HostedClusterSpec) DeepCopyInto(out *HostedClusterSpec) {
 	out.PullSecret = in.PullSecret
 	if in.AuditWebhook != nil {
 		in, out := &in.AuditWebhook, &out.AuditWebhook
-		*out = new(v1.LocalObjectReference)
+		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(AuditSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.SigningKey = in.SigningKey
+	if in.SigningKeyOverlapDuration != nil {
+		in, out := &in.SigningKeyOverlapDuration, &out.SigningKeyOverlapDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	out.SSHKey = in.SSHKey
 	in.Networking.DeepCopyInto(&out.Networking)
 	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
@@ -472,6 +1008,18 @@ func (in *HostedClusterSpec) DeepCopyInto(out *HostedClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ComponentOverrides != nil {
+		in, out := &in.ComponentOverrides, &out.ComponentOverrides
+		*out = make([]ControlPlaneComponentOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make([]ImageOverride, len(*in))
+		copy(*out, *in)
+	}
 	in.Etcd.DeepCopyInto(&out.Etcd)
 	if in.Configuration != nil {
 		in, out := &in.Configuration, &out.Configuration
@@ -485,6 +1033,16 @@ func (in *HostedClusterSpec) DeepCopyInto(out *HostedClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SecretEncryption != nil {
+		in, out := &in.SecretEncryption, &out.SecretEncryption
+		*out = new(SecretEncryptionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateRotation != nil {
+		in, out := &in.CertificateRotation, &out.CertificateRotation
+		*out = new(CertificateRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostedClusterSpec.
@@ -507,12 +1065,27 @@ func (in *HostedClusterStatus) DeepCopyInto(out *HostedClusterStatus) {
 	}
 	if in.KubeConfig != nil {
 		in, out := &in.KubeConfig, &out.KubeConfig
-		*out = new(v1.LocalObjectReference)
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.UnmanagedEtcd != nil {
+		in, out := &in.UnmanagedEtcd, &out.UnmanagedEtcd
+		*out = new(UnmanagedEtcdStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		*out = new(ClusterSizeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KonnectivityImages != nil {
+		in, out := &in.KonnectivityImages, &out.KonnectivityImages
+		*out = new(KonnectivityImagesStatus)
 		**out = **in
 	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -620,9 +1193,26 @@ func (in *HostedControlPlaneSpec) DeepCopyInto(out *HostedControlPlaneSpec) {
 	}
 	if in.AuditWebhook != nil {
 		in, out := &in.AuditWebhook, &out.AuditWebhook
-		*out = new(v1.LocalObjectReference)
+		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(AuditSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComponentOverrides != nil {
+		in, out := &in.ComponentOverrides, &out.ComponentOverrides
+		*out = make([]ControlPlaneComponentOverride, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImageOverrides != nil {
+		in, out := &in.ImageOverrides, &out.ImageOverrides
+		*out = make([]ImageOverride, len(*in))
+		copy(*out, *in)
+	}
 	in.Etcd.DeepCopyInto(&out.Etcd)
 	if in.Configuration != nil {
 		in, out := &in.Configuration, &out.Configuration
@@ -636,6 +1226,21 @@ func (in *HostedControlPlaneSpec) DeepCopyInto(out *HostedControlPlaneSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SecretEncryption != nil {
+		in, out := &in.SecretEncryption, &out.SecretEncryption
+		*out = new(SecretEncryptionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SigningKeyOverlapDuration != nil {
+		in, out := &in.SigningKeyOverlapDuration, &out.SigningKeyOverlapDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.CertificateRotation != nil {
+		in, out := &in.CertificateRotation, &out.CertificateRotation
+		*out = new(CertificateRotationSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostedControlPlaneSpec.
@@ -666,9 +1271,31 @@ func (in *HostedControlPlaneStatus) DeepCopyInto(out *HostedControlPlaneStatus)
 		*out = new(KubeconfigSecretRef)
 		**out = **in
 	}
+	if in.Etcd != nil {
+		in, out := &in.Etcd, &out.Etcd
+		*out = new(ManagedEtcdStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KonnectivityImages != nil {
+		in, out := &in.KonnectivityImages, &out.KonnectivityImages
+		*out = new(KonnectivityImagesStatus)
+		**out = **in
+	}
+	if in.NodeCount != nil {
+		in, out := &in.NodeCount, &out.NodeCount
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -705,9 +1332,29 @@ func (in *ImageContentSource) DeepCopy() *ImageContentSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageOverride) DeepCopyInto(out *ImageOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageOverride.
+func (in *ImageOverride) DeepCopy() *ImageOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InPlaceUpgrade) DeepCopyInto(out *InPlaceUpgrade) {
 	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InPlaceUpgrade.
@@ -720,6 +1367,43 @@ func (in *InPlaceUpgrade) DeepCopy() *InPlaceUpgrade {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KMSSpec) DeepCopyInto(out *KMSSpec) {
+	*out = *in
+	if in.AWS != nil {
+		in, out := &in.AWS, &out.AWS
+		*out = new(AWSKMSSpec)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KMSSpec.
+func (in *KMSSpec) DeepCopy() *KMSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KMSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KonnectivityImagesStatus) DeepCopyInto(out *KonnectivityImagesStatus) {
+	*out = *in
+	out.Server = in.Server
+	out.Agent = in.Agent
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KonnectivityImagesStatus.
+func (in *KonnectivityImagesStatus) DeepCopy() *KonnectivityImagesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KonnectivityImagesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeconfigSecretRef) DeepCopyInto(out *KubeconfigSecretRef) {
 	*out = *in
@@ -735,9 +1419,43 @@ func (in *KubeconfigSecretRef) DeepCopy() *KubeconfigSecretRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedEtcdMemberStatus) DeepCopyInto(out *ManagedEtcdMemberStatus) {
+	*out = *in
+	if in.LastDefragmentationTime != nil {
+		in, out := &in.LastDefragmentationTime, &out.LastDefragmentationTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedEtcdMemberStatus.
+func (in *ManagedEtcdMemberStatus) DeepCopy() *ManagedEtcdMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedEtcdMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedEtcdSpec) DeepCopyInto(out *ManagedEtcdSpec) {
 	*out = *in
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(ManagedEtcdStorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(EtcdBackupPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(EtcdRestoreSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedEtcdSpec.
@@ -750,6 +1468,48 @@ func (in *ManagedEtcdSpec) DeepCopy() *ManagedEtcdSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedEtcdStatus) DeepCopyInto(out *ManagedEtcdStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]ManagedEtcdMemberStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedEtcdStatus.
+func (in *ManagedEtcdStatus) DeepCopy() *ManagedEtcdStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedEtcdStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedEtcdStorageSpec) DeepCopyInto(out *ManagedEtcdStorageSpec) {
+	*out = *in
+	if in.PersistentVolume != nil {
+		in, out := &in.PersistentVolume, &out.PersistentVolume
+		*out = new(PersistentVolumeEtcdStorageSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedEtcdStorageSpec.
+func (in *ManagedEtcdStorageSpec) DeepCopy() *ManagedEtcdStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedEtcdStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePool) DeepCopyInto(out *NodePool) {
 	*out = *in
@@ -835,7 +1595,7 @@ func (in *NodePoolManagement) DeepCopyInto(out *NodePoolManagement) {
 	if in.InPlace != nil {
 		in, out := &in.InPlace, &out.InPlace
 		*out = new(InPlaceUpgrade)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -849,6 +1609,21 @@ func (in *NodePoolManagement) DeepCopy() *NodePoolManagement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolNodeStatus) DeepCopyInto(out *NodePoolNodeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolNodeStatus.
+func (in *NodePoolNodeStatus) DeepCopy() *NodePoolNodeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolNodeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodePoolPlatform) DeepCopyInto(out *NodePoolPlatform) {
 	*out = *in
@@ -879,7 +1654,7 @@ func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
 	}
 	if in.Config != nil {
 		in, out := &in.Config, &out.Config
-		*out = make([]v1.LocalObjectReference, len(*in))
+		*out = make([]corev1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
 	in.Management.DeepCopyInto(&out.Management)
@@ -889,6 +1664,18 @@ func (in *NodePoolSpec) DeepCopyInto(out *NodePoolSpec) {
 		**out = **in
 	}
 	in.Platform.DeepCopyInto(&out.Platform)
+	if in.NodeLabels != nil {
+		in, out := &in.NodeLabels, &out.NodeLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]Taint, len(*in))
+		copy(*out, *in)
+	}
 	out.Release = in.Release
 }
 
@@ -907,11 +1694,16 @@ func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+		*out = make([]v1.Condition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodePoolNodeStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolStatus.
@@ -939,6 +1731,31 @@ func (in *NodePortPublishingStrategy) DeepCopy() *NodePortPublishingStrategy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PersistentVolumeEtcdStorageSpec) DeepCopyInto(out *PersistentVolumeEtcdStorageSpec) {
+	*out = *in
+	if in.StorageClassName != nil {
+		in, out := &in.StorageClassName, &out.StorageClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Size != nil {
+		in, out := &in.Size, &out.Size
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PersistentVolumeEtcdStorageSpec.
+func (in *PersistentVolumeEtcdStorageSpec) DeepCopy() *PersistentVolumeEtcdStorageSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PersistentVolumeEtcdStorageSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PlatformSpec) DeepCopyInto(out *PlatformSpec) {
 	*out = *in
@@ -1019,6 +1836,26 @@ func (in *RollingUpdate) DeepCopy() *RollingUpdate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretEncryptionSpec) DeepCopyInto(out *SecretEncryptionSpec) {
+	*out = *in
+	if in.KMS != nil {
+		in, out := &in.KMS, &out.KMS
+		*out = new(KMSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretEncryptionSpec.
+func (in *SecretEncryptionSpec) DeepCopy() *SecretEncryptionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretEncryptionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServicePublishingStrategy) DeepCopyInto(out *ServicePublishingStrategy) {
 	*out = *in
@@ -1055,6 +1892,41 @@ func (in *ServicePublishingStrategyMapping) DeepCopy() *ServicePublishingStrateg
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Taint) DeepCopyInto(out *Taint) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Taint.
+func (in *Taint) DeepCopy() *Taint {
+	if in == nil {
+		return nil
+	}
+	out := new(Taint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnmanagedEtcdMemberStatus) DeepCopyInto(out *UnmanagedEtcdMemberStatus) {
+	*out = *in
+	if in.ClientURLs != nil {
+		in, out := &in.ClientURLs, &out.ClientURLs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnmanagedEtcdMemberStatus.
+func (in *UnmanagedEtcdMemberStatus) DeepCopy() *UnmanagedEtcdMemberStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UnmanagedEtcdMemberStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UnmanagedEtcdSpec) DeepCopyInto(out *UnmanagedEtcdSpec) {
 	*out = *in
@@ -1070,3 +1942,41 @@ func (in *UnmanagedEtcdSpec) DeepCopy() *UnmanagedEtcdSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnmanagedEtcdStatus) DeepCopyInto(out *UnmanagedEtcdStatus) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]UnmanagedEtcdMemberStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnmanagedEtcdStatus.
+func (in *UnmanagedEtcdStatus) DeepCopy() *UnmanagedEtcdStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UnmanagedEtcdStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Volume) DeepCopyInto(out *Volume) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Volume.
+func (in *Volume) DeepCopy() *Volume {
+	if in == nil {
+		return nil
+	}
+	out := new(Volume)
+	in.DeepCopyInto(out)
+	return out
+}
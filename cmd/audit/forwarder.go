@@ -0,0 +1,340 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bombsimon/logrusr"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var log = logrusr.NewLogger(logrus.New())
+
+// Options configure how the audit log is tailed, independently of the sink
+// events are forwarded to.
+type Options struct {
+	LogFile      string
+	PositionFile string
+	PollInterval time.Duration
+	BatchSize    int
+	MetricsAddr  string
+}
+
+// NewCommand returns the audit log forwarder which runs alongside the kube
+// apiserver of a hosted control plane to ship its audit log to a sink. It is
+// run from the control plane operator image and is not meant to be used
+// directly.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "audit-forwarder",
+		Short:        "Forwards the audit log of the kube apiserver to a sink",
+		Hidden:       true,
+		SilenceUsage: true,
+	}
+
+	opts := &Options{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		MetricsAddr:  "0",
+	}
+	cmd.PersistentFlags().StringVar(&opts.LogFile, "log-file", opts.LogFile, "Path to the audit log written by the apiserver (required)")
+	cmd.PersistentFlags().StringVar(&opts.PositionFile, "position-file", opts.PositionFile, "Path to the file recording how much of the audit log was forwarded. Defaults to the log file with a .pos suffix")
+	cmd.PersistentFlags().DurationVar(&opts.PollInterval, "poll-interval", opts.PollInterval, "How often the audit log is checked for new events")
+	cmd.PersistentFlags().IntVar(&opts.BatchSize, "batch-size", opts.BatchSize, "The maximum number of events sent to the sink at once")
+	cmd.PersistentFlags().StringVar(&opts.MetricsAddr, "metrics-addr", opts.MetricsAddr, "The address the metric endpoint binds to, 0 disables it")
+
+	cmd.MarkPersistentFlagRequired("log-file")
+
+	cmd.AddCommand(NewFileCommand(opts))
+	cmd.AddCommand(NewSyslogCommand(opts))
+	cmd.AddCommand(NewHTTPCommand(opts))
+
+	return cmd
+}
+
+// Sink receives audit events, each of them a single line of JSON. Write
+// returns a *PermanentError if the sink rejected the events and retrying them
+// can't succeed.
+type Sink interface {
+	Name() string
+	Write(ctx context.Context, events [][]byte) error
+}
+
+// PermanentError is returned by a sink which rejected events it will never
+// accept, for example because they are malformed or too large.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+func isPermanent(err error) bool {
+	var permanentErr *PermanentError
+	return errors.As(err, &permanentErr)
+}
+
+// position identifies how far a log file was forwarded. The inode tells the
+// current log file apart from the files it was rotated to.
+type position struct {
+	Inode  uint64 `json:"inode"`
+	Offset int64  `json:"offset"`
+}
+
+// Forwarder tails the audit log of the apiserver and forwards complete events
+// to a sink. It follows the log across rotations and records its position, so
+// events are forwarded at least once but are not forwarded again when the
+// forwarder restarts.
+type Forwarder struct {
+	LogFile      string
+	PositionFile string
+	BatchSize    int
+	Sink         Sink
+
+	file     *os.File
+	position position
+}
+
+func (o *Options) Forwarder(sink Sink) *Forwarder {
+	positionFile := o.PositionFile
+	if positionFile == "" {
+		positionFile = o.LogFile + ".pos"
+	}
+	return &Forwarder{
+		LogFile:      o.LogFile,
+		PositionFile: positionFile,
+		BatchSize:    o.BatchSize,
+		Sink:         sink,
+	}
+}
+
+// Run polls the audit log until the context is done.
+func (f *Forwarder) Run(ctx context.Context, interval time.Duration) {
+	log.Info("Forwarding audit log", "file", f.LogFile, "sink", f.Sink.Name())
+	defer f.Close()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := f.Poll(ctx); err != nil {
+			log.Error(err, "Failed to forward audit events")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll forwards the events appended to the audit log since the last poll. A
+// failed poll is retried from the same position by the next one.
+func (f *Forwarder) Poll(ctx context.Context) error {
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return err
+		}
+		if f.file == nil {
+			return nil
+		}
+	}
+	if err := f.drain(ctx); err != nil {
+		return err
+	}
+	rotated, err := f.rotated()
+	if err != nil {
+		return err
+	}
+	if rotated {
+		// The apiserver may have written more events before rotating the file
+		if err := f.drain(ctx); err != nil {
+			return err
+		}
+		f.Close()
+		f.position = position{}
+	}
+	return nil
+}
+
+func (f *Forwarder) Close() {
+	if f.file != nil {
+		f.file.Close()
+		f.file = nil
+	}
+}
+
+// open opens the current audit log, resuming from the recorded position if it
+// belongs to the same file. It leaves the file unset if the apiserver has not
+// created the log yet.
+func (f *Forwarder) open() error {
+	file, err := os.Open(f.LogFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	f.file = file
+	f.position = position{Inode: inode(info)}
+	if saved, err := f.loadPosition(); err != nil {
+		log.Error(err, "Ignoring unreadable position file", "file", f.PositionFile)
+	} else if saved.Inode == f.position.Inode && saved.Offset <= info.Size() {
+		f.position.Offset = saved.Offset
+	}
+	return nil
+}
+
+// drain forwards the complete events between the current position and the end
+// of the file. An incomplete last line is left for the next poll.
+func (f *Forwarder) drain(ctx context.Context) error {
+	info, err := f.file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	if info.Size() < f.position.Offset {
+		// The file was truncated, start over
+		f.position.Offset = 0
+	}
+	if _, err := f.file.Seek(f.position.Offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek audit log: %w", err)
+	}
+	reader := bufio.NewReader(f.file)
+	var batch [][]byte
+	var batchBytes int64
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := f.write(ctx, batch); err != nil {
+			return err
+		}
+		f.position.Offset += batchBytes
+		batch, batchBytes = nil, 0
+		return f.savePosition()
+	}
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read audit log: %w", err)
+		}
+		batchBytes += int64(len(line))
+		if event := bytes.TrimSpace(line); len(event) > 0 {
+			batch = append(batch, event)
+		}
+		if len(batch) >= f.BatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// write sends a batch of events to the sink. When the sink rejects the batch
+// permanently, the events are sent one at a time and those which are still
+// rejected are dropped, so that a single bad event doesn't block the events
+// after it forever. Events of the batch may be sent more than once.
+func (f *Forwarder) write(ctx context.Context, batch [][]byte) error {
+	err := f.Sink.Write(ctx, batch)
+	if err == nil {
+		return nil
+	}
+	if !isPermanent(err) {
+		return fmt.Errorf("failed to write to %s sink: %w", f.Sink.Name(), err)
+	}
+	for _, event := range batch {
+		err := f.Sink.Write(ctx, [][]byte{event})
+		if err == nil {
+			continue
+		}
+		if !isPermanent(err) {
+			return fmt.Errorf("failed to write to %s sink: %w", f.Sink.Name(), err)
+		}
+		log.Error(err, "Dropping audit event rejected by the sink", "sink", f.Sink.Name(), "size", len(event))
+		droppedEventsTotal.WithLabelValues(f.Sink.Name()).Inc()
+	}
+	return nil
+}
+
+// rotated returns true if the log file was replaced by a new one.
+func (f *Forwarder) rotated() (bool, error) {
+	info, err := os.Stat(f.LogFile)
+	if os.IsNotExist(err) {
+		// Keep reading the old file until the new one is created
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	current, err := f.file.Stat()
+	if err != nil {
+		return false, fmt.Errorf("failed to stat audit log: %w", err)
+	}
+	return !os.SameFile(info, current), nil
+}
+
+func (f *Forwarder) loadPosition() (position, error) {
+	var saved position
+	data, err := ioutil.ReadFile(f.PositionFile)
+	if os.IsNotExist(err) {
+		return saved, nil
+	}
+	if err != nil {
+		return saved, err
+	}
+	return saved, json.Unmarshal(data, &saved)
+}
+
+func (f *Forwarder) savePosition() error {
+	data, err := json.Marshal(f.position)
+	if err != nil {
+		return err
+	}
+	tmp := f.PositionFile + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write position file: %w", err)
+	}
+	return os.Rename(tmp, f.PositionFile)
+}
+
+func inode(info os.FileInfo) uint64 {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(stat.Ino)
+	}
+	return 0
+}
+
+func run(opts *Options, sink func() (Sink, error)) {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	s, err := sink()
+	if err != nil {
+		log.Error(err, "Failed to configure audit sink")
+		os.Exit(1)
+	}
+	serveMetrics(ctx, opts.MetricsAddr)
+	opts.Forwarder(s).Run(ctx, opts.PollInterval)
+}
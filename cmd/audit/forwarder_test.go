@@ -0,0 +1,213 @@
+package audit
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type recordingSink struct {
+	events   []string
+	err      error
+	rejected string
+}
+
+func (s *recordingSink) Name() string {
+	return "recording"
+}
+
+func (s *recordingSink) Write(ctx context.Context, events [][]byte) error {
+	if s.err != nil {
+		return s.err
+	}
+	for _, event := range events {
+		if string(event) == s.rejected {
+			return &PermanentError{Err: fmt.Errorf("rejected")}
+		}
+	}
+	for _, event := range events {
+		s.events = append(s.events, string(event))
+	}
+	return nil
+}
+
+func appendEvents(g *WithT, name string, events ...string) {
+	file, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer file.Close()
+	for _, event := range events {
+		_, err := file.WriteString(event)
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+}
+
+func TestForwarder(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "audit.log")
+	sink := &recordingSink{}
+	opts := &Options{LogFile: logFile, BatchSize: 2}
+	forwarder := opts.Forwarder(sink)
+	defer forwarder.Close()
+
+	// Nothing happens until the apiserver creates the log
+	g.Expect(forwarder.Poll(ctx)).To(Succeed())
+	g.Expect(sink.events).To(BeEmpty())
+
+	// Incomplete lines are left for the next poll
+	appendEvents(g, logFile, "{\"a\":1}\n", "{\"a\":2}\n", "{\"a\":3}\n", "{\"a\":")
+	g.Expect(forwarder.Poll(ctx)).To(Succeed())
+	g.Expect(sink.events).To(Equal([]string{`{"a":1}`, `{"a":2}`, `{"a":3}`}))
+
+	// Failed writes are retried from the same position
+	sink.err = fmt.Errorf("unavailable")
+	appendEvents(g, logFile, "4}\n")
+	g.Expect(forwarder.Poll(ctx)).ToNot(Succeed())
+	sink.err = nil
+	g.Expect(forwarder.Poll(ctx)).To(Succeed())
+	g.Expect(sink.events).To(HaveLen(4))
+	g.Expect(sink.events[3]).To(Equal(`{"a":4}`))
+
+	// Events written before a rotation are forwarded before the new file
+	appendEvents(g, logFile, "{\"a\":5}\n")
+	g.Expect(os.Rename(logFile, filepath.Join(dir, "audit-1.log"))).To(Succeed())
+	appendEvents(g, logFile, "{\"a\":6}\n")
+	g.Expect(forwarder.Poll(ctx)).To(Succeed())
+	g.Expect(forwarder.Poll(ctx)).To(Succeed())
+	g.Expect(sink.events[4:]).To(Equal([]string{`{"a":5}`, `{"a":6}`}))
+
+	// A restarted forwarder resumes from the recorded position
+	forwarder.Close()
+	appendEvents(g, logFile, "{\"a\":7}\n")
+	restarted := &recordingSink{}
+	forwarder = opts.Forwarder(restarted)
+	g.Expect(forwarder.Poll(ctx)).To(Succeed())
+	g.Expect(restarted.events).To(Equal([]string{`{"a":7}`}))
+}
+
+func TestForwarderDropsRejectedEvents(t *testing.T) {
+	g := NewGomegaWithT(t)
+	ctx := context.Background()
+	logFile := filepath.Join(t.TempDir(), "audit.log")
+	sink := &recordingSink{rejected: `{"a":2}`}
+	forwarder := (&Options{LogFile: logFile, BatchSize: 3}).Forwarder(sink)
+	defer forwarder.Close()
+
+	dropped := func() float64 {
+		metric := &dto.Metric{}
+		g.Expect(droppedEventsTotal.WithLabelValues(sink.Name()).Write(metric)).To(Succeed())
+		return metric.GetCounter().GetValue()
+	}
+	before := dropped()
+
+	// Only the rejected event of a batch is dropped
+	appendEvents(g, logFile, "{\"a\":1}\n", "{\"a\":2}\n", "{\"a\":3}\n", "{\"a\":4}\n")
+	g.Expect(forwarder.Poll(ctx)).To(Succeed())
+	g.Expect(sink.events).To(Equal([]string{`{"a":1}`, `{"a":3}`, `{"a":4}`}))
+	g.Expect(dropped() - before).To(Equal(float64(1)))
+
+	// The forwarder moves on past the dropped event
+	appendEvents(g, logFile, "{\"a\":5}\n")
+	g.Expect(forwarder.Poll(ctx)).To(Succeed())
+	g.Expect(sink.events).To(Equal([]string{`{"a":1}`, `{"a":3}`, `{"a":4}`, `{"a":5}`}))
+}
+
+func TestFileSink(t *testing.T) {
+	g := NewGomegaWithT(t)
+	dir := t.TempDir()
+	sink := &FileSink{Dir: dir, Now: func() time.Time { return time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC) }}
+	g.Expect(sink.Write(context.Background(), [][]byte{[]byte(`{"a":1}`)})).To(Succeed())
+	g.Expect(sink.Write(context.Background(), [][]byte{[]byte(`{"a":2}`)})).To(Succeed())
+	data, err := ioutil.ReadFile(filepath.Join(dir, "audit-2021-09-01.log"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(Equal("{\"a\":1}\n{\"a\":2}\n"))
+}
+
+func TestHTTPSink(t *testing.T) {
+	g := NewGomegaWithT(t)
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received = append(received, r.Header.Get("Content-Type"), string(body))
+	}))
+	defer server.Close()
+
+	sink := &HTTPSink{URL: server.URL, Client: server.Client()}
+	g.Expect(sink.Write(context.Background(), [][]byte{[]byte(`{"a":1}`), []byte(`{"a":2}`)})).To(Succeed())
+	g.Expect(received).To(Equal([]string{"application/x-ndjson", "{\"a\":1}\n{\"a\":2}\n"}))
+}
+
+func TestHTTPSinkErrors(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	sink := &HTTPSink{URL: server.URL, Client: server.Client()}
+
+	for _, tc := range []struct {
+		status    int
+		permanent bool
+	}{
+		{status: http.StatusBadRequest, permanent: true},
+		{status: http.StatusRequestEntityTooLarge, permanent: true},
+		{status: http.StatusUnauthorized},
+		{status: http.StatusTooManyRequests},
+		{status: http.StatusServiceUnavailable},
+	} {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			status = tc.status
+			err := sink.Write(context.Background(), [][]byte{[]byte(`{"a":1}`)})
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(isPermanent(err)).To(Equal(tc.permanent))
+		})
+	}
+}
+
+func TestHTTPSinkTLS(t *testing.T) {
+	g := NewGomegaWithT(t)
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	g.Expect(ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644)).To(Succeed())
+	credentialsDir := filepath.Join(dir, "credentials")
+	g.Expect(os.Mkdir(credentialsDir, 0755)).To(Succeed())
+
+	// The server is verified with the CA bundle
+	opts := TLSOptions{CAFile: caFile, CredentialsDir: credentialsDir}
+	config, err := opts.Config()
+	g.Expect(err).ToNot(HaveOccurred())
+	sink := &HTTPSink{
+		URL:       server.URL,
+		Client:    &http.Client{Transport: &http.Transport{TLSClientConfig: config}},
+		TokenFile: filepath.Join(credentialsDir, "token"),
+	}
+	g.Expect(sink.Write(context.Background(), [][]byte{[]byte(`{"a":1}`)})).To(Succeed())
+	g.Expect(authorization).To(BeEmpty())
+
+	// A token is sent once it exists
+	g.Expect(ioutil.WriteFile(sink.TokenFile, []byte("secret\n"), 0600)).To(Succeed())
+	g.Expect(sink.Write(context.Background(), [][]byte{[]byte(`{"a":2}`)})).To(Succeed())
+	g.Expect(authorization).To(Equal("Bearer secret"))
+
+	// Servers the CA bundle doesn't trust are rejected
+	sink.Client = &http.Client{}
+	g.Expect(sink.Write(context.Background(), [][]byte{[]byte(`{"a":3}`)})).ToNot(Succeed())
+}
@@ -0,0 +1,45 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	droppedEventsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hypershift_audit_forwarder_dropped_events_total",
+		Help: "Number of audit events dropped because the sink permanently rejected them.",
+	}, []string{"sink"})
+)
+
+func init() {
+	registry.MustRegister(droppedEventsTotal)
+}
+
+// serveMetrics serves the metrics of the forwarder on addr until the context
+// is done. An addr of 0 disables the metrics endpoint.
+func serveMetrics(ctx context.Context, addr string) {
+	if addr == "" || addr == "0" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Error(err, "Failed to serve metrics", "address", addr)
+		}
+	}()
+}
@@ -0,0 +1,337 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log/syslog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+
+	"github.com/spf13/cobra"
+)
+
+func NewFileCommand(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "file",
+		Short:        "Appends audit events to daily files in a directory",
+		SilenceUsage: true,
+	}
+
+	var dir string
+	cmd.Flags().StringVar(&dir, "dir", dir, "The directory audit files are written to (required)")
+
+	cmd.MarkFlagRequired("dir")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		run(opts, func() (Sink, error) {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return nil, fmt.Errorf("failed to create audit directory: %w", err)
+			}
+			return &FileSink{Dir: dir, Now: time.Now}, nil
+		})
+	}
+
+	return cmd
+}
+
+// FileSink appends audit events to a file per day, named after the UTC date
+// of the day the events were forwarded.
+type FileSink struct {
+	Dir string
+	Now func() time.Time
+}
+
+func (s *FileSink) Name() string {
+	return "file"
+}
+
+func (s *FileSink) Write(ctx context.Context, events [][]byte) error {
+	name := filepath.Join(s.Dir, fmt.Sprintf("audit-%s.log", s.Now().UTC().Format("2006-01-02")))
+	file, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(ndjson(events)); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func NewSyslogCommand(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "syslog",
+		Short:        "Sends audit events to a syslog server",
+		SilenceUsage: true,
+	}
+
+	sink := &SyslogSink{
+		Protocol: "tcp",
+		Tag:      "kube-apiserver-audit",
+	}
+	cmd.Flags().StringVar(&sink.Address, "address", sink.Address, "The host:port of the syslog server (required)")
+	cmd.Flags().StringVar(&sink.Protocol, "protocol", sink.Protocol, "The protocol used to reach the syslog server, tcp or udp")
+	cmd.Flags().StringVar(&sink.Tag, "tag", sink.Tag, "The tag of the syslog messages")
+	var tlsOpts TLSOptions
+	tlsOpts.AddFlags(cmd, "The syslog server is reached over TLS when it is set")
+
+	cmd.MarkFlagRequired("address")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		run(opts, func() (Sink, error) {
+			sink.Protocol = strings.ToLower(sink.Protocol)
+			if sink.Protocol != "tcp" && sink.Protocol != "udp" {
+				return nil, fmt.Errorf("unsupported syslog protocol %s", sink.Protocol)
+			}
+			if tlsOpts.CAFile != "" {
+				if sink.Protocol != "tcp" {
+					return nil, fmt.Errorf("syslog over TLS requires tcp")
+				}
+				config, err := tlsOpts.Config()
+				if err != nil {
+					return nil, err
+				}
+				sink.TLSConfig = config
+			}
+			return sink, nil
+		})
+	}
+
+	return cmd
+}
+
+// SyslogSink sends every audit event as a syslog message. It connects lazily
+// and reconnects after a failed write. When TLSConfig is set, messages are
+// sent over TLS as described by RFC 5425.
+type SyslogSink struct {
+	Address   string
+	Protocol  string
+	Tag       string
+	TLSConfig *tls.Config
+
+	writer syslogWriter
+}
+
+// syslogWriter is the part of syslog.Writer used by the sink.
+type syslogWriter interface {
+	Info(m string) error
+	Close() error
+}
+
+func (s *SyslogSink) Name() string {
+	return "syslog"
+}
+
+func (s *SyslogSink) Write(ctx context.Context, events [][]byte) error {
+	if s.writer == nil {
+		writer, err := s.dial()
+		if err != nil {
+			return err
+		}
+		s.writer = writer
+	}
+	for _, event := range events {
+		if err := s.writer.Info(string(event)); err != nil {
+			if errors.Is(err, syscall.EMSGSIZE) {
+				// The event doesn't fit in a datagram, the connection is
+				// still usable
+				return &PermanentError{Err: fmt.Errorf("audit event of %d bytes is too large for a syslog message: %w", len(event), err)}
+			}
+			s.writer.Close()
+			s.writer = nil
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SyslogSink) dial() (syslogWriter, error) {
+	if s.TLSConfig == nil {
+		return syslog.Dial(s.Protocol, s.Address, syslog.LOG_INFO|syslog.LOG_AUTHPRIV, s.Tag)
+	}
+	conn, err := tls.Dial("tcp", s.Address, s.TLSConfig)
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	return &tlsSyslogWriter{conn: conn, hostname: hostname, tag: s.Tag}, nil
+}
+
+// tlsSyslogWriter writes RFC 5424 messages framed by their length, the
+// transport mapping of syslog over TLS.
+type tlsSyslogWriter struct {
+	conn     *tls.Conn
+	hostname string
+	tag      string
+}
+
+func (w *tlsSyslogWriter) Info(m string) error {
+	msg := fmt.Sprintf("<%d>1 %s %s %s %d - - %s", syslog.LOG_INFO|syslog.LOG_AUTHPRIV,
+		time.Now().UTC().Format("2006-01-02T15:04:05.000000Z07:00"), nilValue(w.hostname), nilValue(w.tag), os.Getpid(), m)
+	_, err := fmt.Fprintf(w.conn, "%d %s", len(msg), msg)
+	return err
+}
+
+func (w *tlsSyslogWriter) Close() error {
+	return w.conn.Close()
+}
+
+func nilValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func NewHTTPCommand(opts *Options) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "http",
+		Short:        "Posts audit events to an HTTP endpoint",
+		SilenceUsage: true,
+	}
+
+	sink := &HTTPSink{}
+	cmd.Flags().StringVar(&sink.URL, "url", sink.URL, "The URL audit events are posted to (required)")
+	var tlsOpts TLSOptions
+	tlsOpts.AddFlags(cmd, "The system roots are used if unset")
+
+	cmd.MarkFlagRequired("url")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		run(opts, func() (Sink, error) {
+			config, err := tlsOpts.Config()
+			if err != nil {
+				return nil, err
+			}
+			transport := http.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = config
+			sink.Client = &http.Client{Timeout: 30 * time.Second, Transport: transport}
+			if tlsOpts.CredentialsDir != "" {
+				sink.TokenFile = filepath.Join(tlsOpts.CredentialsDir, hyperv1.AuditLogTokenSecretKey)
+			}
+			return sink, nil
+		})
+	}
+
+	return cmd
+}
+
+// HTTPSink posts batches of audit events as newline delimited JSON. The
+// token in TokenFile, if it exists, is sent as a bearer token. It is read
+// for every batch so a rotated token is picked up.
+type HTTPSink struct {
+	URL       string
+	Client    *http.Client
+	TokenFile string
+}
+
+func (s *HTTPSink) Name() string {
+	return "http"
+}
+
+func (s *HTTPSink) Write(ctx context.Context, events [][]byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(ndjson(events)))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if s.TokenFile != "" {
+		token, err := ioutil.ReadFile(s.TokenFile)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read token: %w", err)
+		}
+		if len(bytes.TrimSpace(token)) > 0 {
+			req.Header.Set("Authorization", "Bearer "+string(bytes.TrimSpace(token)))
+		}
+	}
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		err := fmt.Errorf("unexpected status %s: %s", resp.Status, string(body))
+		if isPermanentStatus(resp.StatusCode) {
+			return &PermanentError{Err: err}
+		}
+		return err
+	}
+	return nil
+}
+
+// isPermanentStatus returns true if the server rejected the request itself,
+// so that sending it again can't succeed. Authentication and authorization
+// failures are retried since the token or the permissions of the sink may be
+// fixed, as are timeouts and rate limiting.
+func isPermanentStatus(code int) bool {
+	switch code {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return false
+	}
+	return code >= 400 && code < 500
+}
+
+// TLSOptions configure how the sinks reached over the network are verified
+// and authenticated to.
+type TLSOptions struct {
+	CAFile         string
+	CredentialsDir string
+}
+
+func (o *TLSOptions) AddFlags(cmd *cobra.Command, caUsage string) {
+	cmd.Flags().StringVar(&o.CAFile, "ca-file", o.CAFile, "Path to the PEM encoded CA bundle the sink is verified with. "+caUsage)
+	cmd.Flags().StringVar(&o.CredentialsDir, "credentials-dir", o.CredentialsDir, "Path to a directory with the credentials presented to the sink: a client certificate in tls.crt and tls.key, and for HTTP a bearer token in token")
+}
+
+// Config returns the TLS configuration of the sink. The client certificate is
+// loaded for every connection so a rotated certificate is picked up.
+func (o *TLSOptions) Config() (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if o.CAFile != "" {
+		ca, err := ioutil.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", o.CAFile)
+		}
+	}
+	if o.CredentialsDir != "" {
+		certFile := filepath.Join(o.CredentialsDir, corev1.TLSCertKey)
+		keyFile := filepath.Join(o.CredentialsDir, corev1.TLSPrivateKeyKey)
+		if _, err := os.Stat(certFile); err == nil {
+			config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+				cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load client certificate: %w", err)
+				}
+				return &cert, nil
+			}
+		}
+	}
+	return config, nil
+}
+
+func ndjson(events [][]byte) []byte {
+	var buf bytes.Buffer
+	for _, event := range events {
+		buf.Write(event)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
@@ -36,6 +36,7 @@ type Options struct {
 	IAMJSON            string
 	InstanceType       string
 	Region             string
+	Zones              []string
 	BaseDomain         string
 	IssuerURL          string
 	PublicZoneID       string
@@ -100,6 +101,7 @@ func NewCreateCommand() *cobra.Command {
 	cmd.Flags().StringVar(&opts.Region, "region", opts.Region, "Region to use for AWS infrastructure.")
 	cmd.Flags().StringVar(&opts.InfraID, "infra-id", opts.InfraID, "Infrastructure ID to use for AWS resources.")
 	cmd.Flags().StringVar(&opts.InstanceType, "instance-type", opts.InstanceType, "Instance type for AWS instances.")
+	cmd.Flags().StringSliceVar(&opts.Zones, "zones", opts.Zones, "The availability zones in which NodePools will be created. The default NodePool is spread across all of them.")
 	cmd.Flags().StringVar(&opts.BaseDomain, "base-domain", opts.BaseDomain, "The ingress base domain for the cluster")
 	cmd.Flags().StringArrayVar(&opts.Annotations, "annotations", opts.Annotations, "Annotations to apply to the hostedcluster (key=value). Can be specified multiple times.")
 	cmd.Flags().StringVar(&opts.NetworkType, "network-type", opts.NetworkType, "Enum specifying the cluster SDN provider. Supports either Calico or OpenshiftSDN.")
@@ -186,6 +188,7 @@ func CreateCluster(ctx context.Context, opts Options) error {
 			AWSCredentialsFile: opts.AWSCredentialsFile,
 			Name:               opts.Name,
 			BaseDomain:         opts.BaseDomain,
+			Zones:              opts.Zones,
 		}
 		infra, err = opt.CreateInfra(ctx)
 		if err != nil {
@@ -238,6 +241,7 @@ func CreateCluster(ctx context.Context, opts Options) error {
 			Zone:                                   infra.Zone,
 			VPCID:                                  infra.VPCID,
 			SubnetID:                               infra.PrivateSubnetID,
+			Zones:                                  exampleAWSZones(infra.Zones),
 			SecurityGroupID:                        infra.SecurityGroupID,
 			InstanceProfile:                        iamInfo.ProfileName,
 			InstanceType:                           opts.InstanceType,
@@ -272,3 +276,14 @@ func CreateCluster(ctx context.Context, opts Options) error {
 
 	return nil
 }
+
+func exampleAWSZones(zones []awsinfra.CreateInfraOutputZone) []apifixtures.ExampleAWSOptionsZones {
+	var result []apifixtures.ExampleAWSOptionsZones
+	for _, zone := range zones {
+		result = append(result, apifixtures.ExampleAWSOptionsZones{
+			Name:     zone.Name,
+			SubnetID: zone.SubnetID,
+		})
+	}
+	return result
+}
@@ -0,0 +1,74 @@
+package etcd
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/bombsimon/logrusr"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+var log = logrusr.NewLogger(logrus.New())
+
+// NewCommand returns the commands used by managed etcd clusters to move
+// snapshots in and out of object storage. They are run from the control plane
+// operator image and are not meant to be used directly.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "etcd",
+		Short:        "Commands for managing etcd snapshots",
+		Hidden:       true,
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(NewUploadSnapshotCommand())
+	cmd.AddCommand(NewFetchSnapshotCommand())
+
+	return cmd
+}
+
+// S3Options identifies an S3 compatible bucket and how to authenticate to it.
+type S3Options struct {
+	Bucket          string
+	Endpoint        string
+	ForcePathStyle  bool
+	Region          string
+	CredentialsFile string
+	ConfigFile      string
+}
+
+func (o *S3Options) BindFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&o.Bucket, "bucket", o.Bucket, "The bucket storing the snapshots (required)")
+	cmd.Flags().StringVar(&o.Endpoint, "endpoint", o.Endpoint, "The URL of an S3 compatible object store, AWS S3 is used if unset")
+	cmd.Flags().BoolVar(&o.ForcePathStyle, "force-path-style", o.ForcePathStyle, "Use path style bucket addressing")
+	cmd.Flags().StringVar(&o.Region, "region", o.Region, "The region of the bucket, read from the config file if unset")
+	cmd.Flags().StringVar(&o.CredentialsFile, "credentials-file", o.CredentialsFile, "Path to an AWS credentials file (required)")
+	cmd.Flags().StringVar(&o.ConfigFile, "config-file", o.ConfigFile, "Path to an AWS config file")
+
+	cmd.MarkFlagRequired("bucket")
+	cmd.MarkFlagRequired("credentials-file")
+}
+
+func (o *S3Options) Client() (*s3.S3, error) {
+	files := []string{o.CredentialsFile}
+	if len(o.ConfigFile) > 0 {
+		files = append(files, o.ConfigFile)
+	}
+	awsConfig := aws.NewConfig().WithS3ForcePathStyle(o.ForcePathStyle)
+	if len(o.Region) > 0 {
+		awsConfig = awsConfig.WithRegion(o.Region)
+	}
+	if len(o.Endpoint) > 0 {
+		awsConfig = awsConfig.WithEndpoint(o.Endpoint)
+	}
+	awsSession, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+		SharedConfigFiles: files,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s3.New(awsSession), nil
+}
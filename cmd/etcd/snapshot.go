@@ -0,0 +1,182 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/spf13/cobra"
+)
+
+// snapshotNamePrefix is the name every uploaded snapshot starts with. Snapshot
+// names end with a UTC timestamp, so that sorting them sorts them by age.
+const snapshotNamePrefix = "etcd-snapshot-"
+
+type UploadSnapshotOptions struct {
+	S3Options
+	File       string
+	Prefix     string
+	MaxBackups int
+}
+
+func NewUploadSnapshotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "upload-snapshot",
+		Short:        "Uploads an etcd snapshot and removes old snapshots",
+		SilenceUsage: true,
+	}
+
+	opts := UploadSnapshotOptions{}
+	opts.BindFlags(cmd)
+	cmd.Flags().StringVar(&opts.File, "file", opts.File, "Path to the snapshot to upload (required)")
+	cmd.Flags().StringVar(&opts.Prefix, "prefix", opts.Prefix, "Prefix of the snapshot names in the bucket")
+	cmd.Flags().IntVar(&opts.MaxBackups, "max-backups", opts.MaxBackups, "Number of snapshots to retain, zero retains all snapshots")
+
+	cmd.MarkFlagRequired("file")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		if err := opts.Run(context.Background()); err != nil {
+			log.Error(err, "Failed to upload snapshot")
+			os.Exit(1)
+		}
+	}
+
+	return cmd
+}
+
+func (o *UploadSnapshotOptions) Run(ctx context.Context) error {
+	client, err := o.Client()
+	if err != nil {
+		return fmt.Errorf("failed to create S3 client: %w", err)
+	}
+	f, err := os.Open(o.File)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	key := path.Join(o.Prefix, snapshotNamePrefix+time.Now().UTC().Format("20060102150405")+".db")
+	if _, err := client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(o.Bucket),
+		Key:    aws.String(key),
+		Body:   f,
+	}); err != nil {
+		return fmt.Errorf("failed to upload snapshot %s: %w", key, err)
+	}
+	log.Info("Uploaded snapshot", "bucket", o.Bucket, "key", key)
+
+	if o.MaxBackups > 0 {
+		return pruneSnapshots(ctx, client, o.Bucket, o.Prefix, o.MaxBackups)
+	}
+	return nil
+}
+
+// pruneSnapshots removes all but the newest maxBackups snapshots stored under
+// prefix.
+func pruneSnapshots(ctx context.Context, client s3iface.S3API, bucket, prefix string, maxBackups int) error {
+	keyPrefix := path.Join(prefix, snapshotNamePrefix)
+	var keys []string
+	if err := client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(keyPrefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			key := aws.StringValue(object.Key)
+			// Skip snapshots in nested prefixes
+			if !strings.Contains(strings.TrimPrefix(key, keyPrefix), "/") {
+				keys = append(keys, key)
+			}
+		}
+		return true
+	}); err != nil {
+		return fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if len(keys) <= maxBackups {
+		return nil
+	}
+	sort.Strings(keys)
+	for _, key := range keys[:len(keys)-maxBackups] {
+		if _, err := client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		}); err != nil {
+			return fmt.Errorf("failed to delete snapshot %s: %w", key, err)
+		}
+		log.Info("Deleted snapshot", "bucket", bucket, "key", key)
+	}
+	return nil
+}
+
+type FetchSnapshotOptions struct {
+	S3Options
+	Key    string
+	Output string
+}
+
+func NewFetchSnapshotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "fetch-snapshot",
+		Short:        "Downloads an etcd snapshot",
+		SilenceUsage: true,
+	}
+
+	opts := FetchSnapshotOptions{}
+	opts.BindFlags(cmd)
+	cmd.Flags().StringVar(&opts.Key, "key", opts.Key, "Name of the snapshot in the bucket (required)")
+	cmd.Flags().StringVar(&opts.Output, "output", opts.Output, "Path the snapshot is written to (required)")
+
+	cmd.MarkFlagRequired("key")
+	cmd.MarkFlagRequired("output")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		if err := opts.Run(context.Background()); err != nil {
+			log.Error(err, "Failed to fetch snapshot")
+			os.Exit(1)
+		}
+	}
+
+	return cmd
+}
+
+func (o *FetchSnapshotOptions) Run(ctx context.Context) error {
+	client, err := o.Client()
+	if err != nil {
+		return fmt.Errorf("failed to create S3 client: %w", err)
+	}
+	object, err := client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(o.Bucket),
+		Key:    aws.String(o.Key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get snapshot %s: %w", o.Key, err)
+	}
+	defer object.Body.Close()
+
+	// Write to a temporary file first so that a partial download is never
+	// mistaken for a snapshot
+	tmp := o.Output + ".part"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, object.Body); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to download snapshot %s: %w", o.Key, err)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, o.Output); err != nil {
+		return err
+	}
+	log.Info("Fetched snapshot", "bucket", o.Bucket, "key", o.Key, "output", o.Output)
+	return nil
+}
@@ -23,6 +23,7 @@ type CreateInfraOptions struct {
 	BaseDomain         string
 	OutputFile         string
 	AdditionalTags     []string
+	Zones              []string
 
 	additionalEC2Tags []*ec2.Tag
 }
@@ -40,6 +41,14 @@ type CreateInfraOutput struct {
 	BaseDomain      string `json:"baseDomain"`
 	PublicZoneID    string `json:"publicZoneID"`
 	PrivateZoneID   string `json:"privateZoneID"`
+	// Zones are all the zones with their private subnet. Zone and
+	// PrivateSubnetID are the ones of the first zone.
+	Zones []CreateInfraOutputZone `json:"zones"`
+}
+
+type CreateInfraOutputZone struct {
+	Name     string `json:"name"`
+	SubnetID string `json:"subnetID"`
 }
 
 const (
@@ -47,6 +56,10 @@ const (
 	PrivateSubnetCIDR = "10.0.128.0/20"
 	PublicSubnetCIDR  = "10.0.0.0/20"
 
+	// maxZones is the number of zones which fit their public and private
+	// subnets in the VPC.
+	maxZones = 8
+
 	clusterTagValue = "owned"
 )
 
@@ -69,6 +82,7 @@ func NewCreateCommand() *cobra.Command {
 	cmd.Flags().StringSliceVar(&opts.AdditionalTags, "additional-tags", opts.AdditionalTags, "Additional tags to set on AWS resources")
 	cmd.Flags().StringVar(&opts.Name, "name", opts.Name, "A name for the cluster")
 	cmd.Flags().StringVar(&opts.BaseDomain, "base-domain", opts.BaseDomain, "The ingress base domain for the cluster")
+	cmd.Flags().StringSliceVar(&opts.Zones, "zones", opts.Zones, "The availability zones in which to create subnets (defaults to the first zone of the region)")
 
 	cmd.MarkFlagRequired("infra-id")
 	cmd.MarkFlagRequired("aws-creds")
@@ -137,9 +151,16 @@ func (o *CreateInfraOptions) CreateInfra(ctx context.Context) (*CreateInfraOutpu
 		Name:        o.Name,
 		BaseDomain:  o.BaseDomain,
 	}
-	result.Zone, err = o.firstZone(ec2Client)
-	if err != nil {
-		return nil, err
+	zones := o.Zones
+	if len(zones) == 0 {
+		zone, err := o.firstZone(ec2Client)
+		if err != nil {
+			return nil, err
+		}
+		zones = []string{zone}
+	}
+	if len(zones) > maxZones {
+		return nil, fmt.Errorf("at most %d zones are supported", maxZones)
 	}
 	result.VPCID, err = o.createVPC(ec2Client)
 	if err != nil {
@@ -148,35 +169,49 @@ func (o *CreateInfraOptions) CreateInfra(ctx context.Context) (*CreateInfraOutpu
 	if err = o.CreateDHCPOptions(ec2Client, result.VPCID); err != nil {
 		return nil, err
 	}
-	result.PrivateSubnetID, err = o.CreatePrivateSubnet(ec2Client, result.VPCID, result.Zone)
-	if err != nil {
-		return nil, err
-	}
-	result.PublicSubnetID, err = o.CreatePublicSubnet(ec2Client, result.VPCID, result.Zone)
-	if err != nil {
-		return nil, err
-	}
 	igwID, err := o.CreateInternetGateway(ec2Client, result.VPCID)
 	if err != nil {
 		return nil, err
 	}
-	natGatewayID, err := o.CreateNATGateway(ec2Client, result.PublicSubnetID, result.Zone)
-	if err != nil {
-		return nil, err
-	}
 	result.SecurityGroupID, err = o.CreateWorkerSecurityGroup(ec2Client, result.VPCID)
 	if err != nil {
 		return nil, err
 	}
-	privateRouteTable, err := o.CreatePrivateRouteTable(ec2Client, result.VPCID, natGatewayID, result.PrivateSubnetID, result.Zone)
-	if err != nil {
-		return nil, err
-	}
-	publicRouteTable, err := o.CreatePublicRouteTable(ec2Client, result.VPCID, igwID, result.PublicSubnetID, result.Zone)
-	if err != nil {
-		return nil, err
+	// Every zone has its own private route table through the NAT gateway
+	// of the zone, the public subnets share the route table of the first zone.
+	var routeTableIDs []string
+	var publicRouteTable string
+	for i, zone := range zones {
+		privateSubnetID, err := o.CreatePrivateSubnet(ec2Client, result.VPCID, zone, i)
+		if err != nil {
+			return nil, err
+		}
+		publicSubnetID, err := o.CreatePublicSubnet(ec2Client, result.VPCID, zone, i)
+		if err != nil {
+			return nil, err
+		}
+		natGatewayID, err := o.CreateNATGateway(ec2Client, publicSubnetID, zone)
+		if err != nil {
+			return nil, err
+		}
+		privateRouteTable, err := o.CreatePrivateRouteTable(ec2Client, result.VPCID, natGatewayID, privateSubnetID, zone)
+		if err != nil {
+			return nil, err
+		}
+		routeTableIDs = append(routeTableIDs, privateRouteTable)
+		publicRouteTable, err = o.CreatePublicRouteTable(ec2Client, result.VPCID, igwID, publicSubnetID, zones[0])
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			result.Zone = zone
+			result.PrivateSubnetID = privateSubnetID
+			result.PublicSubnetID = publicSubnetID
+		}
+		result.Zones = append(result.Zones, CreateInfraOutputZone{Name: zone, SubnetID: privateSubnetID})
 	}
-	err = o.CreateVPCS3Endpoint(ec2Client, result.VPCID, privateRouteTable, publicRouteTable)
+	routeTableIDs = append(routeTableIDs, publicRouteTable)
+	err = o.CreateVPCS3Endpoint(ec2Client, result.VPCID, routeTableIDs)
 	if err != nil {
 		return nil, err
 	}
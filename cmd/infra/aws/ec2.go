@@ -1,7 +1,9 @@
 package aws
 
 import (
+	"encoding/binary"
 	"fmt"
+	"net"
 	"strings"
 	"time"
 
@@ -83,7 +85,7 @@ func (o *CreateInfraOptions) existingVPC(client ec2iface.EC2API, vpcName string)
 	return vpcID, nil
 }
 
-func (o *CreateInfraOptions) CreateVPCS3Endpoint(client ec2iface.EC2API, vpcID, privateRouteTableId, publicRouteTableId string) error {
+func (o *CreateInfraOptions) CreateVPCS3Endpoint(client ec2iface.EC2API, vpcID string, routeTableIds []string) error {
 	existingEndpoint, err := o.existingVPCS3Endpoint(client)
 	if err != nil {
 		return err
@@ -93,12 +95,9 @@ func (o *CreateInfraOptions) CreateVPCS3Endpoint(client ec2iface.EC2API, vpcID,
 		return nil
 	}
 	result, err := client.CreateVpcEndpoint(&ec2.CreateVpcEndpointInput{
-		VpcId:       aws.String(vpcID),
-		ServiceName: aws.String(fmt.Sprintf("com.amazonaws.%s.s3", o.Region)),
-		RouteTableIds: []*string{
-			aws.String(privateRouteTableId),
-			aws.String(publicRouteTableId),
-		},
+		VpcId:             aws.String(vpcID),
+		ServiceName:       aws.String(fmt.Sprintf("com.amazonaws.%s.s3", o.Region)),
+		RouteTableIds:     aws.StringSlice(routeTableIds),
 		TagSpecifications: o.ec2TagSpecifications("vpc-endpoint", ""),
 	})
 	if err != nil {
@@ -175,12 +174,37 @@ func (o *CreateInfraOptions) existingDHCPOptions(client ec2iface.EC2API) (string
 	return optID, nil
 }
 
-func (o *CreateInfraOptions) CreatePrivateSubnet(client ec2iface.EC2API, vpcID string, zone string) (string, error) {
-	return o.CreateSubnet(client, vpcID, zone, PrivateSubnetCIDR, fmt.Sprintf("%s-private-%s", o.InfraID, zone))
+// CreatePrivateSubnet creates the private subnet of the zone with the given
+// index. The subnets of the zones take consecutive /20 blocks.
+func (o *CreateInfraOptions) CreatePrivateSubnet(client ec2iface.EC2API, vpcID string, zone string, index int) (string, error) {
+	cidr, err := subnetCIDR(PrivateSubnetCIDR, index)
+	if err != nil {
+		return "", err
+	}
+	return o.CreateSubnet(client, vpcID, zone, cidr, fmt.Sprintf("%s-private-%s", o.InfraID, zone))
+}
+
+// CreatePublicSubnet creates the public subnet of the zone with the given
+// index. The subnets of the zones take consecutive /20 blocks.
+func (o *CreateInfraOptions) CreatePublicSubnet(client ec2iface.EC2API, vpcID string, zone string, index int) (string, error) {
+	cidr, err := subnetCIDR(PublicSubnetCIDR, index)
+	if err != nil {
+		return "", err
+	}
+	return o.CreateSubnet(client, vpcID, zone, cidr, fmt.Sprintf("%s-public-%s", o.InfraID, zone))
 }
 
-func (o *CreateInfraOptions) CreatePublicSubnet(client ec2iface.EC2API, vpcID string, zone string) (string, error) {
-	return o.CreateSubnet(client, vpcID, zone, PublicSubnetCIDR, fmt.Sprintf("%s-public-%s", o.InfraID, zone))
+// subnetCIDR returns the index-th block of the size of the base block,
+// starting at the base block.
+func subnetCIDR(base string, index int) (string, error) {
+	_, ipNet, err := net.ParseCIDR(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid subnet CIDR %s: %w", base, err)
+	}
+	ones, bits := ipNet.Mask.Size()
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, binary.BigEndian.Uint32(ipNet.IP.To4())+uint32(index)<<(bits-ones))
+	return fmt.Sprintf("%s/%d", ip, ones), nil
 }
 
 func (o *CreateInfraOptions) CreateSubnet(client ec2iface.EC2API, vpcID, zone, cidr, name string) (string, error) {
@@ -269,7 +293,8 @@ func (o *CreateInfraOptions) existingInternetGateway(client ec2iface.EC2API, nam
 }
 
 func (o *CreateInfraOptions) CreateNATGateway(client ec2iface.EC2API, publicSubnetID, availabilityZone string) (string, error) {
-	allocationID, err := o.existingEIP(client)
+	eipName := fmt.Sprintf("%s-eip-%s", o.InfraID, availabilityZone)
+	allocationID, err := o.existingEIP(client, eipName)
 	if err != nil {
 		return "", err
 	}
@@ -285,7 +310,7 @@ func (o *CreateInfraOptions) CreateNATGateway(client ec2iface.EC2API, publicSubn
 		// recognizing the EIP as belonging to the cluster
 		_, err = client.CreateTags(&ec2.CreateTagsInput{
 			Resources: []*string{aws.String(allocationID)},
-			Tags:      append(ec2Tags(o.InfraID, fmt.Sprintf("%s-eip-%s", o.InfraID, availabilityZone)), o.additionalEC2Tags...),
+			Tags:      append(ec2Tags(o.InfraID, eipName), o.additionalEC2Tags...),
 		})
 		if err != nil {
 			return "", fmt.Errorf("cannot tag NAT gateway EIP: %w", err)
@@ -314,9 +339,9 @@ func (o *CreateInfraOptions) CreateNATGateway(client ec2iface.EC2API, publicSubn
 	return natGatewayID, nil
 }
 
-func (o *CreateInfraOptions) existingEIP(client ec2iface.EC2API) (string, error) {
+func (o *CreateInfraOptions) existingEIP(client ec2iface.EC2API, name string) (string, error) {
 	var assocID string
-	result, err := client.DescribeAddresses(&ec2.DescribeAddressesInput{Filters: o.ec2Filters("")})
+	result, err := client.DescribeAddresses(&ec2.DescribeAddressesInput{Filters: o.ec2Filters(name)})
 	if err != nil {
 		return "", fmt.Errorf("cannot list EIPs: %w", err)
 	}
@@ -1,6 +1,9 @@
 package assets
 
 import (
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/hypershift-operator/webhooks"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -13,6 +16,13 @@ import (
 	k8sutilspointer "k8s.io/utils/pointer"
 )
 
+const (
+	// operatorServingCertSecretName is the secret the service CA operator
+	// populates with the serving certificate for the operator service.
+	operatorServingCertSecretName = "operator-serving-cert"
+	webhookCertDir                = "/var/run/secrets/serving-cert"
+)
+
 type HyperShiftNamespace struct {
 	Name string
 }
@@ -82,13 +92,34 @@ func (o HyperShiftOperatorDeployment) Build() *appsv1.Deployment {
 								},
 							},
 							Command: []string{"/usr/bin/hypershift-operator"},
-							Args:    []string{"run", "--namespace=$(MY_NAMESPACE)", "--deployment-name=operator", "--metrics-addr=:9000"},
+							Args:    []string{"run", "--namespace=$(MY_NAMESPACE)", "--deployment-name=operator", "--metrics-addr=:9000", "--webhook-cert-dir=" + webhookCertDir},
 							Ports: []corev1.ContainerPort{
 								{
 									Name:          "metrics",
 									ContainerPort: 9000,
 									Protocol:      corev1.ProtocolTCP,
 								},
+								{
+									Name:          "webhook",
+									ContainerPort: 9443,
+									Protocol:      corev1.ProtocolTCP,
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "serving-cert",
+									MountPath: webhookCertDir,
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "serving-cert",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: operatorServingCertSecretName,
+								},
 							},
 						},
 					},
@@ -115,6 +146,9 @@ func (o HyperShiftOperatorService) Build() *corev1.Service {
 			Labels: map[string]string{
 				"name": "operator",
 			},
+			Annotations: map[string]string{
+				"service.beta.openshift.io/serving-cert-secret-name": operatorServingCertSecretName,
+			},
 		},
 		Spec: corev1.ServiceSpec{
 			Type: corev1.ServiceTypeClusterIP,
@@ -128,6 +162,12 @@ func (o HyperShiftOperatorService) Build() *corev1.Service {
 					Port:       9393,
 					TargetPort: intstr.FromString("metrics"),
 				},
+				{
+					Name:       "webhook",
+					Protocol:   corev1.ProtocolTCP,
+					Port:       443,
+					TargetPort: intstr.FromString("webhook"),
+				},
 			},
 		},
 	}
@@ -431,6 +471,12 @@ func (o HyperShiftMachineConfigServersCustomResourceDefinition) Build() *apiexte
 	return crd
 }
 
+type HyperShiftClusterSizingConfigurationsCustomResourceDefinition struct{}
+
+func (o HyperShiftClusterSizingConfigurationsCustomResourceDefinition) Build() *apiextensionsv1.CustomResourceDefinition {
+	return getCustomResourceDefinition("hypershift-operator/hypershift.openshift.io_clustersizingconfigurations.yaml")
+}
+
 type HyperShiftPrometheusRole struct {
 	Namespace *corev1.Namespace
 }
@@ -527,3 +573,117 @@ func (o HyperShiftServiceMonitor) Build() *unstructured.Unstructured {
 	sm.SetNamespace(o.Namespace.Name)
 	return sm
 }
+
+type HyperShiftMutatingWebhookConfiguration struct {
+	Namespace *corev1.Namespace
+}
+
+func (o HyperShiftMutatingWebhookConfiguration) Build() *admissionregistrationv1.MutatingWebhookConfiguration {
+	return &admissionregistrationv1.MutatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "MutatingWebhookConfiguration",
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "hypershift.openshift.io",
+			Annotations: map[string]string{
+				"service.beta.openshift.io/inject-cabundle": "true",
+			},
+		},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{
+			{
+				Name:                    "hostedclusters.hypershift.openshift.io",
+				ClientConfig:            webhookClientConfig(o.Namespace, webhooks.HostedClusterMutatingPath),
+				Rules:                   webhookRules("hostedclusters"),
+				FailurePolicy:           &webhookFailurePolicy,
+				SideEffects:             &webhookSideEffects,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+			},
+			{
+				Name:                    "nodepools.hypershift.openshift.io",
+				ClientConfig:            webhookClientConfig(o.Namespace, webhooks.NodePoolMutatingPath),
+				Rules:                   webhookRules("nodepools"),
+				FailurePolicy:           &webhookFailurePolicy,
+				SideEffects:             &webhookSideEffects,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+			},
+		},
+	}
+}
+
+type HyperShiftValidatingWebhookConfiguration struct {
+	Namespace *corev1.Namespace
+}
+
+func (o HyperShiftValidatingWebhookConfiguration) Build() *admissionregistrationv1.ValidatingWebhookConfiguration {
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ValidatingWebhookConfiguration",
+			APIVersion: admissionregistrationv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "hypershift.openshift.io",
+			Annotations: map[string]string{
+				"service.beta.openshift.io/inject-cabundle": "true",
+			},
+		},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{
+				Name:                    "hostedclusters.hypershift.openshift.io",
+				ClientConfig:            webhookClientConfig(o.Namespace, webhooks.HostedClusterValidatingPath),
+				Rules:                   webhookRules("hostedclusters"),
+				FailurePolicy:           &webhookFailurePolicy,
+				SideEffects:             &webhookSideEffects,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+			},
+			{
+				Name:                    "nodepools.hypershift.openshift.io",
+				ClientConfig:            webhookClientConfig(o.Namespace, webhooks.NodePoolValidatingPath),
+				Rules:                   webhookRules("nodepools"),
+				FailurePolicy:           &webhookFailurePolicy,
+				SideEffects:             &webhookSideEffects,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+			},
+			{
+				Name:                    "clustersizingconfigurations.hypershift.openshift.io",
+				ClientConfig:            webhookClientConfig(o.Namespace, webhooks.ClusterSizingConfigurationValidatingPath),
+				Rules:                   webhookRules("clustersizingconfigurations"),
+				FailurePolicy:           &webhookFailurePolicy,
+				SideEffects:             &webhookSideEffects,
+				AdmissionReviewVersions: []string{"v1", "v1beta1"},
+			},
+		},
+	}
+}
+
+var (
+	webhookFailurePolicy = admissionregistrationv1.Fail
+	webhookSideEffects   = admissionregistrationv1.SideEffectClassNone
+)
+
+func webhookClientConfig(namespace *corev1.Namespace, path string) admissionregistrationv1.WebhookClientConfig {
+	return admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: namespace.Name,
+			Name:      "operator",
+			Path:      k8sutilspointer.StringPtr(path),
+			Port:      k8sutilspointer.Int32Ptr(443),
+		},
+	}
+}
+
+func webhookRules(resource string) []admissionregistrationv1.RuleWithOperations {
+	return []admissionregistrationv1.RuleWithOperations{
+		{
+			Operations: []admissionregistrationv1.OperationType{
+				admissionregistrationv1.Create,
+				admissionregistrationv1.Update,
+			},
+			Rule: admissionregistrationv1.Rule{
+				APIGroups:   []string{hyperv1.GroupVersion.Group},
+				APIVersions: []string{hyperv1.GroupVersion.Version},
+				Resources:   []string{resource},
+			},
+		},
+	}
+}
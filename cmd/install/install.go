@@ -142,6 +142,7 @@ func hyperShiftOperatorManifests(opts Options) []crclient.Object {
 	hostedControlPlanesCRD := assets.HyperShiftHostedControlPlaneCustomResourceDefinition{}.Build()
 	externalInfraClustersCRD := assets.HyperShiftExternalInfraClustersCustomResourceDefinition{}.Build()
 	machineConfigServersCRD := assets.HyperShiftMachineConfigServersCustomResourceDefinition{}.Build()
+	clusterSizingConfigurationsCRD := assets.HyperShiftClusterSizingConfigurationsCustomResourceDefinition{}.Build()
 	controlPlanePriorityClass := assets.HyperShiftControlPlanePriorityClass{}.Build()
 	etcdPriorityClass := assets.HyperShiftEtcdPriorityClass{}.Build()
 	apiCriticalPriorityClass := assets.HyperShiftAPICriticalPriorityClass{}.Build()
@@ -183,12 +184,13 @@ func hyperShiftOperatorManifests(opts Options) []crclient.Object {
 		Namespace: operatorNamespace,
 	}.Build()
 
-	return []crclient.Object{
+	objects := []crclient.Object{
 		hostedClustersCRD,
 		nodePoolsCRD,
 		hostedControlPlanesCRD,
 		externalInfraClustersCRD,
 		machineConfigServersCRD,
+		clusterSizingConfigurationsCRD,
 		controlPlanePriorityClass,
 		apiCriticalPriorityClass,
 		etcdPriorityClass,
@@ -204,6 +206,21 @@ func hyperShiftOperatorManifests(opts Options) []crclient.Object {
 		prometheusRoleBinding,
 		serviceMonitor,
 	}
+
+	// In development mode the operator runs outside the cluster and can't serve
+	// the admission webhooks.
+	if !opts.Development {
+		objects = append(objects,
+			assets.HyperShiftMutatingWebhookConfiguration{
+				Namespace: operatorNamespace,
+			}.Build(),
+			assets.HyperShiftValidatingWebhookConfiguration{
+				Namespace: operatorNamespace,
+			}.Build(),
+		)
+	}
+
+	return objects
 }
 
 func clusterAPIManifests() []crclient.Object {
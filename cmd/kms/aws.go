@@ -0,0 +1,88 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/spf13/cobra"
+)
+
+type AWSOptions struct {
+	Listen          string
+	KeyARN          string
+	Region          string
+	CredentialsFile string
+}
+
+func NewAWSCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "aws",
+		Short:        "Encrypts data encryption keys with an AWS KMS key",
+		SilenceUsage: true,
+	}
+
+	opts := AWSOptions{}
+	cmd.Flags().StringVar(&opts.Listen, "listen", opts.Listen, "The unix socket endpoint to serve the plugin on (required)")
+	cmd.Flags().StringVar(&opts.KeyARN, "key-arn", opts.KeyARN, "The ARN of the KMS key (required)")
+	cmd.Flags().StringVar(&opts.Region, "region", opts.Region, "The region of the KMS key (required)")
+	cmd.Flags().StringVar(&opts.CredentialsFile, "credentials-file", opts.CredentialsFile, "Path to an AWS credentials file (required)")
+
+	cmd.MarkFlagRequired("listen")
+	cmd.MarkFlagRequired("key-arn")
+	cmd.MarkFlagRequired("region")
+	cmd.MarkFlagRequired("credentials-file")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		run(opts.Backend, opts.Listen)
+	}
+
+	return cmd
+}
+
+func (o *AWSOptions) Backend() (Backend, error) {
+	awsSession, err := session.NewSessionWithOptions(session.Options{
+		Config:            *aws.NewConfig().WithRegion(o.Region),
+		SharedConfigState: session.SharedConfigEnable,
+		SharedConfigFiles: []string{o.CredentialsFile},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+	return &AWSBackend{Client: kms.New(awsSession), KeyARN: o.KeyARN}, nil
+}
+
+// AWSBackend encrypts data encryption keys with an AWS KMS key.
+type AWSBackend struct {
+	Client kmsiface.KMSAPI
+	KeyARN string
+}
+
+func (b *AWSBackend) Name() string {
+	return "hypershift-kms-plugin-aws"
+}
+
+func (b *AWSBackend) Encrypt(ctx context.Context, plain []byte) ([]byte, error) {
+	output, err := b.Client.EncryptWithContext(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(b.KeyARN),
+		Plaintext: plain,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.CiphertextBlob, nil
+}
+
+func (b *AWSBackend) Decrypt(ctx context.Context, cipher []byte) ([]byte, error) {
+	output, err := b.Client.DecryptWithContext(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(b.KeyARN),
+		CiphertextBlob: cipher,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Plaintext, nil
+}
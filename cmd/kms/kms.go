@@ -0,0 +1,131 @@
+package kms
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/bombsimon/logrusr"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	kmsapi "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/v1beta1"
+)
+
+var log = logrusr.NewLogger(logrus.New())
+
+const (
+	// apiVersion is the version of the KMS plugin API the kube apiserver
+	// requires plugins to implement.
+	apiVersion = "v1beta1"
+
+	runtimeVersion = "0.1.0"
+)
+
+// NewCommand returns the KMS plugins which run alongside the apiservers of a
+// hosted control plane to encrypt resources at rest with an external key
+// management service. They are run from the control plane operator image and
+// are not meant to be used directly.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "kms-plugin",
+		Short:        "Runs a KMS plugin for envelope encryption",
+		Hidden:       true,
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(NewAWSCommand())
+	cmd.AddCommand(NewMockCommand())
+
+	return cmd
+}
+
+// Backend encrypts and decrypts the data encryption keys of the apiserver.
+type Backend interface {
+	// Name identifies the backend to the apiserver.
+	Name() string
+	Encrypt(ctx context.Context, plain []byte) ([]byte, error)
+	Decrypt(ctx context.Context, cipher []byte) ([]byte, error)
+}
+
+// Server serves a Backend through the KMS plugin API.
+type Server struct {
+	Backend Backend
+}
+
+var _ kmsapi.KeyManagementServiceServer = &Server{}
+
+func (s *Server) Version(ctx context.Context, req *kmsapi.VersionRequest) (*kmsapi.VersionResponse, error) {
+	return &kmsapi.VersionResponse{
+		Version:        apiVersion,
+		RuntimeName:    s.Backend.Name(),
+		RuntimeVersion: runtimeVersion,
+	}, nil
+}
+
+func (s *Server) Encrypt(ctx context.Context, req *kmsapi.EncryptRequest) (*kmsapi.EncryptResponse, error) {
+	cipher, err := s.Backend.Encrypt(ctx, req.Plain)
+	if err != nil {
+		log.Error(err, "Failed to encrypt")
+		return nil, status.Errorf(codes.Internal, "failed to encrypt: %v", err)
+	}
+	return &kmsapi.EncryptResponse{Cipher: cipher}, nil
+}
+
+func (s *Server) Decrypt(ctx context.Context, req *kmsapi.DecryptRequest) (*kmsapi.DecryptResponse, error) {
+	plain, err := s.Backend.Decrypt(ctx, req.Cipher)
+	if err != nil {
+		log.Error(err, "Failed to decrypt")
+		return nil, status.Errorf(codes.Internal, "failed to decrypt: %v", err)
+	}
+	return &kmsapi.DecryptResponse{Plain: plain}, nil
+}
+
+// Listen opens the unix socket of the given endpoint, replacing a socket left
+// behind by a previous plugin.
+func Listen(endpoint string) (net.Listener, error) {
+	if !strings.HasPrefix(endpoint, "unix://") {
+		return nil, fmt.Errorf("endpoint %s is not a unix socket", endpoint)
+	}
+	socket := strings.TrimPrefix(endpoint, "unix://")
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove socket %s: %w", socket, err)
+	}
+	return net.Listen("unix", socket)
+}
+
+// Serve serves the backend on the endpoint until the context is done.
+func Serve(ctx context.Context, endpoint string, backend Backend) error {
+	listener, err := Listen(endpoint)
+	if err != nil {
+		return err
+	}
+	server := grpc.NewServer()
+	kmsapi.RegisterKeyManagementServiceServer(server, &Server{Backend: backend})
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+	log.Info("Serving KMS plugin", "endpoint", endpoint, "backend", backend.Name())
+	return server.Serve(listener)
+}
+
+func run(backend func() (Backend, error), endpoint string) {
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	b, err := backend()
+	if err != nil {
+		log.Error(err, "Failed to configure KMS plugin")
+		os.Exit(1)
+	}
+	if err := Serve(ctx, endpoint, b); err != nil {
+		log.Error(err, "Failed to serve KMS plugin")
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,54 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	kmsapi "k8s.io/apiserver/pkg/storage/value/encrypt/envelope/v1beta1"
+)
+
+func TestMockPlugin(t *testing.T) {
+	g := NewGomegaWithT(t)
+	backend, err := NewMockBackend(bytes.Repeat([]byte{1}, 32))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	endpoint := "unix://" + filepath.Join(t.TempDir(), "kms.sock")
+	listener, err := Listen(endpoint)
+	g.Expect(err).ToNot(HaveOccurred())
+	server := grpc.NewServer()
+	kmsapi.RegisterKeyManagementServiceServer(server, &Server{Backend: backend})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	dialCtx, dialCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer dialCancel()
+	conn, err := grpc.DialContext(dialCtx, endpoint, grpc.WithInsecure(), grpc.WithBlock())
+	g.Expect(err).ToNot(HaveOccurred())
+	defer conn.Close()
+	client := kmsapi.NewKeyManagementServiceClient(conn)
+
+	version, err := client.Version(ctx, &kmsapi.VersionRequest{Version: apiVersion})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(version.Version).To(Equal(apiVersion))
+
+	encrypted, err := client.Encrypt(ctx, &kmsapi.EncryptRequest{Version: apiVersion, Plain: []byte("data encryption key")})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(encrypted.Cipher).ToNot(ContainSubstring("data encryption key"))
+
+	decrypted, err := client.Decrypt(ctx, &kmsapi.DecryptRequest{Version: apiVersion, Cipher: encrypted.Cipher})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(decrypted.Plain)).To(Equal("data encryption key"))
+
+	// Ciphertexts of another key can't be decrypted
+	other, err := NewMockBackend(bytes.Repeat([]byte{2}, 32))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = other.Decrypt(ctx, encrypted.Cipher)
+	g.Expect(err).To(HaveOccurred())
+}
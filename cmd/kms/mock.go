@@ -0,0 +1,90 @@
+package kms
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+)
+
+type MockOptions struct {
+	Listen  string
+	KeyFile string
+}
+
+// NewMockCommand returns a KMS plugin which encrypts with a local key instead
+// of a key management service. It can't be selected as a KMS provider of a
+// HostedCluster and is meant for running the plugin in e2e and local tests.
+func NewMockCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "mock",
+		Short:        "Encrypts data encryption keys with a local key, for testing only",
+		SilenceUsage: true,
+	}
+
+	opts := MockOptions{}
+	cmd.Flags().StringVar(&opts.Listen, "listen", opts.Listen, "The unix socket endpoint to serve the plugin on (required)")
+	cmd.Flags().StringVar(&opts.KeyFile, "key-file", opts.KeyFile, "Path to a file containing a 256 bit key (required)")
+
+	cmd.MarkFlagRequired("listen")
+	cmd.MarkFlagRequired("key-file")
+
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		run(opts.Backend, opts.Listen)
+	}
+
+	return cmd
+}
+
+func (o *MockOptions) Backend() (Backend, error) {
+	key, err := ioutil.ReadFile(o.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	return NewMockBackend(key)
+}
+
+// MockBackend encrypts data encryption keys with AES-GCM using a local key.
+// It stands in for a key management service in tests.
+type MockBackend struct {
+	aead cipher.AEAD
+}
+
+func NewMockBackend(key []byte) (*MockBackend, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("mock key must be 32 bytes long, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &MockBackend{aead: aead}, nil
+}
+
+func (b *MockBackend) Name() string {
+	return "hypershift-kms-plugin-mock"
+}
+
+func (b *MockBackend) Encrypt(ctx context.Context, plain []byte) ([]byte, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return b.aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+func (b *MockBackend) Decrypt(ctx context.Context, cipher []byte) ([]byte, error) {
+	if len(cipher) < b.aead.NonceSize() {
+		return nil, fmt.Errorf("ciphertext is too short")
+	}
+	nonce, sealed := cipher[:b.aead.NonceSize()], cipher[b.aead.NonceSize():]
+	return b.aead.Open(nil, nonce, sealed, nil)
+}
@@ -0,0 +1,169 @@
+package etcdmaintenance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/etcd"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+)
+
+const (
+	// maintenanceInterval is how often the databases of the managed etcd
+	// members are checked.
+	maintenanceInterval = 5 * time.Minute
+
+	// nextDefragmentationDelay is how long to wait after defragmenting a member
+	// before checking whether another member needs it.
+	nextDefragmentationDelay = 30 * time.Second
+)
+
+// EtcdMaintenanceReconciler periodically reads the database size of every
+// member of the managed etcd cluster, reports it in the HostedControlPlane
+// status and as metrics, and defragments members one at a time once enough of
+// their database is unused.
+type EtcdMaintenanceReconciler struct {
+	client.Client
+
+	Log logr.Logger
+}
+
+func (r *EtcdMaintenanceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Status updates made by this controller must not retrigger it.
+	_, err := ctrl.NewControllerManagedBy(mgr).
+		Named("etcd-maintenance").
+		For(&hyperv1.HostedControlPlane{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Build(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up with a controller manager %w", err)
+	}
+	return nil
+}
+
+func (r *EtcdMaintenanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log = ctrl.LoggerFrom(ctx)
+
+	hcp := &hyperv1.HostedControlPlane{}
+	if err := r.Get(ctx, req.NamespacedName, hcp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !hcp.DeletionTimestamp.IsZero() || hcp.Spec.Etcd.ManagementType != hyperv1.Managed {
+		return ctrl.Result{}, nil
+	}
+
+	statefulSet := manifests.EtcdStatefulSet(hcp.Namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(statefulSet), statefulSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{RequeueAfter: maintenanceInterval}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get etcd statefulset: %w", err)
+	}
+
+	etcdClient, err := etcd.NewEtcdClient(ctx, r, hcp.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	defer etcdClient.Close()
+	members, err := etcdClient.MemberList(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Members are only defragmented while every member is healthy, since a
+	// member being defragmented doesn't count towards quorum.
+	healthy := statefulSet.Spec.Replicas != nil && statefulSet.Status.ReadyReplicas == *statefulSet.Spec.Replicas
+	var leader uint64
+	var databases []etcd.MemberDatabase
+	for _, member := range members {
+		if !member.Started() {
+			healthy = false
+			continue
+		}
+		status, err := etcdClient.Status(ctx, member.ClientURLs[0])
+		if err != nil {
+			r.Log.Error(err, "failed to get etcd member status", "member", member.Name)
+			healthy = false
+			continue
+		}
+		if len(status.Errors) > 0 {
+			healthy = false
+		}
+		leader = status.Leader
+		databases = append(databases, etcd.MemberDatabase{
+			ID:        member.ID,
+			Name:      member.Name,
+			Endpoint:  member.ClientURLs[0],
+			Size:      status.DBSize,
+			SizeInUse: status.DBSizeInUse,
+		})
+	}
+	updateMetrics(databases)
+
+	result := ctrl.Result{RequeueAfter: maintenanceInterval}
+	var defragmented *etcd.MemberDatabase
+	if healthy {
+		if member := etcd.NextMemberToDefragment(databases, leader); member != nil {
+			r.Log.Info("Defragmenting etcd member", "member", member.Name, "size", member.Size, "sizeInUse", member.SizeInUse)
+			if err := etcdClient.Defragment(ctx, member.Endpoint); err != nil {
+				etcdDefragmentationFailuresTotal.WithLabelValues(member.Name).Inc()
+				return ctrl.Result{}, err
+			}
+			etcdDefragmentationsTotal.WithLabelValues(member.Name).Inc()
+			defragmented = member
+			// The member's database size is read again along with the next member
+			result.RequeueAfter = nextDefragmentationDelay
+		}
+	}
+
+	hcp.Status.Etcd = computeManagedEtcdStatus(hcp.Status.Etcd, databases, defragmented, metav1.Now())
+	meta.SetStatusCondition(&hcp.Status.Conditions, etcd.ComputeEtcdQuotaRiskCondition(hcp.Status.Etcd))
+	if err := r.Status().Update(ctx, hcp); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+	return result, nil
+}
+
+// computeManagedEtcdStatus returns the status of the managed etcd databases.
+// The last defragmentation time of each member is kept from the previous
+// status unless the member was just defragmented.
+func computeManagedEtcdStatus(previous *hyperv1.ManagedEtcdStatus, databases []etcd.MemberDatabase, defragmented *etcd.MemberDatabase, now metav1.Time) *hyperv1.ManagedEtcdStatus {
+	lastDefragmentation := map[string]*metav1.Time{}
+	if previous != nil {
+		for _, member := range previous.Members {
+			lastDefragmentation[member.Name] = member.LastDefragmentationTime
+		}
+	}
+	status := &hyperv1.ManagedEtcdStatus{
+		QuotaBackendBytes: etcd.EtcdQuotaBackendBytes,
+	}
+	for _, database := range databases {
+		member := hyperv1.ManagedEtcdMemberStatus{
+			Name:                    database.Name,
+			DBSizeBytes:             database.Size,
+			DBSizeInUseBytes:        database.SizeInUse,
+			LastDefragmentationTime: lastDefragmentation[database.Name],
+		}
+		if defragmented != nil && defragmented.ID == database.ID {
+			member.LastDefragmentationTime = &now
+		}
+		status.Members = append(status.Members, member)
+	}
+	return status
+}
@@ -0,0 +1,57 @@
+package etcdmaintenance
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/etcd"
+)
+
+var (
+	etcdDBSizeBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hypershift_etcd_db_size_bytes",
+		Help: "Size of the database of a managed etcd member, including unused space.",
+	}, []string{"member"})
+
+	etcdDBSizeInUseBytes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hypershift_etcd_db_size_in_use_bytes",
+		Help: "Size of the database of a managed etcd member which is in use.",
+	}, []string{"member"})
+
+	etcdQuotaBackendBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "hypershift_etcd_quota_backend_bytes",
+		Help: "Size the database of a managed etcd member may grow to before etcd stops accepting writes.",
+	})
+
+	etcdDefragmentationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hypershift_etcd_defragmentations_total",
+		Help: "Number of times a managed etcd member was defragmented.",
+	}, []string{"member"})
+
+	etcdDefragmentationFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hypershift_etcd_defragmentation_failures_total",
+		Help: "Number of times defragmenting a managed etcd member failed.",
+	}, []string{"member"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		etcdDBSizeBytes,
+		etcdDBSizeInUseBytes,
+		etcdQuotaBackendBytes,
+		etcdDefragmentationsTotal,
+		etcdDefragmentationFailuresTotal,
+	)
+}
+
+// updateMetrics reports the database sizes of the given members. Members
+// which are gone are no longer reported.
+func updateMetrics(databases []etcd.MemberDatabase) {
+	etcdDBSizeBytes.Reset()
+	etcdDBSizeInUseBytes.Reset()
+	for _, database := range databases {
+		etcdDBSizeBytes.WithLabelValues(database.Name).Set(float64(database.Size))
+		etcdDBSizeInUseBytes.WithLabelValues(database.Name).Set(float64(database.SizeInUse))
+	}
+	etcdQuotaBackendBytes.Set(float64(etcd.EtcdQuotaBackendBytes))
+}
@@ -9,8 +9,10 @@ import (
 
 	"github.com/go-logr/logr"
 	configv1 "github.com/openshift/api/config/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	toolscache "k8s.io/client-go/tools/cache"
@@ -101,7 +103,7 @@ func (h *hostedAPICache) destroy() {
 //
 // For now, the event handlers installed in the cache are statically defined within
 // this function and are limited to a set of significant changes to ClusterVersion
-// resources (add, delete, status updated).
+// resources (add, delete, status updated) and to nodes being added or deleted.
 func (h *hostedAPICache) update(requestCtx context.Context, triggerObj client.Object, newKubeConfig []byte) error {
 	h.lock.Lock()
 	defer h.lock.Unlock()
@@ -160,6 +162,23 @@ func (h *hostedAPICache) update(requestCtx context.Context, triggerObj client.Ob
 				h.events <- event.GenericEvent{Object: triggerObj}
 			},
 		})
+
+		// Only the number of nodes is used, so nodes are watched through their
+		// metadata and node status updates are ignored
+		nodeMetadata := &metav1.PartialObjectMetadata{}
+		nodeMetadata.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Node"))
+		nodeInformer, err := c.GetInformer(requestCtx, nodeMetadata)
+		if err != nil {
+			return fmt.Errorf("failed to set up node informer: %w", err)
+		}
+		nodeInformer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				h.events <- event.GenericEvent{Object: triggerObj}
+			},
+			DeleteFunc: func(obj interface{}) {
+				h.events <- event.GenericEvent{Object: triggerObj}
+			},
+		})
 		return nil
 	}(newCache)
 	if err != nil {
@@ -7,6 +7,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
 )
 
 type ClusterPolicyControllerParams struct {
@@ -38,6 +39,7 @@ func NewClusterPolicyControllerParams(hcp *hyperv1.HostedControlPlane, globalCon
 	params.DeploymentConfig.SetColocation(hcp)
 	params.DeploymentConfig.SetMultizoneSpread(clusterPolicyControllerLabels)
 	params.DeploymentConfig.SetRestartAnnotation(hcp.ObjectMeta)
+	params.DeploymentConfig.SetComponentOverrides(hcp, manifests.ClusterPolicyControllerDeployment("").Name)
 	params.DeploymentConfig.SetControlPlaneIsolation(hcp)
 
 	switch hcp.Spec.ControllerAvailabilityPolicy {
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"fmt"
+	"path"
+)
+
+// AWSWebIdentityTokenDir is the directory holding the service account token
+// used by components which assume an AWS role through the OIDC provider of
+// the hosted cluster.
+const AWSWebIdentityTokenDir = "/var/run/secrets/openshift/serviceaccount"
+
+// AWSWebIdentityTokenKey is the name of the token file in AWSWebIdentityTokenDir.
+const AWSWebIdentityTokenKey = "token"
+
+const awsCredentialsTemplate = `[default]
+role_arn = %s
+web_identity_token_file = %s
+`
+
+// AWSRoleCredentials returns an AWS credentials file which assumes the given
+// role with the token in AWSWebIdentityTokenDir.
+func AWSRoleCredentials(roleARN string) string {
+	return fmt.Sprintf(awsCredentialsTemplate, roleARN, path.Join(AWSWebIdentityTokenDir, AWSWebIdentityTokenKey))
+}
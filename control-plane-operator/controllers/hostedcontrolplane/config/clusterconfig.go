@@ -13,6 +13,10 @@ import (
 	"github.com/openshift/hypershift/control-plane-operator/api"
 )
 
+// EncryptionTypeAESGCM encrypts resources at rest with AES-GCM. It is not
+// defined by the vendored openshift/api yet.
+const EncryptionTypeAESGCM configv1.EncryptionType = "aesgcm"
+
 type GlobalConfig struct {
 	APIServer      *configv1.APIServer
 	Authentication *configv1.Authentication
@@ -90,6 +94,7 @@ func ValidateGlobalConfig(ctx context.Context, hcp *hyperv1.HostedControlPlane)
 	if gCfg.APIServer != nil {
 		refErrs := validateAPIServerReferencedResources(gCfg.APIServer, referencedSecrets, referencedConfigMaps)
 		errs = append(errs, refErrs...)
+		errs = append(errs, validateAPIServerEncryption(gCfg.APIServer)...)
 	}
 	if gCfg.Authentication != nil {
 		refErrs := validateAuthenticationReferencedResources(gCfg.Authentication, referencedSecrets, referencedConfigMaps)
@@ -130,6 +135,15 @@ func validateAPIServerReferencedResources(cfg *configv1.APIServer, secrets, conf
 	return errs
 }
 
+func validateAPIServerEncryption(cfg *configv1.APIServer) []error {
+	switch cfg.Spec.Encryption.Type {
+	case "", configv1.EncryptionTypeIdentity, configv1.EncryptionTypeAESCBC, EncryptionTypeAESGCM:
+		return nil
+	default:
+		return []error{fmt.Errorf("APIServer: unsupported encryption type %s", cfg.Spec.Encryption.Type)}
+	}
+}
+
 func validateAuthenticationReferencedResources(cfg *configv1.Authentication, secrets, configMaps sets.String) []error {
 	var errs []error
 	if len(cfg.Spec.OAuthMetadata.Name) > 0 {
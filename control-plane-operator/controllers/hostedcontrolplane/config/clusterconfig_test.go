@@ -39,3 +39,23 @@ func TestParseGlobalConfig(t *testing.T) {
 		t.Errorf("unexpected featureset: %q", globalConfig.FeatureGate.Spec.FeatureSet)
 	}
 }
+
+func TestValidateAPIServerEncryption(t *testing.T) {
+	for encryptionType, valid := range map[configv1.EncryptionType]bool{
+		"":                              true,
+		configv1.EncryptionTypeIdentity: true,
+		configv1.EncryptionTypeAESCBC:   true,
+		EncryptionTypeAESGCM:            true,
+		"secretbox":                     false,
+	} {
+		cfg := &configv1.APIServer{}
+		cfg.Spec.Encryption.Type = encryptionType
+		errs := validateAPIServerEncryption(cfg)
+		if valid && len(errs) > 0 {
+			t.Errorf("unexpected error for encryption type %q: %v", encryptionType, errs)
+		}
+		if !valid && len(errs) == 0 {
+			t.Errorf("expected an error for encryption type %q", encryptionType)
+		}
+	}
+}
@@ -18,6 +18,10 @@ type DeploymentConfig struct {
 	LivenessProbes        LivenessProbes        `json:"livenessProbes"`
 	ReadinessProbes       ReadinessProbes       `json:"readinessProbes"`
 	Resources             ResourcesSpec         `json:"resources"`
+
+	// Overrides are the user defined customizations of the component. They
+	// are applied on top of the rest of the configuration.
+	Overrides *hyperv1.ControlPlaneComponentOverride `json:"overrides,omitempty"`
 }
 
 func (c *DeploymentConfig) SetRestartAnnotation(objectMetadata metav1.ObjectMeta) {
@@ -160,35 +164,49 @@ func (c *DeploymentConfig) SetControlPlaneIsolation(hcp *hyperv1.HostedControlPl
 }
 
 func (c *DeploymentConfig) ApplyTo(deployment *appsv1.Deployment) {
-	deployment.Spec.Replicas = pointer.Int32Ptr(int32(c.Replicas))
+	cfg := c.withOverrides()
+	deployment.Spec.Replicas = pointer.Int32Ptr(int32(cfg.Replicas))
 	// there are two standard cases currently with hypershift: HA mode where there are 3 replicas spread across
 	// zones and then non ha with one replica. When only 3 zones are available you need to be able to set maxUnavailable
 	// in order to progress the rollout. However, you do not want to set that in the single replica case because it will
 	// result in downtime.
-	if c.Replicas > 1 {
+	if cfg.Replicas > 1 {
 		maxSurge := intstr.FromInt(3)
 		maxUnavailable := intstr.FromInt(1)
 		deployment.Spec.Strategy.RollingUpdate.MaxSurge = &maxSurge
 		deployment.Spec.Strategy.RollingUpdate.MaxUnavailable = &maxUnavailable
 	}
-	c.Scheduling.ApplyTo(&deployment.Spec.Template.Spec)
-	c.AdditionalLabels.ApplyTo(&deployment.Spec.Template.ObjectMeta)
-	c.SecurityContexts.ApplyTo(&deployment.Spec.Template.Spec)
-	c.Resources.ApplyTo(&deployment.Spec.Template.Spec)
-	c.LivenessProbes.ApplyTo(&deployment.Spec.Template.Spec)
-	c.ReadinessProbes.ApplyTo(&deployment.Spec.Template.Spec)
-	c.Resources.ApplyTo(&deployment.Spec.Template.Spec)
-	c.AdditionalAnnotations.ApplyTo(&deployment.Spec.Template.ObjectMeta)
+	cfg.Scheduling.ApplyTo(&deployment.Spec.Template.Spec)
+	cfg.AdditionalLabels.ApplyTo(&deployment.Spec.Template.ObjectMeta)
+	cfg.SecurityContexts.ApplyTo(&deployment.Spec.Template.Spec)
+	cfg.Resources.ApplyTo(&deployment.Spec.Template.Spec)
+	cfg.LivenessProbes.ApplyTo(&deployment.Spec.Template.Spec)
+	cfg.ReadinessProbes.ApplyTo(&deployment.Spec.Template.Spec)
+	cfg.Resources.ApplyTo(&deployment.Spec.Template.Spec)
+	cfg.AdditionalAnnotations.ApplyTo(&deployment.Spec.Template.ObjectMeta)
 }
 
 func (c *DeploymentConfig) ApplyToDaemonSet(daemonset *appsv1.DaemonSet) {
+	cfg := c.withOverrides()
 	// replicas is not used for DaemonSets
-	c.Scheduling.ApplyTo(&daemonset.Spec.Template.Spec)
-	c.AdditionalLabels.ApplyTo(&daemonset.Spec.Template.ObjectMeta)
-	c.SecurityContexts.ApplyTo(&daemonset.Spec.Template.Spec)
-	c.Resources.ApplyTo(&daemonset.Spec.Template.Spec)
-	c.LivenessProbes.ApplyTo(&daemonset.Spec.Template.Spec)
-	c.ReadinessProbes.ApplyTo(&daemonset.Spec.Template.Spec)
-	c.Resources.ApplyTo(&daemonset.Spec.Template.Spec)
-	c.AdditionalAnnotations.ApplyTo(&daemonset.Spec.Template.ObjectMeta)
+	cfg.Scheduling.ApplyTo(&daemonset.Spec.Template.Spec)
+	cfg.AdditionalLabels.ApplyTo(&daemonset.Spec.Template.ObjectMeta)
+	cfg.SecurityContexts.ApplyTo(&daemonset.Spec.Template.Spec)
+	cfg.Resources.ApplyTo(&daemonset.Spec.Template.Spec)
+	cfg.LivenessProbes.ApplyTo(&daemonset.Spec.Template.Spec)
+	cfg.ReadinessProbes.ApplyTo(&daemonset.Spec.Template.Spec)
+	cfg.Resources.ApplyTo(&daemonset.Spec.Template.Spec)
+	cfg.AdditionalAnnotations.ApplyTo(&daemonset.Spec.Template.ObjectMeta)
+}
+
+func (c *DeploymentConfig) ApplyToStatefulSet(statefulSet *appsv1.StatefulSet) {
+	cfg := c.withOverrides()
+	// replicas is not set here, StatefulSets are scaled by their reconcilers
+	cfg.Scheduling.ApplyTo(&statefulSet.Spec.Template.Spec)
+	cfg.AdditionalLabels.ApplyTo(&statefulSet.Spec.Template.ObjectMeta)
+	cfg.SecurityContexts.ApplyTo(&statefulSet.Spec.Template.Spec)
+	cfg.Resources.ApplyTo(&statefulSet.Spec.Template.Spec)
+	cfg.LivenessProbes.ApplyTo(&statefulSet.Spec.Template.Spec)
+	cfg.ReadinessProbes.ApplyTo(&statefulSet.Spec.Template.Spec)
+	cfg.AdditionalAnnotations.ApplyTo(&statefulSet.Spec.Template.ObjectMeta)
 }
@@ -0,0 +1,61 @@
+package config
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+// SetComponentOverrides selects the overrides the HostedControlPlane defines
+// for the named component, which is the name of its deployment or stateful
+// set. Since overrides are applied together with the rest of the
+// configuration, they take precedence regardless of when defaults are set.
+func (c *DeploymentConfig) SetComponentOverrides(hcp *hyperv1.HostedControlPlane, component string) {
+	c.Overrides = nil
+	for i := range hcp.Spec.ComponentOverrides {
+		if hcp.Spec.ComponentOverrides[i].Name == component {
+			c.Overrides = hcp.Spec.ComponentOverrides[i].DeepCopy()
+			return
+		}
+	}
+}
+
+// EffectiveReplicas returns the number of replicas of the component once its
+// overrides are applied.
+func (c *DeploymentConfig) EffectiveReplicas() int {
+	return c.withOverrides().Replicas
+}
+
+// withOverrides returns a copy of the configuration with the overrides applied.
+func (c *DeploymentConfig) withOverrides() DeploymentConfig {
+	cfg := *c
+	o := c.Overrides
+	if o == nil {
+		return cfg
+	}
+	if o.Replicas != nil {
+		cfg.Replicas = int(*o.Replicas)
+	}
+	if len(o.NodeSelector) > 0 {
+		cfg.Scheduling.NodeSelector = o.NodeSelector
+	}
+	if len(o.Tolerations) > 0 {
+		tolerations := make([]corev1.Toleration, 0, len(c.Scheduling.Tolerations)+len(o.Tolerations))
+		tolerations = append(tolerations, c.Scheduling.Tolerations...)
+		cfg.Scheduling.Tolerations = append(tolerations, o.Tolerations...)
+	}
+	if o.PriorityClassName != "" {
+		cfg.Scheduling.PriorityClass = o.PriorityClassName
+	}
+	if len(o.Resources) > 0 {
+		resources := ResourcesSpec{}
+		for container, requirements := range c.Resources {
+			resources[container] = requirements
+		}
+		for _, override := range o.Resources {
+			resources[override.Container] = override.Resources
+		}
+		cfg.Resources = resources
+	}
+	return cfg
+}
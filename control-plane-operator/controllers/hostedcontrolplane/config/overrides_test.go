@@ -0,0 +1,89 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+func TestComponentOverrides(t *testing.T) {
+	hcp := &hyperv1.HostedControlPlane{}
+	hcp.Namespace = "clusters-test"
+	overrideResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("2"),
+		},
+	}
+	toleration := corev1.Toleration{Key: "dedicated", Operator: corev1.TolerationOpExists}
+	hcp.Spec.ComponentOverrides = []hyperv1.ControlPlaneComponentOverride{
+		{
+			Name:              "kube-apiserver",
+			Replicas:          pointer.Int32Ptr(5),
+			Resources:         []hyperv1.ContainerResourcesOverride{{Container: "kube-apiserver", Resources: overrideResources}},
+			NodeSelector:      map[string]string{"tier": "api"},
+			Tolerations:       []corev1.Toleration{toleration},
+			PriorityClassName: "custom",
+		},
+	}
+
+	defaultResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("50Mi"),
+		},
+	}
+	cfg := DeploymentConfig{
+		Replicas: 3,
+		Resources: ResourcesSpec{
+			"kube-apiserver":  defaultResources,
+			"apply-bootstrap": defaultResources,
+		},
+		Scheduling: Scheduling{PriorityClass: APICriticalPriorityClass},
+	}
+	cfg.SetControlPlaneIsolation(hcp)
+	cfg.SetComponentOverrides(hcp, "kube-apiserver")
+	if cfg.EffectiveReplicas() != 5 {
+		t.Fatalf("Expected 5 effective replicas, got %d", cfg.EffectiveReplicas())
+	}
+
+	deployment := &appsv1.Deployment{}
+	deployment.Spec.Strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "kube-apiserver"}, {Name: "apply-bootstrap"}}
+	cfg.ApplyTo(deployment)
+
+	podSpec := deployment.Spec.Template.Spec
+	if *deployment.Spec.Replicas != 5 {
+		t.Fatalf("Expected 5 replicas, got %d", *deployment.Spec.Replicas)
+	}
+	if !reflect.DeepEqual(podSpec.Containers[0].Resources, overrideResources) {
+		t.Fatalf("Expected overridden resources, got %v", podSpec.Containers[0].Resources)
+	}
+	if !reflect.DeepEqual(podSpec.Containers[1].Resources, defaultResources) {
+		t.Fatalf("Expected default resources, got %v", podSpec.Containers[1].Resources)
+	}
+	if !reflect.DeepEqual(podSpec.NodeSelector, map[string]string{"tier": "api"}) {
+		t.Fatalf("Unexpected node selector %v", podSpec.NodeSelector)
+	}
+	if len(podSpec.Tolerations) != 3 || !reflect.DeepEqual(podSpec.Tolerations[2], toleration) {
+		t.Fatalf("Expected the toleration to be added to the isolation tolerations, got %v", podSpec.Tolerations)
+	}
+	if podSpec.PriorityClassName != "custom" {
+		t.Fatalf("Unexpected priority class %s", podSpec.PriorityClassName)
+	}
+
+	// The configuration itself is left untouched
+	if cfg.Replicas != 3 || len(cfg.Scheduling.Tolerations) != 2 || !reflect.DeepEqual(cfg.Resources["kube-apiserver"], defaultResources) {
+		t.Fatalf("Expected overrides not to modify the configuration")
+	}
+
+	// Components without overrides keep their configuration
+	cfg.SetComponentOverrides(hcp, "etcd")
+	if cfg.Overrides != nil {
+		t.Fatalf("Expected no overrides for etcd")
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// NeedsPodDisruptionBudget returns whether the component runs more than one
+// replica and should be protected from voluntary disruptions such as node
+// drains.
+func (c *DeploymentConfig) NeedsPodDisruptionBudget() bool {
+	return c.EffectiveReplicas() > 1
+}
+
+// ReconcilePodDisruptionBudget allows a single pod of the component selected by
+// the given labels to be evicted at a time. This keeps etcd quorum and leaves
+// the remaining replicas of the other components serving during drains.
+func ReconcilePodDisruptionBudget(pdb *policyv1beta1.PodDisruptionBudget, ownerRef OwnerRef, selector map[string]string) {
+	ownerRef.ApplyTo(pdb)
+	maxUnavailable := intstr.FromInt(1)
+	pdb.Spec = policyv1beta1.PodDisruptionBudgetSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: selector,
+		},
+		MaxUnavailable: &maxUnavailable,
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/utils/pointer"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+func TestNeedsPodDisruptionBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      DeploymentConfig
+		expected bool
+	}{
+		{
+			name: "single replica",
+			cfg:  DeploymentConfig{Replicas: 1},
+		},
+		{
+			name:     "highly available",
+			cfg:      DeploymentConfig{Replicas: 3},
+			expected: true,
+		},
+		{
+			name: "scaled down by an override",
+			cfg: DeploymentConfig{
+				Replicas:  3,
+				Overrides: &hyperv1.ControlPlaneComponentOverride{Replicas: pointer.Int32Ptr(1)},
+			},
+		},
+		{
+			name: "scaled up by an override",
+			cfg: DeploymentConfig{
+				Replicas:  1,
+				Overrides: &hyperv1.ControlPlaneComponentOverride{Replicas: pointer.Int32Ptr(2)},
+			},
+			expected: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := test.cfg.NeedsPodDisruptionBudget(); actual != test.expected {
+				t.Errorf("Expected %t, got %t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestReconcilePodDisruptionBudget(t *testing.T) {
+	hcp := &hyperv1.HostedControlPlane{}
+	hcp.Name = "test"
+	hcp.Namespace = "clusters-test"
+	selector := map[string]string{"app": "kube-apiserver"}
+
+	pdb := &policyv1beta1.PodDisruptionBudget{}
+	ReconcilePodDisruptionBudget(pdb, OwnerRefFrom(hcp), selector)
+	if !reflect.DeepEqual(pdb.Spec.Selector.MatchLabels, selector) {
+		t.Fatalf("Unexpected selector %v", pdb.Spec.Selector.MatchLabels)
+	}
+	if pdb.Spec.MaxUnavailable == nil || pdb.Spec.MaxUnavailable.IntValue() != 1 {
+		t.Fatalf("Expected a single unavailable pod, got %v", pdb.Spec.MaxUnavailable)
+	}
+	if pdb.Spec.MinAvailable != nil {
+		t.Fatalf("Unexpected min available %v", pdb.Spec.MinAvailable)
+	}
+	if len(pdb.OwnerReferences) != 1 || pdb.OwnerReferences[0].Name != hcp.Name {
+		t.Fatalf("Expected the pod disruption budget to be owned by the hosted control plane, got %v", pdb.OwnerReferences)
+	}
+}
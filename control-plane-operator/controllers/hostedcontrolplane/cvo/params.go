@@ -7,6 +7,7 @@ import (
 	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
 )
 
 type CVOParams struct {
@@ -37,6 +38,7 @@ func NewCVOParams(hcp *hyperv1.HostedControlPlane) *CVOParams {
 	p.DeploymentConfig.SetColocation(hcp)
 	p.DeploymentConfig.SetMultizoneSpread(cvoLabels)
 	p.DeploymentConfig.SetRestartAnnotation(hcp.ObjectMeta)
+	p.DeploymentConfig.SetComponentOverrides(hcp, manifests.ClusterVersionOperatorDeployment("").Name)
 	switch hcp.Spec.ControllerAvailabilityPolicy {
 	case hyperv1.HighlyAvailable:
 		p.DeploymentConfig.Replicas = 3
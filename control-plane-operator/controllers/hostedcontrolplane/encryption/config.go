@@ -0,0 +1,228 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/util"
+)
+
+const (
+	EncryptionConfigKey = "config.yaml"
+
+	// ConfigHashAnnotation is set on the pod templates of the apiservers to the
+	// hash of the EncryptionConfiguration they were rolled out with.
+	ConfigHashAnnotation = "hypershift.openshift.io/encryption-config-hash"
+)
+
+// EncryptedResources are the resources which are encrypted at rest. Secrets
+// and configmaps are served by the kube apiserver, routes by the openshift
+// apiserver. Each apiserver ignores the resources it doesn't serve.
+var EncryptedResources = []string{
+	"secrets",
+	"configmaps",
+	"routes.route.openshift.io",
+}
+
+// The EncryptionConfiguration types of k8s.io/apiserver are internal to the
+// apiserver, so the subset needed to render it is defined here.
+type encryptionConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+	Resources       []resourceConfiguration `json:"resources"`
+}
+
+type resourceConfiguration struct {
+	Resources []string                `json:"resources"`
+	Providers []providerConfiguration `json:"providers"`
+}
+
+type providerConfiguration struct {
+	AESGCM   *aesConfiguration      `json:"aesgcm,omitempty"`
+	AESCBC   *aesConfiguration      `json:"aescbc,omitempty"`
+	KMS      *kmsConfiguration      `json:"kms,omitempty"`
+	Identity *identityConfiguration `json:"identity,omitempty"`
+}
+
+type aesConfiguration struct {
+	Keys []keyConfiguration `json:"keys"`
+}
+
+type keyConfiguration struct {
+	Name   string `json:"name"`
+	Secret string `json:"secret"`
+}
+
+type kmsConfiguration struct {
+	Name      string           `json:"name"`
+	Endpoint  string           `json:"endpoint"`
+	CacheSize int32            `json:"cachesize,omitempty"`
+	Timeout   *metav1.Duration `json:"timeout,omitempty"`
+}
+
+type identityConfiguration struct{}
+
+// ReconcileConfigSecret renders the EncryptionConfiguration for the given
+// encryption state. The write key is the first provider; the remaining keys
+// and the identity provider are only used to read resources which haven't
+// been migrated to the write key yet.
+func ReconcileConfigSecret(secret *corev1.Secret, ownerRef config.OwnerRef, state *State) error {
+	ownerRef.ApplyTo(secret)
+	serializedConfig, err := json.Marshal(encryptionConfig(state))
+	if err != nil {
+		return fmt.Errorf("failed to serialize encryption config: %w", err)
+	}
+	secret.Data = map[string][]byte{
+		EncryptionConfigKey: serializedConfig,
+	}
+	return nil
+}
+
+// ConfigHash returns the hash of the EncryptionConfiguration rendered for the
+// state, which matches the hash of the config secret reconciled for it.
+func (s *State) ConfigHash() (string, error) {
+	secret := &corev1.Secret{}
+	if err := ReconcileConfigSecret(secret, config.OwnerRef{}, s); err != nil {
+		return "", err
+	}
+	return ConfigHash(secret), nil
+}
+
+// StateFromConfigSecret recovers the encryption state from the rendered
+// EncryptionConfiguration when the keys secret is lost. The keys of aes
+// providers are read back from the configuration. kms keys can't be recovered,
+// since the configuration only holds the endpoint of their KMS plugin. Which
+// resources were migrated isn't recorded in the configuration either, so the
+// recovered state migrates all resources to the write key again.
+func StateFromConfigSecret(secret *corev1.Secret, rotation string) (*State, error) {
+	rendered := &encryptionConfiguration{}
+	if err := json.Unmarshal(secret.Data[EncryptionConfigKey], rendered); err != nil {
+		return nil, fmt.Errorf("failed to parse encryption config: %w", err)
+	}
+	if len(rendered.Resources) != 1 || len(rendered.Resources[0].Providers) == 0 {
+		return nil, fmt.Errorf("unexpected encryption config")
+	}
+	state := &State{ObservedRotation: rotation}
+	for i, provider := range rendered.Resources[0].Providers {
+		var name string
+		switch {
+		case provider.Identity != nil:
+			name = IdentityKey
+		case provider.KMS != nil:
+			return nil, fmt.Errorf("kms encryption key %s can't be recovered from the encryption config", provider.KMS.Name)
+		default:
+			aes := provider.AESCBC
+			if provider.AESGCM != nil {
+				aes = provider.AESGCM
+			}
+			if aes == nil || len(aes.Keys) != 1 {
+				return nil, fmt.Errorf("unexpected encryption config provider")
+			}
+			key, err := parseKeyName(aes.Keys[0].Name)
+			if err != nil {
+				return nil, err
+			}
+			if key.Secret, err = base64.StdEncoding.DecodeString(aes.Keys[0].Secret); err != nil {
+				return nil, fmt.Errorf("invalid encryption key %s: %w", key.Name, err)
+			}
+			if err := key.validate(); err != nil {
+				return nil, err
+			}
+			state.Keys = append(state.Keys, key)
+			name = key.Name
+		}
+		if i == 0 {
+			state.WriteKey = name
+		}
+	}
+	sort.Slice(state.Keys, func(i, j int) bool { return state.Keys[i].Index < state.Keys[j].Index })
+
+	state.MigratedKey = IdentityKey
+	if state.WriteKey == IdentityKey {
+		if newest := state.newestKey(); newest != nil {
+			state.MigratedKey = newest.Name
+		}
+	}
+	return state, nil
+}
+
+func encryptionConfig(state *State) *encryptionConfiguration {
+	var providers []providerConfiguration
+	if state.WriteKey == IdentityKey {
+		providers = append(providers, providerConfiguration{Identity: &identityConfiguration{}})
+	} else {
+		providers = append(providers, keyProvider(*state.key(state.WriteKey)))
+	}
+	for _, key := range state.ReadKeys() {
+		providers = append(providers, keyProvider(key))
+	}
+	if state.WriteKey != IdentityKey {
+		providers = append(providers, providerConfiguration{Identity: &identityConfiguration{}})
+	}
+	return &encryptionConfiguration{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "EncryptionConfiguration",
+			APIVersion: "apiserver.config.k8s.io/v1",
+		},
+		Resources: []resourceConfiguration{
+			{
+				Resources: EncryptedResources,
+				Providers: providers,
+			},
+		},
+	}
+}
+
+func keyProvider(key Key) providerConfiguration {
+	if key.Type == KMSKeyType {
+		return providerConfiguration{
+			KMS: &kmsConfiguration{
+				Name:      key.Name,
+				Endpoint:  kmsEndpoint(key),
+				CacheSize: kmsCacheSize,
+				Timeout:   &metav1.Duration{Duration: kmsPluginTimeout},
+			},
+		}
+	}
+	aes := &aesConfiguration{
+		Keys: []keyConfiguration{
+			{
+				Name:   key.Name,
+				Secret: base64.StdEncoding.EncodeToString(key.Secret),
+			},
+		},
+	}
+	if key.Type == config.EncryptionTypeAESGCM {
+		return providerConfiguration{AESGCM: aes}
+	}
+	return providerConfiguration{AESCBC: aes}
+}
+
+// ConfigHash returns the hash of the rendered EncryptionConfiguration.
+func ConfigHash(secret *corev1.Secret) string {
+	return util.ComputeHash(string(secret.Data[EncryptionConfigKey]))
+}
+
+// IsRolledOut returns true if all pods of the deployment run with the
+// EncryptionConfiguration of the given hash.
+func IsRolledOut(deployment *appsv1.Deployment, configHash string) bool {
+	if deployment.Spec.Template.Annotations[ConfigHashAnnotation] != configHash {
+		return false
+	}
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.Replicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
@@ -0,0 +1,174 @@
+package encryption
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+)
+
+func renderProviders(t *testing.T, state *State) []map[string]interface{} {
+	secret := &corev1.Secret{}
+	if err := ReconcileConfigSecret(secret, config.OwnerRef{}, state); err != nil {
+		t.Fatalf("failed to render encryption config: %v", err)
+	}
+	rendered := struct {
+		Kind      string `json:"kind"`
+		Resources []struct {
+			Resources []string                 `json:"resources"`
+			Providers []map[string]interface{} `json:"providers"`
+		} `json:"resources"`
+	}{}
+	if err := json.Unmarshal(secret.Data[EncryptionConfigKey], &rendered); err != nil {
+		t.Fatalf("failed to parse encryption config: %v", err)
+	}
+	if rendered.Kind != "EncryptionConfiguration" || len(rendered.Resources) != 1 {
+		t.Fatalf("unexpected encryption config: %s", secret.Data[EncryptionConfigKey])
+	}
+	return rendered.Resources[0].Providers
+}
+
+func providerNames(providers []map[string]interface{}) []string {
+	var names []string
+	for _, provider := range providers {
+		for providerType, providerConfig := range provider {
+			if providerType == "identity" {
+				names = append(names, providerType)
+				continue
+			}
+			if providerType == "kms" {
+				names = append(names, providerType+":"+providerConfig.(map[string]interface{})["name"].(string))
+				continue
+			}
+			key := providerConfig.(map[string]interface{})["keys"].([]interface{})[0].(map[string]interface{})
+			names = append(names, providerType+":"+key["name"].(string))
+		}
+	}
+	return names
+}
+
+func TestEncryptionConfig(t *testing.T) {
+	generateKey := testKeyGenerator()
+	unencrypted := &State{WriteKey: IdentityKey, MigratedKey: IdentityKey}
+	encrypted, err := NewState(aescbc, "", generateKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rotating := &State{WriteKey: "aescbc-1", MigratedKey: "aescbc-1", Keys: append([]Key{}, encrypted.Keys...)}
+	if _, err := rotating.Sync(aesgcm, "", true, "", generateKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	promoted := &State{WriteKey: "aesgcm-2", MigratedKey: "aescbc-1", Keys: rotating.Keys}
+
+	tests := map[string]struct {
+		state    *State
+		expected []string
+	}{
+		"unencrypted resources are only written with identity": {
+			state:    unencrypted,
+			expected: []string{"identity"},
+		},
+		"encrypted resources can still be read unencrypted": {
+			state:    encrypted,
+			expected: []string{"aescbc:aescbc-1", "identity"},
+		},
+		"new key is only read until it is rolled out": {
+			state:    rotating,
+			expected: []string{"aescbc:aescbc-1", "aesgcm:aesgcm-2", "identity"},
+		},
+		"new key is written once it is rolled out": {
+			state:    promoted,
+			expected: []string{"aesgcm:aesgcm-2", "aescbc:aescbc-1", "identity"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(providerNames(renderProviders(t, test.state))).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestConfigHash(t *testing.T) {
+	g := NewGomegaWithT(t)
+	state, err := NewState(aescbc, "", testKeyGenerator())
+	g.Expect(err).ToNot(HaveOccurred())
+	secret := &corev1.Secret{}
+	g.Expect(ReconcileConfigSecret(secret, config.OwnerRef{}, state)).To(Succeed())
+	hash, err := state.ConfigHash()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hash).To(Equal(ConfigHash(secret)))
+}
+
+func TestStateFromConfigSecret(t *testing.T) {
+	generateKey := testKeyGenerator()
+	encrypted, err := NewState(aescbc, "", generateKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rotating := &State{WriteKey: "aescbc-1", MigratedKey: "aescbc-1", Keys: append([]Key{}, encrypted.Keys...)}
+	if _, err := rotating.Sync(aesgcm, "", true, "", generateKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	promoted := &State{WriteKey: "aesgcm-2", MigratedKey: "aescbc-1", Keys: rotating.Keys}
+	decrypting := &State{WriteKey: IdentityKey, MigratedKey: "aescbc-1", Keys: encrypted.Keys}
+	kms, err := NewState(awsKMS("arn:aws:kms:us-east-1:123456789012:key/1"), "", generateKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := map[string]struct {
+		state               *State
+		expectedMigratedKey string
+		expectErr           bool
+	}{
+		"unencrypted state is recovered": {
+			state:               &State{WriteKey: IdentityKey, MigratedKey: IdentityKey},
+			expectedMigratedKey: IdentityKey,
+		},
+		"encrypted state is migrated again": {
+			state:               encrypted,
+			expectedMigratedKey: IdentityKey,
+		},
+		"read key is recovered": {
+			state:               rotating,
+			expectedMigratedKey: IdentityKey,
+		},
+		"promoted key is recovered": {
+			state:               promoted,
+			expectedMigratedKey: IdentityKey,
+		},
+		"keys are kept while migrating to identity": {
+			state:               decrypting,
+			expectedMigratedKey: "aescbc-1",
+		},
+		"kms keys can't be recovered": {
+			state:     kms,
+			expectErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			secret := &corev1.Secret{}
+			g.Expect(ReconcileConfigSecret(secret, config.OwnerRef{}, test.state)).To(Succeed())
+			state, err := StateFromConfigSecret(secret, "2021-01-01")
+			if test.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(state.WriteKey).To(Equal(test.state.WriteKey))
+			g.Expect(state.MigratedKey).To(Equal(test.expectedMigratedKey))
+			g.Expect(state.ObservedRotation).To(Equal("2021-01-01"))
+			g.Expect(state.Keys).To(Equal(test.state.Keys))
+			hash, err := state.ConfigHash()
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(hash).To(Equal(ConfigHash(secret)))
+		})
+	}
+}
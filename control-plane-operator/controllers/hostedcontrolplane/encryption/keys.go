@@ -0,0 +1,333 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	configv1 "github.com/openshift/api/config/v1"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+)
+
+const (
+	// writeKeyAnnotation names the key resources are encrypted with when they
+	// are written.
+	writeKeyAnnotation = "hypershift.openshift.io/encryption-write-key"
+
+	// migratedKeyAnnotation names the key all existing resources have been
+	// rewritten with.
+	migratedKeyAnnotation = "hypershift.openshift.io/encryption-migrated-key"
+
+	// observedRotationAnnotation is the value of the key rotation annotation on
+	// the HostedControlPlane which the newest key was generated for.
+	observedRotationAnnotation = "hypershift.openshift.io/encryption-observed-rotation"
+
+	// IdentityKey is the name used for the identity provider, which stores
+	// resources unencrypted.
+	IdentityKey = "identity"
+
+	keySize = 32
+
+	EncryptionReasonAsExpected = "AsExpected"
+	EncryptionReasonKeysLost   = "EncryptionKeysLost"
+)
+
+// KMSKeyType is the type of keys which encrypt resources with data encryption
+// keys protected by an external key management service. Instead of a secret,
+// a kms key stores the configuration of its KMS plugin.
+const KMSKeyType configv1.EncryptionType = "kms"
+
+// Desired is the encryption requested for a control plane.
+type Desired struct {
+	// Type is the type of key resources are encrypted with.
+	Type configv1.EncryptionType
+
+	// KMS configures the key management service of kms keys.
+	KMS *hyperv1.KMSSpec
+}
+
+// Key is a key used to encrypt resources at rest. Keys are named after their
+// type and an index which increases with every new key.
+type Key struct {
+	Name   string
+	Type   configv1.EncryptionType
+	Index  int
+	Secret []byte
+}
+
+// State is the set of encryption keys of a hosted control plane and the
+// progress of moving resources from one key to another.
+type State struct {
+	// Keys are the keys which may still be needed to read resources, oldest
+	// first.
+	Keys []Key
+
+	// WriteKey is the key new and updated resources are encrypted with.
+	WriteKey string
+
+	// MigratedKey is the key all existing resources are encrypted with. Until
+	// it equals WriteKey, resources written with older keys remain.
+	MigratedKey string
+
+	// ObservedRotation is the rotation request the newest key was generated for.
+	ObservedRotation string
+}
+
+// NewState returns the state of a control plane which has never stored any
+// resources, which can start out writing with the desired encryption without
+// migrating anything.
+func NewState(desired Desired, rotation string, generateKey func() ([]byte, error)) (*State, error) {
+	state := &State{WriteKey: IdentityKey, MigratedKey: IdentityKey}
+	if desired.Type == configv1.EncryptionTypeIdentity {
+		return state, nil
+	}
+	key, err := state.addKey(desired, rotation, generateKey)
+	if err != nil {
+		return nil, err
+	}
+	state.WriteKey = key.Name
+	state.MigratedKey = key.Name
+	return state, nil
+}
+
+// StateFromSecret reads the encryption state from the keys secret. A secret
+// without state describes a control plane which stores resources unencrypted.
+func StateFromSecret(secret *corev1.Secret) (*State, error) {
+	state := &State{
+		WriteKey:         secret.Annotations[writeKeyAnnotation],
+		MigratedKey:      secret.Annotations[migratedKeyAnnotation],
+		ObservedRotation: secret.Annotations[observedRotationAnnotation],
+	}
+	if state.WriteKey == "" {
+		state.WriteKey = IdentityKey
+	}
+	if state.MigratedKey == "" {
+		state.MigratedKey = IdentityKey
+	}
+	for name, secret := range secret.Data {
+		key, err := parseKeyName(name)
+		if err != nil {
+			return nil, err
+		}
+		key.Secret = secret
+		if err := key.validate(); err != nil {
+			return nil, err
+		}
+		state.Keys = append(state.Keys, key)
+	}
+	sort.Slice(state.Keys, func(i, j int) bool { return state.Keys[i].Index < state.Keys[j].Index })
+	for _, name := range []string{state.WriteKey, state.MigratedKey} {
+		if name != IdentityKey && state.key(name) == nil {
+			return nil, fmt.Errorf("encryption key %s is missing from the keys secret", name)
+		}
+	}
+	return state, nil
+}
+
+// ApplyTo stores the encryption state in the keys secret.
+func (s *State) ApplyTo(secret *corev1.Secret) {
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[writeKeyAnnotation] = s.WriteKey
+	secret.Annotations[migratedKeyAnnotation] = s.MigratedKey
+	secret.Annotations[observedRotationAnnotation] = s.ObservedRotation
+	secret.Data = map[string][]byte{}
+	for _, key := range s.Keys {
+		secret.Data[key.Name] = key.Secret
+	}
+}
+
+// NeedsMigration returns true if resources encrypted with other keys than the
+// write key may remain.
+func (s *State) NeedsMigration() bool {
+	return s.WriteKey != s.MigratedKey
+}
+
+// Sync moves the state one step towards encrypting every resource with a key
+// of the desired type, generating a new key when the type changes or a
+// rotation is requested. A kms key is also replaced when its KMS configuration
+// changes, since resources must be readable with the previous configuration
+// until they are migrated. Since every step changes the EncryptionConfiguration,
+// each one waits until the configuration of the current state has been rolled
+// out to all apiservers:
+//
+//  1. A new key is added as a read-only key, so all apiservers can read
+//     resources encrypted with it.
+//  2. The new key becomes the write key.
+//  3. All resources are migrated to the write key. Sync returns true while the
+//     migration should run, until migratedKey reports it has completed.
+//  4. Keys other than the write key are no longer needed and are removed.
+func (s *State) Sync(desired Desired, rotation string, rolledOut bool, migratedKey string, generateKey func() ([]byte, error)) (bool, error) {
+	target := IdentityKey
+	if desired.Type != configv1.EncryptionTypeIdentity {
+		newest := s.newestKey()
+		if newest == nil || !desired.matches(*newest) || s.ObservedRotation != rotation {
+			_, err := s.addKey(desired, rotation, generateKey)
+			return false, err
+		}
+		target = newest.Name
+	}
+
+	switch {
+	case !rolledOut:
+		// Wait for the apiservers to pick up the current configuration
+	case s.WriteKey != target:
+		s.WriteKey = target
+	case s.NeedsMigration():
+		if migratedKey != s.WriteKey {
+			return true, nil
+		}
+		s.MigratedKey = s.WriteKey
+	default:
+		s.pruneKeys()
+	}
+	return false, nil
+}
+
+// ReadKeys returns the keys other than the write key, newest first.
+func (s *State) ReadKeys() []Key {
+	var keys []Key
+	for i := len(s.Keys) - 1; i >= 0; i-- {
+		if s.Keys[i].Name != s.WriteKey {
+			keys = append(keys, s.Keys[i])
+		}
+	}
+	return keys
+}
+
+func (s *State) key(name string) *Key {
+	for i := range s.Keys {
+		if s.Keys[i].Name == name {
+			return &s.Keys[i]
+		}
+	}
+	return nil
+}
+
+func (s *State) newestKey() *Key {
+	if len(s.Keys) == 0 {
+		return nil
+	}
+	return &s.Keys[len(s.Keys)-1]
+}
+
+func (s *State) addKey(desired Desired, rotation string, generateKey func() ([]byte, error)) (*Key, error) {
+	secret, err := desired.newSecret(generateKey)
+	if err != nil {
+		return nil, err
+	}
+	index := 1
+	if newest := s.newestKey(); newest != nil {
+		index = newest.Index + 1
+	}
+	s.Keys = append(s.Keys, Key{
+		Name:   fmt.Sprintf("%s-%d", desired.Type, index),
+		Type:   desired.Type,
+		Index:  index,
+		Secret: secret,
+	})
+	s.ObservedRotation = rotation
+	return s.newestKey(), nil
+}
+
+func (s *State) pruneKeys() {
+	var keys []Key
+	for _, key := range s.Keys {
+		if key.Name == s.WriteKey {
+			keys = append(keys, key)
+		}
+	}
+	s.Keys = keys
+}
+
+func parseKeyName(name string) (Key, error) {
+	separator := strings.LastIndex(name, "-")
+	if separator < 0 {
+		return Key{}, fmt.Errorf("invalid encryption key name %s", name)
+	}
+	index, err := strconv.Atoi(name[separator+1:])
+	if err != nil || index < 1 {
+		return Key{}, fmt.Errorf("invalid encryption key name %s", name)
+	}
+	keyType := configv1.EncryptionType(name[:separator])
+	if keyType != configv1.EncryptionTypeAESCBC && keyType != config.EncryptionTypeAESGCM && keyType != KMSKeyType {
+		return Key{}, fmt.Errorf("unsupported type for encryption key %s", name)
+	}
+	return Key{Name: name, Type: keyType, Index: index}, nil
+}
+
+func (k Key) validate() error {
+	if k.Type == KMSKeyType {
+		if _, err := k.kmsConfig(); err != nil {
+			return fmt.Errorf("invalid kms key %s: %w", k.Name, err)
+		}
+		return nil
+	}
+	if len(k.Secret) != keySize {
+		return fmt.Errorf("encryption key %s must be %d bytes long", k.Name, keySize)
+	}
+	return nil
+}
+
+// matches returns true if resources encrypted with the key are encrypted the
+// desired way.
+func (d Desired) matches(key Key) bool {
+	if key.Type != d.Type {
+		return false
+	}
+	if d.Type != KMSKeyType {
+		return true
+	}
+	kms, err := key.kmsConfig()
+	return err == nil && equality.Semantic.DeepEqual(kms.KMSSpec, *d.KMS)
+}
+
+func (d Desired) newSecret(generateKey func() ([]byte, error)) ([]byte, error) {
+	if d.Type == KMSKeyType {
+		return json.Marshal(kmsConfig{KMSSpec: *d.KMS})
+	}
+	secret, err := generateKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+	return secret, nil
+}
+
+// GenerateKey returns a new random 256 bit key.
+func GenerateKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// DesiredEncryption returns the encryption requested for the control plane.
+// A KMS configured on the control plane takes precedence over the encryption
+// type of the APIServer configuration, which defaults to identity.
+func DesiredEncryption(hcp *hyperv1.HostedControlPlane, apiServer *configv1.APIServer) (Desired, error) {
+	if hcp.Spec.SecretEncryption != nil && hcp.Spec.SecretEncryption.KMS != nil {
+		kms := hcp.Spec.SecretEncryption.KMS
+		if err := validateKMSSpec(kms); err != nil {
+			return Desired{}, err
+		}
+		return Desired{Type: KMSKeyType, KMS: kms}, nil
+	}
+	if apiServer == nil || apiServer.Spec.Encryption.Type == "" {
+		return Desired{Type: configv1.EncryptionTypeIdentity}, nil
+	}
+	return Desired{Type: apiServer.Spec.Encryption.Type}, nil
+}
+
+// RequestedRotation returns the key rotation requested on the control plane.
+func RequestedRotation(hcp *hyperv1.HostedControlPlane) string {
+	return hcp.Annotations[hyperv1.EncryptionKeyRotationAnnotation]
+}
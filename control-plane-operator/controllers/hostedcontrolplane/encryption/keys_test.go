@@ -0,0 +1,157 @@
+package encryption
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+)
+
+var (
+	identity = Desired{Type: configv1.EncryptionTypeIdentity}
+	aescbc   = Desired{Type: configv1.EncryptionTypeAESCBC}
+	aesgcm   = Desired{Type: config.EncryptionTypeAESGCM}
+)
+
+func testKeyGenerator() func() ([]byte, error) {
+	var generated byte
+	return func() ([]byte, error) {
+		generated++
+		return bytes.Repeat([]byte{generated}, keySize), nil
+	}
+}
+
+func keyNames(state *State) []string {
+	var names []string
+	for _, key := range state.Keys {
+		names = append(names, key.Name)
+	}
+	return names
+}
+
+func TestSyncEnablesEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+	generateKey := testKeyGenerator()
+	state := &State{WriteKey: IdentityKey, MigratedKey: IdentityKey}
+
+	// The new key is only readable until the configuration is rolled out
+	migrate, err := state.Sync(aescbc, "", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(migrate).To(BeFalse())
+	g.Expect(keyNames(state)).To(Equal([]string{"aescbc-1"}))
+	g.Expect(state.WriteKey).To(Equal(IdentityKey))
+
+	_, err = state.Sync(aescbc, "", false, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(state.WriteKey).To(Equal(IdentityKey))
+
+	_, err = state.Sync(aescbc, "", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(state.WriteKey).To(Equal("aescbc-1"))
+	g.Expect(state.NeedsMigration()).To(BeTrue())
+
+	// Resources are migrated once the write key is rolled out
+	migrate, err = state.Sync(aescbc, "", false, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(migrate).To(BeFalse())
+
+	migrate, err = state.Sync(aescbc, "", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(migrate).To(BeTrue())
+
+	migrate, err = state.Sync(aescbc, "", true, "aescbc-1", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(migrate).To(BeFalse())
+	g.Expect(state.NeedsMigration()).To(BeFalse())
+	g.Expect(keyNames(state)).To(Equal([]string{"aescbc-1"}))
+}
+
+func TestSyncRotatesKey(t *testing.T) {
+	g := NewGomegaWithT(t)
+	generateKey := testKeyGenerator()
+	state, err := NewState(aescbc, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(state.WriteKey).To(Equal("aescbc-1"))
+	g.Expect(state.NeedsMigration()).To(BeFalse())
+
+	// Requesting a rotation or another encryption type adds a new key
+	_, err = state.Sync(aescbc, "2021-01-01", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(keyNames(state)).To(Equal([]string{"aescbc-1", "aescbc-2"}))
+	g.Expect(state.ObservedRotation).To(Equal("2021-01-01"))
+
+	_, err = state.Sync(aesgcm, "2021-01-01", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(keyNames(state)).To(Equal([]string{"aescbc-1", "aescbc-2", "aesgcm-3"}))
+	g.Expect(state.WriteKey).To(Equal("aescbc-1"))
+
+	_, err = state.Sync(aesgcm, "2021-01-01", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(state.WriteKey).To(Equal("aesgcm-3"))
+
+	// A migration to an older key doesn't complete the migration
+	migrate, err := state.Sync(aesgcm, "2021-01-01", true, "aescbc-1", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(migrate).To(BeTrue())
+
+	_, err = state.Sync(aesgcm, "2021-01-01", true, "aesgcm-3", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(state.MigratedKey).To(Equal("aesgcm-3"))
+	g.Expect(keyNames(state)).To(Equal([]string{"aescbc-1", "aescbc-2", "aesgcm-3"}))
+
+	// Older keys are removed once the migration is done
+	_, err = state.Sync(aesgcm, "2021-01-01", true, "aesgcm-3", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(keyNames(state)).To(Equal([]string{"aesgcm-3"}))
+}
+
+func TestSyncDisablesEncryption(t *testing.T) {
+	g := NewGomegaWithT(t)
+	generateKey := testKeyGenerator()
+	state, err := NewState(aesgcm, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = state.Sync(identity, "", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(state.WriteKey).To(Equal(IdentityKey))
+	g.Expect(keyNames(state)).To(Equal([]string{"aesgcm-1"}))
+
+	migrate, err := state.Sync(identity, "", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(migrate).To(BeTrue())
+
+	_, err = state.Sync(identity, "", true, IdentityKey, generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = state.Sync(identity, "", true, IdentityKey, generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(state.Keys).To(BeEmpty())
+}
+
+func TestStateFromSecret(t *testing.T) {
+	g := NewGomegaWithT(t)
+	generateKey := testKeyGenerator()
+	state, err := NewState(aescbc, "1", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = state.Sync(aesgcm, "1", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	secret := &corev1.Secret{}
+	state.ApplyTo(secret)
+	read, err := StateFromSecret(secret)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(read).To(Equal(state))
+
+	secret.Data["aescbc-3"] = []byte("short")
+	_, err = StateFromSecret(secret)
+	g.Expect(err).To(HaveOccurred())
+
+	delete(secret.Data, "aescbc-3")
+	delete(secret.Data, "aescbc-1")
+	_, err = StateFromSecret(secret)
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,243 @@
+package encryption
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/pki"
+)
+
+const (
+	// KMSServiceAccountNamespace and KMSServiceAccountName identify the service
+	// account of the hosted cluster the KMS plugins authenticate as. Roles used
+	// for KMS must trust tokens issued for it.
+	KMSServiceAccountNamespace = "kube-system"
+	KMSServiceAccountName      = "kms-provider"
+
+	// kmsTokenAudience is the audience AWS roles trusting the OIDC provider of
+	// the hosted cluster expect.
+	kmsTokenAudience = "openshift"
+
+	// kmsTokenLifetime is how long a KMS plugin token is valid. Tokens are
+	// replaced once less than half of their lifetime remains.
+	kmsTokenLifetime = 24 * time.Hour
+
+	// KMSTokenRefreshInterval is how often the KMS plugin token should be
+	// checked for expiration.
+	KMSTokenRefreshInterval = time.Hour
+
+	// tokenExpirationAnnotation is set on the KMS plugin credentials secret to
+	// the expiration time of its token.
+	tokenExpirationAnnotation = "hypershift.openshift.io/token-expiration"
+
+	// tokenSigningKeyHashAnnotation is set on the KMS plugin credentials secret
+	// to the hash of the service account signing key its token was signed with.
+	tokenSigningKeyHashAnnotation = "hypershift.openshift.io/token-signing-key-hash"
+
+	kmsSocketDir      = "/var/run/kmsplugin"
+	kmsCredentialsDir = "/etc/kms-plugin"
+	kmsPluginTimeout  = 3 * time.Second
+	kmsCacheSize      = 1000
+)
+
+// kmsConfig is stored in place of a secret in kms keys.
+type kmsConfig struct {
+	hyperv1.KMSSpec `json:",inline"`
+}
+
+func (k Key) kmsConfig() (*kmsConfig, error) {
+	kms := &kmsConfig{}
+	if err := json.Unmarshal(k.Secret, kms); err != nil {
+		return nil, err
+	}
+	if err := validateKMSSpec(&kms.KMSSpec); err != nil {
+		return nil, err
+	}
+	return kms, nil
+}
+
+func validateKMSSpec(kms *hyperv1.KMSSpec) error {
+	switch kms.Provider {
+	case hyperv1.AWSKMSProvider:
+		if kms.AWS == nil || kms.AWS.KeyARN == "" || kms.AWS.Region == "" || kms.AWS.Auth.RoleARN == "" {
+			return fmt.Errorf("the AWS KMS provider requires a key ARN, region and role ARN")
+		}
+	default:
+		return fmt.Errorf("unsupported KMS provider %q", kms.Provider)
+	}
+	return nil
+}
+
+// KMSKeys returns the kms keys which are used to read or write resources.
+func (s *State) KMSKeys() []Key {
+	var keys []Key
+	for _, key := range s.Keys {
+		if key.Type == KMSKeyType {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func kmsEndpoint(key Key) string {
+	return "unix://" + kmsSocket(key)
+}
+
+func kmsSocket(key Key) string {
+	return path.Join(kmsSocketDir, key.Name+".sock")
+}
+
+func kmsCredentialsKey(key Key) string {
+	return key.Name + ".credentials"
+}
+
+func kmsVolumeSocket() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "kms-socket",
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+func kmsVolumeCredentials() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "kms-credentials",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: manifests.KMSPluginCredentialsSecret("").Name,
+			},
+		},
+	}
+}
+
+func kmsVolumeToken() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "kms-token",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: manifests.KMSPluginCredentialsSecret("").Name,
+				Items: []corev1.KeyToPath{
+					{
+						Key:  config.AWSWebIdentityTokenKey,
+						Path: config.AWSWebIdentityTokenKey,
+					},
+				},
+			},
+		},
+	}
+}
+
+// ApplyKMSPlugins adds a KMS plugin sidecar for every kms key of the state to
+// an apiserver pod. Each plugin listens on a unix socket in a directory shared
+// with the apiserver container, at the endpoint of its key in the
+// EncryptionConfiguration.
+func ApplyKMSPlugins(podSpec *corev1.PodSpec, apiServerContainer, image string, state *State) error {
+	keys := state.KMSKeys()
+	if len(keys) == 0 {
+		return nil
+	}
+	var container *corev1.Container
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == apiServerContainer {
+			container = &podSpec.Containers[i]
+		}
+	}
+	if container == nil {
+		return fmt.Errorf("apiserver container %s not found", apiServerContainer)
+	}
+	socketMount := corev1.VolumeMount{Name: kmsVolumeSocket().Name, MountPath: kmsSocketDir}
+	container.VolumeMounts = append(container.VolumeMounts, socketMount)
+	podSpec.Volumes = append(podSpec.Volumes, *kmsVolumeSocket(), *kmsVolumeCredentials())
+
+	needsToken := false
+	for _, key := range keys {
+		kms, err := key.kmsConfig()
+		if err != nil {
+			return fmt.Errorf("invalid kms key %s: %w", key.Name, err)
+		}
+		plugin := corev1.Container{
+			Name:    "kms-plugin-" + strconv.Itoa(key.Index),
+			Image:   image,
+			Command: []string{"/usr/bin/hypershift", "kms-plugin"},
+			VolumeMounts: []corev1.VolumeMount{
+				socketMount,
+				{Name: kmsVolumeCredentials().Name, MountPath: kmsCredentialsDir},
+			},
+		}
+		switch kms.Provider {
+		case hyperv1.AWSKMSProvider:
+			plugin.Args = []string{
+				"aws",
+				"--listen", kmsEndpoint(key),
+				"--key-arn", kms.AWS.KeyARN,
+				"--region", kms.AWS.Region,
+				"--credentials-file", path.Join(kmsCredentialsDir, kmsCredentialsKey(key)),
+			}
+			plugin.VolumeMounts = append(plugin.VolumeMounts, corev1.VolumeMount{Name: kmsVolumeToken().Name, MountPath: config.AWSWebIdentityTokenDir})
+			needsToken = true
+		}
+		podSpec.Containers = append(podSpec.Containers, plugin)
+	}
+	if needsToken {
+		podSpec.Volumes = append(podSpec.Volumes, *kmsVolumeToken())
+	}
+	return nil
+}
+
+// ReconcileKMSCredentialsSecret stores the credentials of the KMS plugins of
+// every kms key in the state. AWS plugins assume their role with a token for
+// the KMS service account, which is signed with the service account signing
+// key of the hosted cluster and replaced before it expires or when the signing
+// key changes, since tokens signed by a replaced key stop being trusted once
+// the signing key overlap has passed.
+func ReconcileKMSCredentialsSecret(secret *corev1.Secret, ownerRef config.OwnerRef, state *State, signingKey *corev1.Secret, issuer string, now time.Time) error {
+	ownerRef.ApplyTo(secret)
+	data := map[string][]byte{}
+	needsToken := false
+	for _, key := range state.KMSKeys() {
+		kms, err := key.kmsConfig()
+		if err != nil {
+			return fmt.Errorf("invalid kms key %s: %w", key.Name, err)
+		}
+		switch kms.Provider {
+		case hyperv1.AWSKMSProvider:
+			data[kmsCredentialsKey(key)] = []byte(config.AWSRoleCredentials(kms.AWS.Auth.RoleARN))
+			needsToken = true
+		}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	if !needsToken {
+		delete(secret.Annotations, tokenExpirationAnnotation)
+		delete(secret.Annotations, tokenSigningKeyHashAnnotation)
+		secret.Data = data
+		return nil
+	}
+
+	token := secret.Data[config.AWSWebIdentityTokenKey]
+	signingKeyHash := pki.ServiceAccountSigningKeyHash(signingKey)
+	expiration, err := time.Parse(time.RFC3339, secret.Annotations[tokenExpirationAnnotation])
+	if len(token) == 0 || err != nil || expiration.Sub(now) < kmsTokenLifetime/2 || secret.Annotations[tokenSigningKeyHashAnnotation] != signingKeyHash {
+		expiration = now.Add(kmsTokenLifetime)
+		signed, err := pki.ServiceAccountToken(signingKey, issuer, KMSServiceAccountNamespace, KMSServiceAccountName, kmsTokenAudience, now, expiration)
+		if err != nil {
+			return fmt.Errorf("failed to create KMS plugin token: %w", err)
+		}
+		token = []byte(signed)
+		secret.Annotations[tokenExpirationAnnotation] = expiration.UTC().Format(time.RFC3339)
+		secret.Annotations[tokenSigningKeyHashAnnotation] = signingKeyHash
+	}
+	data[config.AWSWebIdentityTokenKey] = token
+	secret.Data = data
+	return nil
+}
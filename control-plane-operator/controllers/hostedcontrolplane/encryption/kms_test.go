@@ -0,0 +1,179 @@
+package encryption
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/pki"
+	"github.com/openshift/hypershift/support/certs"
+)
+
+func awsKMS(keyARN string) Desired {
+	return Desired{
+		Type: KMSKeyType,
+		KMS: &hyperv1.KMSSpec{
+			Provider: hyperv1.AWSKMSProvider,
+			AWS: &hyperv1.AWSKMSSpec{
+				Region: "us-east-1",
+				KeyARN: keyARN,
+				Auth: hyperv1.AWSKMSAuthSpec{
+					RoleARN: "arn:aws:iam::123456789012:role/kms",
+				},
+			},
+		},
+	}
+}
+
+func TestSyncReplacesKMSKeyWhenConfigChanges(t *testing.T) {
+	g := NewGomegaWithT(t)
+	generateKey := testKeyGenerator()
+	state, err := NewState(aescbc, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = state.Sync(awsKMS("arn:aws:kms:us-east-1:123456789012:key/1"), "", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(keyNames(state)).To(Equal([]string{"aescbc-1", "kms-2"}))
+
+	// The same configuration doesn't add another key
+	_, err = state.Sync(awsKMS("arn:aws:kms:us-east-1:123456789012:key/1"), "", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(keyNames(state)).To(Equal([]string{"aescbc-1", "kms-2"}))
+	g.Expect(state.WriteKey).To(Equal("kms-2"))
+
+	_, err = state.Sync(awsKMS("arn:aws:kms:us-east-1:123456789012:key/2"), "", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(keyNames(state)).To(Equal([]string{"aescbc-1", "kms-2", "kms-3"}))
+
+	secret := &corev1.Secret{}
+	state.ApplyTo(secret)
+	read, err := StateFromSecret(secret)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(read).To(Equal(state))
+
+	g.Expect(providerNames(renderProviders(t, state))).To(Equal([]string{"kms:kms-2", "kms:kms-3", "aescbc:aescbc-1", "identity"}))
+}
+
+func TestDesiredEncryption(t *testing.T) {
+	hcp := &hyperv1.HostedControlPlane{}
+	hcp.Spec.SecretEncryption = &hyperv1.SecretEncryptionSpec{KMS: &hyperv1.KMSSpec{Provider: hyperv1.AWSKMSProvider}}
+
+	g := NewGomegaWithT(t)
+	_, err := DesiredEncryption(hcp, nil)
+	g.Expect(err).To(HaveOccurred())
+
+	hcp.Spec.SecretEncryption.KMS = awsKMS("arn:aws:kms:us-east-1:123456789012:key/1").KMS
+	desired, err := DesiredEncryption(hcp, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(desired.Type).To(Equal(KMSKeyType))
+}
+
+func TestApplyKMSPlugins(t *testing.T) {
+	g := NewGomegaWithT(t)
+	generateKey := testKeyGenerator()
+	state, err := NewState(awsKMS("arn:aws:kms:us-east-1:123456789012:key/1"), "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = state.Sync(awsKMS("arn:aws:kms:us-east-1:123456789012:key/2"), "", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	podSpec := &corev1.PodSpec{Containers: []corev1.Container{{Name: "apiserver"}}}
+	g.Expect(ApplyKMSPlugins(podSpec, "apiserver", "hypershift", state)).To(Succeed())
+
+	g.Expect(podSpec.Containers).To(HaveLen(3))
+	g.Expect(podSpec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: kmsVolumeSocket().Name, MountPath: kmsSocketDir}))
+	g.Expect(podSpec.Containers[1].Name).To(Equal("kms-plugin-1"))
+	g.Expect(podSpec.Containers[1].Args).To(ContainElement("unix:///var/run/kmsplugin/kms-1.sock"))
+	g.Expect(podSpec.Containers[1].Args[0]).To(Equal("aws"))
+	g.Expect(podSpec.Containers[1].Args).To(ContainElement("arn:aws:kms:us-east-1:123456789012:key/1"))
+	g.Expect(podSpec.Containers[2].Name).To(Equal("kms-plugin-2"))
+	g.Expect(podSpec.Containers[2].Args).To(ContainElement("unix:///var/run/kmsplugin/kms-2.sock"))
+	g.Expect(podSpec.Containers[2].Args).To(ContainElement("arn:aws:kms:us-east-1:123456789012:key/2"))
+
+	var volumes []string
+	for _, volume := range podSpec.Volumes {
+		volumes = append(volumes, volume.Name)
+	}
+	g.Expect(volumes).To(ConsistOf(kmsVolumeSocket().Name, kmsVolumeCredentials().Name, kmsVolumeToken().Name))
+}
+
+func TestReconcileKMSCredentialsSecret(t *testing.T) {
+	g := NewGomegaWithT(t)
+	key, err := certs.PrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+	signingKey := &corev1.Secret{Data: map[string][]byte{pki.ServiceSignerPrivateKey: certs.PrivateKeyToPem(key)}}
+
+	generateKey := testKeyGenerator()
+	state, err := NewState(awsKMS("arn:aws:kms:us-east-1:123456789012:key/1"), "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = state.Sync(awsKMS("arn:aws:kms:us-east-1:123456789012:key/2"), "", true, "", generateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{}
+	g.Expect(ReconcileKMSCredentialsSecret(secret, config.OwnerRef{}, state, signingKey, "https://issuer", now)).To(Succeed())
+	g.Expect(string(secret.Data["kms-1.credentials"])).To(ContainSubstring("role_arn = arn:aws:iam::123456789012:role/kms"))
+	g.Expect(secret.Data).To(HaveKey("kms-2.credentials"))
+
+	token := string(secret.Data[config.AWSWebIdentityTokenKey])
+	parts := strings.Split(token, ".")
+	g.Expect(parts).To(HaveLen(3))
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature)).To(Succeed())
+	serializedClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	g.Expect(err).ToNot(HaveOccurred())
+	claims := map[string]interface{}{}
+	g.Expect(json.Unmarshal(serializedClaims, &claims)).To(Succeed())
+	g.Expect(claims["iss"]).To(Equal("https://issuer"))
+	g.Expect(claims["sub"]).To(Equal("system:serviceaccount:kube-system:kms-provider"))
+
+	// The token is kept until less than half of its lifetime remains
+	g.Expect(ReconcileKMSCredentialsSecret(secret, config.OwnerRef{}, state, signingKey, "https://issuer", now.Add(time.Hour))).To(Succeed())
+	g.Expect(string(secret.Data[config.AWSWebIdentityTokenKey])).To(Equal(token))
+	g.Expect(ReconcileKMSCredentialsSecret(secret, config.OwnerRef{}, state, signingKey, "https://issuer", now.Add(13*time.Hour))).To(Succeed())
+	g.Expect(string(secret.Data[config.AWSWebIdentityTokenKey])).ToNot(Equal(token))
+}
+
+func TestReconcileKMSCredentialsSecretSigningKeyRotation(t *testing.T) {
+	g := NewGomegaWithT(t)
+	key, err := certs.PrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+	signingKey := &corev1.Secret{Data: map[string][]byte{pki.ServiceSignerPrivateKey: certs.PrivateKeyToPem(key)}}
+	state, err := NewState(awsKMS("arn:aws:kms:us-east-1:123456789012:key/1"), "", testKeyGenerator())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	secret := &corev1.Secret{}
+	g.Expect(ReconcileKMSCredentialsSecret(secret, config.OwnerRef{}, state, signingKey, "https://issuer", now)).To(Succeed())
+	token := string(secret.Data[config.AWSWebIdentityTokenKey])
+
+	// The token is signed again with the rotated signing key, long before it
+	// expires
+	rotated, err := certs.PrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+	signingKey.Data[pki.ServiceSignerPrivateKey] = certs.PrivateKeyToPem(rotated)
+	g.Expect(ReconcileKMSCredentialsSecret(secret, config.OwnerRef{}, state, signingKey, "https://issuer", now.Add(time.Minute))).To(Succeed())
+	rotatedToken := string(secret.Data[config.AWSWebIdentityTokenKey])
+	g.Expect(rotatedToken).ToNot(Equal(token))
+	parts := strings.Split(rotatedToken, ".")
+	g.Expect(parts).To(HaveLen(3))
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rsa.VerifyPKCS1v15(&rotated.PublicKey, crypto.SHA256, digest[:], signature)).To(Succeed())
+
+	g.Expect(ReconcileKMSCredentialsSecret(secret, config.OwnerRef{}, state, signingKey, "https://issuer", now.Add(2*time.Minute))).To(Succeed())
+	g.Expect(string(secret.Data[config.AWSWebIdentityTokenKey])).To(Equal(rotatedToken))
+}
@@ -0,0 +1,128 @@
+package encryption
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/util"
+)
+
+// migrationKeyAnnotation is set on the migration job to the key resources are
+// migrated to.
+const migrationKeyAnnotation = "hypershift.openshift.io/encryption-migration-key"
+
+var (
+	migrationJobVolumeMounts = util.PodVolumeMounts{
+		migrationJobContainer().Name: {
+			migrationJobVolumeKubeconfig().Name: "/etc/kubernetes/kubeconfig",
+		},
+	}
+)
+
+func migrationJobContainer() *corev1.Container {
+	return &corev1.Container{
+		Name: "migrate",
+	}
+}
+
+func migrationJobVolumeKubeconfig() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "kubeconfig",
+	}
+}
+
+func buildMigrationJobVolumeKubeconfig(v *corev1.Volume) {
+	v.Secret = &corev1.SecretVolumeSource{
+		SecretName: manifests.KASServiceKubeconfigSecret("").Name,
+	}
+}
+
+// ReconcileMigrationJob configures a job which rewrites every encrypted
+// resource of the hosted cluster, so that it is stored encrypted with the
+// given key.
+func ReconcileMigrationJob(job *batchv1.Job, ownerRef config.OwnerRef, key, image, kubeconfigKey string) {
+	ownerRef.ApplyTo(job)
+	if job.Annotations == nil {
+		job.Annotations = map[string]string{}
+	}
+	job.Annotations[migrationKeyAnnotation] = key
+	job.Spec.BackoffLimit = pointer.Int32Ptr(3)
+	job.Spec.Template.Spec = corev1.PodSpec{
+		RestartPolicy:                corev1.RestartPolicyNever,
+		AutomountServiceAccountToken: pointer.BoolPtr(false),
+		Containers: []corev1.Container{
+			util.BuildContainer(migrationJobContainer(), buildMigrationJobContainer(image, kubeconfigKey)),
+		},
+		Volumes: []corev1.Volume{
+			util.BuildVolume(migrationJobVolumeKubeconfig(), buildMigrationJobVolumeKubeconfig),
+		},
+	}
+}
+
+func buildMigrationJobContainer(image, kubeconfigKey string) func(c *corev1.Container) {
+	return func(c *corev1.Container) {
+		c.Image = image
+		c.Command = []string{"/bin/bash", "-c"}
+		c.Args = []string{migrationScript(EncryptedResources)}
+		c.Env = []corev1.EnvVar{
+			{
+				Name:  "KUBECONFIG",
+				Value: path.Join(migrationJobVolumeMounts.Path(c.Name, migrationJobVolumeKubeconfig().Name), kubeconfigKey),
+			},
+		}
+		c.VolumeMounts = migrationJobVolumeMounts.ContainerMounts(c.Name)
+	}
+}
+
+// migrationScript replaces every instance of the given resources with itself,
+// which makes the apiserver store it again with the current write key.
+// Resources which were changed or deleted since they were listed don't need to
+// be rewritten.
+func migrationScript(resources []string) string {
+	script := `set -euo pipefail
+for resource in %s; do
+  oc get "${resource}" --all-namespaces --no-headers -o custom-columns=NAMESPACE:.metadata.namespace,NAME:.metadata.name |
+  while read -r namespace name; do
+    if ! output="$(oc get "${resource}" -n "${namespace}" "${name}" -o json | oc replace -f - 2>&1)"; then
+      if ! grep -qiE "conflict|not ?found|has been modified" <<< "${output}"; then
+        echo "${output}"
+        exit 1
+      fi
+    fi
+  done
+  echo "Migrated ${resource}"
+done
+`
+	return fmt.Sprintf(script, strings.Join(resources, " "))
+}
+
+// MigratedKey returns the key the job has migrated all resources to, or an
+// empty string if the job hasn't succeeded.
+func MigratedKey(job *batchv1.Job) string {
+	if job.Status.Succeeded == 0 {
+		return ""
+	}
+	return job.Annotations[migrationKeyAnnotation]
+}
+
+// MigrationKey returns the key the job migrates resources to.
+func MigrationKey(job *batchv1.Job) string {
+	return job.Annotations[migrationKeyAnnotation]
+}
+
+// MigrationFailed returns true if the job gave up migrating resources.
+func MigrationFailed(job *batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,233 @@
+package etcd
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/pki"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/util"
+)
+
+var (
+	etcdBackupJobVolumeMounts = util.PodVolumeMounts{
+		etcdBackupJobContainer().Name: {
+			etcdBackupJobVolumeClientCert().Name: "/etc/etcd/tls/client",
+			etcdBackupJobVolumeSnapshots().Name:  "/var/lib/etcd-backup",
+		},
+		etcdUploadSnapshotContainer().Name: {
+			etcdBackupJobVolumeSnapshots().Name: "/var/lib/etcd-backup",
+			etcdVolumeS3Credentials().Name:      "/etc/etcd/s3",
+		},
+	}
+)
+
+func ReconcileBackupPVC(pvc *corev1.PersistentVolumeClaim, ownerRef config.OwnerRef, destination *hyperv1.EtcdBackupPersistentVolumeDestination) error {
+	ownerRef.ApplyTo(pvc)
+	// Only the requested size can be changed once the claim is bound
+	if pvc.CreationTimestamp.IsZero() {
+		pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+		pvc.Spec.StorageClassName = destination.StorageClassName
+	}
+	pvc.Spec.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceStorage: destination.Size,
+	}
+	return nil
+}
+
+func etcdBackupJobContainer() *corev1.Container {
+	return &corev1.Container{
+		Name: "etcd-backup",
+	}
+}
+
+func etcdBackupJobVolumeClientCert() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "client-tls",
+	}
+}
+
+func buildEtcdBackupJobVolumeClientCert(v *corev1.Volume) {
+	v.Secret = &corev1.SecretVolumeSource{
+		SecretName: manifests.EtcdClientSecret("").Name,
+	}
+}
+
+func etcdBackupJobVolumeSnapshots() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "snapshots",
+	}
+}
+
+func buildEtcdBackupJobVolumeSnapshotsPVC(v *corev1.Volume) {
+	v.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+		ClaimName: manifests.EtcdBackupPVC("").Name,
+	}
+}
+
+func buildEtcdBackupJobVolumeSnapshotsEmptyDir(v *corev1.Volume) {
+	v.EmptyDir = &corev1.EmptyDirVolumeSource{}
+}
+
+func etcdUploadSnapshotContainer() *corev1.Container {
+	return &corev1.Container{
+		Name: "upload-snapshot",
+	}
+}
+
+func buildEtcdBackupJobContainer(image string, script func(snapshotDir string) string) func(c *corev1.Container) {
+	return func(c *corev1.Container) {
+		certDir := etcdBackupJobVolumeMounts.Path(c.Name, etcdBackupJobVolumeClientCert().Name)
+		snapshotDir := etcdBackupJobVolumeMounts.Path(c.Name, etcdBackupJobVolumeSnapshots().Name)
+		c.Image = image
+		c.Command = []string{"/bin/sh", "-c"}
+		c.Args = []string{script(snapshotDir)}
+		c.Env = []corev1.EnvVar{
+			{
+				Name:  "ETCDCTL_API",
+				Value: "3",
+			},
+			{
+				Name:  "ETCDCTL_ENDPOINTS",
+				Value: config.DefaultEtcdURL,
+			},
+			{
+				Name:  "ETCDCTL_CACERT",
+				Value: path.Join(certDir, pki.EtcdClientCAKey),
+			},
+			{
+				Name:  "ETCDCTL_CERT",
+				Value: path.Join(certDir, pki.EtcdClientCrtKey),
+			},
+			{
+				Name:  "ETCDCTL_KEY",
+				Value: path.Join(certDir, pki.EtcdClientKeyKey),
+			},
+		}
+		c.VolumeMounts = etcdBackupJobVolumeMounts.ContainerMounts(c.Name)
+	}
+}
+
+func buildEtcdUploadSnapshotContainer(image string, s3 *hyperv1.EtcdBackupS3Destination, maxBackups int) func(c *corev1.Container) {
+	return func(c *corev1.Container) {
+		snapshotDir := etcdBackupJobVolumeMounts.Path(c.Name, etcdBackupJobVolumeSnapshots().Name)
+		c.Image = image
+		c.Command = []string{"/usr/bin/hypershift"}
+		c.Args = append([]string{
+			"etcd",
+			"upload-snapshot",
+			"--file",
+			path.Join(snapshotDir, etcdSnapshotFile),
+			"--prefix",
+			s3.Prefix,
+			"--max-backups",
+			strconv.Itoa(maxBackups),
+		}, s3Args(s3, etcdBackupJobVolumeMounts.Path(c.Name, etcdVolumeS3Credentials().Name))...)
+		c.VolumeMounts = etcdBackupJobVolumeMounts.ContainerMounts(c.Name)
+	}
+}
+
+// s3Args returns the arguments of the hypershift etcd commands which select
+// the bucket and the credentials mounted in credentialsDir.
+func s3Args(s3 *hyperv1.EtcdBackupS3Destination, credentialsDir string) []string {
+	args := []string{
+		"--bucket",
+		s3.Bucket,
+		"--credentials-file",
+		path.Join(credentialsDir, "credentials"),
+		"--config-file",
+		path.Join(credentialsDir, "config"),
+	}
+	if len(s3.Endpoint) > 0 {
+		args = append(args, "--endpoint", s3.Endpoint)
+	}
+	if s3.ForcePathStyle {
+		args = append(args, "--force-path-style")
+	}
+	return args
+}
+
+// etcdBackupScript saves a snapshot to snapshotDir and removes all but the
+// newest maxBackups snapshots.
+func etcdBackupScript(maxBackups int) func(snapshotDir string) string {
+	return func(snapshotDir string) string {
+		script := []string{
+			"set -eu",
+			fmt.Sprintf(`snapshot="%s/snapshot-$(date -u +%%Y%%m%%d%%H%%M%%S).db"`, snapshotDir),
+			`etcdctl snapshot save "${snapshot}.part"`,
+			`mv "${snapshot}.part" "${snapshot}"`,
+		}
+		if maxBackups > 0 {
+			script = append(script, fmt.Sprintf(`ls -1t %s/snapshot-*.db | tail -n +%d | xargs -r rm -f`, snapshotDir, maxBackups+1))
+		}
+		return strings.Join(script, "\n")
+	}
+}
+
+// etcdSnapshotScript saves a single snapshot to snapshotDir, to be uploaded
+// by another container.
+func etcdSnapshotScript(snapshotDir string) string {
+	return fmt.Sprintf("etcdctl snapshot save %s", path.Join(snapshotDir, etcdSnapshotFile))
+}
+
+// ReconcileBackupCronJob configures a job which periodically saves snapshots
+// of the etcd cluster to the destination of the backup policy. Snapshots are
+// uploaded to S3 with the snapshot image.
+func ReconcileBackupCronJob(cronJob *batchv1beta1.CronJob, ownerRef config.OwnerRef, policy *hyperv1.EtcdBackupPolicy, image, snapshotImage string) error {
+	ownerRef.ApplyTo(cronJob)
+	podSpec := corev1.PodSpec{
+		RestartPolicy:                corev1.RestartPolicyOnFailure,
+		AutomountServiceAccountToken: pointer.BoolPtr(false),
+		Volumes: []corev1.Volume{
+			util.BuildVolume(etcdBackupJobVolumeClientCert(), buildEtcdBackupJobVolumeClientCert),
+		},
+	}
+	switch policy.Destination.Type {
+	case hyperv1.PersistentVolumeEtcdBackupDestination:
+		podSpec.Containers = []corev1.Container{
+			util.BuildContainer(etcdBackupJobContainer(), buildEtcdBackupJobContainer(image, etcdBackupScript(policy.MaxBackups))),
+		}
+		podSpec.Volumes = append(podSpec.Volumes,
+			util.BuildVolume(etcdBackupJobVolumeSnapshots(), buildEtcdBackupJobVolumeSnapshotsPVC))
+	case hyperv1.S3EtcdBackupDestination:
+		s3 := policy.Destination.S3
+		if s3 == nil {
+			return fmt.Errorf("etcd backup destination %s requires S3 settings", hyperv1.S3EtcdBackupDestination)
+		}
+		podSpec.InitContainers = []corev1.Container{
+			util.BuildContainer(etcdBackupJobContainer(), buildEtcdBackupJobContainer(image, etcdSnapshotScript)),
+		}
+		podSpec.Containers = []corev1.Container{
+			util.BuildContainer(etcdUploadSnapshotContainer(), buildEtcdUploadSnapshotContainer(snapshotImage, s3, policy.MaxBackups)),
+		}
+		podSpec.Volumes = append(podSpec.Volumes,
+			util.BuildVolume(etcdBackupJobVolumeSnapshots(), buildEtcdBackupJobVolumeSnapshotsEmptyDir),
+			util.BuildVolume(etcdVolumeS3Credentials(), buildEtcdVolumeS3Credentials(s3.Credentials.Name)))
+	default:
+		return fmt.Errorf("unsupported etcd backup destination type: %s", policy.Destination.Type)
+	}
+	cronJob.Spec = batchv1beta1.CronJobSpec{
+		Schedule:                   fmt.Sprintf("@every %s", policy.Schedule.Duration),
+		ConcurrencyPolicy:          batchv1beta1.ForbidConcurrent,
+		SuccessfulJobsHistoryLimit: pointer.Int32Ptr(1),
+		FailedJobsHistoryLimit:     pointer.Int32Ptr(3),
+		JobTemplate: batchv1beta1.JobTemplateSpec{
+			Spec: batchv1.JobSpec{
+				BackoffLimit: pointer.Int32Ptr(2),
+				Template: corev1.PodTemplateSpec{
+					Spec: podSpec,
+				},
+			},
+		},
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package etcd
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+)
+
+func s3BackupPolicy() *hyperv1.EtcdBackupPolicy {
+	return &hyperv1.EtcdBackupPolicy{
+		Schedule:   metav1.Duration{Duration: 30 * time.Minute},
+		MaxBackups: 5,
+		Destination: hyperv1.EtcdBackupDestination{
+			Type: hyperv1.S3EtcdBackupDestination,
+			S3: &hyperv1.EtcdBackupS3Destination{
+				Bucket:      "backups",
+				Prefix:      "clusters/example",
+				Credentials: corev1.LocalObjectReference{Name: "etcd-backup-credentials"},
+			},
+		},
+	}
+}
+
+func TestReconcileBackupCronJobS3(t *testing.T) {
+	g := NewGomegaWithT(t)
+	cronJob := manifests.EtcdBackupCronJob("test")
+	g.Expect(ReconcileBackupCronJob(cronJob, config.OwnerRef{}, s3BackupPolicy(), "etcd", "hypershift")).To(Succeed())
+	g.Expect(cronJob.Spec.Schedule).To(Equal("@every 30m0s"))
+
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	g.Expect(podSpec.InitContainers).To(HaveLen(1))
+	g.Expect(podSpec.InitContainers[0].Image).To(Equal("etcd"))
+	g.Expect(podSpec.InitContainers[0].Args[0]).To(Equal("etcdctl snapshot save /var/lib/etcd-backup/snapshot.db"))
+	g.Expect(podSpec.Containers).To(HaveLen(1))
+	g.Expect(podSpec.Containers[0].Image).To(Equal("hypershift"))
+	g.Expect(podSpec.Containers[0].Args).To(Equal([]string{
+		"etcd", "upload-snapshot",
+		"--file", "/var/lib/etcd-backup/snapshot.db",
+		"--prefix", "clusters/example",
+		"--max-backups", "5",
+		"--bucket", "backups",
+		"--credentials-file", "/etc/etcd/s3/credentials",
+		"--config-file", "/etc/etcd/s3/config",
+	}))
+	var credentials *corev1.Volume
+	for i, v := range podSpec.Volumes {
+		if v.Name == etcdVolumeS3Credentials().Name {
+			credentials = &podSpec.Volumes[i]
+		}
+	}
+	g.Expect(credentials).ToNot(BeNil())
+	g.Expect(credentials.Secret.SecretName).To(Equal("etcd-backup-credentials"))
+}
+
+func TestReconcileBackupCronJobPersistentVolume(t *testing.T) {
+	g := NewGomegaWithT(t)
+	policy := &hyperv1.EtcdBackupPolicy{
+		Schedule: metav1.Duration{Duration: time.Hour},
+		Destination: hyperv1.EtcdBackupDestination{
+			Type: hyperv1.PersistentVolumeEtcdBackupDestination,
+			PersistentVolume: &hyperv1.EtcdBackupPersistentVolumeDestination{
+				Size: resource.MustParse("10Gi"),
+			},
+		},
+	}
+	cronJob := manifests.EtcdBackupCronJob("test")
+	g.Expect(ReconcileBackupCronJob(cronJob, config.OwnerRef{}, policy, "etcd", "hypershift")).To(Succeed())
+
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	g.Expect(podSpec.InitContainers).To(BeEmpty())
+	g.Expect(podSpec.Containers).To(HaveLen(1))
+	g.Expect(podSpec.Containers[0].Image).To(Equal("etcd"))
+	var snapshots *corev1.Volume
+	for i, v := range podSpec.Volumes {
+		if v.Name == etcdBackupJobVolumeSnapshots().Name {
+			snapshots = &podSpec.Volumes[i]
+		}
+	}
+	g.Expect(snapshots).ToNot(BeNil())
+	g.Expect(snapshots.PersistentVolumeClaim.ClaimName).To(Equal(manifests.EtcdBackupPVC("test").Name))
+
+	policy.Destination.Type = hyperv1.S3EtcdBackupDestination
+	g.Expect(ReconcileBackupCronJob(cronJob, config.OwnerRef{}, policy, "etcd", "hypershift")).ToNot(Succeed())
+}
+
+func TestEtcdBackupScript(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(etcdBackupScript(0)("/backup")).ToNot(ContainSubstring("rm -f"))
+	g.Expect(etcdBackupScript(3)("/backup")).To(ContainSubstring("ls -1t /backup/snapshot-*.db | tail -n +4 | xargs -r rm -f"))
+}
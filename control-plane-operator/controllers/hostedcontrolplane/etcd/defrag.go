@@ -0,0 +1,99 @@
+package etcd
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+const (
+	// etcdMinDefragBytes is the database size below which members are not
+	// defragmented, since there is little space to reclaim.
+	etcdMinDefragBytes = 100 * 1024 * 1024
+
+	// etcdMaxFragmentedPercentage is the percentage of the database which may
+	// be unused before a member is defragmented.
+	etcdMaxFragmentedPercentage = 45
+
+	// etcdMinDefragMembers is the number of members a cluster needs to keep
+	// quorum and serve requests while one of its members is defragmented.
+	etcdMinDefragMembers = 3
+
+	// etcdQuotaRiskPercentage is the percentage of the backend quota a member's
+	// database may use before the cluster is at risk of running out of space.
+	etcdQuotaRiskPercentage = 80
+
+	EtcdReasonQuotaRisk   = "EtcdDatabaseNearQuota"
+	EtcdReasonWithinQuota = "EtcdDatabaseWithinQuota"
+)
+
+// MemberDatabase is the observed size of the database of an etcd member.
+type MemberDatabase struct {
+	ID        uint64
+	Name      string
+	Endpoint  string
+	Size      int64
+	SizeInUse int64
+}
+
+// FragmentedPercentage returns the percentage of the database which is not in
+// use and can be reclaimed by defragmenting it.
+func (m *MemberDatabase) FragmentedPercentage() int64 {
+	if m.Size <= 0 {
+		return 0
+	}
+	return (m.Size - m.SizeInUse) * 100 / m.Size
+}
+
+// NeedsDefragmentation returns true if enough space can be reclaimed from the
+// database of the member to make defragmenting it worthwhile.
+func (m *MemberDatabase) NeedsDefragmentation() bool {
+	return m.Size >= etcdMinDefragBytes && m.FragmentedPercentage() >= etcdMaxFragmentedPercentage
+}
+
+// NextMemberToDefragment returns the member which should be defragmented next,
+// or nil if no member needs it. A member doesn't serve requests while it is
+// defragmented, so members are defragmented one at a time, followers first,
+// and the leader last to avoid repeated leader elections. Smaller clusters are
+// never defragmented automatically, since defragmenting any of their members
+// makes etcd unavailable.
+func NextMemberToDefragment(members []MemberDatabase, leader uint64) *MemberDatabase {
+	if len(members) < etcdMinDefragMembers {
+		return nil
+	}
+	var next *MemberDatabase
+	for i := range members {
+		if !members[i].NeedsDefragmentation() {
+			continue
+		}
+		if members[i].ID != leader {
+			return &members[i]
+		}
+		next = &members[i]
+	}
+	return next
+}
+
+// ComputeEtcdQuotaRiskCondition computes the EtcdQuotaRisk condition from the
+// observed database sizes of the etcd members. The cluster is at risk once the
+// database of any member gets close to the backend quota.
+func ComputeEtcdQuotaRiskCondition(status *hyperv1.ManagedEtcdStatus) metav1.Condition {
+	for _, member := range status.Members {
+		if status.QuotaBackendBytes > 0 && member.DBSizeBytes*100/status.QuotaBackendBytes >= etcdQuotaRiskPercentage {
+			return metav1.Condition{
+				Type:    string(hyperv1.EtcdQuotaRisk),
+				Status:  metav1.ConditionTrue,
+				Reason:  EtcdReasonQuotaRisk,
+				Message: fmt.Sprintf("Etcd member %s database size %d bytes is approaching the quota of %d bytes", member.Name, member.DBSizeBytes, status.QuotaBackendBytes),
+			}
+		}
+	}
+	return metav1.Condition{
+		Type:    string(hyperv1.EtcdQuotaRisk),
+		Status:  metav1.ConditionFalse,
+		Reason:  EtcdReasonWithinQuota,
+		Message: "Etcd databases are within the quota",
+	}
+}
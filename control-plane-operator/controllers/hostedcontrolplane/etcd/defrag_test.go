@@ -0,0 +1,95 @@
+package etcd
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+const mib = 1024 * 1024
+
+func TestNextMemberToDefragment(t *testing.T) {
+	fragmented := func(id uint64) MemberDatabase {
+		return MemberDatabase{ID: id, Size: 500 * mib, SizeInUse: 200 * mib}
+	}
+	compact := func(id uint64) MemberDatabase {
+		return MemberDatabase{ID: id, Size: 500 * mib, SizeInUse: 450 * mib}
+	}
+	tests := []struct {
+		name     string
+		members  []MemberDatabase
+		leader   uint64
+		expected *uint64
+	}{
+		{
+			name:    "no member is fragmented",
+			members: []MemberDatabase{compact(1), compact(2), compact(3)},
+			leader:  1,
+		},
+		{
+			name:    "small databases are not defragmented",
+			members: []MemberDatabase{{ID: 1, Size: 50 * mib, SizeInUse: 1 * mib}, compact(2), compact(3)},
+			leader:  1,
+		},
+		{
+			name:    "single member is not defragmented",
+			members: []MemberDatabase{fragmented(1)},
+			leader:  1,
+		},
+		{
+			name:    "cluster without a spare member is not defragmented",
+			members: []MemberDatabase{fragmented(1), fragmented(2)},
+			leader:  1,
+		},
+		{
+			name:     "followers are defragmented before the leader",
+			members:  []MemberDatabase{fragmented(1), compact(2), fragmented(3)},
+			leader:   1,
+			expected: pointerUint64(3),
+		},
+		{
+			name:     "leader is defragmented last",
+			members:  []MemberDatabase{fragmented(1), compact(2), compact(3)},
+			leader:   1,
+			expected: pointerUint64(1),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			member := NextMemberToDefragment(test.members, test.leader)
+			if test.expected == nil {
+				g.Expect(member).To(BeNil())
+				return
+			}
+			g.Expect(member).ToNot(BeNil())
+			g.Expect(member.ID).To(Equal(*test.expected))
+		})
+	}
+}
+
+func pointerUint64(i uint64) *uint64 {
+	return &i
+}
+
+func TestComputeEtcdQuotaRiskCondition(t *testing.T) {
+	g := NewGomegaWithT(t)
+	status := &hyperv1.ManagedEtcdStatus{
+		QuotaBackendBytes: 1000 * mib,
+		Members: []hyperv1.ManagedEtcdMemberStatus{
+			{Name: "etcd-0", DBSizeBytes: 100 * mib},
+			{Name: "etcd-1", DBSizeBytes: 790 * mib},
+		},
+	}
+	g.Expect(ComputeEtcdQuotaRiskCondition(status).Status).To(Equal(metav1.ConditionFalse))
+
+	status.Members[1].DBSizeBytes = 800 * mib
+	cond := ComputeEtcdQuotaRiskCondition(status)
+	g.Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+	g.Expect(cond.Reason).To(Equal(EtcdReasonQuotaRisk))
+	g.Expect(cond.Message).To(ContainSubstring("etcd-1"))
+}
@@ -0,0 +1,95 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/pki"
+	supportetcd "github.com/openshift/hypershift/support/etcd"
+)
+
+// etcdMemberCrashLoopRestarts is the number of restarts after which a
+// crashlooping member is considered failed.
+const etcdMemberCrashLoopRestarts = 3
+
+// Quorum returns the number of members a cluster of the given size needs to
+// be available.
+func Quorum(members int) int {
+	return members/2 + 1
+}
+
+// MemberOrdinal returns the ordinal of the StatefulSet pod with the given name.
+func MemberOrdinal(podName string) (int, error) {
+	i := strings.LastIndex(podName, "-")
+	if i < 0 {
+		return 0, fmt.Errorf("invalid etcd member name: %s", podName)
+	}
+	return strconv.Atoi(podName[i+1:])
+}
+
+// IsMemberFailed returns true if the etcd container of the pod keeps crashing
+// or has terminated.
+func IsMemberFailed(pod *corev1.Pod) bool {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != etcdContainer().Name {
+			continue
+		}
+		if cs.State.Terminated != nil {
+			return true
+		}
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" && cs.RestartCount >= etcdMemberCrashLoopRestarts {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMemberReady returns true if the pod of an etcd member is ready.
+func IsMemberReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// ListMemberPods returns the pods of the etcd members.
+func ListMemberPods(ctx context.Context, c client.Client, namespace string) ([]corev1.Pod, error) {
+	etcdPods := &corev1.PodList{}
+	if err := c.List(ctx, etcdPods, client.InNamespace(namespace), client.MatchingLabels(etcdLabels)); err != nil {
+		return nil, fmt.Errorf("cannot list etcd cluster pods: %w", err)
+	}
+	return etcdPods.Items, nil
+}
+
+// ListDataClaims returns the volume claims holding the data of the etcd
+// members.
+func ListDataClaims(ctx context.Context, c client.Client, namespace string) ([]corev1.PersistentVolumeClaim, error) {
+	claims := &corev1.PersistentVolumeClaimList{}
+	if err := c.List(ctx, claims, client.InNamespace(namespace), client.MatchingLabels(etcdLabels)); err != nil {
+		return nil, fmt.Errorf("cannot list etcd data volume claims: %w", err)
+	}
+	return claims.Items, nil
+}
+
+// NewEtcdClient returns a client for the managed etcd cluster in the given
+// namespace, authenticated with the etcd client certificate. The client must be
+// closed once it is no longer used.
+func NewEtcdClient(ctx context.Context, c client.Client, namespace string) (*supportetcd.Client, error) {
+	secret := manifests.EtcdClientSecret(namespace)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(secret), secret); err != nil {
+		return nil, fmt.Errorf("failed to get etcd client secret: %w", err)
+	}
+	tlsConfig, err := supportetcd.NewTLSConfig(secret.Data[pki.EtcdClientCrtKey], secret.Data[pki.EtcdClientKeyKey], secret.Data[pki.EtcdClientCAKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid etcd client secret: %w", err)
+	}
+	return supportetcd.NewClient([]string{EtcdClientEndpoint(namespace)}, tlsConfig), nil
+}
@@ -0,0 +1,282 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/util"
+	etcdv1 "github.com/openshift/hypershift/control-plane-operator/thirdparty/etcd/v1beta2"
+)
+
+// Managed etcd clusters created by previous versions are run by the etcd
+// operator. They keep running with it, since their data can't be moved to
+// the etcd StatefulSet without taking the control plane down. Backups,
+// restores and member maintenance are only supported for the StatefulSet.
+
+const (
+	// EtcdOperatorClusterVersion is the etcd version of clusters run by the
+	// etcd operator.
+	EtcdOperatorClusterVersion = "3.4.9"
+
+	etcdOperatorClusterLabel = "etcd_cluster"
+)
+
+var etcdOperatorDeploymentLabels = map[string]string{
+	"name": "etcd-operator",
+}
+
+func ReconcileOperatorServiceAccount(sa *corev1.ServiceAccount, ownerRef config.OwnerRef) error {
+	ownerRef.ApplyTo(sa)
+	return nil
+}
+
+func ReconcileOperatorRole(role *rbacv1.Role, ownerRef config.OwnerRef) error {
+	ownerRef.ApplyTo(role)
+	role.Rules = []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{
+				etcdv1.SchemeGroupVersion.Group,
+			},
+			Resources: []string{
+				"etcdclusters",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				corev1.SchemeGroupVersion.Group,
+			},
+			Resources: []string{
+				"pods",
+				"services",
+				"endpoints",
+				"persistentvolumeclaims",
+				"events",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				appsv1.SchemeGroupVersion.Group,
+			},
+			Resources: []string{
+				"deployments",
+			},
+			Verbs: []string{
+				"*",
+			},
+		},
+		{
+			APIGroups: []string{
+				corev1.SchemeGroupVersion.Group,
+			},
+			Resources: []string{
+				"secrets",
+			},
+			Verbs: []string{
+				"get",
+			},
+		},
+	}
+	return nil
+}
+
+func ReconcileOperatorRoleBinding(roleBinding *rbacv1.RoleBinding, ownerRef config.OwnerRef) error {
+	ownerRef.ApplyTo(roleBinding)
+	serviceAccount := manifests.EtcdOperatorServiceAccount(roleBinding.Namespace)
+	roleBinding.RoleRef = rbacv1.RoleRef{
+		APIGroup: rbacv1.SchemeGroupVersion.Group,
+		Kind:     "Role",
+		Name:     manifests.EtcdOperatorRole(roleBinding.Namespace).Name,
+	}
+	roleBinding.Subjects = []rbacv1.Subject{
+		{
+			Kind:      "ServiceAccount",
+			APIGroup:  corev1.SchemeGroupVersion.Group,
+			Namespace: serviceAccount.Namespace,
+			Name:      serviceAccount.Name,
+		},
+	}
+	return nil
+}
+
+func etcdOperatorContainer() *corev1.Container {
+	return &corev1.Container{
+		Name: "etcd-operator",
+	}
+}
+
+func buildEtcdOperatorContainer(image string) func(c *corev1.Container) {
+	return func(c *corev1.Container) {
+		c.Image = image
+		c.Command = []string{"etcd-operator"}
+		c.Args = []string{"-create-crd=false"}
+		c.Env = []corev1.EnvVar{
+			{
+				Name: "MY_POD_NAMESPACE",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.namespace",
+					},
+				},
+			},
+			{
+				Name: "MY_POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.name",
+					},
+				},
+			},
+		}
+	}
+}
+
+func ReconcileOperatorDeployment(deployment *appsv1.Deployment, ownerRef config.OwnerRef, deploymentConfig config.DeploymentConfig, operatorImage string) error {
+	ownerRef.ApplyTo(deployment)
+	serviceAccount := manifests.EtcdOperatorServiceAccount(deployment.Namespace)
+	deployment.Spec = appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: etcdOperatorDeploymentLabels,
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: etcdOperatorDeploymentLabels,
+			},
+			Spec: corev1.PodSpec{
+				ServiceAccountName: serviceAccount.Name,
+				Containers: []corev1.Container{
+					util.BuildContainer(etcdOperatorContainer(), buildEtcdOperatorContainer(operatorImage)),
+				},
+			},
+		},
+	}
+	deploymentConfig.ApplyTo(deployment)
+	return nil
+}
+
+// ReconcileCluster configures an existing EtcdCluster. The volume claim of
+// its members is left as it is, the etcd operator only uses it for new
+// members.
+func ReconcileCluster(cluster *etcdv1.EtcdCluster, ownerRef config.OwnerRef, etcdDeploymentConfig config.DeploymentConfig) error {
+	ownerRef.ApplyTo(cluster)
+	peerSecret := manifests.EtcdPeerSecret(cluster.Namespace)
+	serverSecret := manifests.EtcdServerSecret(cluster.Namespace)
+	clientSecret := manifests.EtcdClientSecret(cluster.Namespace)
+
+	podPolicy := &etcdv1.PodPolicy{}
+	if cluster.Spec.Pod != nil {
+		podPolicy.PersistentVolumeClaimSpec = cluster.Spec.Pod.PersistentVolumeClaimSpec
+	}
+	if resources, ok := etcdDeploymentConfig.Resources[etcdContainer().Name]; ok {
+		podPolicy.Resources = resources
+	}
+	podPolicy.Affinity = etcdDeploymentConfig.Scheduling.Affinity
+	podPolicy.Tolerations = etcdDeploymentConfig.Scheduling.Tolerations
+	if sc, ok := etcdDeploymentConfig.SecurityContexts[etcdContainer().Name]; ok {
+		podPolicy.SecurityContext = &corev1.PodSecurityContext{
+			SELinuxOptions: sc.SELinuxOptions,
+			WindowsOptions: sc.WindowsOptions,
+			RunAsUser:      sc.RunAsUser,
+			RunAsGroup:     sc.RunAsGroup,
+			RunAsNonRoot:   sc.RunAsNonRoot,
+			SeccompProfile: sc.SeccompProfile,
+		}
+	}
+	podPolicy.Labels = etcdDeploymentConfig.AdditionalLabels
+
+	cluster.Spec = etcdv1.ClusterSpec{
+		Size:    etcdDeploymentConfig.Replicas,
+		Version: EtcdOperatorClusterVersion,
+		TLS: &etcdv1.TLSPolicy{
+			Static: &etcdv1.StaticTLS{
+				Member: &etcdv1.MemberSecret{
+					PeerSecret:   peerSecret.Name,
+					ServerSecret: serverSecret.Name,
+				},
+				OperatorSecret: clientSecret.Name,
+			},
+		},
+		Pod: podPolicy,
+	}
+	return nil
+}
+
+// ComputeEtcdOperatorClusterStatus computes the EtcdAvailable condition of a
+// cluster run by the etcd operator.
+func ComputeEtcdOperatorClusterStatus(ctx context.Context, c client.Client, cluster *etcdv1.EtcdCluster) (metav1.Condition, error) {
+	var available *etcdv1.ClusterCondition
+	for i := range cluster.Status.Conditions {
+		if cluster.Status.Conditions[i].Type == etcdv1.ClusterConditionAvailable {
+			available = &cluster.Status.Conditions[i]
+		}
+	}
+
+	var cond metav1.Condition
+	switch {
+	case available != nil && available.Status == corev1.ConditionTrue:
+		cond = metav1.Condition{
+			Type:    string(hyperv1.EtcdAvailable),
+			Status:  metav1.ConditionTrue,
+			Reason:  EtcdReasonRunning,
+			Message: "Etcd cluster is running and available",
+		}
+	case len(cluster.Status.Members.Ready) == 0 && time.Since(cluster.CreationTimestamp.Time) > etcdClusterBootstrapTimeout:
+		cond = metav1.Condition{
+			Type:    string(hyperv1.EtcdAvailable),
+			Status:  metav1.ConditionFalse,
+			Reason:  EtcdReasonFailed,
+			Message: "Etcd cluster has no ready members",
+		}
+	default:
+		hasTerminatedPods, err := etcdOperatorClusterHasTerminatedPods(ctx, c, cluster)
+		if err != nil {
+			return cond, err
+		}
+		if hasTerminatedPods {
+			cond = metav1.Condition{
+				Type:    string(hyperv1.EtcdAvailable),
+				Status:  metav1.ConditionFalse,
+				Reason:  EtcdReasonFailed,
+				Message: "Etcd has failed to achieve quorum",
+			}
+		} else {
+			cond = metav1.Condition{
+				Type:    string(hyperv1.EtcdAvailable),
+				Status:  metav1.ConditionFalse,
+				Reason:  EtcdReasonScaling,
+				Message: "Etcd cluster is scaling up",
+			}
+		}
+	}
+	return cond, nil
+}
+
+func etcdOperatorClusterHasTerminatedPods(ctx context.Context, c client.Client, cluster *etcdv1.EtcdCluster) (bool, error) {
+	etcdPods := &corev1.PodList{}
+	if err := c.List(ctx, etcdPods, client.InNamespace(cluster.Namespace), client.MatchingLabels{etcdOperatorClusterLabel: cluster.Name}); err != nil {
+		return false, fmt.Errorf("cannot list etcd cluster pods: %w", err)
+	}
+	for _, pod := range etcdPods.Items {
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.State.Terminated != nil {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
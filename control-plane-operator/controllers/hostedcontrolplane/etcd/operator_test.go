@@ -0,0 +1,61 @@
+package etcd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+	etcdv1 "github.com/openshift/hypershift/control-plane-operator/thirdparty/etcd/v1beta2"
+)
+
+func TestComputeEtcdOperatorClusterStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		conditions     []etcdv1.ClusterCondition
+		age            time.Duration
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "cluster available",
+			conditions:     []etcdv1.ClusterCondition{{Type: etcdv1.ClusterConditionAvailable, Status: corev1.ConditionTrue}},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: EtcdReasonRunning,
+		},
+		{
+			name:           "no members ready after bootstrap timeout",
+			age:            2 * etcdClusterBootstrapTimeout,
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: EtcdReasonFailed,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			cluster := manifests.EtcdCluster("test")
+			cluster.CreationTimestamp = metav1.NewTime(time.Now().Add(-test.age))
+			cluster.Status.Conditions = test.conditions
+			cond, err := ComputeEtcdOperatorClusterStatus(context.Background(), nil, cluster)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cond.Status).To(Equal(test.expectedStatus))
+			g.Expect(cond.Reason).To(Equal(test.expectedReason))
+		})
+	}
+}
+
+func TestReconcileClusterKeepsVolumeClaim(t *testing.T) {
+	g := NewGomegaWithT(t)
+	claim := &corev1.PersistentVolumeClaimSpec{StorageClassName: &[]string{"gp2"}[0]}
+	cluster := manifests.EtcdCluster("test")
+	cluster.Spec.Pod = &etcdv1.PodPolicy{PersistentVolumeClaimSpec: claim}
+	g.Expect(ReconcileCluster(cluster, config.OwnerRef{}, config.DeploymentConfig{Replicas: 3})).To(Succeed())
+	g.Expect(cluster.Spec.Size).To(Equal(3))
+	g.Expect(cluster.Spec.Pod.PersistentVolumeClaimSpec).To(Equal(claim))
+}
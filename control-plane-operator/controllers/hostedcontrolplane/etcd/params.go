@@ -3,19 +3,31 @@ package etcd
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
 )
 
 type EtcdParams struct {
-	ClusterVersion           string
+	EtcdImage string
+	// SnapshotImage runs the hypershift CLI, which moves snapshots in and
+	// out of object storage
+	SnapshotImage string
+	// EtcdOperatorImage runs the etcd operator of clusters created before
+	// managed etcd moved to a StatefulSet
 	EtcdOperatorImage        string
-	OwnerRef                 config.OwnerRef `json:"ownerRef"`
 	OperatorDeploymentConfig config.DeploymentConfig
+	OwnerRef                 config.OwnerRef `json:"ownerRef"`
 	EtcdDeploymentConfig     config.DeploymentConfig
 	PVCClaim                 *corev1.PersistentVolumeClaimSpec `json:"pvcClaim"`
+
+	// BackupPolicy and Restore are copied from the managed etcd spec and are
+	// nil when no backups or restores are requested.
+	BackupPolicy *hyperv1.EtcdBackupPolicy
+	Restore      *hyperv1.EtcdRestoreSpec
 }
 
 var etcdLabels = map[string]string{
@@ -24,21 +36,16 @@ var etcdLabels = map[string]string{
 
 func NewEtcdParams(hcp *hyperv1.HostedControlPlane, images map[string]string) *EtcdParams {
 	p := &EtcdParams{
+		EtcdImage:         images["etcd"],
+		SnapshotImage:     images["hypershift"],
 		EtcdOperatorImage: images["etcd-operator"],
 		OwnerRef:          config.OwnerRefFrom(hcp),
-		ClusterVersion:    config.DefaultEtcdClusterVersion,
 	}
-	p.OperatorDeploymentConfig.Resources = config.ResourcesSpec{
-		etcdOperatorContainer().Name: {
-			Requests: corev1.ResourceList{
-				corev1.ResourceMemory: resource.MustParse("50Mi"),
-				corev1.ResourceCPU:    resource.MustParse("10m"),
-			},
-		},
+	if managed := hcp.Spec.Etcd.Managed; managed != nil {
+		p.PVCClaim = etcdPVCClaim(managed.Storage)
+		p.BackupPolicy = managed.Backup
+		p.Restore = managed.Restore
 	}
-	p.OperatorDeploymentConfig.SetMultizoneSpread(etcdOperatorDeploymentLabels)
-	p.OperatorDeploymentConfig.SetRestartAnnotation(hcp.ObjectMeta)
-	p.OperatorDeploymentConfig.SetControlPlaneIsolation(hcp)
 	p.EtcdDeploymentConfig.Resources = config.ResourcesSpec{
 		etcdContainer().Name: {
 			Requests: corev1.ResourceList{
@@ -47,9 +54,41 @@ func NewEtcdParams(hcp *hyperv1.HostedControlPlane, images map[string]string) *E
 			},
 		},
 	}
+	p.EtcdDeploymentConfig.ReadinessProbes = config.ReadinessProbes{
+		etcdContainer().Name: {
+			Handler: corev1.Handler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Scheme: corev1.URISchemeHTTP,
+					Port:   intstr.FromInt(EtcdMetricsPort),
+					Path:   "health",
+				},
+			},
+			InitialDelaySeconds: 5,
+			PeriodSeconds:       10,
+			TimeoutSeconds:      5,
+			FailureThreshold:    3,
+			SuccessThreshold:    1,
+		},
+	}
+	p.EtcdDeploymentConfig.Scheduling = config.Scheduling{
+		PriorityClass: config.APICriticalPriorityClass,
+	}
 	p.EtcdDeploymentConfig.SetMultizoneSpread(etcdLabels)
 	p.EtcdDeploymentConfig.SetColocationAnchor(hcp)
 	p.EtcdDeploymentConfig.SetControlPlaneIsolation(hcp)
+	p.EtcdDeploymentConfig.SetRestartAnnotation(hcp.ObjectMeta)
+	p.EtcdDeploymentConfig.SetComponentOverrides(hcp, manifests.EtcdStatefulSet("").Name)
+	p.OperatorDeploymentConfig.Resources = config.ResourcesSpec{
+		etcdOperatorContainer().Name: {
+			Requests: corev1.ResourceList{
+				corev1.ResourceMemory: resource.MustParse("50Mi"),
+				corev1.ResourceCPU:    resource.MustParse("10m"),
+			},
+		},
+	}
+	p.OperatorDeploymentConfig.SetMultizoneSpread(etcdOperatorDeploymentLabels)
+	p.OperatorDeploymentConfig.SetRestartAnnotation(hcp.ObjectMeta)
+	p.OperatorDeploymentConfig.SetControlPlaneIsolation(hcp)
 	p.OperatorDeploymentConfig.Replicas = 1
 	switch hcp.Spec.ControllerAvailabilityPolicy {
 	case hyperv1.HighlyAvailable:
@@ -59,3 +98,25 @@ func NewEtcdParams(hcp *hyperv1.HostedControlPlane, images map[string]string) *E
 	}
 	return p
 }
+
+// etcdPVCClaim returns the template of the volume claimed for every etcd
+// member, or nil if the members use ephemeral storage.
+func etcdPVCClaim(storage *hyperv1.ManagedEtcdStorageSpec) *corev1.PersistentVolumeClaimSpec {
+	if storage == nil || storage.Type != hyperv1.PersistentVolumeEtcdStorage {
+		return nil
+	}
+	size := resource.MustParse(hyperv1.DefaultPersistentVolumeEtcdStorageSize)
+	claim := &corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+	}
+	if pv := storage.PersistentVolume; pv != nil {
+		claim.StorageClassName = pv.StorageClassName
+		if pv.Size != nil {
+			size = *pv.Size
+		}
+	}
+	claim.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceStorage: size,
+	}
+	return claim
+}
@@ -0,0 +1,74 @@
+package etcd
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+func TestNewEtcdParamsStorage(t *testing.T) {
+	size := resource.MustParse("16Gi")
+	tests := []struct {
+		name     string
+		storage  *hyperv1.ManagedEtcdStorageSpec
+		expected *corev1.PersistentVolumeClaimSpec
+	}{
+		{
+			name:     "not specified",
+			expected: nil,
+		},
+		{
+			name:     "ephemeral",
+			storage:  &hyperv1.ManagedEtcdStorageSpec{Type: hyperv1.EphemeralEtcdStorage},
+			expected: nil,
+		},
+		{
+			name:    "persistent volume with defaults",
+			storage: &hyperv1.ManagedEtcdStorageSpec{Type: hyperv1.PersistentVolumeEtcdStorage},
+			expected: &corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(hyperv1.DefaultPersistentVolumeEtcdStorageSize),
+					},
+				},
+			},
+		},
+		{
+			name: "persistent volume with storage class and size",
+			storage: &hyperv1.ManagedEtcdStorageSpec{
+				Type: hyperv1.PersistentVolumeEtcdStorage,
+				PersistentVolume: &hyperv1.PersistentVolumeEtcdStorageSpec{
+					StorageClassName: pointer.StringPtr("gp3"),
+					Size:             &size,
+				},
+			},
+			expected: &corev1.PersistentVolumeClaimSpec{
+				AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				StorageClassName: pointer.StringPtr("gp3"),
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: size,
+					},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hcp := &hyperv1.HostedControlPlane{}
+			hcp.Spec.Etcd.ManagementType = hyperv1.Managed
+			hcp.Spec.Etcd.Managed = &hyperv1.ManagedEtcdSpec{Storage: test.storage}
+			p := NewEtcdParams(hcp, map[string]string{})
+			g := NewGomegaWithT(t)
+			g.Expect(p.PVCClaim).To(Equal(test.expected))
+		})
+	}
+}
@@ -1,124 +1,313 @@
 package etcd
 
 import (
+	"fmt"
+	"path"
+	"strconv"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
 
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/pki"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/util"
-	etcdv1 "github.com/openshift/hypershift/control-plane-operator/thirdparty/etcd/v1beta2"
 )
 
-func ReconcileOperatorServiceAccount(sa *corev1.ServiceAccount, ownerRef config.OwnerRef) error {
-	ownerRef.ApplyTo(sa)
-	return nil
-}
+const (
+	EtcdClientPort  = 2379
+	EtcdPeerPort    = 2380
+	EtcdMetricsPort = 2381
 
-func ReconcileOperatorRole(role *rbacv1.Role, ownerRef config.OwnerRef) error {
-	ownerRef.ApplyTo(role)
-	role.Rules = []rbacv1.PolicyRule{
-		{
-			APIGroups: []string{
-				etcdv1.SchemeGroupVersion.Group,
-			},
-			Resources: []string{
-				"etcdclusters",
-				"etcdbackups",
-				"etcdrestores",
-			},
-			Verbs: []string{
-				"*",
-			},
+	// etcdDataDir is the directory within the data volume which holds the
+	// member's data. It is a subdirectory so that its absence reliably means
+	// the member has no data, even on volumes with a lost+found directory.
+	etcdDataDir = "data"
+
+	// etcdSnapshotFile is the name of the snapshot a new cluster is restored
+	// from, within the snapshot volume.
+	etcdSnapshotFile = "snapshot.db"
+
+	etcdClusterToken = "etcd-cluster"
+
+	// EtcdQuotaBackendBytes is the size the database of a member may grow to
+	// before etcd stops accepting writes. It is the etcd default, set
+	// explicitly so that the database size can be compared against it.
+	EtcdQuotaBackendBytes int64 = 2 * 1024 * 1024 * 1024
+
+	// EtcdClusterFormedAnnotation is set on the etcd StatefulSet once its
+	// members have formed a cluster. From then on, members without data only
+	// join the existing cluster and never bootstrap a new one.
+	EtcdClusterFormedAnnotation = "hypershift.openshift.io/etcd-cluster-formed"
+)
+
+var (
+	volumeMounts = util.PodVolumeMounts{
+		etcdContainer().Name: {
+			etcdVolumeData().Name:      "/var/lib/etcd",
+			etcdVolumePeerTLS().Name:   "/etc/etcd/tls/peer",
+			etcdVolumeServerTLS().Name: "/etc/etcd/tls/server",
+			etcdVolumeSnapshot().Name:  "/var/lib/etcd-snapshot",
 		},
-		{
-			APIGroups: []string{
-				corev1.SchemeGroupVersion.Group,
-			},
-			Resources: []string{
-				"pods",
-				"services",
-				"endpoints",
-				"persistentvolumeclaims",
-				"events",
-			},
-			Verbs: []string{
-				"*",
-			},
+		etcdFetchSnapshotContainer().Name: {
+			etcdVolumeData().Name:          "/var/lib/etcd",
+			etcdVolumeSnapshot().Name:      "/var/lib/etcd-snapshot",
+			etcdVolumeS3Credentials().Name: "/etc/etcd/s3",
+		},
+		etcdCopySnapshotContainer().Name: {
+			etcdVolumeData().Name:     "/var/lib/etcd",
+			etcdVolumeSnapshot().Name: "/var/lib/etcd-snapshot",
+			etcdVolumeBackup().Name:   "/var/lib/etcd-backup",
 		},
+	}
+)
+
+func ReconcileDiscoveryService(svc *corev1.Service, ownerRef config.OwnerRef) error {
+	ownerRef.ApplyTo(svc)
+	svc.Spec.Selector = etcdLabels
+	svc.Spec.ClusterIP = corev1.ClusterIPNone
+	// Members must be able to resolve each other before they are ready, since
+	// readiness depends on the cluster having a leader.
+	svc.Spec.PublishNotReadyAddresses = true
+	svc.Spec.Ports = []corev1.ServicePort{
 		{
-			APIGroups: []string{
-				appsv1.SchemeGroupVersion.Group,
-			},
-			Resources: []string{
-				"deployments",
-			},
-			Verbs: []string{
-				"*",
-			},
+			Name:       "peer",
+			Port:       EtcdPeerPort,
+			Protocol:   corev1.ProtocolTCP,
+			TargetPort: intstr.FromInt(EtcdPeerPort),
 		},
 		{
-			APIGroups: []string{
-				corev1.SchemeGroupVersion.Group,
-			},
-			Resources: []string{
-				"secrets",
-			},
-			Verbs: []string{
-				"get",
-			},
+			Name:       "client",
+			Port:       EtcdClientPort,
+			Protocol:   corev1.ProtocolTCP,
+			TargetPort: intstr.FromInt(EtcdClientPort),
 		},
 	}
 	return nil
 }
 
-func ReconcileOperatorRoleBinding(roleBinding *rbacv1.RoleBinding, ownerRef config.OwnerRef) error {
-	ownerRef.ApplyTo(roleBinding)
-	serviceAccount := manifests.EtcdOperatorServiceAccount(roleBinding.Namespace)
-	roleBinding.RoleRef = rbacv1.RoleRef{
-		APIGroup: rbacv1.SchemeGroupVersion.Group,
-		Kind:     "Role",
-		Name:     "etcd-operator",
-	}
-	roleBinding.Subjects = []rbacv1.Subject{
-		{
-			Kind:      "ServiceAccount",
-			APIGroup:  corev1.SchemeGroupVersion.Group,
-			Namespace: serviceAccount.Namespace,
-			Name:      serviceAccount.Name,
-		},
+func ReconcileClientService(svc *corev1.Service, ownerRef config.OwnerRef) error {
+	ownerRef.ApplyTo(svc)
+	svc.Spec.Selector = etcdLabels
+	var portSpec corev1.ServicePort
+	if len(svc.Spec.Ports) > 0 {
+		portSpec = svc.Spec.Ports[0]
+	} else {
+		svc.Spec.Ports = []corev1.ServicePort{portSpec}
 	}
+	portSpec.Name = "client"
+	portSpec.Port = int32(EtcdClientPort)
+	portSpec.Protocol = corev1.ProtocolTCP
+	portSpec.TargetPort = intstr.FromInt(EtcdClientPort)
+	svc.Spec.Type = corev1.ServiceTypeClusterIP
+	svc.Spec.Ports[0] = portSpec
 	return nil
 }
 
-var etcdOperatorDeploymentLabels = map[string]string{
-	"name": "etcd-operator",
+// etcdContainer is the etcd member container
+func etcdContainer() *corev1.Container {
+	return &corev1.Container{
+		Name: "etcd",
+	}
 }
 
-func etcdOperatorContainer() *corev1.Container {
+func etcdFetchSnapshotContainer() *corev1.Container {
 	return &corev1.Container{
-		Name: "etcd-operator",
+		Name: "fetch-snapshot",
 	}
 }
 
-// etcdContainer is not a container that we build directly but is used
-// to assign scheduling/resources to it
-func etcdContainer() *corev1.Container {
+func etcdCopySnapshotContainer() *corev1.Container {
 	return &corev1.Container{
-		Name: "etcd",
+		Name: "copy-snapshot",
+	}
+}
+
+func etcdVolumeData() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "data",
+	}
+}
+
+func buildEtcdVolumeData(v *corev1.Volume) {
+	v.EmptyDir = &corev1.EmptyDirVolumeSource{}
+}
+
+func etcdVolumePeerTLS() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "peer-tls",
+	}
+}
+
+func buildEtcdVolumePeerTLS(v *corev1.Volume) {
+	v.Secret = &corev1.SecretVolumeSource{
+		SecretName: manifests.EtcdPeerSecret("").Name,
+	}
+}
+
+func etcdVolumeServerTLS() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "server-tls",
+	}
+}
+
+func buildEtcdVolumeServerTLS(v *corev1.Volume) {
+	v.Secret = &corev1.SecretVolumeSource{
+		SecretName: manifests.EtcdServerSecret("").Name,
+	}
+}
+
+func etcdVolumeSnapshot() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "snapshot",
+	}
+}
+
+func buildEtcdVolumeSnapshot(v *corev1.Volume) {
+	v.EmptyDir = &corev1.EmptyDirVolumeSource{}
+}
+
+func etcdVolumeS3Credentials() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "s3-credentials",
+	}
+}
+
+func buildEtcdVolumeS3Credentials(secretName string) func(v *corev1.Volume) {
+	return func(v *corev1.Volume) {
+		v.Secret = &corev1.SecretVolumeSource{
+			SecretName: secretName,
+		}
+	}
+}
+
+func etcdVolumeBackup() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "backup",
+	}
+}
+
+func buildEtcdVolumeBackup(v *corev1.Volume) {
+	v.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+		ClaimName: manifests.EtcdBackupPVC("").Name,
+		ReadOnly:  true,
 	}
 }
 
-func buildEtcdOperatorContainer(image string) func(c *corev1.Container) {
+// ReconcileStatefulSet reconciles the etcd members. The number of replicas is
+// not set, since the cluster membership has to change along with it. Restore
+// is only set while the cluster is being restored, so that members only have
+// access to the backup destination until the first one has been restored. The
+// first member may only bootstrap a new cluster until the StatefulSet has the
+// EtcdClusterFormedAnnotation.
+func ReconcileStatefulSet(sts *appsv1.StatefulSet, ownerRef config.OwnerRef, deploymentConfig config.DeploymentConfig, image, snapshotImage string, pvcClaim *corev1.PersistentVolumeClaimSpec, backupPolicy *hyperv1.EtcdBackupPolicy, restore *hyperv1.EtcdRestoreSpec) error {
+	ownerRef.ApplyTo(sts)
+	sts.Spec.ServiceName = manifests.EtcdDiscoveryService(sts.Namespace).Name
+	sts.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: etcdLabels,
+	}
+	// Members are started in parallel so that a cluster which lost all of its
+	// pods can regain quorum. New members are added one at a time by scaling
+	// the StatefulSet one replica at a time.
+	sts.Spec.PodManagementPolicy = appsv1.ParallelPodManagement
+	sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+	}
+	sts.Spec.Template = corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: etcdLabels,
+		},
+		Spec: corev1.PodSpec{
+			AutomountServiceAccountToken: pointer.BoolPtr(false),
+			Containers: []corev1.Container{
+				util.BuildContainer(etcdContainer(), buildEtcdContainer(image, !IsClusterFormed(sts))),
+			},
+			Volumes: []corev1.Volume{
+				util.BuildVolume(etcdVolumePeerTLS(), buildEtcdVolumePeerTLS),
+				util.BuildVolume(etcdVolumeServerTLS(), buildEtcdVolumeServerTLS),
+				util.BuildVolume(etcdVolumeSnapshot(), buildEtcdVolumeSnapshot),
+			},
+		},
+	}
+
+	if restore != nil {
+		if backupPolicy == nil {
+			return fmt.Errorf("restoring etcd requires a backup policy")
+		}
+		switch backupPolicy.Destination.Type {
+		case hyperv1.S3EtcdBackupDestination:
+			s3 := backupPolicy.Destination.S3
+			if s3 == nil {
+				return fmt.Errorf("etcd backup destination %s requires S3 settings", hyperv1.S3EtcdBackupDestination)
+			}
+			sts.Spec.Template.Spec.InitContainers = []corev1.Container{
+				util.BuildContainer(etcdFetchSnapshotContainer(), buildEtcdFetchSnapshotContainer(snapshotImage, s3, restore.SnapshotName)),
+			}
+			sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes,
+				util.BuildVolume(etcdVolumeS3Credentials(), buildEtcdVolumeS3Credentials(s3.Credentials.Name)))
+		case hyperv1.PersistentVolumeEtcdBackupDestination:
+			sts.Spec.Template.Spec.InitContainers = []corev1.Container{
+				util.BuildContainer(etcdCopySnapshotContainer(), buildEtcdCopySnapshotContainer(image, restore.SnapshotName)),
+			}
+			sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes,
+				util.BuildVolume(etcdVolumeBackup(), buildEtcdVolumeBackup))
+		default:
+			return fmt.Errorf("unsupported etcd backup destination type: %s", backupPolicy.Destination.Type)
+		}
+	}
+
+	// The volume claim templates of a StatefulSet cannot be changed, so the
+	// storage of an existing StatefulSet is left as it is.
+	if sts.CreationTimestamp.IsZero() {
+		if pvcClaim != nil {
+			sts.Spec.VolumeClaimTemplates = []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   etcdVolumeData().Name,
+						Labels: etcdLabels,
+					},
+					Spec: *pvcClaim,
+				},
+			}
+		} else {
+			sts.Spec.VolumeClaimTemplates = nil
+		}
+	}
+	if len(sts.Spec.VolumeClaimTemplates) == 0 {
+		sts.Spec.Template.Spec.Volumes = append(sts.Spec.Template.Spec.Volumes,
+			util.BuildVolume(etcdVolumeData(), buildEtcdVolumeData))
+	}
+
+	deploymentConfig.ApplyToStatefulSet(sts)
+	return nil
+}
+
+// IsClusterFormed returns true once the members of the etcd StatefulSet have
+// formed a cluster.
+func IsClusterFormed(sts *appsv1.StatefulSet) bool {
+	return sts.Annotations[EtcdClusterFormedAnnotation] == "true"
+}
+
+func buildEtcdContainer(image string, bootstrap bool) func(c *corev1.Container) {
 	return func(c *corev1.Container) {
+		serverTLSDir := volumeMounts.Path(c.Name, etcdVolumeServerTLS().Name)
 		c.Image = image
-		c.Command = []string{"etcd-operator"}
-		c.Args = []string{"-create-crd=false"}
+		c.Command = []string{"/bin/sh", "-c"}
+		c.Args = []string{
+			etcdStartScript(
+				path.Join(volumeMounts.Path(c.Name, etcdVolumeData().Name), etcdDataDir),
+				path.Join(volumeMounts.Path(c.Name, etcdVolumeSnapshot().Name), etcdSnapshotFile),
+				serverTLSDir,
+				volumeMounts.Path(c.Name, etcdVolumePeerTLS().Name),
+			),
+		}
 		c.Env = []corev1.EnvVar{
 			{
-				Name: "MY_POD_NAMESPACE",
+				Name: "NAMESPACE",
 				ValueFrom: &corev1.EnvVarSource{
 					FieldRef: &corev1.ObjectFieldSelector{
 						FieldPath: "metadata.namespace",
@@ -126,81 +315,183 @@ func buildEtcdOperatorContainer(image string) func(c *corev1.Container) {
 				},
 			},
 			{
-				Name: "MY_POD_NAME",
-				ValueFrom: &corev1.EnvVarSource{
-					FieldRef: &corev1.ObjectFieldSelector{
-						FieldPath: "metadata.name",
-					},
-				},
+				Name:  "ETCDCTL_API",
+				Value: "3",
+			},
+			{
+				Name:  "ETCDCTL_ENDPOINTS",
+				Value: fmt.Sprintf("https://%s.$(NAMESPACE).svc:%d", manifests.EtcdClientService("").Name, EtcdClientPort),
+			},
+			{
+				Name:  "ETCDCTL_CACERT",
+				Value: path.Join(serverTLSDir, pki.EtcdServerCAKey),
+			},
+			{
+				Name:  "ETCDCTL_CERT",
+				Value: path.Join(serverTLSDir, pki.EtcdServerCrtKey),
+			},
+			{
+				Name:  "ETCDCTL_KEY",
+				Value: path.Join(serverTLSDir, pki.EtcdServerKeyKey),
+			},
+			{
+				Name:  "ETCDCTL_DIAL_TIMEOUT",
+				Value: "5s",
+			},
+			{
+				Name:  "ETCDCTL_COMMAND_TIMEOUT",
+				Value: "15s",
+			},
+			{
+				Name:  "BOOTSTRAP_CLUSTER",
+				Value: strconv.FormatBool(bootstrap),
 			},
 		}
-	}
-}
-
-func ReconcileOperatorDeployment(deployment *appsv1.Deployment, ownerRef config.OwnerRef, deploymentConfig config.DeploymentConfig, operatorImage string) error {
-	ownerRef.ApplyTo(deployment)
-	serviceAccount := manifests.EtcdOperatorServiceAccount(deployment.Namespace)
-	deployment.Spec = appsv1.DeploymentSpec{
-		Selector: &metav1.LabelSelector{
-			MatchLabels: etcdOperatorDeploymentLabels,
-		},
-		Template: corev1.PodTemplateSpec{
-			ObjectMeta: metav1.ObjectMeta{
-				Labels: etcdOperatorDeploymentLabels,
+		c.Ports = []corev1.ContainerPort{
+			{
+				Name:          "client",
+				ContainerPort: EtcdClientPort,
+				Protocol:      corev1.ProtocolTCP,
 			},
-			Spec: corev1.PodSpec{
-				ServiceAccountName: serviceAccount.Name,
-				Containers: []corev1.Container{
-					util.BuildContainer(etcdOperatorContainer(), buildEtcdOperatorContainer(operatorImage)),
-				},
+			{
+				Name:          "peer",
+				ContainerPort: EtcdPeerPort,
+				Protocol:      corev1.ProtocolTCP,
 			},
-		},
+			{
+				Name:          "metrics",
+				ContainerPort: EtcdMetricsPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		}
+		c.VolumeMounts = volumeMounts.ContainerMounts(c.Name)
 	}
-	deploymentConfig.ApplyTo(deployment)
-	return nil
 }
 
-func ReconcileCluster(cluster *etcdv1.EtcdCluster, ownerRef config.OwnerRef, etcdDeploymentConfig config.DeploymentConfig, etcdVersion string, pvcClaim *corev1.PersistentVolumeClaimSpec) error {
-	ownerRef.ApplyTo(cluster)
-	peerSecret := manifests.EtcdPeerSecret(cluster.Namespace)
-	serverSecret := manifests.EtcdServerSecret(cluster.Namespace)
-	clientSecret := manifests.EtcdClientSecret(cluster.Namespace)
-
-	podPolicy := &etcdv1.PodPolicy{}
-
-	if resources, ok := etcdDeploymentConfig.Resources[etcdContainer().Name]; ok {
-		podPolicy.Resources = resources
-	}
-	podPolicy.Affinity = etcdDeploymentConfig.Scheduling.Affinity
-	podPolicy.Tolerations = etcdDeploymentConfig.Scheduling.Tolerations
-	// TODO: Figure out how to set priority class on etcd pods
-	// podPolicy.PriorityClass = p.EtcdScheduling.PriorityClass
-	if sc, ok := etcdDeploymentConfig.SecurityContexts[etcdContainer().Name]; ok {
-		podPolicy.SecurityContext = &corev1.PodSecurityContext{
-			SELinuxOptions: sc.SELinuxOptions,
-			WindowsOptions: sc.WindowsOptions,
-			RunAsUser:      sc.RunAsUser,
-			RunAsGroup:     sc.RunAsGroup,
-			RunAsNonRoot:   sc.RunAsNonRoot,
-			SeccompProfile: sc.SeccompProfile,
-		}
-	}
-	podPolicy.PersistentVolumeClaimSpec = pvcClaim
-	podPolicy.Labels = etcdDeploymentConfig.AdditionalLabels
-
-	cluster.Spec = etcdv1.ClusterSpec{
-		Size:    etcdDeploymentConfig.Replicas,
-		Version: etcdVersion,
-		TLS: &etcdv1.TLSPolicy{
-			Static: &etcdv1.StaticTLS{
-				Member: &etcdv1.MemberSecret{
-					PeerSecret:   peerSecret.Name,
-					ServerSecret: serverSecret.Name,
-				},
-				OperatorSecret: clientSecret.Name,
-			},
-		},
-		Pod: podPolicy,
+// etcdStartScript starts an etcd member named after its pod. A member without
+// data joins the existing cluster, replacing a previous incarnation of itself
+// if necessary. Only the first member bootstraps a new cluster, restored from
+// a snapshot if one was fetched, and only while BOOTSTRAP_CLUSTER is true.
+// Once the members have formed a cluster, a member without data waits for the
+// cluster to become reachable, since a new cluster behind the same client
+// service would hide the data of the existing one.
+func etcdStartScript(dataDir, snapshotFile, serverTLSDir, peerTLSDir string) string {
+	var script = `#!/bin/sh
+set -eu
+NAME="${HOSTNAME}"
+ORDINAL="${HOSTNAME##*-}"
+PEER_URL="https://${NAME}.%[1]s.${NAMESPACE}.svc:%[2]d"
+CLIENT_URL="https://${NAME}.%[1]s.${NAMESPACE}.svc:%[3]d"
+DATA_DIR="%[4]s"
+SNAPSHOT="%[5]s"
+INITIAL_CLUSTER="${NAME}=${PEER_URL}"
+INITIAL_CLUSTER_STATE="new"
+
+# Members with data start on their own so that the cluster can regain quorum.
+# Until a cluster has formed, the first member gives up after a few attempts
+# and bootstraps a new cluster.
+ATTEMPTS=0
+until MEMBERS="$(etcdctl member list)"; do
+  MEMBERS=""
+  ATTEMPTS=$((ATTEMPTS + 1))
+  if [ -d "${DATA_DIR}" ] || { [ "${ORDINAL}" = "0" ] && [ "${BOOTSTRAP_CLUSTER}" = "true" ] && [ "${ATTEMPTS}" -ge 3 ]; }; then
+    break
+  fi
+  echo "Waiting for the etcd cluster to become available"
+  sleep 5
+done
+
+if [ -n "${MEMBERS}" ]; then
+  if [ -d "${DATA_DIR}" ] && ! echo "${MEMBERS}" | grep -qF "${PEER_URL}"; then
+    echo "Member was removed from the cluster, discarding its data"
+    rm -rf "${DATA_DIR}"
+  fi
+  if [ ! -d "${DATA_DIR}" ]; then
+    ID="$(echo "${MEMBERS}" | awk -F', ' -v url="${PEER_URL}" '$4 == url { print $1 }')"
+    STATUS="$(echo "${MEMBERS}" | awk -F', ' -v url="${PEER_URL}" '$4 == url { print $2 }')"
+    if [ -n "${ID}" ] && [ "${STATUS}" = "started" ]; then
+      echo "Replacing member ${ID} which lost its data"
+      etcdctl member remove "${ID}"
+      ID=""
+    fi
+    if [ -z "${ID}" ]; then
+      etcdctl member add "${NAME}" --peer-urls="${PEER_URL}"
+    fi
+    INITIAL_CLUSTER="$(etcdctl member list | awk -F', ' '{ split($4, url, "//"); split(url[2], host, "."); printf "%%s%%s=%%s", sep, host[1], $4; sep="," }')"
+    INITIAL_CLUSTER_STATE="existing"
+  fi
+elif [ ! -d "${DATA_DIR}" ] && [ -f "${SNAPSHOT}" ]; then
+  echo "Restoring the etcd cluster from ${SNAPSHOT}"
+  etcdctl snapshot restore "${SNAPSHOT}" \
+    --name="${NAME}" \
+    --initial-cluster="${INITIAL_CLUSTER}" \
+    --initial-cluster-token=%[6]s \
+    --initial-advertise-peer-urls="${PEER_URL}" \
+    --data-dir="${DATA_DIR}"
+fi
+
+exec etcd \
+  --name="${NAME}" \
+  --data-dir="${DATA_DIR}" \
+  --initial-advertise-peer-urls="${PEER_URL}" \
+  --listen-peer-urls=https://0.0.0.0:%[2]d \
+  --listen-client-urls=https://0.0.0.0:%[3]d \
+  --advertise-client-urls="${CLIENT_URL}" \
+  --listen-metrics-urls=http://0.0.0.0:%[7]d \
+  --initial-cluster="${INITIAL_CLUSTER}" \
+  --initial-cluster-state="${INITIAL_CLUSTER_STATE}" \
+  --initial-cluster-token=%[6]s \
+  --client-cert-auth=true \
+  --trusted-ca-file=%[8]s \
+  --cert-file=%[9]s \
+  --key-file=%[10]s \
+  --peer-client-cert-auth=true \
+  --peer-trusted-ca-file=%[11]s \
+  --peer-cert-file=%[12]s \
+  --peer-key-file=%[13]s \
+  --quota-backend-bytes=%[14]d
+`
+	return fmt.Sprintf(script,
+		manifests.EtcdDiscoveryService("").Name,
+		EtcdPeerPort,
+		EtcdClientPort,
+		dataDir,
+		snapshotFile,
+		etcdClusterToken,
+		EtcdMetricsPort,
+		path.Join(serverTLSDir, pki.EtcdServerCAKey),
+		path.Join(serverTLSDir, pki.EtcdServerCrtKey),
+		path.Join(serverTLSDir, pki.EtcdServerKeyKey),
+		path.Join(peerTLSDir, pki.EtcdPeerCAKey),
+		path.Join(peerTLSDir, pki.EtcdPeerCrtKey),
+		path.Join(peerTLSDir, pki.EtcdPeerKeyKey),
+		EtcdQuotaBackendBytes,
+	)
+}
+
+// EtcdMemberPeerURL returns the peer URL of the member run by the pod with the
+// given name.
+func EtcdMemberPeerURL(namespace, podName string) string {
+	return fmt.Sprintf("https://%s.%s.%s.svc:%d", podName, manifests.EtcdDiscoveryService(namespace).Name, namespace, EtcdPeerPort)
+}
+
+// EtcdClientEndpoint returns the client URL of the etcd cluster as seen from
+// the management cluster.
+func EtcdClientEndpoint(namespace string) string {
+	return fmt.Sprintf("https://%s.%s.svc:%d", manifests.EtcdClientService(namespace).Name, namespace, EtcdClientPort)
+}
+
+// NextReplicas returns the number of replicas the etcd StatefulSet is scaled
+// to next on its way to the desired number of members. Members are added one
+// at a time, once all current members are ready, so that every new member
+// joins a healthy cluster. Members are removed one at a time as well.
+func NextReplicas(current, ready, desired int) int {
+	switch {
+	case current < desired && ready >= current:
+		return current + 1
+	case current > desired:
+		return current - 1
+	default:
+		return current
 	}
-	return nil
 }
@@ -0,0 +1,133 @@
+package etcd
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+)
+
+func podVolume(spec corev1.PodSpec, name string) *corev1.Volume {
+	for i := range spec.Volumes {
+		if spec.Volumes[i].Name == name {
+			return &spec.Volumes[i]
+		}
+	}
+	return nil
+}
+
+func TestReconcileStatefulSet(t *testing.T) {
+	claim := &corev1.PersistentVolumeClaimSpec{
+		AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: resource.MustParse("8Gi"),
+			},
+		},
+	}
+
+	t.Run("ephemeral storage", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		sts := manifests.EtcdStatefulSet("test")
+		g.Expect(ReconcileStatefulSet(sts, config.OwnerRef{}, config.DeploymentConfig{}, "etcd", "hypershift", nil, nil, nil)).To(Succeed())
+		g.Expect(sts.Spec.ServiceName).To(Equal(manifests.EtcdDiscoveryService("test").Name))
+		g.Expect(sts.Spec.Replicas).To(BeNil())
+		g.Expect(sts.Spec.VolumeClaimTemplates).To(BeEmpty())
+		g.Expect(podVolume(sts.Spec.Template.Spec, etcdVolumeData().Name).EmptyDir).ToNot(BeNil())
+		g.Expect(sts.Spec.Template.Spec.InitContainers).To(BeEmpty())
+		g.Expect(sts.Spec.Template.Spec.Containers[0].Image).To(Equal("etcd"))
+	})
+
+	t.Run("persistent volume storage", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		sts := manifests.EtcdStatefulSet("test")
+		g.Expect(ReconcileStatefulSet(sts, config.OwnerRef{}, config.DeploymentConfig{}, "etcd", "hypershift", claim, nil, nil)).To(Succeed())
+		g.Expect(sts.Spec.VolumeClaimTemplates).To(HaveLen(1))
+		g.Expect(sts.Spec.VolumeClaimTemplates[0].Name).To(Equal(etcdVolumeData().Name))
+		g.Expect(sts.Spec.VolumeClaimTemplates[0].Spec).To(Equal(*claim))
+		g.Expect(podVolume(sts.Spec.Template.Spec, etcdVolumeData().Name)).To(BeNil())
+	})
+
+	t.Run("storage of existing statefulset is kept", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		sts := manifests.EtcdStatefulSet("test")
+		sts.CreationTimestamp = metav1.Now()
+		g.Expect(ReconcileStatefulSet(sts, config.OwnerRef{}, config.DeploymentConfig{}, "etcd", "hypershift", claim, nil, nil)).To(Succeed())
+		g.Expect(sts.Spec.VolumeClaimTemplates).To(BeEmpty())
+		g.Expect(podVolume(sts.Spec.Template.Spec, etcdVolumeData().Name).EmptyDir).ToNot(BeNil())
+	})
+
+	t.Run("bootstrap only until the cluster has formed", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		sts := manifests.EtcdStatefulSet("test")
+		g.Expect(ReconcileStatefulSet(sts, config.OwnerRef{}, config.DeploymentConfig{}, "etcd", "hypershift", nil, nil, nil)).To(Succeed())
+		g.Expect(sts.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "BOOTSTRAP_CLUSTER", Value: "true"}))
+
+		sts.Annotations = map[string]string{EtcdClusterFormedAnnotation: "true"}
+		g.Expect(ReconcileStatefulSet(sts, config.OwnerRef{}, config.DeploymentConfig{}, "etcd", "hypershift", nil, nil, nil)).To(Succeed())
+		g.Expect(sts.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "BOOTSTRAP_CLUSTER", Value: "false"}))
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		sts := manifests.EtcdStatefulSet("test")
+		restore := &hyperv1.EtcdRestoreSpec{SnapshotName: "clusters/example/etcd-snapshot-20210901000000.db"}
+		g.Expect(ReconcileStatefulSet(sts, config.OwnerRef{}, config.DeploymentConfig{}, "etcd", "hypershift", nil, s3BackupPolicy(), restore)).To(Succeed())
+		g.Expect(sts.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+		g.Expect(sts.Spec.Template.Spec.InitContainers[0].Image).To(Equal("hypershift"))
+		g.Expect(sts.Spec.Template.Spec.InitContainers[0].Args[0]).To(ContainSubstring("--key' 'clusters/example/etcd-snapshot-20210901000000.db'"))
+		g.Expect(podVolume(sts.Spec.Template.Spec, etcdVolumeS3Credentials().Name).Secret.SecretName).To(Equal("etcd-backup-credentials"))
+
+		g.Expect(ReconcileStatefulSet(manifests.EtcdStatefulSet("test"), config.OwnerRef{}, config.DeploymentConfig{}, "etcd", "hypershift", nil, nil, restore)).ToNot(Succeed())
+	})
+
+	t.Run("restore from persistent volume", func(t *testing.T) {
+		g := NewGomegaWithT(t)
+		sts := manifests.EtcdStatefulSet("test")
+		restore := &hyperv1.EtcdRestoreSpec{SnapshotName: "snapshot-20210901000000.db"}
+		pvPolicy := &hyperv1.EtcdBackupPolicy{
+			Destination: hyperv1.EtcdBackupDestination{Type: hyperv1.PersistentVolumeEtcdBackupDestination},
+		}
+		g.Expect(ReconcileStatefulSet(sts, config.OwnerRef{}, config.DeploymentConfig{}, "etcd", "hypershift", nil, pvPolicy, restore)).To(Succeed())
+		g.Expect(sts.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+		g.Expect(sts.Spec.Template.Spec.InitContainers[0].Image).To(Equal("etcd"))
+		g.Expect(sts.Spec.Template.Spec.InitContainers[0].Args[0]).To(ContainSubstring(`cp "/var/lib/etcd-backup/snapshot-20210901000000.db"`))
+		backup := podVolume(sts.Spec.Template.Spec, etcdVolumeBackup().Name)
+		g.Expect(backup).ToNot(BeNil())
+		g.Expect(backup.PersistentVolumeClaim.ClaimName).To(Equal(manifests.EtcdBackupPVC("test").Name))
+		g.Expect(backup.PersistentVolumeClaim.ReadOnly).To(BeTrue())
+
+		g.Expect(ReconcileStatefulSet(sts, config.OwnerRef{}, config.DeploymentConfig{}, "etcd", "hypershift", nil, pvPolicy, nil)).To(Succeed())
+		g.Expect(sts.Spec.Template.Spec.InitContainers).To(BeEmpty())
+		g.Expect(podVolume(sts.Spec.Template.Spec, etcdVolumeBackup().Name)).To(BeNil())
+	})
+}
+
+func TestNextReplicas(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  int
+		ready    int
+		desired  int
+		expected int
+	}{
+		{name: "steady", current: 3, ready: 3, desired: 3, expected: 3},
+		{name: "scale up when all members are ready", current: 1, ready: 1, desired: 3, expected: 2},
+		{name: "wait for new member before scaling up", current: 2, ready: 1, desired: 3, expected: 2},
+		{name: "scale down one member at a time", current: 3, ready: 3, desired: 1, expected: 2},
+		{name: "scale down without ready members", current: 3, ready: 0, desired: 1, expected: 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			g.Expect(NextReplicas(test.current, test.ready, test.desired)).To(Equal(test.expected))
+		})
+	}
+}
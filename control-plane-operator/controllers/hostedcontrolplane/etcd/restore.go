@@ -0,0 +1,89 @@
+package etcd
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+const (
+	// EtcdRestoreSnapshotAnnotation records the snapshot the etcd StatefulSet was
+	// last restored from, so that a restore only happens once per snapshot.
+	EtcdRestoreSnapshotAnnotation = "hypershift.openshift.io/etcd-snapshot"
+
+	// EtcdRestoredSnapshotAnnotation records the snapshot once the first member
+	// has been restored from it and is ready, after which the members no longer
+	// need access to the backup destination.
+	EtcdRestoredSnapshotAnnotation = "hypershift.openshift.io/etcd-restored-snapshot"
+)
+
+func buildEtcdFetchSnapshotContainer(image string, s3 *hyperv1.EtcdBackupS3Destination, snapshotName string) func(c *corev1.Container) {
+	return func(c *corev1.Container) {
+		c.Image = image
+		c.Command = []string{"/bin/sh", "-c"}
+		c.Args = []string{
+			etcdFetchSnapshotScript(
+				path.Join(volumeMounts.Path(c.Name, etcdVolumeData().Name), etcdDataDir),
+				path.Join(volumeMounts.Path(c.Name, etcdVolumeSnapshot().Name), etcdSnapshotFile),
+				append([]string{"--key", snapshotName}, s3Args(s3, volumeMounts.Path(c.Name, etcdVolumeS3Credentials().Name))...),
+			),
+		}
+		c.VolumeMounts = volumeMounts.ContainerMounts(c.Name)
+	}
+}
+
+// etcdFetchSnapshotScript downloads the snapshot a new cluster is restored
+// from. Only the first member of a cluster without data needs it.
+func etcdFetchSnapshotScript(dataDir, snapshotFile string, s3Args []string) string {
+	var script = `#!/bin/sh
+set -eu
+if [ "${HOSTNAME##*-}" != "0" ] || [ -d "%[1]s" ] || [ -f "%[2]s" ]; then
+  exit 0
+fi
+exec /usr/bin/hypershift etcd fetch-snapshot --output "%[2]s" %[3]s
+`
+	quoted := make([]string, 0, len(s3Args))
+	for _, arg := range s3Args {
+		quoted = append(quoted, fmt.Sprintf("'%s'", strings.ReplaceAll(arg, "'", `'\''`)))
+	}
+	return fmt.Sprintf(script, dataDir, snapshotFile, strings.Join(quoted, " "))
+}
+
+func buildEtcdCopySnapshotContainer(image, snapshotName string) func(c *corev1.Container) {
+	return func(c *corev1.Container) {
+		c.Image = image
+		c.Command = []string{"/bin/sh", "-c"}
+		c.Args = []string{
+			etcdCopySnapshotScript(
+				path.Join(volumeMounts.Path(c.Name, etcdVolumeData().Name), etcdDataDir),
+				path.Join(volumeMounts.Path(c.Name, etcdVolumeSnapshot().Name), etcdSnapshotFile),
+				path.Join(volumeMounts.Path(c.Name, etcdVolumeBackup().Name), snapshotName),
+			),
+		}
+		c.VolumeMounts = volumeMounts.ContainerMounts(c.Name)
+		for i := range c.VolumeMounts {
+			if c.VolumeMounts[i].Name == etcdVolumeBackup().Name {
+				c.VolumeMounts[i].ReadOnly = true
+			}
+		}
+	}
+}
+
+// etcdCopySnapshotScript copies the snapshot a new cluster is restored from
+// out of the backup volume. Only the first member of a cluster without data
+// needs it.
+func etcdCopySnapshotScript(dataDir, snapshotFile, backupFile string) string {
+	var script = `#!/bin/sh
+set -eu
+if [ "${HOSTNAME##*-}" != "0" ] || [ -d "%[1]s" ] || [ -f "%[2]s" ]; then
+  exit 0
+fi
+cp "%[3]s" "%[2]s.part"
+mv "%[2]s.part" "%[2]s"
+`
+	return fmt.Sprintf(script, dataDir, snapshotFile, backupFile)
+}
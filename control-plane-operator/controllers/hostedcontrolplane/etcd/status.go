@@ -2,41 +2,38 @@ package etcd
 
 import (
 	"context"
-	"fmt"
 	"time"
 
-	corev1 "k8s.io/api/core/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
-	etcdv1 "github.com/openshift/hypershift/control-plane-operator/thirdparty/etcd/v1beta2"
 )
 
 const (
-	etcdClusterLabel            = "etcd_cluster"
 	etcdClusterBootstrapTimeout = 5 * time.Minute
 
-	EtcdReasonFailed  = "EtcdFailed"
-	EtcdReasonRunning = "EtcdRunning"
-	EtcdReasonScaling = "EtcdScalingUp"
+	EtcdReasonFailed     = "EtcdFailed"
+	EtcdReasonRunning    = "EtcdRunning"
+	EtcdReasonScaling    = "EtcdScalingUp"
+	EtcdReasonQuorumLost = "EtcdQuorumLost"
 )
 
-func etcdClusterConditionByType(conditions []etcdv1.ClusterCondition, t etcdv1.ClusterConditionType) *etcdv1.ClusterCondition {
-	for i, cond := range conditions {
-		if cond.Type == t {
-			return &conditions[i]
-		}
+// ComputeEtcdClusterStatus computes the EtcdAvailable condition from the etcd
+// StatefulSet and its pods. The cluster is available as long as a quorum of
+// its members is ready. Once the members have formed a cluster, a missing
+// quorum is reported as lost rather than as a cluster still being created.
+func ComputeEtcdClusterStatus(ctx context.Context, c client.Client, sts *appsv1.StatefulSet) (metav1.Condition, error) {
+	replicas := 1
+	if sts.Spec.Replicas != nil {
+		replicas = int(*sts.Spec.Replicas)
 	}
-	return nil
-}
-
-func ComputeEtcdClusterStatus(ctx context.Context, c client.Client, cluster *etcdv1.EtcdCluster) (metav1.Condition, error) {
-	availableCondition := etcdClusterConditionByType(cluster.Status.Conditions, etcdv1.ClusterConditionAvailable)
+	ready := int(sts.Status.ReadyReplicas)
 
 	var cond metav1.Condition
 	switch {
-	case availableCondition != nil && availableCondition.Status == corev1.ConditionTrue:
+	case replicas > 0 && ready >= Quorum(replicas):
 		// Etcd cluster is available
 		cond = metav1.Condition{
 			Type:    string(hyperv1.EtcdAvailable),
@@ -44,27 +41,41 @@ func ComputeEtcdClusterStatus(ctx context.Context, c client.Client, cluster *etc
 			Reason:  EtcdReasonRunning,
 			Message: "Etcd cluster is running and available",
 		}
-	case len(cluster.Status.Members.Ready) == 0 && time.Since(cluster.CreationTimestamp.Time) > etcdClusterBootstrapTimeout:
-		// The etcd cluster failed to bootstrap, will delete
+	case ready == 0 && replicas > 0 && !IsClusterFormed(sts) && time.Since(sts.CreationTimestamp.Time) > etcdClusterBootstrapTimeout:
 		cond = metav1.Condition{
 			Type:    string(hyperv1.EtcdAvailable),
 			Status:  metav1.ConditionFalse,
 			Reason:  EtcdReasonFailed,
-			Message: "Etcd cluster failed to bootstrap within timeout, recreating",
+			Message: "Etcd cluster has no ready members",
 		}
-	case cluster.Spec.Size > 1 && len(cluster.Status.Members.Ready) <= 1:
-		hasTerminatedPods, err := etcdClusterHasTerminatedPods(ctx, c, cluster)
+	default:
+		pods, err := ListMemberPods(ctx, c, sts.Namespace)
 		if err != nil {
 			return cond, err
 		}
-		if hasTerminatedPods {
+		hasFailedMembers := false
+		for i := range pods {
+			if IsMemberFailed(&pods[i]) {
+				hasFailedMembers = true
+				break
+			}
+		}
+		switch {
+		case hasFailedMembers:
 			cond = metav1.Condition{
 				Type:    string(hyperv1.EtcdAvailable),
 				Status:  metav1.ConditionFalse,
 				Reason:  EtcdReasonFailed,
-				Message: "Etcd has failed to achieve quorum after bootstrap, recreating",
+				Message: "Etcd has failed to achieve quorum, failed members are being replaced",
 			}
-		} else {
+		case IsClusterFormed(sts):
+			cond = metav1.Condition{
+				Type:    string(hyperv1.EtcdAvailable),
+				Status:  metav1.ConditionFalse,
+				Reason:  EtcdReasonQuorumLost,
+				Message: "Etcd cluster has lost quorum, members without data wait for the remaining members instead of bootstrapping a new cluster",
+			}
+		default:
 			cond = metav1.Condition{
 				Type:    string(hyperv1.EtcdAvailable),
 				Status:  metav1.ConditionFalse,
@@ -72,31 +83,6 @@ func ComputeEtcdClusterStatus(ctx context.Context, c client.Client, cluster *etc
 				Message: "Etcd cluster is scaling up",
 			}
 		}
-	default:
-		cond = metav1.Condition{
-			Type:    string(hyperv1.EtcdAvailable),
-			Status:  metav1.ConditionFalse,
-			Reason:  EtcdReasonScaling,
-			Message: "Etcd cluster is scaling up",
-		}
 	}
 	return cond, nil
 }
-
-func etcdClusterHasTerminatedPods(ctx context.Context, c client.Client, cluster *etcdv1.EtcdCluster) (bool, error) {
-	// If only one member ready and waiting for another to come up, check pod status
-	etcdPods := &corev1.PodList{}
-	err := c.List(ctx, etcdPods, client.MatchingLabels{etcdClusterLabel: cluster.Name})
-	if err != nil {
-		return false, fmt.Errorf("cannot list etcd cluster pods: %w", err)
-	}
-	// Check for any pods in error
-	for _, pod := range etcdPods.Items {
-		for _, cs := range pod.Status.ContainerStatuses {
-			if cs.State.Terminated != nil {
-				return true, nil
-			}
-		}
-	}
-	return false, nil
-}
@@ -0,0 +1,102 @@
+package etcd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+)
+
+// noPodsClient is a client which finds no etcd member pods.
+type noPodsClient struct {
+	client.Client
+}
+
+func (noPodsClient) List(context.Context, client.ObjectList, ...client.ListOption) error {
+	return nil
+}
+
+func TestComputeEtcdClusterStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		replicas       int32
+		ready          int32
+		age            time.Duration
+		formed         bool
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "single member ready",
+			replicas:       1,
+			ready:          1,
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: EtcdReasonRunning,
+		},
+		{
+			name:           "quorum of members ready",
+			replicas:       3,
+			ready:          2,
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: EtcdReasonRunning,
+		},
+		{
+			name:           "no members ready after bootstrap timeout",
+			replicas:       3,
+			ready:          0,
+			age:            2 * etcdClusterBootstrapTimeout,
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: EtcdReasonFailed,
+		},
+		{
+			name:           "formed cluster without quorum",
+			replicas:       3,
+			ready:          1,
+			formed:         true,
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: EtcdReasonQuorumLost,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			sts := manifests.EtcdStatefulSet("test")
+			sts.CreationTimestamp = metav1.NewTime(time.Now().Add(-test.age))
+			sts.Spec.Replicas = pointer.Int32Ptr(test.replicas)
+			sts.Status.ReadyReplicas = test.ready
+			if test.formed {
+				sts.Annotations = map[string]string{EtcdClusterFormedAnnotation: "true"}
+			}
+			cond, err := ComputeEtcdClusterStatus(context.Background(), noPodsClient{}, sts)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cond.Status).To(Equal(test.expectedStatus))
+			g.Expect(cond.Reason).To(Equal(test.expectedReason))
+		})
+	}
+}
+
+func TestIsMemberFailed(t *testing.T) {
+	g := NewGomegaWithT(t)
+	pod := func(state corev1.ContainerState, restarts int32) *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: etcdContainer().Name, State: state, RestartCount: restarts},
+				},
+			},
+		}
+	}
+	crashLoop := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+	g.Expect(IsMemberFailed(pod(corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}, 0))).To(BeFalse())
+	g.Expect(IsMemberFailed(pod(crashLoop, 1))).To(BeFalse())
+	g.Expect(IsMemberFailed(pod(crashLoop, etcdMemberCrashLoopRestarts))).To(BeTrue())
+	g.Expect(IsMemberFailed(pod(corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}, 0))).To(BeTrue())
+}
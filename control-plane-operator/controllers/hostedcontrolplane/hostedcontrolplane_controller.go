@@ -11,6 +11,7 @@ import (
 	"math/big"
 	"math/rand"
 	"net/url"
+	"sort"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -18,7 +19,9 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	"golang.org/x/crypto/bcrypt"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,11 +34,13 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/util"
@@ -47,6 +52,7 @@ import (
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/common"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/cvo"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/encryption"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/etcd"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/ingress"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/kas"
@@ -69,6 +75,10 @@ const (
 	DefaultAdminKubeconfigName = "admin-kubeconfig"
 	DefaultAdminKubeconfigKey  = "kubeconfig"
 	oauthBrandingManifest      = "v4-0-config-system-branding.yaml"
+
+	// minCertificateRotationRequeue bounds how often the control plane is
+	// requeued for certificates which are already due for rotation.
+	minCertificateRotationRequeue = time.Minute
 )
 
 var (
@@ -107,6 +117,10 @@ type HostedControlPlaneReconciler struct {
 	Log             logr.Logger
 	ReleaseProvider releaseinfo.Provider
 	HostedAPICache  hostedapicache.HostedAPICache
+
+	// DefaultKonnectivityImage is the konnectivity image used for release
+	// images which don't carry one.
+	DefaultKonnectivityImage string
 }
 
 func (r *HostedControlPlaneReconciler) SetupWithManager(mgr ctrl.Manager) error {
@@ -116,9 +130,13 @@ func (r *HostedControlPlaneReconciler) SetupWithManager(mgr ctrl.Manager) error
 			RateLimiter: workqueue.NewItemExponentialFailureRateLimiter(1*time.Second, 10*time.Second),
 		}).
 		Watches(&source.Kind{Type: &etcdv1.EtcdCluster{}}, &handler.EnqueueRequestForOwner{OwnerType: &hyperv1.HostedControlPlane{}}).
+		Watches(&source.Kind{Type: &appsv1.StatefulSet{}}, &handler.EnqueueRequestForOwner{OwnerType: &hyperv1.HostedControlPlane{}}).
+		Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(r.etcdPodToHostedControlPlane)).
 		Watches(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{OwnerType: &hyperv1.HostedControlPlane{}}).
 		Watches(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestForOwner{OwnerType: &hyperv1.HostedControlPlane{}}).
 		Watches(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{OwnerType: &hyperv1.HostedControlPlane{}}).
+		Watches(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{OwnerType: &hyperv1.HostedControlPlane{}}).
+		Watches(&source.Kind{Type: &policyv1beta1.PodDisruptionBudget{}}, &handler.EnqueueRequestForOwner{OwnerType: &hyperv1.HostedControlPlane{}}).
 		Watches(&source.Channel{Source: r.HostedAPICache.Events()}, &handler.EnqueueRequestForOwner{OwnerType: &hyperv1.HostedControlPlane{}}).
 		Build(r)
 	if err != nil {
@@ -171,10 +189,18 @@ func (r *HostedControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.R
 			Type:               string(hyperv1.ValidConfiguration),
 			ObservedGeneration: hostedControlPlane.Generation,
 		}
+		restoreUnsupported, err := r.etcdRestoreUnsupported(ctx, hostedControlPlane)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
 		if err := config.ValidateGlobalConfig(ctx, hostedControlPlane); err != nil {
 			condition.Status = metav1.ConditionFalse
 			condition.Message = err.Error()
 			condition.Reason = "InvalidConfiguration"
+		} else if restoreUnsupported {
+			condition.Status = metav1.ConditionFalse
+			condition.Message = fmt.Sprintf("The etcd restore from snapshot %s is ignored, etcd clusters created by the etcd operator can't be restored", hostedControlPlane.Spec.Etcd.Managed.Restore.SnapshotName)
+			condition.Reason = "InvalidConfiguration"
 		} else {
 			condition.Status = metav1.ConditionTrue
 			condition.Message = "Configuration passes validation"
@@ -183,6 +209,16 @@ func (r *HostedControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.R
 		meta.SetStatusCondition(&hostedControlPlane.Status.Conditions, condition)
 	}
 
+	// Validate the custom audit policy. An invalid policy doesn't block the
+	// reconcile, the kube apiserver keeps auditing with its previous policy.
+	{
+		condition, err := r.auditPolicyCondition(ctx, hostedControlPlane)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		meta.SetStatusCondition(&hostedControlPlane.Status.Conditions, condition)
+	}
+
 	// Reconcile etcd cluster status
 	{
 		newCondition := metav1.Condition{
@@ -194,7 +230,16 @@ func (r *HostedControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.R
 		case hyperv1.Managed:
 			r.Log.Info("Reconciling etcd cluster status for managed strategy")
 			etcdCluster := manifests.EtcdCluster(hostedControlPlane.Namespace)
-			if err := r.Get(ctx, types.NamespacedName{Namespace: etcdCluster.Namespace, Name: etcdCluster.Name}, etcdCluster); err != nil {
+			etcdStatefulSet := manifests.EtcdStatefulSet(hostedControlPlane.Namespace)
+			if err := r.Get(ctx, client.ObjectKeyFromObject(etcdCluster), etcdCluster); err == nil {
+				cond, err := etcd.ComputeEtcdOperatorClusterStatus(ctx, r.Client, etcdCluster)
+				if err != nil {
+					return ctrl.Result{}, fmt.Errorf("failed to compute etcd cluster status: %w", err)
+				}
+				newCondition = cond
+			} else if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+				return ctrl.Result{}, fmt.Errorf("failed to fetch etcd cluster %s/%s: %w", etcdCluster.Namespace, etcdCluster.Name, err)
+			} else if err := r.Get(ctx, types.NamespacedName{Namespace: etcdStatefulSet.Namespace, Name: etcdStatefulSet.Name}, etcdStatefulSet); err != nil {
 				if apierrors.IsNotFound(err) {
 					newCondition = metav1.Condition{
 						Type:   string(hyperv1.EtcdAvailable),
@@ -202,11 +247,11 @@ func (r *HostedControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.R
 						Reason: "EtcdClusterNotFound",
 					}
 				} else {
-					return ctrl.Result{}, fmt.Errorf("failed to fetch etcd cluster %s/%s: %w", etcdCluster.Namespace, etcdCluster.Name, err)
+					return ctrl.Result{}, fmt.Errorf("failed to fetch etcd statefulset %s/%s: %w", etcdStatefulSet.Namespace, etcdStatefulSet.Name, err)
 				}
 			} else {
 				r.Log.Info("Computing proper etcd cluster status based on current state of etcd cluster")
-				cond, err := etcd.ComputeEtcdClusterStatus(ctx, r.Client, etcdCluster)
+				cond, err := etcd.ComputeEtcdClusterStatus(ctx, r.Client, etcdStatefulSet)
 				if err != nil {
 					return ctrl.Result{}, fmt.Errorf("failed to compute etcd cluster status: %w", err)
 				}
@@ -371,6 +416,22 @@ func (r *HostedControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.R
 		r.Log.Info("Finished reconciling hosted cluster version conditions")
 	}
 
+	// Record the number of nodes of the hosted cluster, the HostedCluster is
+	// sized by it
+	{
+		timeout, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		// Only the metadata of nodes is cached by the hosted API cache
+		nodes := &metav1.PartialObjectMetadataList{}
+		nodes.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NodeList"))
+		if err := r.HostedAPICache.List(timeout, nodes); err != nil {
+			r.Log.Info("failed to list nodes, can't determine the node count", "error", err)
+		} else {
+			nodeCount := int32(len(nodes.Items))
+			hostedControlPlane.Status.NodeCount = &nodeCount
+		}
+	}
+
 	if hostedControlPlane.Spec.KubeConfig != nil {
 		hostedControlPlane.Status.KubeConfig = hostedControlPlane.Spec.KubeConfig
 	} else {
@@ -381,6 +442,25 @@ func (r *HostedControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 	hostedControlPlane.Status.Initialized = true
 
+	nextRotation, err := r.reconcileCertificateStatus(ctx, hostedControlPlane)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	{
+		releaseImage, err := r.LookupReleaseImage(ctx, hostedControlPlane)
+		if err != nil {
+			r.Log.Error(err, "failed to look up release image metadata, can't determine konnectivity images")
+		} else {
+			images := konnectivity.ResolveImages(releaseImage.ComponentImages(), r.DefaultKonnectivityImage)
+			if len(images.Server.Image) > 0 && len(images.Agent.Image) > 0 {
+				hostedControlPlane.Status.KonnectivityImages = images
+			} else {
+				hostedControlPlane.Status.KonnectivityImages = nil
+			}
+		}
+	}
+
 	// If a rollout is in progress, compute and record the rollout status. The
 	// image version will be considered rolled out if the hosted CVO reports
 	// having completed the rollout of the semantic version matching the release
@@ -423,7 +503,92 @@ func (r *HostedControlPlaneReconciler) Reconcile(ctx context.Context, req ctrl.R
 	}
 
 	r.Log.Info("Successfully reconciled")
-	return ctrl.Result{}, nil
+	var result ctrl.Result
+	if hostedControlPlane.Spec.SecretEncryption != nil && hostedControlPlane.Spec.SecretEncryption.KMS != nil {
+		// The token of the KMS plugins must be refreshed before it expires
+		result.RequeueAfter = encryption.KMSTokenRefreshInterval
+	}
+	if !nextRotation.IsZero() {
+		// Certificates must be renewed and replaced CAs pruned on time
+		untilRotation := time.Until(nextRotation)
+		if untilRotation < minCertificateRotationRequeue {
+			untilRotation = minCertificateRotationRequeue
+		}
+		if result.RequeueAfter == 0 || untilRotation < result.RequeueAfter {
+			result.RequeueAfter = untilRotation
+		}
+	}
+	return result, nil
+}
+
+// reconcileCertificateStatus reports the expiry of the certificates of the
+// control plane PKI in the status, the CertificatesExpiring condition and
+// metrics. It returns the next time one of them needs to be rotated.
+func (r *HostedControlPlaneReconciler) reconcileCertificateStatus(ctx context.Context, hcp *hyperv1.HostedControlPlane) (time.Time, error) {
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, client.InNamespace(hcp.Namespace)); err != nil {
+		return time.Time{}, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	var certificates []hyperv1.CertificateStatus
+	var expiries []pki.CertificateExpiry
+	var nextRotation time.Time
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if next, ok := pki.NextRotation(secret); ok && (nextRotation.IsZero() || next.Before(nextRotation)) {
+			nextRotation = next
+		}
+		if !pki.IsManagedSecret(secret) {
+			continue
+		}
+		expiries = append(expiries, pki.CertificateExpiries(secret)...)
+		if status, ok := pki.CertificateStatus(secret); ok {
+			certificates = append(certificates, status)
+		}
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].Secret < certificates[j].Secret
+	})
+	hcp.Status.Certificates = certificates
+
+	updateCertificateMetrics(expiries)
+	condition := pki.NewRotationPolicy(hcp, time.Now()).CertificatesExpiringCondition(expiries)
+	condition.ObservedGeneration = hcp.Generation
+	meta.SetStatusCondition(&hcp.Status.Conditions, condition)
+	return nextRotation, nil
+}
+
+// auditPolicyCondition computes the ValidAuditPolicy condition from the custom
+// audit policy of the control plane, if it has one.
+func (r *HostedControlPlaneReconciler) auditPolicyCondition(ctx context.Context, hcp *hyperv1.HostedControlPlane) (metav1.Condition, error) {
+	condition := metav1.Condition{
+		Type:               string(hyperv1.ValidAuditPolicy),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: hcp.Generation,
+		Reason:             hyperv1.HostedClusterAsExpectedReason,
+		Message:            "No custom audit policy is set",
+	}
+	if hcp.Spec.Audit == nil || hcp.Spec.Audit.Policy == nil || len(hcp.Spec.Audit.Policy.Name) == 0 {
+		return condition, nil
+	}
+	name := hcp.Spec.Audit.Policy.Name
+	policy := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: hcp.Namespace, Name: name}}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(policy), policy); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return condition, fmt.Errorf("failed to get audit policy %s: %w", name, err)
+		}
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidAuditPolicy"
+		condition.Message = fmt.Sprintf("Audit policy config map %s not found", name)
+		return condition, nil
+	}
+	if _, err := kas.ValidateCustomAuditPolicy(policy); err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidAuditPolicy"
+		condition.Message = fmt.Sprintf("Invalid audit policy in config map %s: %v", name, err)
+		return condition, nil
+	}
+	condition.Message = fmt.Sprintf("Audit policy in config map %s is valid", name)
+	return condition, nil
 }
 
 func (r *HostedControlPlaneReconciler) LookupReleaseImage(ctx context.Context, hcp *hyperv1.HostedControlPlane) (*releaseinfo.ReleaseImage, error) {
@@ -433,7 +598,28 @@ func (r *HostedControlPlaneReconciler) LookupReleaseImage(ctx context.Context, h
 	}
 	lookupCtx, lookupCancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer lookupCancel()
-	return r.ReleaseProvider.Lookup(lookupCtx, hcp.Spec.ReleaseImage, pullSecret.Data[corev1.DockerConfigJsonKey])
+	releaseImage, err := r.ReleaseProvider.Lookup(lookupCtx, hcp.Spec.ReleaseImage, pullSecret.Data[corev1.DockerConfigJsonKey])
+	if err != nil {
+		return nil, err
+	}
+	return releaseImage.WithComponentImages(componentImageOverrides(hcp)), nil
+}
+
+// componentImageOverrides returns the images which replace the images of the
+// release image for a HostedControlPlane. The ImageOverrides of the spec take
+// precedence over the deprecated konnectivity image annotations.
+func componentImageOverrides(hcp *hyperv1.HostedControlPlane) map[string]string {
+	images := map[string]string{}
+	if image, ok := hcp.Annotations[hyperv1.KonnectivityServerImageAnnotation]; ok {
+		images[string(hyperv1.KonnectivityServerImageComponent)] = image
+	}
+	if image, ok := hcp.Annotations[hyperv1.KonnectivityAgentImageAnnotation]; ok {
+		images[string(hyperv1.KonnectivityAgentImageComponent)] = image
+	}
+	for _, override := range hcp.Spec.ImageOverrides {
+		images[string(override.Component)] = override.Image
+	}
+	return images
 }
 
 func (r *HostedControlPlaneReconciler) update(ctx context.Context, hostedControlPlane *hyperv1.HostedControlPlane) error {
@@ -569,6 +755,12 @@ func (r *HostedControlPlaneReconciler) update(ctx context.Context, hostedControl
 		return fmt.Errorf("failed to reconcile konnectivity: %w", err)
 	}
 
+	// Reconcile encryption at rest
+	r.Log.Info("Reconciling Encryption")
+	if err := r.reconcileEncryption(ctx, hostedControlPlane, globalConfig, releaseImage); err != nil {
+		return fmt.Errorf("failed to reconcile encryption: %w", err)
+	}
+
 	// Reconcile kube apiserver
 	r.Log.Info("Reconciling Kube API Server")
 	if err := r.reconcileKubeAPIServer(ctx, hostedControlPlane, globalConfig, releaseImage, infraStatus.OAuthHost, infraStatus.OAuthPort); err != nil {
@@ -899,6 +1091,32 @@ func (r *HostedControlPlaneReconciler) reconcileClusterIPServiceStatus(ctx conte
 	return svc.Spec.ClusterIP, nil
 }
 
+// reconcilePodDisruptionBudget protects the pods selected by a workload from
+// voluntary disruptions while the component runs more than one replica, and
+// removes the PodDisruptionBudget otherwise since it would block node drains.
+func (r *HostedControlPlaneReconciler) reconcilePodDisruptionBudget(ctx context.Context, workload client.Object, selector *metav1.LabelSelector, deploymentConfig config.DeploymentConfig, ownerRef config.OwnerRef) error {
+	pdb := manifests.PodDisruptionBudget(workload)
+	if !deploymentConfig.NeedsPodDisruptionBudget() || selector == nil {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(pdb), pdb); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get pod disruption budget %s: %w", pdb.Name, err)
+		}
+		if err := r.Delete(ctx, pdb); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete pod disruption budget %s: %w", pdb.Name, err)
+		}
+		return nil
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r, pdb, func() error {
+		config.ReconcilePodDisruptionBudget(pdb, ownerRef, selector.MatchLabels)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile pod disruption budget %s: %w", pdb.Name, err)
+	}
+	return nil
+}
+
 func (r *HostedControlPlaneReconciler) ensureControlPlane(ctx context.Context, hcp *hyperv1.HostedControlPlane, infraStatus InfrastructureStatus, releaseImage *releaseinfo.ReleaseImage) error {
 	r.Log.Info("ensuring control plane for cluster", "cluster", hcp.Name)
 
@@ -979,18 +1197,19 @@ func (r *HostedControlPlaneReconciler) ensureControlPlane(ctx context.Context, h
 
 func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hyperv1.HostedControlPlane, infraStatus InfrastructureStatus) error {
 	p := pki.NewPKIParams(hcp, infraStatus.APIHost, infraStatus.OAuthHost, infraStatus.KonnectivityHost)
+	rotation := pki.NewRotationPolicy(hcp, time.Now())
 
 	// Root CA
 	rootCASecret := manifests.RootCASecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, rootCASecret, func() error {
-		return pki.ReconcileRootCA(rootCASecret, p.OwnerRef)
+		return pki.ReconcileRootCA(rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile root CA: %w", err)
 	}
 	// Signer CA
 	signerCASecret := manifests.ClusterSignerCASecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, signerCASecret, func() error {
-		return pki.ReconcileClusterSignerCA(signerCASecret, p.OwnerRef)
+		return pki.ReconcileClusterSignerCA(signerCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile signer CA: %w", err)
 	}
@@ -1005,7 +1224,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// Etcd client secret
 	etcdClientSecret := manifests.EtcdClientSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, etcdClientSecret, func() error {
-		return pki.ReconcileEtcdClientSecret(etcdClientSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileEtcdClientSecret(etcdClientSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile etcd client secret: %w", err)
 	}
@@ -1013,7 +1232,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// Etcd server secret
 	etcdServerSecret := manifests.EtcdServerSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, etcdServerSecret, func() error {
-		return pki.ReconcileEtcdServerSecret(etcdServerSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileEtcdServerSecret(etcdServerSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile etcd server secret: %w", err)
 	}
@@ -1021,7 +1240,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// Etcd peer secret
 	etcdPeerSecret := manifests.EtcdPeerSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, etcdPeerSecret, func() error {
-		return pki.ReconcileEtcdPeerSecret(etcdPeerSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileEtcdPeerSecret(etcdPeerSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile etcd peer secret: %w", err)
 	}
@@ -1029,7 +1248,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// KAS server secret
 	kasServerSecret := manifests.KASServerCertSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, kasServerSecret, func() error {
-		return pki.ReconcileKASServerCertSecret(kasServerSecret, rootCASecret, p.OwnerRef, p.ExternalAPIAddress, p.ServiceCIDR)
+		return pki.ReconcileKASServerCertSecret(kasServerSecret, rootCASecret, p.OwnerRef, rotation, p.ExternalAPIAddress, p.ServiceCIDR)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile kas server secret: %w", err)
 	}
@@ -1037,7 +1256,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// KAS kubelet client secret
 	kasKubeletClientSecret := manifests.KASKubeletClientCertSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, kasKubeletClientSecret, func() error {
-		return pki.ReconcileKASKubeletClientCertSecret(kasKubeletClientSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileKASKubeletClientCertSecret(kasKubeletClientSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile kas kubelet client secret: %w", err)
 	}
@@ -1045,7 +1264,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// KAS aggregator cert secret
 	kasAggregatorCertSecret := manifests.KASAggregatorCertSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, kasAggregatorCertSecret, func() error {
-		return pki.ReconcileKASAggregatorCertSecret(kasAggregatorCertSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileKASAggregatorCertSecret(kasAggregatorCertSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile kas aggregator secret: %w", err)
 	}
@@ -1053,7 +1272,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// KAS admin client cert secret
 	kasAdminClientCertSecret := manifests.KASAdminClientCertSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, kasAdminClientCertSecret, func() error {
-		return pki.ReconcileKASAdminClientCertSecret(kasAdminClientCertSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileKASAdminClientCertSecret(kasAdminClientCertSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile kas admin client secret: %w", err)
 	}
@@ -1061,7 +1280,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// KAS bootstrap client cert secret
 	kasBootstrapClientCertSecret := manifests.KASMachineBootstrapClientCertSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, kasBootstrapClientCertSecret, func() error {
-		return pki.ReconcileKASMachineBootstrapClientCertSecret(kasBootstrapClientCertSecret, signerCASecret, p.OwnerRef)
+		return pki.ReconcileKASMachineBootstrapClientCertSecret(kasBootstrapClientCertSecret, signerCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile kas bootstrap client secret: %w", err)
 	}
@@ -1076,7 +1295,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 		}
 	}
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, serviceAccountSigningKeySecret, func() error {
-		return pki.ReconcileServiceAccountSigningKeySecret(serviceAccountSigningKeySecret, signingKeySecret, p.OwnerRef)
+		return pki.ReconcileServiceAccountSigningKeySecret(serviceAccountSigningKeySecret, signingKeySecret, p.OwnerRef, pki.SigningKeyOverlap(hcp), time.Now())
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile api server service account key secret: %w", err)
 	}
@@ -1084,7 +1303,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// OpenShift APIServer
 	openshiftAPIServerCertSecret := manifests.OpenShiftAPIServerCertSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, openshiftAPIServerCertSecret, func() error {
-		return pki.ReconcileOpenShiftAPIServerCertSecret(openshiftAPIServerCertSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileOpenShiftAPIServerCertSecret(openshiftAPIServerCertSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile kas admin client secret: %w", err)
 	}
@@ -1092,7 +1311,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// OpenShift OAuth APIServer
 	openshiftOAuthAPIServerCertSecret := manifests.OpenShiftOAuthAPIServerCertSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, openshiftOAuthAPIServerCertSecret, func() error {
-		return pki.ReconcileOpenShiftOAuthAPIServerCertSecret(openshiftOAuthAPIServerCertSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileOpenShiftOAuthAPIServerCertSecret(openshiftOAuthAPIServerCertSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile openshift oauth apiserver cert: %w", err)
 	}
@@ -1100,7 +1319,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// OpenShift ControllerManager Cert
 	openshiftControllerManagerCertSecret := manifests.OpenShiftControllerManagerCertSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, openshiftControllerManagerCertSecret, func() error {
-		return pki.ReconcileOpenShiftControllerManagerCertSecret(openshiftControllerManagerCertSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileOpenShiftControllerManagerCertSecret(openshiftControllerManagerCertSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile openshift controller manager cert: %w", err)
 	}
@@ -1108,7 +1327,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// Cluster Policy Controller Cert
 	clusterPolicyControllerCertSecret := manifests.ClusterPolicyControllerCertSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, clusterPolicyControllerCertSecret, func() error {
-		return pki.ReconcileOpenShiftControllerManagerCertSecret(clusterPolicyControllerCertSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileOpenShiftControllerManagerCertSecret(clusterPolicyControllerCertSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile cluster policy controller cert: %w", err)
 	}
@@ -1116,7 +1335,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// Konnectivity Server Cert
 	konnectivityServerSecret := manifests.KonnectivityServerSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, konnectivityServerSecret, func() error {
-		return pki.ReconcileKonnectivityServerSecret(konnectivityServerSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileKonnectivityServerSecret(konnectivityServerSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile konnectivity server cert: %w", err)
 	}
@@ -1124,7 +1343,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// Konnectivity Cluster Cert
 	konnectivityClusterSecret := manifests.KonnectivityClusterSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, konnectivityClusterSecret, func() error {
-		return pki.ReconcileKonnectivityClusterSecret(konnectivityClusterSecret, rootCASecret, p.OwnerRef, p.ExternalKconnectivityAddress)
+		return pki.ReconcileKonnectivityClusterSecret(konnectivityClusterSecret, rootCASecret, p.OwnerRef, rotation, p.ExternalKconnectivityAddress)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile konnectivity cluster cert: %w", err)
 	}
@@ -1132,7 +1351,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// Konnectivity Client Cert
 	konnectivityClientSecret := manifests.KonnectivityClientSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, konnectivityClientSecret, func() error {
-		return pki.ReconcileKonnectivityClientSecret(konnectivityClientSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileKonnectivityClientSecret(konnectivityClientSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile konnectivity client cert: %w", err)
 	}
@@ -1140,7 +1359,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// Konnectivity Agent Cert
 	konnectivityAgentSecret := manifests.KonnectivityAgentSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, konnectivityAgentSecret, func() error {
-		return pki.ReconcileKonnectivityAgentSecret(konnectivityAgentSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileKonnectivityAgentSecret(konnectivityAgentSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile konnectivity agent cert: %w", err)
 	}
@@ -1148,7 +1367,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// Konnectivity Worker Agent Cert
 	konnectivityWorkerAgentSecret := manifests.KonnectivityWorkerAgentSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, konnectivityWorkerAgentSecret, func() error {
-		return pki.ReconcileKonnectivityWorkerAgentSecret(konnectivityWorkerAgentSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileKonnectivityWorkerAgentSecret(konnectivityWorkerAgentSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile konnectivity worker agent cert: %w", err)
 	}
@@ -1156,7 +1375,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// Ingress Cert
 	ingressCert := manifests.IngressCert(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, ingressCert, func() error {
-		return pki.ReconcileIngressCert(ingressCert, rootCASecret, p.OwnerRef, p.ExternalOauthAddress, p.IngressSubdomain)
+		return pki.ReconcileIngressCert(ingressCert, rootCASecret, p.OwnerRef, rotation, p.ExternalOauthAddress, p.IngressSubdomain)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile ingress cert secret: %w", err)
 	}
@@ -1174,7 +1393,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// MCS Cert
 	machineConfigServerCert := manifests.MachineConfigServerCert(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, machineConfigServerCert, func() error {
-		return pki.ReconcileMachineConfigServerCert(machineConfigServerCert, rootCASecret, p.OwnerRef)
+		return pki.ReconcileMachineConfigServerCert(machineConfigServerCert, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile machine config server cert secret: %w", err)
 	}
@@ -1182,7 +1401,7 @@ func (r *HostedControlPlaneReconciler) reconcilePKI(ctx context.Context, hcp *hy
 	// OLM PackageServer Cert
 	packageServerCertSecret := manifests.OLMPackageServerCertSecret(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, packageServerCertSecret, func() error {
-		return pki.ReconcileOLMPackageServerCertSecret(packageServerCertSecret, rootCASecret, p.OwnerRef)
+		return pki.ReconcileOLMPackageServerCertSecret(packageServerCertSecret, rootCASecret, p.OwnerRef, rotation)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile packageserver cert: %w", err)
 	}
@@ -1207,8 +1426,255 @@ func (r *HostedControlPlaneReconciler) reconcileCloudProviderConfig(ctx context.
 func (r *HostedControlPlaneReconciler) reconcileManagedEtcd(ctx context.Context, hcp *hyperv1.HostedControlPlane, releaseImage *releaseinfo.ReleaseImage) error {
 	p := etcd.NewEtcdParams(hcp, releaseImage.ComponentImages())
 
+	// Clusters created by the etcd operator keep running with it, their data
+	// can't be moved to the statefulset without taking the control plane down.
+	etcdCluster := manifests.EtcdCluster(hcp.Namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(etcdCluster), etcdCluster); err == nil {
+		return r.reconcileEtcdOperatorCluster(ctx, p, etcdCluster)
+	} else if !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to get etcd cluster: %w", err)
+	}
+
+	if err := r.removeEtcdOperatorResources(ctx, hcp.Namespace); err != nil {
+		return err
+	}
+
+	// Etcd discovery service
+	discoveryService := manifests.EtcdDiscoveryService(hcp.Namespace)
+	if _, err := controllerutil.CreateOrUpdate(ctx, r, discoveryService, func() error {
+		return etcd.ReconcileDiscoveryService(discoveryService, p.OwnerRef)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile etcd discovery service: %w", err)
+	}
+
+	// Etcd client service
+	clientService := manifests.EtcdClientService(hcp.Namespace)
+	if _, err := controllerutil.CreateOrUpdate(ctx, r, clientService, func() error {
+		return etcd.ReconcileClientService(clientService, p.OwnerRef)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile etcd client service: %w", err)
+	}
+
+	if err := r.reconcileManagedEtcdBackup(ctx, p, hcp.Namespace); err != nil {
+		return err
+	}
+
+	// Etcd statefulset
+	statefulSet := manifests.EtcdStatefulSet(hcp.Namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(statefulSet), statefulSet); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get etcd statefulset: %w", err)
+	}
+	replicas, snapshot, err := r.reconcileManagedEtcdMembers(ctx, p, statefulSet)
+	if err != nil {
+		return err
+	}
+	// The members only get access to the backup destination until the first
+	// member has been restored from the snapshot and is ready.
+	restore, restored := p.Restore, ""
+	if restore != nil && len(snapshot) == 0 {
+		switch {
+		case statefulSet.Annotations[etcd.EtcdRestoredSnapshotAnnotation] == restore.SnapshotName:
+			restore = nil
+		case statefulSet.Annotations[etcd.EtcdRestoreSnapshotAnnotation] == restore.SnapshotName &&
+			statefulSet.Status.ObservedGeneration >= statefulSet.Generation && statefulSet.Status.ReadyReplicas > 0:
+			restore, restored = nil, restore.SnapshotName
+		}
+	}
+	// The cluster has formed once a quorum of its members has been ready.
+	// Restoring replaces the cluster, so the first member bootstraps it again.
+	formed := etcd.IsClusterFormed(statefulSet)
+	switch {
+	case len(snapshot) > 0:
+		formed = false
+	case !formed && statefulSet.Spec.Replicas != nil && statefulSet.Status.ObservedGeneration >= statefulSet.Generation &&
+		statefulSet.Status.ReadyReplicas > 0 && int(statefulSet.Status.ReadyReplicas) >= etcd.Quorum(int(*statefulSet.Spec.Replicas)):
+		formed = true
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r, statefulSet, func() error {
+		if statefulSet.Annotations == nil {
+			statefulSet.Annotations = map[string]string{}
+		}
+		if formed {
+			statefulSet.Annotations[etcd.EtcdClusterFormedAnnotation] = "true"
+		} else {
+			delete(statefulSet.Annotations, etcd.EtcdClusterFormedAnnotation)
+		}
+		if err := etcd.ReconcileStatefulSet(statefulSet, p.OwnerRef, p.EtcdDeploymentConfig, p.EtcdImage, p.SnapshotImage, p.PVCClaim, p.BackupPolicy, restore); err != nil {
+			return err
+		}
+		statefulSet.Spec.Replicas = pointer.Int32Ptr(int32(replicas))
+		if len(snapshot) > 0 {
+			statefulSet.Annotations[etcd.EtcdRestoreSnapshotAnnotation] = snapshot
+		}
+		if len(restored) > 0 {
+			statefulSet.Annotations[etcd.EtcdRestoredSnapshotAnnotation] = restored
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile etcd statefulset: %w", err)
+	}
+	if err := r.reconcilePodDisruptionBudget(ctx, statefulSet, statefulSet.Spec.Selector, p.EtcdDeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
+	return nil
+}
+
+// reconcileManagedEtcdMembers returns the number of replicas of the etcd
+// statefulset, and the snapshot the cluster is being restored from if a new
+// restore starts. Members are removed from the etcd cluster before their pods
+// are removed, and failed members are replaced as long as the remaining
+// members have quorum, so that the data of the cluster is preserved.
+func (r *HostedControlPlaneReconciler) reconcileManagedEtcdMembers(ctx context.Context, p *etcd.EtcdParams, statefulSet *appsv1.StatefulSet) (int, string, error) {
+	if statefulSet.CreationTimestamp.IsZero() {
+		// A new cluster is bootstrapped by its first member
+		if p.Restore != nil {
+			return 1, p.Restore.SnapshotName, nil
+		}
+		return 1, "", nil
+	}
+	current := 1
+	if statefulSet.Spec.Replicas != nil {
+		current = int(*statefulSet.Spec.Replicas)
+	}
+	pods, err := etcd.ListMemberPods(ctx, r, statefulSet.Namespace)
+	if err != nil {
+		return current, "", err
+	}
+
+	// Restoring replaces the cluster, so all members are stopped and their data
+	// is discarded before the first member is restored from the snapshot.
+	if p.Restore != nil && statefulSet.Annotations[etcd.EtcdRestoreSnapshotAnnotation] != p.Restore.SnapshotName {
+		if current > 0 || len(pods) > 0 {
+			r.Log.Info("Stopping etcd members to restore from snapshot", "snapshot", p.Restore.SnapshotName)
+			return 0, "", nil
+		}
+		claims, err := etcd.ListDataClaims(ctx, r, statefulSet.Namespace)
+		if err != nil {
+			return 0, "", err
+		}
+		if len(claims) > 0 {
+			for i := range claims {
+				if err := r.Delete(ctx, &claims[i]); err != nil && !apierrors.IsNotFound(err) {
+					return 0, "", fmt.Errorf("failed to delete etcd data volume claim %s: %w", claims[i].Name, err)
+				}
+			}
+			return 0, "", fmt.Errorf("waiting for etcd data volume claims to be removed before restoring from snapshot %s", p.Restore.SnapshotName)
+		}
+		return 1, p.Restore.SnapshotName, nil
+	}
+
+	ready := 0
+	var members []*corev1.Pod
+	for i := range pods {
+		ordinal, err := etcd.MemberOrdinal(pods[i].Name)
+		if err != nil || ordinal >= current {
+			continue
+		}
+		members = append(members, &pods[i])
+		if etcd.IsMemberReady(&pods[i]) {
+			ready++
+		}
+	}
+
+	next := etcd.NextReplicas(current, ready, p.EtcdDeploymentConfig.Replicas)
+	if next < current {
+		// The pod with the highest ordinal is removed when scaling down
+		name := fmt.Sprintf("%s-%d", statefulSet.Name, current-1)
+		r.Log.Info("Removing etcd member", "member", name)
+		if err := r.removeEtcdMember(ctx, statefulSet.Namespace, name); err != nil {
+			return current, "", err
+		}
+		return next, "", nil
+	}
+
+	for _, pod := range members {
+		if !etcd.IsMemberFailed(pod) {
+			continue
+		}
+		if ready < etcd.Quorum(current) {
+			r.Log.Info("Cannot replace failed etcd member without quorum", "member", pod.Name)
+			break
+		}
+		// The member discards its data and rejoins the cluster once its pod
+		// is recreated.
+		r.Log.Info("Replacing failed etcd member", "member", pod.Name)
+		if err := r.removeEtcdMember(ctx, statefulSet.Namespace, pod.Name); err != nil {
+			return current, "", err
+		}
+		if err := r.Delete(ctx, pod); err != nil && !apierrors.IsNotFound(err) {
+			return current, "", fmt.Errorf("failed to delete etcd pod %s: %w", pod.Name, err)
+		}
+		break
+	}
+	return next, "", nil
+}
+
+// removeEtcdMember removes the member run by the given pod from the etcd
+// cluster, if it is a member.
+func (r *HostedControlPlaneReconciler) removeEtcdMember(ctx context.Context, namespace, podName string) error {
+	etcdClient, err := etcd.NewEtcdClient(ctx, r, namespace)
+	if err != nil {
+		return err
+	}
+	defer etcdClient.Close()
+	members, err := etcdClient.MemberList(ctx)
+	if err != nil {
+		return err
+	}
+	peerURL := etcd.EtcdMemberPeerURL(namespace, podName)
+	for _, member := range members {
+		for _, url := range member.PeerURLs {
+			if url == peerURL {
+				return etcdClient.MemberRemove(ctx, member.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// etcdPodToHostedControlPlane maps etcd member pods to the hosted control
+// plane in their namespace, so that failed members are noticed.
+func (r *HostedControlPlaneReconciler) etcdPodToHostedControlPlane(obj client.Object) []reconcile.Request {
+	owner := metav1.GetControllerOf(obj)
+	if owner == nil || owner.Kind != "StatefulSet" || owner.Name != manifests.EtcdStatefulSet("").Name {
+		return nil
+	}
+	hcpList := &hyperv1.HostedControlPlaneList{}
+	if err := r.List(context.Background(), hcpList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list hosted control planes")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, hcp := range hcpList.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&hcp)})
+	}
+	return requests
+}
+
+// etcdRestoreUnsupported returns true if a restore is requested for a managed
+// etcd cluster created by a previous version with the etcd operator, which
+// can't be restored.
+func (r *HostedControlPlaneReconciler) etcdRestoreUnsupported(ctx context.Context, hcp *hyperv1.HostedControlPlane) (bool, error) {
+	if hcp.Spec.Etcd.ManagementType != hyperv1.Managed || hcp.Spec.Etcd.Managed == nil || hcp.Spec.Etcd.Managed.Restore == nil {
+		return false, nil
+	}
+	etcdCluster := manifests.EtcdCluster(hcp.Namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(etcdCluster), etcdCluster); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to fetch etcd cluster %s/%s: %w", etcdCluster.Namespace, etcdCluster.Name, err)
+	}
+	return true, nil
+}
+
+// reconcileEtcdOperatorCluster reconciles an etcd cluster created by a
+// previous version with the etcd operator. The cluster is still backed up,
+// but it can't be restored from a snapshot. A requested restore is reported
+// by the ValidConfiguration condition.
+func (r *HostedControlPlaneReconciler) reconcileEtcdOperatorCluster(ctx context.Context, p *etcd.EtcdParams, etcdCluster *etcdv1.EtcdCluster) error {
 	// Etcd Operator ServiceAccount
-	operatorServiceAccount := manifests.EtcdOperatorServiceAccount(hcp.Namespace)
+	operatorServiceAccount := manifests.EtcdOperatorServiceAccount(etcdCluster.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, operatorServiceAccount, func() error {
 		return etcd.ReconcileOperatorServiceAccount(operatorServiceAccount, p.OwnerRef)
 	}); err != nil {
@@ -1216,7 +1682,7 @@ func (r *HostedControlPlaneReconciler) reconcileManagedEtcd(ctx context.Context,
 	}
 
 	// Etcd operator role
-	operatorRole := manifests.EtcdOperatorRole(hcp.Namespace)
+	operatorRole := manifests.EtcdOperatorRole(etcdCluster.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, operatorRole, func() error {
 		return etcd.ReconcileOperatorRole(operatorRole, p.OwnerRef)
 	}); err != nil {
@@ -1224,7 +1690,7 @@ func (r *HostedControlPlaneReconciler) reconcileManagedEtcd(ctx context.Context,
 	}
 
 	// Etcd operator rolebinding
-	operatorRoleBinding := manifests.EtcdOperatorRoleBinding(hcp.Namespace)
+	operatorRoleBinding := manifests.EtcdOperatorRoleBinding(etcdCluster.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, operatorRoleBinding, func() error {
 		return etcd.ReconcileOperatorRoleBinding(operatorRoleBinding, p.OwnerRef)
 	}); err != nil {
@@ -1232,7 +1698,7 @@ func (r *HostedControlPlaneReconciler) reconcileManagedEtcd(ctx context.Context,
 	}
 
 	// Etcd operator deployment
-	operatorDeployment := manifests.EtcdOperatorDeployment(hcp.Namespace)
+	operatorDeployment := manifests.EtcdOperatorDeployment(etcdCluster.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, operatorDeployment, func() error {
 		return etcd.ReconcileOperatorDeployment(operatorDeployment, p.OwnerRef, p.OperatorDeploymentConfig, p.EtcdOperatorImage)
 	}); err != nil {
@@ -1240,31 +1706,64 @@ func (r *HostedControlPlaneReconciler) reconcileManagedEtcd(ctx context.Context,
 	}
 
 	// Etcd cluster
-	etcdCluster := manifests.EtcdCluster(hcp.Namespace)
+	if _, err := controllerutil.CreateOrUpdate(ctx, r, etcdCluster, func() error {
+		return etcd.ReconcileCluster(etcdCluster, p.OwnerRef, p.EtcdDeploymentConfig)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile etcd cluster: %w", err)
+	}
 
-	// The EtcdCluster can currently enter a permanently failed state, so when
-	// that's detected, delete the EtcdCluster and start over.
-	// TODO(dmace): Fix this in the etcd operator and delete this code
-	shouldDeleteFailedEtcd := false
-	for _, cond := range hcp.Status.Conditions {
-		if cond.Type == string(hyperv1.EtcdAvailable) && cond.Status == metav1.ConditionFalse && cond.Reason == etcd.EtcdReasonFailed {
-			shouldDeleteFailedEtcd = true
-			break
+	return r.reconcileManagedEtcdBackup(ctx, p, etcdCluster.Namespace)
+}
+
+// removeEtcdOperatorResources removes the resources previous versions created
+// to run managed etcd with the etcd operator. Clusters created by the etcd
+// operator are never removed, they keep running with it.
+func (r *HostedControlPlaneReconciler) removeEtcdOperatorResources(ctx context.Context, namespace string) error {
+	for _, obj := range []client.Object{
+		manifests.EtcdRestore(namespace),
+		manifests.EtcdBackup(namespace),
+		manifests.EtcdRestoreOperatorDeployment(namespace),
+		manifests.EtcdBackupOperatorDeployment(namespace),
+		manifests.EtcdOperatorDeployment(namespace),
+		manifests.EtcdOperatorRoleBinding(namespace),
+		manifests.EtcdOperatorRole(namespace),
+		manifests.EtcdOperatorServiceAccount(namespace),
+	} {
+		if err := r.Delete(ctx, obj); err != nil && !apierrors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+			return fmt.Errorf("failed to delete %T %s: %w", obj, obj.GetName(), err)
 		}
 	}
-	if shouldDeleteFailedEtcd {
-		if err := r.Delete(ctx, etcdCluster); err != nil {
-			if !apierrors.IsNotFound(err) {
-				return fmt.Errorf("failed to delete etcd cluster: %w", err)
-			}
+	return nil
+}
+
+// reconcileManagedEtcdBackup reconciles the job which takes periodic snapshots
+// of the managed etcd cluster, and removes it if no backups are requested.
+// Backup volumes are never removed.
+func (r *HostedControlPlaneReconciler) reconcileManagedEtcdBackup(ctx context.Context, p *etcd.EtcdParams, namespace string) error {
+	cronJob := manifests.EtcdBackupCronJob(namespace)
+	if p.BackupPolicy == nil {
+		if err := r.Delete(ctx, cronJob); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete etcd backup cron job: %w", err)
 		}
+		return nil
 	}
 
-	if _, err := controllerutil.CreateOrUpdate(ctx, r, etcdCluster, func() error {
-		etcdCluster.OwnerReferences = ensureHCPOwnerRef(hcp, etcdCluster.OwnerReferences)
-		return etcd.ReconcileCluster(etcdCluster, p.OwnerRef, p.EtcdDeploymentConfig, p.ClusterVersion, p.PVCClaim)
+	if destinationType := p.BackupPolicy.Destination.Type; destinationType == hyperv1.PersistentVolumeEtcdBackupDestination {
+		if p.BackupPolicy.Destination.PersistentVolume == nil {
+			return fmt.Errorf("etcd backup destination %s requires persistent volume settings", destinationType)
+		}
+		pvc := manifests.EtcdBackupPVC(namespace)
+		if _, err := controllerutil.CreateOrUpdate(ctx, r, pvc, func() error {
+			return etcd.ReconcileBackupPVC(pvc, p.OwnerRef, p.BackupPolicy.Destination.PersistentVolume)
+		}); err != nil {
+			return fmt.Errorf("failed to reconcile etcd backup volume claim: %w", err)
+		}
+	}
+
+	if _, err := controllerutil.CreateOrUpdate(ctx, r, cronJob, func() error {
+		return etcd.ReconcileBackupCronJob(cronJob, p.OwnerRef, p.BackupPolicy, p.EtcdImage, p.SnapshotImage)
 	}); err != nil {
-		return fmt.Errorf("failed to reconcile etcd cluster: %w", err)
+		return fmt.Errorf("failed to reconcile etcd backup cron job: %w", err)
 	}
 	return nil
 }
@@ -1303,13 +1802,19 @@ func (r *HostedControlPlaneReconciler) reconcileUnmanagedEtcd(ctx context.Contex
 
 func (r *HostedControlPlaneReconciler) reconcileKonnectivity(ctx context.Context, hcp *hyperv1.HostedControlPlane, releaseImage *releaseinfo.ReleaseImage, infraStatus InfrastructureStatus) error {
 	r.Log.Info("Reconciling Konnectivity")
-	p := konnectivity.NewKonnectivityParams(hcp, releaseImage.ComponentImages(), infraStatus.KonnectivityHost, infraStatus.KonnectivityPort)
+	p := konnectivity.NewKonnectivityParams(hcp, releaseImage.ComponentImages(), r.DefaultKonnectivityImage, infraStatus.KonnectivityHost, infraStatus.KonnectivityPort)
+	if len(p.KonnectivityServerImage) == 0 || len(p.KonnectivityAgentImage) == 0 {
+		return fmt.Errorf("the release image has no %s image and no default konnectivity image is configured, override the konnectivity-server and konnectivity-agent images", konnectivity.ReleaseImageComponent)
+	}
 	serverDeployment := manifests.KonnectivityServerDeployment(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, serverDeployment, func() error {
-		return konnectivity.ReconcileServerDeployment(serverDeployment, p.OwnerRef, p.ServerDeploymentConfig, p.KonnectivityServerImage)
+		return konnectivity.ReconcileServerDeployment(serverDeployment, p.OwnerRef, p.ServerDeploymentConfig, p.KonnectivityServerImage, p.ServerCount())
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile konnectivity server deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, serverDeployment, serverDeployment.Spec.Selector, p.ServerDeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 	serverLocalService := manifests.KonnectivityServerLocalService(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, serverLocalService, func() error {
 		return konnectivity.ReconcileServerLocalService(serverLocalService, p.OwnerRef)
@@ -1323,19 +1828,219 @@ func (r *HostedControlPlaneReconciler) reconcileKonnectivity(ctx context.Context
 		infraStatus.PackageServerAPIAddress,
 	}
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, agentDeployment, func() error {
-		return konnectivity.ReconcileAgentDeployment(agentDeployment, p.OwnerRef, p.AgentDeploymentConfig, p.KonnectivityAgentImage, ips)
+		return konnectivity.ReconcileAgentDeployment(agentDeployment, p.OwnerRef, p.AgentDeploymentConfig, p.KonnectivityAgentImage, ips, p.ServerCount())
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile konnectivity agent deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, agentDeployment, agentDeployment.Spec.Selector, p.AgentDeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 	agentDaemonSet := manifests.KonnectivityWorkerAgentDaemonSet(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, agentDaemonSet, func() error {
-		return konnectivity.ReconcileWorkerAgentDaemonSet(agentDaemonSet, p.OwnerRef, p.AgentDeamonSetConfig, p.KonnectivityAgentImage, p.ExternalAddress, p.ExternalPort)
+		return konnectivity.ReconcileWorkerAgentDaemonSet(agentDaemonSet, p.OwnerRef, p.AgentDeamonSetConfig, p.KonnectivityAgentImage, p.ExternalAddress, p.ExternalPort, p.ServerCount())
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile konnectivity agent daemonset: %w", err)
 	}
 	return nil
 }
 
+// reconcileEncryption reconciles the keys used to encrypt resources at rest
+// and the EncryptionConfiguration of the kube and openshift apiservers. Keys
+// are generated and rotated in steps which each wait for the apiservers to
+// roll out the previous configuration, and resources are migrated to a new
+// key with a job before older keys are removed.
+func (r *HostedControlPlaneReconciler) reconcileEncryption(ctx context.Context, hcp *hyperv1.HostedControlPlane, globalConfig config.GlobalConfig, releaseImage *releaseinfo.ReleaseImage) error {
+	ownerRef := config.OwnerRefFrom(hcp)
+	desired, err := encryption.DesiredEncryption(hcp, globalConfig.APIServer)
+	if err != nil {
+		return fmt.Errorf("invalid secret encryption configuration: %w", err)
+	}
+	rotation := encryption.RequestedRotation(hcp)
+
+	keys := manifests.EncryptionKeysSecret(hcp.Namespace)
+	var state *encryption.State
+	if err := r.Get(ctx, client.ObjectKeyFromObject(keys), keys); err == nil {
+		if state, err = encryption.StateFromSecret(keys); err != nil {
+			return fmt.Errorf("failed to read encryption keys: %w", err)
+		}
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get encryption keys: %w", err)
+	} else {
+		// Until the kube apiserver is deployed no resources have been stored which
+		// would need to be migrated, so the desired encryption is used right away.
+		// Once it is deployed, the keys are recovered from the encryption config
+		// it runs with. A kube apiserver without encryption config was deployed
+		// before encryption was managed and stores resources unencrypted.
+		kasDeployment := manifests.KASDeployment(hcp.Namespace)
+		encryptionConfig := manifests.EncryptionConfigSecret(hcp.Namespace)
+		if err := r.Get(ctx, client.ObjectKeyFromObject(kasDeployment), kasDeployment); err == nil {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(encryptionConfig), encryptionConfig); err == nil {
+				if state, err = encryption.StateFromConfigSecret(encryptionConfig, rotation); err != nil {
+					err = fmt.Errorf("encryption keys secret %s is missing and can't be recovered: %w", keys.Name, err)
+					if conditionErr := r.setEncryptionKeysCondition(ctx, hcp, err); conditionErr != nil {
+						return conditionErr
+					}
+					return err
+				}
+				r.Log.Info("Recovered encryption keys from the encryption config", "writeKey", state.WriteKey)
+			} else if apierrors.IsNotFound(err) {
+				state = &encryption.State{WriteKey: encryption.IdentityKey, MigratedKey: encryption.IdentityKey}
+			} else {
+				return fmt.Errorf("failed to get encryption config: %w", err)
+			}
+		} else if apierrors.IsNotFound(err) {
+			if state, err = encryption.NewState(desired, rotation, encryption.GenerateKey); err != nil {
+				return err
+			}
+		} else {
+			return fmt.Errorf("failed to get kube apiserver deployment: %w", err)
+		}
+	}
+	if err := r.setEncryptionKeysCondition(ctx, hcp, nil); err != nil {
+		return err
+	}
+
+	configHash, err := state.ConfigHash()
+	if err != nil {
+		return err
+	}
+	rolledOut, err := r.encryptionConfigRolledOut(ctx, hcp.Namespace, configHash)
+	if err != nil {
+		return err
+	}
+	migrationJob := manifests.EncryptionMigrationJob(hcp.Namespace)
+	migrationJobExists := true
+	if err := r.Get(ctx, client.ObjectKeyFromObject(migrationJob), migrationJob); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get encryption migration job: %w", err)
+		}
+		migrationJobExists = false
+	}
+	var migratedKey string
+	if migrationJobExists {
+		migratedKey = encryption.MigratedKey(migrationJob)
+	}
+
+	migrate, err := state.Sync(desired, rotation, rolledOut, migratedKey, encryption.GenerateKey)
+	if err != nil {
+		return err
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r, keys, func() error {
+		ownerRef.ApplyTo(keys)
+		state.ApplyTo(keys)
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile encryption keys: %w", err)
+	}
+	if err := r.reconcileKMSPluginCredentials(ctx, hcp, state); err != nil {
+		return err
+	}
+	encryptionConfig := manifests.EncryptionConfigSecret(hcp.Namespace)
+	if _, err := controllerutil.CreateOrUpdate(ctx, r, encryptionConfig, func() error {
+		return encryption.ReconcileConfigSecret(encryptionConfig, ownerRef, state)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile encryption config: %w", err)
+	}
+
+	if !migrate {
+		return nil
+	}
+	if migrationJobExists {
+		// A job migrating to an older key or which failed is replaced once it is gone.
+		if encryption.MigrationKey(migrationJob) != state.WriteKey || encryption.MigrationFailed(migrationJob) {
+			r.Log.Info("Removing encryption migration job", "key", encryption.MigrationKey(migrationJob))
+			if err := r.Delete(ctx, migrationJob, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete encryption migration job: %w", err)
+			}
+		}
+		return nil
+	}
+	r.Log.Info("Migrating encrypted resources", "key", state.WriteKey)
+	encryption.ReconcileMigrationJob(migrationJob, ownerRef, state.WriteKey, releaseImage.ComponentImages()["cli"], kas.KubeconfigKey)
+	if err := r.Create(ctx, migrationJob); err != nil {
+		return fmt.Errorf("failed to create encryption migration job: %w", err)
+	}
+	return nil
+}
+
+// reconcileKMSPluginCredentials stores the credentials of the KMS plugins of the
+// kms keys in the encryption state. Its token expires, so the secret is
+// reconciled periodically while a KMS is configured.
+func (r *HostedControlPlaneReconciler) reconcileKMSPluginCredentials(ctx context.Context, hcp *hyperv1.HostedControlPlane, state *encryption.State) error {
+	signingKey := manifests.ServiceAccountSigningKeySecret(hcp.Namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(signingKey), signingKey); err != nil {
+		return fmt.Errorf("failed to get service account signing key: %w", err)
+	}
+	issuer := hcp.Spec.IssuerURL
+	if issuer == "" {
+		issuer = config.DefaultServiceAccountIssuer
+	}
+	credentials := manifests.KMSPluginCredentialsSecret(hcp.Namespace)
+	if _, err := controllerutil.CreateOrUpdate(ctx, r, credentials, func() error {
+		return encryption.ReconcileKMSCredentialsSecret(credentials, config.OwnerRefFrom(hcp), state, signingKey, issuer, time.Now())
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile KMS plugin credentials: %w", err)
+	}
+	return nil
+}
+
+// encryptionState reads the encryption state reconciled by reconcileEncryption.
+func (r *HostedControlPlaneReconciler) encryptionState(ctx context.Context, namespace string) (*encryption.State, error) {
+	keys := manifests.EncryptionKeysSecret(namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(keys), keys); err != nil {
+		return nil, fmt.Errorf("failed to get encryption keys: %w", err)
+	}
+	state, err := encryption.StateFromSecret(keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption keys: %w", err)
+	}
+	return state, nil
+}
+
+// setEncryptionKeysCondition reports whether the encryption keys of the control
+// plane are available. The condition is written right away, since a control
+// plane which lost its keys fails to reconcile before its status is updated.
+func (r *HostedControlPlaneReconciler) setEncryptionKeysCondition(ctx context.Context, hcp *hyperv1.HostedControlPlane, keysErr error) error {
+	condition := metav1.Condition{
+		Type:               string(hyperv1.EncryptionKeysAvailable),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: hcp.Generation,
+		Reason:             encryption.EncryptionReasonAsExpected,
+		Message:            "Encryption keys are available",
+	}
+	if keysErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = encryption.EncryptionReasonKeysLost
+		condition.Message = keysErr.Error()
+	}
+	if meta.IsStatusConditionPresentAndEqual(hcp.Status.Conditions, condition.Type, condition.Status) {
+		return nil
+	}
+	original := hcp.DeepCopy()
+	meta.SetStatusCondition(&hcp.Status.Conditions, condition)
+	if err := r.Status().Patch(ctx, hcp, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to update encryption keys condition: %w", err)
+	}
+	return nil
+}
+
+// encryptionConfigRolledOut returns true if all kube and openshift apiserver
+// pods run with the EncryptionConfiguration of the given hash.
+func (r *HostedControlPlaneReconciler) encryptionConfigRolledOut(ctx context.Context, namespace, configHash string) (bool, error) {
+	for _, deployment := range []*appsv1.Deployment{manifests.KASDeployment(namespace), manifests.OpenShiftAPIServerDeployment(namespace)} {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+			if apierrors.IsNotFound(err) {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to get deployment %s: %w", deployment.Name, err)
+		}
+		if !encryption.IsRolledOut(deployment, configHash) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 func (r *HostedControlPlaneReconciler) reconcileKubeAPIServer(ctx context.Context, hcp *hyperv1.HostedControlPlane, globalConfig config.GlobalConfig, releaseImage *releaseinfo.ReleaseImage, oauthAddress string, oauthPort int32) error {
 	p := kas.NewKubeAPIServerParams(ctx, hcp, globalConfig, releaseImage.ComponentImages(), oauthAddress, oauthPort)
 
@@ -1392,9 +2097,17 @@ func (r *HostedControlPlaneReconciler) reconcileKubeAPIServer(ctx context.Contex
 		return fmt.Errorf("failed to reconcile bootstrap kubeconfig secret: %w", err)
 	}
 
+	var auditPolicy *corev1.ConfigMap
+	if p.AuditPolicyRef != nil {
+		auditPolicy = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: hcp.Namespace, Name: p.AuditPolicyRef.Name}}
+		// A missing policy is invalid, the previous policy is kept
+		if err := r.Get(ctx, client.ObjectKeyFromObject(auditPolicy), auditPolicy); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get audit policy %s: %w", p.AuditPolicyRef.Name, err)
+		}
+	}
 	kubeAPIServerAuditConfig := manifests.KASAuditConfig(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, kubeAPIServerAuditConfig, func() error {
-		return kas.ReconcileAuditConfig(kubeAPIServerAuditConfig, p.OwnerRef, p.AuditPolicyProfile())
+		return kas.ReconcileAuditConfig(kubeAPIServerAuditConfig, p.OwnerRef, p.AuditPolicyProfile(), auditPolicy)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile api server audit config: %w", err)
 	}
@@ -1422,6 +2135,28 @@ func (r *HostedControlPlaneReconciler) reconcileKubeAPIServer(ctx context.Contex
 		return fmt.Errorf("failed to reconcile oauth metadata: %w", err)
 	}
 
+	encryptionConfig := manifests.EncryptionConfigSecret(hcp.Namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(encryptionConfig), encryptionConfig); err != nil {
+		return fmt.Errorf("failed to get encryption config: %w", err)
+	}
+	encryptionState, err := r.encryptionState(ctx, hcp.Namespace)
+	if err != nil {
+		return err
+	}
+	serviceAccountSigningKey := manifests.ServiceAccountSigningKeySecret(hcp.Namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(serviceAccountSigningKey), serviceAccountSigningKey); err != nil {
+		return fmt.Errorf("failed to get service account signing key: %w", err)
+	}
+
+	if p.AuditLogBackend != nil && p.AuditLogBackend.Sink.Type == hyperv1.PersistentVolumeAuditSink && p.AuditLogBackend.Sink.PersistentVolume != nil {
+		auditLogPVC := manifests.KASAuditLogPVC(hcp.Namespace)
+		if _, err := controllerutil.CreateOrUpdate(ctx, r, auditLogPVC, func() error {
+			return kas.ReconcileAuditLogPVC(auditLogPVC, p.OwnerRef, p.AuditLogBackend.Sink.PersistentVolume)
+		}); err != nil {
+			return fmt.Errorf("failed to reconcile api server audit log volume claim: %w", err)
+		}
+	}
+
 	kubeAPIServerDeployment := manifests.KASDeployment(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, kubeAPIServerDeployment, func() error {
 		return kas.ReconcileKubeAPIServerDeployment(kubeAPIServerDeployment,
@@ -1431,11 +2166,19 @@ func (r *HostedControlPlaneReconciler) reconcileKubeAPIServer(ctx context.Contex
 			p.CloudProviderConfig,
 			p.Images,
 			kubeAPIServerConfig,
+			kubeAPIServerAuditConfig,
+			encryptionConfig,
+			encryptionState,
+			serviceAccountSigningKey,
 			p.AuditWebhookRef,
+			p.AuditLogBackend,
 		)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile api server deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, kubeAPIServerDeployment, kubeAPIServerDeployment.Spec.Selector, p.DeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1460,12 +2203,20 @@ func (r *HostedControlPlaneReconciler) reconcileKubeControllerManager(ctx contex
 		return fmt.Errorf("failed to reconcile kcm config: %w", err)
 	}
 
+	serviceAccountSigningKey := manifests.ServiceAccountSigningKeySecret(hcp.Namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(serviceAccountSigningKey), serviceAccountSigningKey); err != nil {
+		return fmt.Errorf("failed to get service account signing key: %w", err)
+	}
+
 	kcmDeployment := manifests.KCMDeployment(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, kcmDeployment, func() error {
-		return kcm.ReconcileDeployment(kcmDeployment, kcmConfig, serviceServingCA, p)
+		return kcm.ReconcileDeployment(kcmDeployment, kcmConfig, serviceServingCA, serviceAccountSigningKey, p)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile kcm deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, kcmDeployment, kcmDeployment.Spec.Selector, p.DeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1486,6 +2237,9 @@ func (r *HostedControlPlaneReconciler) reconcileKubeScheduler(ctx context.Contex
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile scheduler deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, schedulerDeployment, schedulerDeployment.Spec.Selector, p.DeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1506,12 +2260,24 @@ func (r *HostedControlPlaneReconciler) reconcileOpenShiftAPIServer(ctx context.C
 		return fmt.Errorf("failed to reconcile openshift apiserver audit config: %w", err)
 	}
 
+	encryptionConfig := manifests.EncryptionConfigSecret(hcp.Namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(encryptionConfig), encryptionConfig); err != nil {
+		return fmt.Errorf("failed to get encryption config: %w", err)
+	}
+	encryptionState, err := r.encryptionState(ctx, hcp.Namespace)
+	if err != nil {
+		return err
+	}
+
 	deployment := manifests.OpenShiftAPIServerDeployment(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, deployment, func() error {
-		return oapi.ReconcileDeployment(deployment, p.OwnerRef, p.OpenShiftAPIServerDeploymentConfig, p.OpenShiftAPIServerImage, p.HaproxyImage, p.EtcdURL)
+		return oapi.ReconcileDeployment(deployment, p.OwnerRef, p.OpenShiftAPIServerDeploymentConfig, p.OpenShiftAPIServerImage, p.HaproxyImage, p.HyperShiftImage, p.EtcdURL, encryptionConfig, encryptionState)
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile openshift apiserver deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, deployment, deployment.Spec.Selector, p.OpenShiftAPIServerDeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 
 	workerEndpoints := manifests.OpenShiftAPIServerWorkerEndpoints(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, workerEndpoints, func() error {
@@ -1558,6 +2324,9 @@ func (r *HostedControlPlaneReconciler) reconcileOpenShiftOAuthAPIServer(ctx cont
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile openshift oauth apiserver deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, deployment, deployment.Spec.Selector, p.OpenShiftOAuthAPIServerDeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 
 	workerEndpoints := manifests.OpenShiftOAuthAPIServerWorkerEndpoints(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, workerEndpoints, func() error {
@@ -1657,6 +2426,9 @@ func (r *HostedControlPlaneReconciler) reconcileOAuthServer(ctx context.Context,
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile oauth deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, deployment, deployment.Spec.Selector, p.DeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 
 	oauthBrowserClient := manifests.OAuthServerBrowserClientManifest(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, oauthBrowserClient, func() error {
@@ -1691,6 +2463,9 @@ func (r *HostedControlPlaneReconciler) reconcileOpenShiftControllerManager(ctx c
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile openshift controller manager deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, deployment, deployment.Spec.Selector, p.DeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 
 	workerNamespace := manifests.OpenShiftControllerManagerNamespaceWorkerManifest(hcp.Namespace)
 	if _, err := controllerutil.CreateOrUpdate(ctx, r, workerNamespace, func() error {
@@ -1725,6 +2500,9 @@ func (r *HostedControlPlaneReconciler) reconcileClusterPolicyController(ctx cont
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile openshift controller manager deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, deployment, deployment.Spec.Selector, p.DeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1737,6 +2515,9 @@ func (r *HostedControlPlaneReconciler) reconcileClusterVersionOperator(ctx conte
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile cluster version operator deployment: %w", err)
 	}
+	if err := r.reconcilePodDisruptionBudget(ctx, deployment, deployment.Spec.Selector, p.DeploymentConfig, p.OwnerRef); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1986,16 +2767,11 @@ func generateTargetPullSecrets(scheme *runtime.Scheme, data []byte, namespace st
 	return result, nil
 }
 
-const awsCredentialsTemplate = `[default]
-role_arn = %s
-web_identity_token_file = /var/run/secrets/openshift/serviceaccount/token
-`
-
 func generateTargetCredentialsSecret(scheme *runtime.Scheme, creds hyperv1.AWSRoleCredentials, namespace string) (*corev1.ConfigMap, error) {
 	secret := &corev1.Secret{}
 	secret.Name = creds.Name
 	secret.Namespace = creds.Namespace
-	credentials := fmt.Sprintf(awsCredentialsTemplate, creds.ARN)
+	credentials := config.AWSRoleCredentials(creds.ARN)
 	secret.Data = map[string][]byte{"credentials": []byte(credentials)}
 	secret.Type = corev1.SecretTypeOpaque
 	secretBytes, err := runtime.Encode(serializer.NewCodecFactory(scheme).LegacyCodec(corev1.SchemeGroupVersion), secret)
@@ -6,10 +6,12 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1beta1"
+	"sigs.k8s.io/yaml"
 
 	configv1 "github.com/openshift/api/config/v1"
 	oauthv1 "github.com/openshift/api/oauth/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
 )
@@ -267,11 +269,25 @@ var (
 	}
 )
 
-func ReconcileAuditConfig(auditCfgMap *corev1.ConfigMap, ownerRef config.OwnerRef, auditProfile configv1.AuditProfileType) error {
+// ReconcileAuditConfig renders the audit policy of the kube apiserver. A
+// custom policy takes precedence over the audit profile. An invalid custom
+// policy is reported by the ValidAuditPolicy condition instead of failing the
+// reconcile; the previous policy is kept, or the profile is used if there is
+// none yet.
+func ReconcileAuditConfig(auditCfgMap *corev1.ConfigMap, ownerRef config.OwnerRef, auditProfile configv1.AuditProfileType, customPolicy *corev1.ConfigMap) error {
 	ownerRef.ApplyTo(auditCfgMap)
 	if auditCfgMap.Data == nil {
 		auditCfgMap.Data = map[string]string{}
 	}
+	if customPolicy != nil {
+		if policy, err := ValidateCustomAuditPolicy(customPolicy); err == nil {
+			auditCfgMap.Data[AuditPolicyConfigMapKey] = policy
+			return nil
+		}
+		if _, ok := auditCfgMap.Data[AuditPolicyConfigMapKey]; ok {
+			return nil
+		}
+	}
 	if auditProfile == "" {
 		auditProfile = configv1.AuditProfileDefaultType
 	}
@@ -286,3 +302,52 @@ func ReconcileAuditConfig(auditCfgMap *corev1.ConfigMap, ownerRef config.OwnerRe
 	auditCfgMap.Data[AuditPolicyConfigMapKey] = string(policyBytes)
 	return nil
 }
+
+// ValidateCustomAuditPolicy checks that a user supplied policy will be accepted
+// by the kube apiserver, so that a mistake is reported instead of leaving the
+// apiserver crashlooping. The v1 and v1beta1 policies share the same fields,
+// the policy is passed on unchanged in either version.
+func ValidateCustomAuditPolicy(cm *corev1.ConfigMap) (string, error) {
+	data, ok := cm.Data[hyperv1.AuditPolicyConfigMapKey]
+	if !ok {
+		return "", fmt.Errorf("missing key %s", hyperv1.AuditPolicyConfigMapKey)
+	}
+	policy := &auditv1.Policy{}
+	if err := yaml.UnmarshalStrict([]byte(data), policy); err != nil {
+		return "", err
+	}
+	if policy.Kind != "Policy" {
+		return "", fmt.Errorf("unexpected kind %q", policy.Kind)
+	}
+	if policy.APIVersion != "audit.k8s.io/v1" && policy.APIVersion != auditv1.SchemeGroupVersion.String() {
+		return "", fmt.Errorf("unsupported apiVersion %q", policy.APIVersion)
+	}
+	if err := validateAuditStages(policy.OmitStages); err != nil {
+		return "", err
+	}
+	for i, rule := range policy.Rules {
+		switch rule.Level {
+		case auditv1.LevelNone, auditv1.LevelMetadata, auditv1.LevelRequest, auditv1.LevelRequestResponse:
+		default:
+			return "", fmt.Errorf("rule %d: invalid level %q", i, rule.Level)
+		}
+		if err := validateAuditStages(rule.OmitStages); err != nil {
+			return "", fmt.Errorf("rule %d: %w", i, err)
+		}
+		if len(rule.NonResourceURLs) > 0 && (len(rule.Resources) > 0 || len(rule.Namespaces) > 0) {
+			return "", fmt.Errorf("rule %d: nonResourceURLs cannot be combined with resources or namespaces", i)
+		}
+	}
+	return data, nil
+}
+
+func validateAuditStages(stages []auditv1.Stage) error {
+	for _, stage := range stages {
+		switch stage {
+		case auditv1.StageRequestReceived, auditv1.StageResponseStarted, auditv1.StageResponseComplete, auditv1.StagePanic:
+		default:
+			return fmt.Errorf("invalid stage %q", stage)
+		}
+	}
+	return nil
+}
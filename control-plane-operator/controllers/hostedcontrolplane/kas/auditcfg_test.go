@@ -0,0 +1,136 @@
+package kas
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+)
+
+const customAuditPolicy = `apiVersion: audit.k8s.io/v1
+kind: Policy
+omitStages:
+- RequestReceived
+rules:
+- level: RequestResponse
+  resources:
+  - group: ""
+    resources: ["secrets", "configmaps"]
+  users: ["system:admin"]
+- level: Metadata
+`
+
+func TestReconcileAuditConfig(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        string
+		expectedError bool
+	}{
+		{
+			name:   "valid policy",
+			policy: customAuditPolicy,
+		},
+		{
+			name:          "invalid level",
+			policy:        "apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: Everything\n",
+			expectedError: true,
+		},
+		{
+			name:          "unknown field",
+			policy:        "apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: Metadata\n  resource: pods\n",
+			expectedError: true,
+		},
+		{
+			name:          "not a policy",
+			policy:        "apiVersion: v1\nkind: ConfigMap\n",
+			expectedError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			customPolicy := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "audit-policy"},
+				Data:       map[string]string{hyperv1.AuditPolicyConfigMapKey: test.policy},
+			}
+			_, err := ValidateCustomAuditPolicy(customPolicy)
+			if test.expectedError {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+
+			// An invalid policy doesn't replace the previous one
+			previous := "apiVersion: audit.k8s.io/v1\nkind: Policy\nrules:\n- level: None\n"
+			cm := &corev1.ConfigMap{Data: map[string]string{AuditPolicyConfigMapKey: previous}}
+			g.Expect(ReconcileAuditConfig(cm, config.OwnerRef{}, configv1.AuditProfileDefaultType, customPolicy)).To(Succeed())
+			if test.expectedError {
+				g.Expect(cm.Data[AuditPolicyConfigMapKey]).To(Equal(previous))
+				return
+			}
+			g.Expect(cm.Data[AuditPolicyConfigMapKey]).To(Equal(test.policy))
+		})
+	}
+}
+
+func TestAuditForwarder(t *testing.T) {
+	g := NewGomegaWithT(t)
+	podSpec := &corev1.PodSpec{}
+	backend := &hyperv1.AuditLogBackendSpec{
+		Sink: hyperv1.AuditSinkSpec{
+			Type:             hyperv1.PersistentVolumeAuditSink,
+			PersistentVolume: &hyperv1.AuditPersistentVolumeSinkSpec{StorageClassName: "nfs"},
+		},
+	}
+	g.Expect(applyAuditForwarder(podSpec, "hypershift", backend)).To(Succeed())
+	g.Expect(podSpec.Containers).To(HaveLen(1))
+	g.Expect(podSpec.Containers[0].Args).To(Equal([]string{"file", "--dir", "/var/audit/$(POD_NAME)", "--log-file", "/var/log/kube-apiserver/audit.log", "--metrics-addr", ":8094"}))
+	g.Expect(podSpec.Containers[0].VolumeMounts).To(HaveLen(2))
+	g.Expect(podSpec.Volumes).To(HaveLen(1))
+	g.Expect(podSpec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("kas-audit-logs"))
+
+	podSpec = &corev1.PodSpec{}
+	backend.Sink = hyperv1.AuditSinkSpec{
+		Type:   hyperv1.SyslogAuditSink,
+		Syslog: &hyperv1.AuditSyslogSinkSpec{Address: "syslog.example.com:514", Protocol: corev1.ProtocolUDP},
+	}
+	g.Expect(applyAuditForwarder(podSpec, "hypershift", backend)).To(Succeed())
+	g.Expect(podSpec.Containers[0].Args).To(Equal([]string{"syslog", "--address", "syslog.example.com:514", "--protocol", "udp", "--log-file", "/var/log/kube-apiserver/audit.log", "--metrics-addr", ":8094"}))
+	g.Expect(podSpec.Containers[0].VolumeMounts).To(HaveLen(1))
+	g.Expect(podSpec.Volumes).To(BeEmpty())
+
+	// The CA and credentials are mounted into the forwarder
+	podSpec = &corev1.PodSpec{}
+	backend.Sink = hyperv1.AuditSinkSpec{
+		Type: hyperv1.HTTPAuditSink,
+		HTTP: &hyperv1.AuditHTTPSinkSpec{URL: "https://audit.example.com"},
+	}
+	backend.CA = &corev1.LocalObjectReference{Name: "user-audit-log-ca"}
+	backend.Credentials = &corev1.LocalObjectReference{Name: "user-audit-log-credentials"}
+	g.Expect(applyAuditForwarder(podSpec, "hypershift", backend)).To(Succeed())
+	g.Expect(podSpec.Containers[0].Args).To(Equal([]string{"http", "--url", "https://audit.example.com",
+		"--ca-file", "/etc/kubernetes/audit-log-ca/ca.crt", "--credentials-dir", "/etc/kubernetes/audit-log-credentials",
+		"--log-file", "/var/log/kube-apiserver/audit.log", "--metrics-addr", ":8094"}))
+	g.Expect(podSpec.Containers[0].VolumeMounts).To(HaveLen(3))
+	g.Expect(podSpec.Volumes).To(HaveLen(2))
+	g.Expect(podSpec.Volumes[0].ConfigMap.Name).To(Equal("user-audit-log-ca"))
+	g.Expect(podSpec.Volumes[1].Secret.SecretName).To(Equal("user-audit-log-credentials"))
+	backend.CA, backend.Credentials = nil, nil
+
+	// A persistent volume without a storage class is rejected
+	backend.Sink = hyperv1.AuditSinkSpec{
+		Type:             hyperv1.PersistentVolumeAuditSink,
+		PersistentVolume: &hyperv1.AuditPersistentVolumeSinkSpec{},
+	}
+	g.Expect(applyAuditForwarder(&corev1.PodSpec{}, "hypershift", backend)).ToNot(Succeed())
+
+	// Sinks without their settings are rejected
+	backend.Sink = hyperv1.AuditSinkSpec{Type: hyperv1.HTTPAuditSink}
+	g.Expect(applyAuditForwarder(&corev1.PodSpec{}, "hypershift", backend)).ToNot(Succeed())
+}
@@ -0,0 +1,210 @@
+package kas
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/util"
+)
+
+// auditForwarderMetricsPort serves the metrics of the audit forwarder, such as
+// the number of audit events the sink rejected.
+const auditForwarderMetricsPort = 8094
+
+var (
+	auditForwarderVolumeMounts = util.PodVolumeMounts{
+		kasContainerAuditForwarder().Name: {
+			kasVolumeWorkLogs().Name: "/var/log/kube-apiserver",
+		},
+	}
+
+	auditLogsVolumeMount = util.PodVolumeMounts{
+		kasContainerAuditForwarder().Name: {
+			kasVolumeAuditLogs().Name: "/var/audit",
+		},
+	}
+
+	auditLogCAVolumeMount = util.PodVolumeMounts{
+		kasContainerAuditForwarder().Name: {
+			kasVolumeAuditLogCA().Name: "/etc/kubernetes/audit-log-ca",
+		},
+	}
+
+	auditLogCredentialsVolumeMount = util.PodVolumeMounts{
+		kasContainerAuditForwarder().Name: {
+			kasVolumeAuditLogCredentials().Name: "/etc/kubernetes/audit-log-credentials",
+		},
+	}
+)
+
+func ReconcileAuditLogPVC(pvc *corev1.PersistentVolumeClaim, ownerRef config.OwnerRef, sink *hyperv1.AuditPersistentVolumeSinkSpec) error {
+	ownerRef.ApplyTo(pvc)
+	// Only the requested size can be changed once the claim is bound
+	if pvc.CreationTimestamp.IsZero() {
+		// Every apiserver replica mounts the volume
+		pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+		storageClassName := sink.StorageClassName
+		pvc.Spec.StorageClassName = &storageClassName
+	}
+	pvc.Spec.Resources.Requests = corev1.ResourceList{
+		corev1.ResourceStorage: sink.Size,
+	}
+	return nil
+}
+
+func kasContainerAuditForwarder() *corev1.Container {
+	return &corev1.Container{
+		Name: "audit-forwarder",
+	}
+}
+
+func buildKASContainerAuditForwarder(image string, backend *hyperv1.AuditLogBackendSpec) func(c *corev1.Container) {
+	return func(c *corev1.Container) {
+		c.Image = image
+		c.Command = []string{"/usr/bin/hypershift", "audit-forwarder"}
+		logFile := path.Join(auditForwarderVolumeMounts.Path(c.Name, kasVolumeWorkLogs().Name), AuditLogFile)
+		switch backend.Sink.Type {
+		case hyperv1.PersistentVolumeAuditSink:
+			// Replicas write to their own directory of the shared volume
+			c.Args = []string{
+				"file",
+				"--dir", path.Join(auditLogsVolumeMount.Path(c.Name, kasVolumeAuditLogs().Name), "$(POD_NAME)"),
+			}
+			c.Env = []corev1.EnvVar{
+				{
+					Name: "POD_NAME",
+					ValueFrom: &corev1.EnvVarSource{
+						FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+					},
+				},
+			}
+		case hyperv1.SyslogAuditSink:
+			protocol := backend.Sink.Syslog.Protocol
+			if protocol == "" {
+				protocol = corev1.ProtocolTCP
+			}
+			c.Args = []string{
+				"syslog",
+				"--address", backend.Sink.Syslog.Address,
+				"--protocol", strings.ToLower(string(protocol)),
+			}
+		case hyperv1.HTTPAuditSink:
+			c.Args = []string{
+				"http",
+				"--url", backend.Sink.HTTP.URL,
+			}
+		}
+		if backend.CA != nil {
+			c.Args = append(c.Args, "--ca-file", path.Join(auditLogCAVolumeMount.Path(c.Name, kasVolumeAuditLogCA().Name), hyperv1.AuditLogCAConfigMapKey))
+		}
+		if backend.Credentials != nil {
+			c.Args = append(c.Args, "--credentials-dir", auditLogCredentialsVolumeMount.Path(c.Name, kasVolumeAuditLogCredentials().Name))
+		}
+		c.Args = append(c.Args, "--log-file", logFile, "--metrics-addr", fmt.Sprintf(":%d", auditForwarderMetricsPort))
+		c.Ports = []corev1.ContainerPort{
+			{
+				Name:          "audit-metrics",
+				ContainerPort: auditForwarderMetricsPort,
+				Protocol:      corev1.ProtocolTCP,
+			},
+		}
+		c.VolumeMounts = auditForwarderVolumeMounts.ContainerMounts(c.Name)
+		if backend.Sink.Type == hyperv1.PersistentVolumeAuditSink {
+			c.VolumeMounts = append(c.VolumeMounts, auditLogsVolumeMount.ContainerMounts(c.Name)...)
+		}
+		if backend.CA != nil {
+			c.VolumeMounts = append(c.VolumeMounts, auditLogCAVolumeMount.ContainerMounts(c.Name)...)
+		}
+		if backend.Credentials != nil {
+			c.VolumeMounts = append(c.VolumeMounts, auditLogCredentialsVolumeMount.ContainerMounts(c.Name)...)
+		}
+	}
+}
+
+func kasVolumeAuditLogs() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "audit-logs",
+	}
+}
+
+func buildKASVolumeAuditLogs(v *corev1.Volume) {
+	v.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+		ClaimName: manifests.KASAuditLogPVC("").Name,
+	}
+}
+
+func kasVolumeAuditLogCA() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "audit-log-ca",
+	}
+}
+
+func buildKASVolumeAuditLogCA(name string) func(v *corev1.Volume) {
+	return func(v *corev1.Volume) {
+		v.ConfigMap = &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		}
+	}
+}
+
+func kasVolumeAuditLogCredentials() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "audit-log-credentials",
+	}
+}
+
+func buildKASVolumeAuditLogCredentials(name string) func(v *corev1.Volume) {
+	return func(v *corev1.Volume) {
+		v.Secret = &corev1.SecretVolumeSource{
+			SecretName: name,
+		}
+	}
+}
+
+// applyAuditForwarder adds a sidecar forwarding the audit log of the apiserver
+// to the sink of the log backend.
+func applyAuditForwarder(podSpec *corev1.PodSpec, image string, backend *hyperv1.AuditLogBackendSpec) error {
+	if err := validateAuditSink(&backend.Sink); err != nil {
+		return err
+	}
+	podSpec.Containers = append(podSpec.Containers, util.BuildContainer(kasContainerAuditForwarder(), buildKASContainerAuditForwarder(image, backend)))
+	if backend.Sink.Type == hyperv1.PersistentVolumeAuditSink {
+		podSpec.Volumes = append(podSpec.Volumes, util.BuildVolume(kasVolumeAuditLogs(), buildKASVolumeAuditLogs))
+	}
+	if backend.CA != nil {
+		podSpec.Volumes = append(podSpec.Volumes, util.BuildVolume(kasVolumeAuditLogCA(), buildKASVolumeAuditLogCA(backend.CA.Name)))
+	}
+	if backend.Credentials != nil {
+		podSpec.Volumes = append(podSpec.Volumes, util.BuildVolume(kasVolumeAuditLogCredentials(), buildKASVolumeAuditLogCredentials(backend.Credentials.Name)))
+	}
+	return nil
+}
+
+func validateAuditSink(sink *hyperv1.AuditSinkSpec) error {
+	switch sink.Type {
+	case hyperv1.PersistentVolumeAuditSink:
+		if sink.PersistentVolume == nil {
+			return fmt.Errorf("audit sink %s requires persistent volume settings", sink.Type)
+		}
+		if len(sink.PersistentVolume.StorageClassName) == 0 {
+			return fmt.Errorf("audit sink %s requires a storage class supporting the ReadWriteMany access mode", sink.Type)
+		}
+	case hyperv1.SyslogAuditSink:
+		if sink.Syslog == nil {
+			return fmt.Errorf("audit sink %s requires syslog settings", sink.Type)
+		}
+	case hyperv1.HTTPAuditSink:
+		if sink.HTTP == nil {
+			return fmt.Errorf("audit sink %s requires http settings", sink.Type)
+		}
+	default:
+		return fmt.Errorf("unsupported audit sink %s", sink.Type)
+	}
+	return nil
+}
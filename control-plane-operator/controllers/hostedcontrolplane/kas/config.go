@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path"
+	"strconv"
 
 	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 
@@ -16,15 +17,18 @@ import (
 	kcpv1 "github.com/openshift/api/kubecontrolplane/v1"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/cloud"
 	hcpconfig "github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/encryption"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/pki"
 )
 
 const (
-	KubeAPIServerConfigKey  = "config.json"
-	OauthMetadataConfigKey  = "oauthMetadata.json"
-	AuditLogFile            = "audit.log"
-	EgressSelectorConfigKey = "config.yaml"
-	DefaultEtcdPort         = 2379
+	KubeAPIServerConfigKey    = "config.json"
+	OauthMetadataConfigKey    = "oauthMetadata.json"
+	AuditLogFile              = "audit.log"
+	EgressSelectorConfigKey   = "config.yaml"
+	DefaultEtcdPort           = 2379
+	DefaultAuditLogMaxSize    = 100
+	DefaultAuditLogMaxBackups = 10
 )
 
 func ReconcileConfig(config *corev1.ConfigMap,
@@ -104,8 +108,8 @@ func generateConfig(ns string, p KubeAPIServerConfigParams) *kcpv1.KubeAPIServer
 	args.Set("anonymous-auth", "true")
 	args.Set("api-audiences", p.ServiceAccountIssuerURL)
 	args.Set("audit-log-format", "json")
-	args.Set("audit-log-maxbackup", "10")
-	args.Set("audit-log-maxsize", "100")
+	args.Set("audit-log-maxbackup", strconv.Itoa(int(p.AuditLogMaxBackups)))
+	args.Set("audit-log-maxsize", strconv.Itoa(int(p.AuditLogMaxSize)))
 	args.Set("audit-log-path", cpath(kasVolumeWorkLogs().Name, AuditLogFile))
 	args.Set("audit-policy-file", cpath(kasVolumeAuditConfig().Name, AuditPolicyConfigMapKey))
 	args.Set("authorization-mode", "Scope", "SystemMasters", "RBAC", "Node")
@@ -125,6 +129,7 @@ func generateConfig(ns string, p KubeAPIServerConfigParams) *kcpv1.KubeAPIServer
 	args.Set("enable-aggregator-routing", "true")
 	args.Set("enable-logs-handler", "false")
 	args.Set("enable-swagger-ui", "true")
+	args.Set("encryption-provider-config", cpath(kasVolumeEncryptionConfig().Name, encryption.EncryptionConfigKey))
 	args.Set("endpoint-reconciler-type", "lease")
 	args.Set("etcd-cafile", cpath(kasVolumeEtcdClientCert().Name, pki.EtcdClientCAKey))
 	args.Set("etcd-certfile", cpath(kasVolumeEtcdClientCert().Name, pki.EtcdClientCrtKey))
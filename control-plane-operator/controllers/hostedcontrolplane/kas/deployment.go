@@ -11,14 +11,18 @@ import (
 	"k8s.io/utils/pointer"
 
 	configv1 "github.com/openshift/api/config/v1"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/encryption"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/pki"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/util"
 )
 
 const (
 	kasNamedCertificateMountPathPrefix = "/etc/kubernetes/certs/named"
 	configHashAnnotation               = "kube-apiserver.hypershift.openshift.io/config-hash"
+	auditConfigHashAnnotation          = "kube-apiserver.hypershift.openshift.io/audit-config-hash"
 )
 
 var (
@@ -46,6 +50,7 @@ var (
 			kasVolumeKubeletClientCA().Name:        "/etc/kubernetes/certs/kubelet-ca",
 			kasVolumeKonnectivityClientCert().Name: "/etc/kubernetes/certs/konnectivity-client",
 			kasVolumeEgressSelectorConfig().Name:   "/etc/kubernetes/egress-selector",
+			kasVolumeEncryptionConfig().Name:       "/etc/kubernetes/encryption",
 		},
 	}
 
@@ -73,7 +78,12 @@ func ReconcileKubeAPIServerDeployment(deployment *appsv1.Deployment,
 	cloudProviderConfigRef *corev1.LocalObjectReference,
 	images KubeAPIServerImages,
 	config *corev1.ConfigMap,
-	auditWebhookRef *corev1.LocalObjectReference) error {
+	auditConfig *corev1.ConfigMap,
+	encryptionConfig *corev1.Secret,
+	encryptionState *encryption.State,
+	serviceAccountSigningKey *corev1.Secret,
+	auditWebhookRef *corev1.LocalObjectReference,
+	auditLogBackend *hyperv1.AuditLogBackendSpec) error {
 
 	configBytes, ok := config.Data[KubeAPIServerConfigKey]
 	if !ok {
@@ -111,7 +121,10 @@ func ReconcileKubeAPIServerDeployment(deployment *appsv1.Deployment,
 			ObjectMeta: metav1.ObjectMeta{
 				Labels: kasLabels,
 				Annotations: map[string]string{
-					configHashAnnotation: configHash,
+					configHashAnnotation:                       configHash,
+					auditConfigHashAnnotation:                  util.ComputeHash(auditConfig.Data[AuditPolicyConfigMapKey]),
+					encryption.ConfigHashAnnotation:            encryption.ConfigHash(encryptionConfig),
+					pki.ServiceAccountSigningKeyHashAnnotation: pki.ServiceAccountSigningKeyHash(serviceAccountSigningKey),
 				},
 			},
 			Spec: corev1.PodSpec{
@@ -141,10 +154,19 @@ func ReconcileKubeAPIServerDeployment(deployment *appsv1.Deployment,
 					util.BuildVolume(kasVolumeKubeletClientCA(), buildKASVolumeKubeletClientCA),
 					util.BuildVolume(kasVolumeKonnectivityClientCert(), buildKASVolumeKonnectivityClientCert),
 					util.BuildVolume(kasVolumeEgressSelectorConfig(), buildKASVolumeEgressSelectorConfig),
+					util.BuildVolume(kasVolumeEncryptionConfig(), buildKASVolumeEncryptionConfig),
 				},
 			},
 		},
 	}
+	if err := encryption.ApplyKMSPlugins(&deployment.Spec.Template.Spec, kasContainerMain().Name, images.HyperShift, encryptionState); err != nil {
+		return fmt.Errorf("failed to add KMS plugins: %w", err)
+	}
+	if auditLogBackend != nil {
+		if err := applyAuditForwarder(&deployment.Spec.Template.Spec, images.HyperShift, auditLogBackend); err != nil {
+			return err
+		}
+	}
 	deploymentConfig.ApplyTo(deployment)
 	applyNamedCertificateMounts(namedCertificates, &deployment.Spec.Template.Spec)
 	applyCloudConfigVolumeMount(cloudProviderConfigRef, &deployment.Spec.Template.Spec)
@@ -354,6 +376,17 @@ func buildKASVolumeEgressSelectorConfig(v *corev1.Volume) {
 	v.ConfigMap.Name = manifests.KASEgressSelectorConfig("").Name
 }
 
+func kasVolumeEncryptionConfig() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "encryption-config",
+	}
+}
+func buildKASVolumeEncryptionConfig(v *corev1.Volume) {
+	v.Secret = &corev1.SecretVolumeSource{
+		SecretName: manifests.EncryptionConfigSecret("").Name,
+	}
+}
+
 func kasVolumeServiceAccountKey() *corev1.Volume {
 	return &corev1.Volume{
 		Name: "svcacct-key",
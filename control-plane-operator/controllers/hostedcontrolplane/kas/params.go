@@ -20,6 +20,7 @@ type KubeAPIServerImages struct {
 	ClusterConfigOperator string `json:"clusterConfigOperator"`
 	CLI                   string `json:"cli"`
 	HyperKube             string `json:"hyperKube"`
+	HyperShift            string `json:"hyperShift"`
 }
 
 type KubeAPIServerParams struct {
@@ -43,6 +44,8 @@ type KubeAPIServerParams struct {
 	APIServerPort        int32                        `json:"apiServerPort"`
 	KubeConfigRef        *hyperv1.KubeconfigSecretRef `json:"kubeConfigRef"`
 	AuditWebhookRef      *corev1.LocalObjectReference `json:"auditWebhookRef"`
+	AuditPolicyRef       *corev1.LocalObjectReference `json:"auditPolicyRef"`
+	AuditLogBackend      *hyperv1.AuditLogBackendSpec `json:"auditLogBackend"`
 	config.DeploymentConfig
 	config.OwnerRef
 
@@ -74,6 +77,7 @@ func NewKubeAPIServerParams(ctx context.Context, hcp *hyperv1.HostedControlPlane
 			HyperKube:             images["hyperkube"],
 			CLI:                   images["cli"],
 			ClusterConfigOperator: images["cluster-config-operator"],
+			HyperShift:            images["hypershift"],
 		},
 	}
 	if hcp.Spec.APIAdvertiseAddress != nil {
@@ -147,6 +151,7 @@ func NewKubeAPIServerParams(ctx context.Context, hcp *hyperv1.HostedControlPlane
 	params.DeploymentConfig.SetColocation(hcp)
 	params.DeploymentConfig.SetMultizoneSpread(kasLabels)
 	params.DeploymentConfig.SetRestartAnnotation(hcp.ObjectMeta)
+	params.DeploymentConfig.SetComponentOverrides(hcp, manifests.KASDeployment("").Name)
 	params.DeploymentConfig.SetControlPlaneIsolation(hcp)
 
 	switch hcp.Spec.Platform.Type {
@@ -157,6 +162,12 @@ func NewKubeAPIServerParams(ctx context.Context, hcp *hyperv1.HostedControlPlane
 	if hcp.Spec.AuditWebhook != nil && len(hcp.Spec.AuditWebhook.Name) > 0 {
 		params.AuditWebhookRef = hcp.Spec.AuditWebhook
 	}
+	if hcp.Spec.Audit != nil {
+		if hcp.Spec.Audit.Policy != nil && len(hcp.Spec.Audit.Policy.Name) > 0 {
+			params.AuditPolicyRef = hcp.Spec.Audit.Policy
+		}
+		params.AuditLogBackend = hcp.Spec.Audit.LogBackend
+	}
 
 	switch hcp.Spec.ControllerAvailabilityPolicy {
 	case hyperv1.HighlyAvailable:
@@ -185,6 +196,20 @@ func (p *KubeAPIServerParams) AuditPolicyProfile() configv1.AuditProfileType {
 	}
 }
 
+func (p *KubeAPIServerParams) AuditLogMaxSize() int32 {
+	if p.AuditLogBackend != nil && p.AuditLogBackend.MaxSize > 0 {
+		return p.AuditLogBackend.MaxSize
+	}
+	return DefaultAuditLogMaxSize
+}
+
+func (p *KubeAPIServerParams) AuditLogMaxBackups() int32 {
+	if p.AuditLogBackend != nil && p.AuditLogBackend.MaxBackups > 0 {
+		return p.AuditLogBackend.MaxBackups
+	}
+	return DefaultAuditLogMaxBackups
+}
+
 func (p *KubeAPIServerParams) ExternalURL() string {
 	return fmt.Sprintf("https://%s:%d", p.ExternalAddress, p.ExternalPort)
 }
@@ -232,6 +257,8 @@ func (p *KubeAPIServerParams) ConfigParams() KubeAPIServerConfigParams {
 		FeatureGates:                 p.FeatureGates(),
 		NodePortRange:                p.ServiceNodePortRange(),
 		AuditWebhookEnabled:          p.AuditWebhookRef != nil,
+		AuditLogMaxSize:              p.AuditLogMaxSize(),
+		AuditLogMaxBackups:           p.AuditLogMaxBackups(),
 	}
 }
 
@@ -254,6 +281,8 @@ type KubeAPIServerConfigParams struct {
 	FeatureGates                 []string
 	NodePortRange                string
 	AuditWebhookEnabled          bool
+	AuditLogMaxSize              int32
+	AuditLogMaxBackups           int32
 }
 
 func (p *KubeAPIServerParams) TLSSecurityProfile() *configv1.TLSSecurityProfile {
@@ -55,7 +55,7 @@ var (
 	}
 )
 
-func ReconcileDeployment(deployment *appsv1.Deployment, config, servingCA *corev1.ConfigMap, p *KubeControllerManagerParams) error {
+func ReconcileDeployment(deployment *appsv1.Deployment, config, servingCA *corev1.ConfigMap, serviceAccountSigningKey *corev1.Secret, p *KubeControllerManagerParams) error {
 	deployment.Spec.Selector = &metav1.LabelSelector{
 		MatchLabels: kcmLabels,
 	}
@@ -82,6 +82,7 @@ func ReconcileDeployment(deployment *appsv1.Deployment, config, servingCA *corev
 		deployment.Spec.Template.ObjectMeta.Annotations = map[string]string{}
 	}
 	deployment.Spec.Template.ObjectMeta.Annotations[configHashAnnotation] = util.ComputeHash(configBytes)
+	deployment.Spec.Template.ObjectMeta.Annotations[pki.ServiceAccountSigningKeyHashAnnotation] = pki.ServiceAccountSigningKeyHash(serviceAccountSigningKey)
 
 	deployment.Spec.Template.Spec = corev1.PodSpec{
 		AutomountServiceAccountToken: pointer.BoolPtr(false),
@@ -0,0 +1,33 @@
+package konnectivity
+
+import (
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+// ReleaseImageComponent is the name of the konnectivity component of release
+// images. It provides both the server and the agent.
+const ReleaseImageComponent = "apiserver-network-proxy"
+
+// ResolveImages determines the images of the konnectivity server and agent
+// from the component images of a release image. Image overrides of a component
+// take precedence over the konnectivity image of the release image, which in
+// turn takes precedence over the default image of the operator. The image of
+// a component is empty if none of them is set.
+func ResolveImages(images map[string]string, defaultImage string) *hyperv1.KonnectivityImagesStatus {
+	return &hyperv1.KonnectivityImagesStatus{
+		Server: resolveImage(images, hyperv1.KonnectivityServerImageComponent, defaultImage),
+		Agent:  resolveImage(images, hyperv1.KonnectivityAgentImageComponent, defaultImage),
+	}
+}
+
+func resolveImage(images map[string]string, component hyperv1.ImageOverrideComponent, defaultImage string) hyperv1.ComponentImageStatus {
+	// Release images don't carry the konnectivity server and agent as separate
+	// components, they only exist when overridden.
+	if image, ok := images[string(component)]; ok {
+		return hyperv1.ComponentImageStatus{Image: image, Source: hyperv1.ImageOverrideSource}
+	}
+	if image, ok := images[ReleaseImageComponent]; ok {
+		return hyperv1.ComponentImageStatus{Image: image, Source: hyperv1.ReleaseImageSource}
+	}
+	return hyperv1.ComponentImageStatus{Image: defaultImage, Source: hyperv1.OperatorDefaultSource}
+}
@@ -0,0 +1,55 @@
+package konnectivity
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+func TestResolveImages(t *testing.T) {
+	const defaultImage = "registry.example.com/apiserver-network-proxy@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	tests := []struct {
+		name           string
+		images         map[string]string
+		expectedServer hyperv1.ComponentImageStatus
+		expectedAgent  hyperv1.ComponentImageStatus
+	}{
+		{
+			name:           "operator default",
+			images:         map[string]string{"hyperkube": "release/hyperkube"},
+			expectedServer: hyperv1.ComponentImageStatus{Image: defaultImage, Source: hyperv1.OperatorDefaultSource},
+			expectedAgent:  hyperv1.ComponentImageStatus{Image: defaultImage, Source: hyperv1.OperatorDefaultSource},
+		},
+		{
+			name:           "release image",
+			images:         map[string]string{ReleaseImageComponent: "release/apiserver-network-proxy"},
+			expectedServer: hyperv1.ComponentImageStatus{Image: "release/apiserver-network-proxy", Source: hyperv1.ReleaseImageSource},
+			expectedAgent:  hyperv1.ComponentImageStatus{Image: "release/apiserver-network-proxy", Source: hyperv1.ReleaseImageSource},
+		},
+		{
+			name: "image override",
+			images: map[string]string{
+				ReleaseImageComponent: "release/apiserver-network-proxy",
+				"konnectivity-agent":  "override/konnectivity-agent",
+			},
+			expectedServer: hyperv1.ComponentImageStatus{Image: "release/apiserver-network-proxy", Source: hyperv1.ReleaseImageSource},
+			expectedAgent:  hyperv1.ComponentImageStatus{Image: "override/konnectivity-agent", Source: hyperv1.ImageOverrideSource},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			images := ResolveImages(test.images, defaultImage)
+			g.Expect(images.Server).To(Equal(test.expectedServer))
+			g.Expect(images.Agent).To(Equal(test.expectedAgent))
+		})
+	}
+
+	// Without a default image, release images without konnectivity need overrides
+	g := NewGomegaWithT(t)
+	images := ResolveImages(map[string]string{"konnectivity-agent": "override/konnectivity-agent"}, "")
+	g.Expect(images.Server.Image).To(BeEmpty())
+	g.Expect(images.Agent.Image).To(Equal("override/konnectivity-agent"))
+}
@@ -7,6 +7,7 @@ import (
 
 	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
 )
 
 const (
@@ -24,10 +25,11 @@ type KonnectivityParams struct {
 	AgentDeamonSetConfig    config.DeploymentConfig
 }
 
-func NewKonnectivityParams(hcp *hyperv1.HostedControlPlane, images map[string]string, externalAddress string, externalPort int32) *KonnectivityParams {
+func NewKonnectivityParams(hcp *hyperv1.HostedControlPlane, images map[string]string, defaultImage string, externalAddress string, externalPort int32) *KonnectivityParams {
+	konnectivityImages := ResolveImages(images, defaultImage)
 	p := &KonnectivityParams{
-		KonnectivityServerImage: images["konnectivity-server"],
-		KonnectivityAgentImage:  images["konnectivity-agent"],
+		KonnectivityServerImage: konnectivityImages.Server.Image,
+		KonnectivityAgentImage:  konnectivityImages.Agent.Image,
 		ExternalAddress:         externalAddress,
 		ExternalPort:            externalPort,
 		OwnerRef:                config.OwnerRefFrom(hcp),
@@ -56,10 +58,16 @@ func NewKonnectivityParams(hcp *hyperv1.HostedControlPlane, images map[string]st
 			},
 		},
 	}
-	p.ServerDeploymentConfig.Replicas = 1
+	switch hcp.Spec.ControllerAvailabilityPolicy {
+	case hyperv1.HighlyAvailable:
+		p.ServerDeploymentConfig.Replicas = 3
+	default:
+		p.ServerDeploymentConfig.Replicas = 1
+	}
 	p.ServerDeploymentConfig.SetColocation(hcp)
 	p.ServerDeploymentConfig.SetMultizoneSpread(konnectivityServerLabels)
 	p.ServerDeploymentConfig.SetRestartAnnotation(hcp.ObjectMeta)
+	p.ServerDeploymentConfig.SetComponentOverrides(hcp, manifests.KonnectivityServerDeployment("").Name)
 	p.ServerDeploymentConfig.SetControlPlaneIsolation(hcp)
 
 	p.AgentDeploymentConfig.Resources = config.ResourcesSpec{
@@ -88,6 +96,7 @@ func NewKonnectivityParams(hcp *hyperv1.HostedControlPlane, images map[string]st
 	}
 	p.AgentDeploymentConfig.Replicas = 1
 	p.AgentDeploymentConfig.SetRestartAnnotation(hcp.ObjectMeta)
+	p.AgentDeploymentConfig.SetComponentOverrides(hcp, manifests.KonnectivityAgentDeployment("").Name)
 	p.AgentDeamonSetConfig.Resources = config.ResourcesSpec{
 		konnectivityAgentContainer().Name: {
 			Requests: corev1.ResourceList{
@@ -115,17 +124,14 @@ func NewKonnectivityParams(hcp *hyperv1.HostedControlPlane, images map[string]st
 			SuccessThreshold:    1,
 		},
 	}
-	if hcp.Annotations != nil {
-		if _, ok := hcp.Annotations[hyperv1.KonnectivityServerImageAnnotation]; ok {
-			p.KonnectivityServerImage = hcp.Annotations[hyperv1.KonnectivityServerImageAnnotation]
-		}
-		if _, ok := hcp.Annotations[hyperv1.KonnectivityAgentImageAnnotation]; ok {
-			p.KonnectivityAgentImage = hcp.Annotations[hyperv1.KonnectivityAgentImageAnnotation]
-		}
-	}
 	return p
 }
 
+// ServerCount is the number of konnectivity servers every agent connects to.
+func (p *KonnectivityParams) ServerCount() int {
+	return p.ServerDeploymentConfig.EffectiveReplicas()
+}
+
 type KonnectivityServiceParams struct {
 	OwnerRef config.OwnerRef
 }
@@ -40,14 +40,28 @@ var (
 
 const (
 	KubeconfigKey = "kubeconfig"
+
+	// serverCountAnnotation records the number of konnectivity servers on the
+	// agents, so they are restarted and connect to every server whenever the
+	// number of servers changes.
+	serverCountAnnotation = "konnectivity.hypershift.openshift.io/server-count"
 )
 
-func ReconcileServerDeployment(deployment *appsv1.Deployment, ownerRef config.OwnerRef, deploymentConfig config.DeploymentConfig, image string) error {
+func ReconcileServerDeployment(deployment *appsv1.Deployment, ownerRef config.OwnerRef, deploymentConfig config.DeploymentConfig, image string, serverCount int) error {
 	ownerRef.ApplyTo(deployment)
+	maxSurge := intstr.FromInt(1)
+	maxUnavailable := intstr.FromInt(1)
 	deployment.Spec = appsv1.DeploymentSpec{
 		Selector: &metav1.LabelSelector{
 			MatchLabels: konnectivityServerLabels,
 		},
+		Strategy: appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxSurge:       &maxSurge,
+				MaxUnavailable: &maxUnavailable,
+			},
+		},
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels: konnectivityServerLabels,
@@ -55,7 +69,7 @@ func ReconcileServerDeployment(deployment *appsv1.Deployment, ownerRef config.Ow
 			Spec: corev1.PodSpec{
 				AutomountServiceAccountToken: pointer.BoolPtr(false),
 				Containers: []corev1.Container{
-					util.BuildContainer(konnectivityServerContainer(), buildKonnectivityServerContainer(image)),
+					util.BuildContainer(konnectivityServerContainer(), buildKonnectivityServerContainer(image, serverCount)),
 				},
 				Volumes: []corev1.Volume{
 					util.BuildVolume(konnectivityVolumeServerCerts(), buildKonnectivityVolumeServerCerts),
@@ -74,7 +88,7 @@ func konnectivityServerContainer() *corev1.Container {
 	}
 }
 
-func buildKonnectivityServerContainer(image string) func(c *corev1.Container) {
+func buildKonnectivityServerContainer(image string, serverCount int) func(c *corev1.Container) {
 	cpath := func(volume, file string) string {
 		return path.Join(volumeMounts.Path(konnectivityServerContainer().Name, volume), file)
 	}
@@ -84,9 +98,28 @@ func buildKonnectivityServerContainer(image string) func(c *corev1.Container) {
 		c.Command = []string{
 			"/usr/bin/proxy-server",
 		}
+		c.Env = []corev1.EnvVar{
+			{
+				Name: "POD_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+				},
+			},
+			{
+				Name: "POD_IP",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+				},
+			},
+		}
 		c.Args = []string{
 			"--logtostderr=true",
 			"--log-file-max-size=0",
+			"--server-id=$(POD_NAME)",
+			// Agents keep connecting through the service until they reach
+			// every server, so the servers must know how many there are.
+			"--server-count",
+			strconv.Itoa(serverCount),
 			"--cluster-cert",
 			cpath(konnectivityVolumeClusterCerts().Name, corev1.TLSCertKey),
 			"--cluster-key",
@@ -104,7 +137,9 @@ func buildKonnectivityServerContainer(image string) func(c *corev1.Container) {
 			strconv.Itoa(KonnectivityServerPort),
 			"--health-port",
 			strconv.Itoa(healthPort),
-			"--admin-port=8093",
+			"--admin-port",
+			strconv.Itoa(AdminPort),
+			"--admin-bind-address=$(POD_IP)",
 			"--mode=http-connect",
 			"--proxy-strategies=destHost,defaultRoute",
 		}
@@ -139,6 +174,9 @@ func buildKonnectivityVolumeClusterCerts(v *corev1.Volume) {
 const (
 	KonnectivityServerLocalPort = 8090
 	KonnectivityServerPort      = 8091
+
+	// AdminPort serves the metrics of the konnectivity servers.
+	AdminPort = 8093
 )
 
 func ReconcileServerLocalService(svc *corev1.Service, ownerRef config.OwnerRef) error {
@@ -216,23 +254,24 @@ func ReconcileServerServiceStatus(svc *corev1.Service, strategy *hyperv1.Service
 	return
 }
 
-func ReconcileWorkerAgentDaemonSet(cm *corev1.ConfigMap, ownerRef config.OwnerRef, deploymentConfig config.DeploymentConfig, image string, host string, port int32) error {
+func ReconcileWorkerAgentDaemonSet(cm *corev1.ConfigMap, ownerRef config.OwnerRef, deploymentConfig config.DeploymentConfig, image string, host string, port int32, serverCount int) error {
 	ownerRef.ApplyTo(cm)
 	agentDaemonSet := manifests.KonnectivityAgentDaemonSet()
-	if err := reconcileWorkerAgentDaemonSet(agentDaemonSet, deploymentConfig, image, host, port); err != nil {
+	if err := reconcileWorkerAgentDaemonSet(agentDaemonSet, deploymentConfig, image, host, port, serverCount); err != nil {
 		return err
 	}
 	return util.ReconcileWorkerManifest(cm, agentDaemonSet)
 }
 
-func reconcileWorkerAgentDaemonSet(daemonset *appsv1.DaemonSet, deploymentConfig config.DeploymentConfig, image string, host string, port int32) error {
+func reconcileWorkerAgentDaemonSet(daemonset *appsv1.DaemonSet, deploymentConfig config.DeploymentConfig, image string, host string, port int32, serverCount int) error {
 	daemonset.Spec = appsv1.DaemonSetSpec{
 		Selector: &metav1.LabelSelector{
 			MatchLabels: konnectivityAgentLabels,
 		},
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
-				Labels: konnectivityAgentLabels,
+				Labels:      konnectivityAgentLabels,
+				Annotations: map[string]string{serverCountAnnotation: strconv.Itoa(serverCount)},
 			},
 			Spec: corev1.PodSpec{
 				AutomountServiceAccountToken: pointer.BoolPtr(false),
@@ -301,15 +340,25 @@ func buildKonnectivityWorkerAgentContainer(image, host string, port int32) func(
 	}
 }
 
-func ReconcileAgentDeployment(deployment *appsv1.Deployment, ownerRef config.OwnerRef, deploymentConfig config.DeploymentConfig, image string, ips []string) error {
+func ReconcileAgentDeployment(deployment *appsv1.Deployment, ownerRef config.OwnerRef, deploymentConfig config.DeploymentConfig, image string, ips []string, serverCount int) error {
 	ownerRef.ApplyTo(deployment)
+	maxSurge := intstr.FromInt(1)
+	maxUnavailable := intstr.FromInt(1)
 	deployment.Spec = appsv1.DeploymentSpec{
 		Selector: &metav1.LabelSelector{
 			MatchLabels: konnectivityAgentLabels,
 		},
+		Strategy: appsv1.DeploymentStrategy{
+			Type: appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{
+				MaxSurge:       &maxSurge,
+				MaxUnavailable: &maxUnavailable,
+			},
+		},
 		Template: corev1.PodTemplateSpec{
 			ObjectMeta: metav1.ObjectMeta{
-				Labels: konnectivityAgentLabels,
+				Labels:      konnectivityAgentLabels,
+				Annotations: map[string]string{serverCountAnnotation: strconv.Itoa(serverCount)},
 			},
 			Spec: corev1.PodSpec{
 				AutomountServiceAccountToken: pointer.BoolPtr(false),
@@ -0,0 +1,67 @@
+package konnectivity
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/pointer"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/support/thirdparty/library-go/pkg/image/reference"
+)
+
+func TestServerCount(t *testing.T) {
+	g := NewGomegaWithT(t)
+	hcp := &hyperv1.HostedControlPlane{}
+	hcp.Namespace = "clusters-test"
+	hcp.Spec.ControllerAvailabilityPolicy = hyperv1.HighlyAvailable
+	p := NewKonnectivityParams(hcp, nil, "konnectivity", "konnectivity.example.com", 8091)
+	g.Expect(p.ServerCount()).To(Equal(3))
+
+	server := &appsv1.Deployment{}
+	g.Expect(ReconcileServerDeployment(server, p.OwnerRef, p.ServerDeploymentConfig, p.KonnectivityServerImage, p.ServerCount())).To(Succeed())
+	g.Expect(*server.Spec.Replicas).To(Equal(int32(3)))
+	g.Expect(server.Spec.Template.Spec.Containers[0].Args).To(ContainElements("--server-count", "3", "--server-id=$(POD_NAME)"))
+
+	agent := &appsv1.Deployment{}
+	g.Expect(ReconcileAgentDeployment(agent, p.OwnerRef, p.AgentDeploymentConfig, p.KonnectivityAgentImage, nil, p.ServerCount())).To(Succeed())
+	g.Expect(agent.Spec.Template.Annotations).To(HaveKeyWithValue(serverCountAnnotation, "3"))
+
+	// The server count follows replica overrides
+	hcp.Spec.ComponentOverrides = []hyperv1.ControlPlaneComponentOverride{
+		{Name: "konnectivity-server", Replicas: pointer.Int32Ptr(5)},
+	}
+	p = NewKonnectivityParams(hcp, nil, "konnectivity", "konnectivity.example.com", 8091)
+	g.Expect(p.ServerCount()).To(Equal(5))
+}
+
+func TestReleaseWithoutKonnectivity(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	// The default image must be usable as is, the operator refuses to start
+	// with an image which isn't pinned by digest
+	ref, err := reference.Parse(hyperv1.DefaultKonnectivityImage)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ref.ID).ToNot(BeEmpty())
+
+	// 4.8 release images don't include the apiserver-network-proxy component
+	hcp := &hyperv1.HostedControlPlane{}
+	hcp.Namespace = "clusters-test"
+	images := map[string]string{
+		"hyperkube": "release/hyperkube",
+		"cli":       "release/cli",
+	}
+	p := NewKonnectivityParams(hcp, images, hyperv1.DefaultKonnectivityImage, "konnectivity.example.com", 8091)
+	g.Expect(p.KonnectivityServerImage).To(Equal(hyperv1.DefaultKonnectivityImage))
+	g.Expect(p.KonnectivityAgentImage).To(Equal(hyperv1.DefaultKonnectivityImage))
+
+	server := &appsv1.Deployment{}
+	g.Expect(ReconcileServerDeployment(server, p.OwnerRef, p.ServerDeploymentConfig, p.KonnectivityServerImage, p.ServerCount())).To(Succeed())
+	g.Expect(server.Spec.Template.Spec.Containers[0].Image).To(Equal(hyperv1.DefaultKonnectivityImage))
+
+	agent := &appsv1.Deployment{}
+	g.Expect(ReconcileAgentDeployment(agent, p.OwnerRef, p.AgentDeploymentConfig, p.KonnectivityAgentImage, nil, p.ServerCount())).To(Succeed())
+	g.Expect(agent.Spec.Template.Spec.Containers[0].Image).To(Equal(hyperv1.DefaultKonnectivityImage))
+}
@@ -0,0 +1,143 @@
+package konnectivity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/expfmt"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+const (
+	// readyBackendConnectionsMetric is the number of agents connected to a
+	// konnectivity server.
+	readyBackendConnectionsMetric = "konnectivity_network_proxy_server_ready_backend_connections"
+
+	KonnectivityReasonAsExpected         = "AsExpected"
+	KonnectivityReasonServersUnavailable = "ServersUnavailable"
+	KonnectivityReasonAgentsDisconnected = "AgentsDisconnected"
+	KonnectivityReasonStatusUnknown      = "StatusUnknown"
+)
+
+// ServerConnections is the number of agents connected to a konnectivity
+// server.
+type ServerConnections struct {
+	Server string
+	Agents int
+	Err    error
+}
+
+// ListServerPods returns the pods of the konnectivity servers.
+func ListServerPods(ctx context.Context, c client.Client, namespace string) ([]corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(namespace), client.MatchingLabels(konnectivityServerLabels)); err != nil {
+		return nil, fmt.Errorf("cannot list konnectivity server pods: %w", err)
+	}
+	return pods.Items, nil
+}
+
+// ServerAgentConnections reads the number of connected agents from the
+// metrics of every running konnectivity server pod.
+func ServerAgentConnections(ctx context.Context, httpClient *http.Client, pods []corev1.Pod) []ServerConnections {
+	var connections []ServerConnections
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || len(pod.Status.PodIP) == 0 || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+		url := fmt.Sprintf("http://%s/metrics", net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(AdminPort)))
+		agents, err := readyBackendConnections(ctx, httpClient, url)
+		connections = append(connections, ServerConnections{Server: pod.Name, Agents: agents, Err: err})
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].Server < connections[j].Server
+	})
+	return connections
+}
+
+func readyBackendConnections(ctx context.Context, httpClient *http.Client, url string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status reading metrics: %s", resp.Status)
+	}
+	return parseReadyBackendConnections(resp.Body)
+}
+
+func parseReadyBackendConnections(r io.Reader) (int, error) {
+	families, err := (&expfmt.TextParser{}).TextToMetricFamilies(r)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+	family, ok := families[readyBackendConnectionsMetric]
+	if !ok {
+		return 0, fmt.Errorf("metric %s not found", readyBackendConnectionsMetric)
+	}
+	var agents float64
+	for _, metric := range family.GetMetric() {
+		agents += metric.GetGauge().GetValue()
+	}
+	return int(agents), nil
+}
+
+// ComputeKonnectivityAvailableCondition computes the KonnectivityAvailable
+// condition from the number of agents connected to each server. Konnectivity
+// is available once the expected number of servers is running and each of
+// them has agents connected, including the agent of the control plane.
+func ComputeKonnectivityAvailableCondition(serverCount int, connections []ServerConnections) metav1.Condition {
+	var servers, unknown, disconnected []string
+	for _, c := range connections {
+		switch {
+		case c.Err != nil:
+			unknown = append(unknown, fmt.Sprintf("%s: %v", c.Server, c.Err))
+		case c.Agents == 0:
+			disconnected = append(disconnected, c.Server)
+		}
+		servers = append(servers, fmt.Sprintf("%s: %d", c.Server, c.Agents))
+	}
+	switch {
+	case len(unknown) > 0:
+		return metav1.Condition{
+			Type:    string(hyperv1.KonnectivityAvailable),
+			Status:  metav1.ConditionUnknown,
+			Reason:  KonnectivityReasonStatusUnknown,
+			Message: fmt.Sprintf("Failed to read the connected agents of konnectivity servers: %s", strings.Join(unknown, "; ")),
+		}
+	case len(connections) < serverCount:
+		return metav1.Condition{
+			Type:    string(hyperv1.KonnectivityAvailable),
+			Status:  metav1.ConditionFalse,
+			Reason:  KonnectivityReasonServersUnavailable,
+			Message: fmt.Sprintf("%d of %d konnectivity servers are running", len(connections), serverCount),
+		}
+	case len(disconnected) > 0:
+		return metav1.Condition{
+			Type:    string(hyperv1.KonnectivityAvailable),
+			Status:  metav1.ConditionFalse,
+			Reason:  KonnectivityReasonAgentsDisconnected,
+			Message: fmt.Sprintf("Konnectivity servers without connected agents: %s", strings.Join(disconnected, ", ")),
+		}
+	}
+	return metav1.Condition{
+		Type:    string(hyperv1.KonnectivityAvailable),
+		Status:  metav1.ConditionTrue,
+		Reason:  KonnectivityReasonAsExpected,
+		Message: fmt.Sprintf("Connected agents per konnectivity server: %s", strings.Join(servers, ", ")),
+	}
+}
@@ -0,0 +1,102 @@
+package konnectivity
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const serverMetrics = `# HELP konnectivity_network_proxy_server_ready_backend_connections Number of konnectivity agent connected to the proxy server
+# TYPE konnectivity_network_proxy_server_ready_backend_connections gauge
+konnectivity_network_proxy_server_ready_backend_connections 4
+# HELP konnectivity_network_proxy_server_dial_failure_count Number of dial failures observed.
+# TYPE konnectivity_network_proxy_server_dial_failure_count counter
+konnectivity_network_proxy_server_dial_failure_count{reason="backend_not_found"} 2
+`
+
+func TestParseReadyBackendConnections(t *testing.T) {
+	g := NewGomegaWithT(t)
+	agents, err := parseReadyBackendConnections(strings.NewReader(serverMetrics))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(agents).To(Equal(4))
+
+	_, err = parseReadyBackendConnections(strings.NewReader("# TYPE other gauge\nother 1\n"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestReadyBackendConnections(t *testing.T) {
+	g := NewGomegaWithT(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, serverMetrics)
+	}))
+	defer server.Close()
+
+	agents, err := readyBackendConnections(context.Background(), server.Client(), server.URL)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(agents).To(Equal(4))
+}
+
+func TestComputeKonnectivityAvailableCondition(t *testing.T) {
+	tests := []struct {
+		name           string
+		serverCount    int
+		connections    []ServerConnections
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:        "all servers have agents",
+			serverCount: 2,
+			connections: []ServerConnections{
+				{Server: "konnectivity-server-a", Agents: 3},
+				{Server: "konnectivity-server-b", Agents: 3},
+			},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: KonnectivityReasonAsExpected,
+		},
+		{
+			name:        "missing server",
+			serverCount: 3,
+			connections: []ServerConnections{
+				{Server: "konnectivity-server-a", Agents: 3},
+				{Server: "konnectivity-server-b", Agents: 3},
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: KonnectivityReasonServersUnavailable,
+		},
+		{
+			name:        "server without agents",
+			serverCount: 2,
+			connections: []ServerConnections{
+				{Server: "konnectivity-server-a", Agents: 3},
+				{Server: "konnectivity-server-b", Agents: 0},
+			},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: KonnectivityReasonAgentsDisconnected,
+		},
+		{
+			name:        "unreadable metrics",
+			serverCount: 1,
+			connections: []ServerConnections{
+				{Server: "konnectivity-server-a", Err: fmt.Errorf("connection refused")},
+			},
+			expectedStatus: metav1.ConditionUnknown,
+			expectedReason: KonnectivityReasonStatusUnknown,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			condition := ComputeKonnectivityAvailableCondition(test.serverCount, test.connections)
+			g.Expect(condition.Status).To(Equal(test.expectedStatus))
+			g.Expect(condition.Reason).To(Equal(test.expectedReason))
+		})
+	}
+}
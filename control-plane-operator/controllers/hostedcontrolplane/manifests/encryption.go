@@ -0,0 +1,49 @@
+package manifests
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// EncryptionKeysSecret holds the keys used to encrypt resources at rest along
+// with the state of the last key rotation.
+func EncryptionKeysSecret(ns string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "encryption-keys",
+			Namespace: ns,
+		},
+	}
+}
+
+// EncryptionConfigSecret holds the EncryptionConfiguration shared by the kube
+// and openshift apiservers.
+func EncryptionConfigSecret(ns string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "encryption-config",
+			Namespace: ns,
+		},
+	}
+}
+
+func EncryptionMigrationJob(ns string) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "encryption-migration",
+			Namespace: ns,
+		},
+	}
+}
+
+// KMSPluginCredentialsSecret holds the credentials of the KMS plugins which
+// run alongside the apiservers.
+func KMSPluginCredentialsSecret(ns string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kms-plugin-credentials",
+			Namespace: ns,
+		},
+	}
+}
@@ -2,6 +2,7 @@ package manifests
 
 import (
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -9,6 +10,56 @@ import (
 	etcdv1 "github.com/openshift/hypershift/control-plane-operator/thirdparty/etcd/v1beta2"
 )
 
+func EtcdStatefulSet(ns string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd",
+			Namespace: ns,
+		},
+	}
+}
+
+// EtcdDiscoveryService is the headless service which gives every etcd member a
+// stable DNS name. It must have the same name as the EtcdStatefulSet.
+func EtcdDiscoveryService(ns string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd",
+			Namespace: ns,
+		},
+	}
+}
+
+func EtcdClientService(ns string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd-client",
+			Namespace: ns,
+		},
+	}
+}
+
+func EtcdBackupPVC(ns string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd-backup",
+			Namespace: ns,
+		},
+	}
+}
+
+func EtcdBackupCronJob(ns string) *batchv1beta1.CronJob {
+	return &batchv1beta1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd-backup",
+			Namespace: ns,
+		},
+	}
+}
+
+// The following resources were created by previous versions to run etcd with
+// the etcd operator. They are only referenced to remove them.
+
 func EtcdOperatorServiceAccount(ns string) *corev1.ServiceAccount {
 	return &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
@@ -53,3 +104,39 @@ func EtcdCluster(ns string) *etcdv1.EtcdCluster {
 		},
 	}
 }
+
+func EtcdBackupOperatorDeployment(ns string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd-backup-operator",
+			Namespace: ns,
+		},
+	}
+}
+
+func EtcdRestoreOperatorDeployment(ns string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd-restore-operator",
+			Namespace: ns,
+		},
+	}
+}
+
+func EtcdBackup(ns string) *etcdv1.EtcdBackup {
+	return &etcdv1.EtcdBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd",
+			Namespace: ns,
+		},
+	}
+}
+
+func EtcdRestore(ns string) *etcdv1.EtcdRestore {
+	return &etcdv1.EtcdRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "etcd",
+			Namespace: ns,
+		},
+	}
+}
@@ -100,6 +100,15 @@ func KASAuditConfig(controlPlaneNamespace string) *corev1.ConfigMap {
 	}
 }
 
+func KASAuditLogPVC(controlPlaneNamespace string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kas-audit-logs",
+			Namespace: controlPlaneNamespace,
+		},
+	}
+}
+
 func KASEgressSelectorConfig(controlPlaneNamespace string) *corev1.ConfigMap {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
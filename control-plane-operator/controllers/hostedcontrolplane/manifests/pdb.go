@@ -0,0 +1,18 @@
+package manifests
+
+import (
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodDisruptionBudget returns the PodDisruptionBudget of the control plane
+// component run by the given deployment or statefulset.
+func PodDisruptionBudget(workload client.Object) *policyv1beta1.PodDisruptionBudget {
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      workload.GetName(),
+			Namespace: workload.GetNamespace(),
+		},
+	}
+}
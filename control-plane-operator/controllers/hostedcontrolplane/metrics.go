@@ -0,0 +1,30 @@
+package hostedcontrolplane
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/pki"
+)
+
+var (
+	certificateNotAfterSeconds = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hypershift_control_plane_certificate_not_after_seconds",
+		Help: "Unix time at which the earliest expiring certificate in a key of a control plane PKI secret expires.",
+	}, []string{"secret", "key"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		certificateNotAfterSeconds,
+	)
+}
+
+// updateCertificateMetrics reports the expiry of the given certificates.
+// Certificates which are gone are no longer reported.
+func updateCertificateMetrics(expiries []pki.CertificateExpiry) {
+	certificateNotAfterSeconds.Reset()
+	for _, expiry := range expiries {
+		certificateNotAfterSeconds.WithLabelValues(expiry.Secret, expiry.Key).Set(float64(expiry.NotAfter.Unix()))
+	}
+}
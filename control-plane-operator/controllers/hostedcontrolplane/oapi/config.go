@@ -13,6 +13,7 @@ import (
 	openshiftcpv1 "github.com/openshift/api/openshiftcontrolplane/v1"
 
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/encryption"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/kas"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/pki"
 )
@@ -56,11 +57,12 @@ func reconcileConfigObject(cfg *openshiftcpv1.OpenShiftAPIServerConfig, etcdURL,
 		return path.Join(dir, file)
 	}
 	cfg.APIServerArguments = map[string][]string{
-		"shutdown-delay-duration": {"3s"},
-		"audit-log-format":        {"json"},
-		"audit-log-maxsize":       {"100"},
-		"audit-log-path":          {cpath(oasVolumeWorkLogs().Name, "audit.log")},
-		"audit-policy-file":       {cpath(oasVolumeAuditConfig().Name, auditPolicyConfigMapKey)},
+		"shutdown-delay-duration":    {"3s"},
+		"audit-log-format":           {"json"},
+		"audit-log-maxsize":          {"100"},
+		"audit-log-path":             {cpath(oasVolumeWorkLogs().Name, "audit.log")},
+		"audit-policy-file":          {cpath(oasVolumeAuditConfig().Name, auditPolicyConfigMapKey)},
+		"encryption-provider-config": {cpath(oasVolumeEncryptionConfig().Name, encryption.EncryptionConfigKey)},
 	}
 	cfg.KubeClientConfig.KubeConfig = cpath(oasVolumeKubeconfig().Name, kas.KubeconfigKey)
 	cfg.ServingInfo = configv1.HTTPServingInfo{
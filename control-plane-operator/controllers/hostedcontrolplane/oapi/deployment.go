@@ -14,6 +14,7 @@ import (
 	"k8s.io/utils/pointer"
 
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/encryption"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/kas"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/pki"
@@ -32,6 +33,7 @@ var (
 			oasVolumeKubeconfig().Name:         "/etc/kubernetes/secrets/svc-kubeconfig",
 			oasVolumeServingCert().Name:        "/etc/kubernetes/certs/serving",
 			oasVolumeEtcdClientCert().Name:     "/etc/kubernetes/certs/etcd-client",
+			oasVolumeEncryptionConfig().Name:   "/etc/kubernetes/encryption",
 		},
 		oasKonnectivityProxyContainer().Name: {
 			oasVolumeConfig().Name:                "/etc/kubernetes/config",
@@ -43,7 +45,7 @@ var (
 	}
 )
 
-func ReconcileDeployment(deployment *appsv1.Deployment, ownerRef config.OwnerRef, config config.DeploymentConfig, image string, haproxyImage string, etcdURL string) error {
+func ReconcileDeployment(deployment *appsv1.Deployment, ownerRef config.OwnerRef, config config.DeploymentConfig, image string, haproxyImage string, hyperShiftImage string, etcdURL string, encryptionConfig *corev1.Secret, encryptionState *encryption.State) error {
 	ownerRef.ApplyTo(deployment)
 
 	maxUnavailable := intstr.FromInt(1)
@@ -60,6 +62,9 @@ func ReconcileDeployment(deployment *appsv1.Deployment, ownerRef config.OwnerRef
 		MatchLabels: openShiftAPIServerLabels,
 	}
 	deployment.Spec.Template.ObjectMeta.Labels = openShiftAPIServerLabels
+	deployment.Spec.Template.ObjectMeta.Annotations = map[string]string{
+		encryption.ConfigHashAnnotation: encryption.ConfigHash(encryptionConfig),
+	}
 	etcdUrlData, err := url.Parse(etcdURL)
 	if err != nil {
 		return fmt.Errorf("failed to parse etcd url: %w", err)
@@ -81,8 +86,12 @@ func ReconcileDeployment(deployment *appsv1.Deployment, ownerRef config.OwnerRef
 			util.BuildVolume(oasVolumeServingCert(), buildOASVolumeServingCert),
 			util.BuildVolume(oasVolumeEtcdClientCert(), buildOASVolumeEtcdClientCert),
 			util.BuildVolume(oasVolumeKonnectivityProxyCert(), buildOASVolumeKonnectivityProxyCert),
+			util.BuildVolume(oasVolumeEncryptionConfig(), buildOASVolumeEncryptionConfig),
 		},
 	}
+	if err := encryption.ApplyKMSPlugins(&deployment.Spec.Template.Spec, oasContainerMain().Name, hyperShiftImage, encryptionState); err != nil {
+		return fmt.Errorf("failed to add KMS plugins: %w", err)
+	}
 
 	config.ApplyTo(deployment)
 
@@ -254,6 +263,17 @@ func buildOASVolumeEtcdClientCert(v *corev1.Volume) {
 	v.Secret.SecretName = manifests.EtcdClientSecret("").Name
 }
 
+func oasVolumeEncryptionConfig() *corev1.Volume {
+	return &corev1.Volume{
+		Name: "encryption-config",
+	}
+}
+
+func buildOASVolumeEncryptionConfig(v *corev1.Volume) {
+	v.Secret = &corev1.SecretVolumeSource{}
+	v.Secret.SecretName = manifests.EncryptionConfigSecret("").Name
+}
+
 func oasVolumeKonnectivityProxyCert() *corev1.Volume {
 	return &corev1.Volume{
 		Name: "oas-konnectivity-proxy-cert",
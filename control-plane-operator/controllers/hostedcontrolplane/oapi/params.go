@@ -8,6 +8,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
 )
 
 type OpenShiftAPIServerParams struct {
@@ -21,6 +22,7 @@ type OpenShiftAPIServerParams struct {
 	OpenShiftAPIServerImage                 string `json:"openshiftAPIServerImage"`
 	OAuthAPIServerImage                     string `json:"oauthAPIServerImage"`
 	HaproxyImage                            string `json:"haproxyImage"`
+	HyperShiftImage                         string `json:"hyperShiftImage"`
 }
 
 type OAuthDeploymentParams struct {
@@ -36,6 +38,7 @@ func NewOpenShiftAPIServerParams(hcp *hyperv1.HostedControlPlane, globalConfig c
 		OpenShiftAPIServerImage: images["openshift-apiserver"],
 		OAuthAPIServerImage:     images["oauth-apiserver"],
 		HaproxyImage:            images["haproxy-router"],
+		HyperShiftImage:         images["hypershift"],
 		APIServer:               globalConfig.APIServer,
 		IngressSubDomain:        config.IngressSubdomain(hcp),
 	}
@@ -85,6 +88,7 @@ func NewOpenShiftAPIServerParams(hcp *hyperv1.HostedControlPlane, globalConfig c
 	}
 	params.OpenShiftAPIServerDeploymentConfig.SetMultizoneSpread(openShiftAPIServerLabels)
 	params.OpenShiftAPIServerDeploymentConfig.SetRestartAnnotation(hcp.ObjectMeta)
+	params.OpenShiftAPIServerDeploymentConfig.SetComponentOverrides(hcp, manifests.OpenShiftAPIServerDeployment("").Name)
 	params.OpenShiftAPIServerDeploymentConfig.SetControlPlaneIsolation(hcp)
 	params.OpenShiftOAuthAPIServerDeploymentConfig = config.DeploymentConfig{
 		Scheduling: config.Scheduling{
@@ -133,6 +137,7 @@ func NewOpenShiftAPIServerParams(hcp *hyperv1.HostedControlPlane, globalConfig c
 	params.OpenShiftOAuthAPIServerDeploymentConfig.SetColocation(hcp)
 	params.OpenShiftOAuthAPIServerDeploymentConfig.SetMultizoneSpread(openShiftOAuthAPIServerLabels)
 	params.OpenShiftOAuthAPIServerDeploymentConfig.SetRestartAnnotation(hcp.ObjectMeta)
+	params.OpenShiftOAuthAPIServerDeploymentConfig.SetComponentOverrides(hcp, manifests.OpenShiftOAuthAPIServerDeployment("").Name)
 	params.OpenShiftOAuthAPIServerDeploymentConfig.SetControlPlaneIsolation(hcp)
 	switch hcp.Spec.Etcd.ManagementType {
 	case hyperv1.Unmanaged:
@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -12,28 +13,125 @@ import (
 	"github.com/openshift/hypershift/support/certs"
 )
 
-func reconcileSelfSignedCA(secret *corev1.Secret, ownerRef config.OwnerRef, cn, ou string) error {
+const (
+	// nextCASignerCertMapKey and nextCASignerKeyMapKey hold a renewed CA while
+	// it is only published in the CA bundle, before it signs certificates.
+	nextCASignerCertMapKey = "next-ca.crt"
+	nextCASignerKeyMapKey  = "next-ca.key"
+)
+
+// reconcileSelfSignedCA generates a CA and renews it once it reaches its
+// renewal time. A renewed CA is rotated in two steps, each lasting the overlap
+// period of the rotation policy, so that everything trusting the CA bundle
+// picks up the renewed CA before it is used:
+//  1. The renewed CA is added to the CA bundle, the current CA keeps signing
+//     certificates.
+//  2. The renewed CA signs certificates, the replaced CA remains in the CA
+//     bundle until certificates it signed have been replaced.
+func reconcileSelfSignedCA(secret *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy, cn, ou string) error {
 	ownerRef.ApplyTo(secret)
 	secret.Type = corev1.SecretTypeOpaque
-	if hasKeys(secret, CASignerKeyMapKey, CASignerKeyMapKey) {
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	var current *x509.Certificate
+	if hasKeys(secret, CASignerCertMapKey, CASignerKeyMapKey) {
+		if crt, err := certs.PemToCertificate(secret.Data[CASignerCertMapKey]); err == nil {
+			current = crt
+		}
+	}
+	if current == nil {
+		keyBytes, crt, err := generateSelfSignedCA(rotation, cn, ou)
+		if err != nil {
+			return err
+		}
+		secret.Data = map[string][]byte{
+			CASignerCertMapKey: certs.CertToPem(crt),
+			CASignerKeyMapKey:  keyBytes,
+		}
+		delete(secret.Annotations, nextCASigningFromAnnotation)
+		delete(secret.Annotations, previousCATrustedUntilAnnotation)
+		rotation.annotateWithExpiry(secret, crt)
+		return nil
+	}
+
+	if signingFrom, pending := secret.Annotations[nextCASigningFromAnnotation]; pending {
+		next, err := certs.PemToCertificate(secret.Data[nextCASignerCertMapKey])
+		if err != nil || !hasKeys(secret, nextCASignerKeyMapKey) {
+			// The renewed CA is unusable, so it is dropped from the CA bundle
+			// and generated again
+			secret.Data[CASignerCertMapKey] = certs.CertToPem(current)
+			removeNextCA(secret)
+			delete(secret.Annotations, previousCATrustedUntilAnnotation)
+			return reconcileSelfSignedCA(secret, ownerRef, rotation, cn, ou)
+		}
+		if from, err := time.Parse(time.RFC3339, signingFrom); err == nil && rotation.Now.Before(from) {
+			annotateWithPendingRenewal(rotation, secret, current, from)
+			return nil
+		}
+		// The renewed CA has been published for the overlap period and starts
+		// signing certificates
+		secret.Data[CASignerCertMapKey] = append(certs.CertToPem(next), certs.CertToPem(current)...)
+		secret.Data[CASignerKeyMapKey] = secret.Data[nextCASignerKeyMapKey]
+		removeNextCA(secret)
+		secret.Annotations[previousCATrustedUntilAnnotation] = rotation.Now.Add(rotation.CAOverlap).UTC().Format(time.RFC3339)
+		rotation.annotateWithExpiry(secret, next)
+		return nil
+	}
+
+	if trustedUntil, hasPrevious := secret.Annotations[previousCATrustedUntilAnnotation]; hasPrevious {
+		if until, err := time.Parse(time.RFC3339, trustedUntil); err != nil || !rotation.Now.Before(until) {
+			secret.Data[CASignerCertMapKey] = certs.CertToPem(current)
+			delete(secret.Annotations, previousCATrustedUntilAnnotation)
+		}
+	}
+	if rotation.NeedsRenewal(current) {
+		keyBytes, next, err := generateSelfSignedCA(rotation, cn, ou)
+		if err != nil {
+			return err
+		}
+		secret.Data[CASignerCertMapKey] = append(secret.Data[CASignerCertMapKey], certs.CertToPem(next)...)
+		secret.Data[nextCASignerCertMapKey] = certs.CertToPem(next)
+		secret.Data[nextCASignerKeyMapKey] = keyBytes
+		signingFrom := rotation.Now.Add(rotation.CAOverlap)
+		secret.Annotations[nextCASigningFromAnnotation] = signingFrom.UTC().Format(time.RFC3339)
+		annotateWithPendingRenewal(rotation, secret, current, signingFrom)
 		return nil
 	}
+	rotation.annotateWithExpiry(secret, current)
+	return nil
+}
+
+// annotateWithPendingRenewal annotates a CA secret with the expiry of its
+// current CA, which is renewed once the renewed CA starts signing.
+func annotateWithPendingRenewal(rotation RotationPolicy, secret *corev1.Secret, current *x509.Certificate, signingFrom time.Time) {
+	rotation.annotateWithExpiry(secret, current)
+	secret.Annotations[CertificateRenewalTimeAnnotation] = signingFrom.UTC().Format(time.RFC3339)
+}
+
+func generateSelfSignedCA(rotation RotationPolicy, cn, ou string) ([]byte, *x509.Certificate, error) {
 	cfg := &certs.CertCfg{
-		Subject:   pkix.Name{CommonName: cn, OrganizationalUnit: []string{ou}},
-		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
-		Validity:  certs.ValidityTenYears,
-		IsCA:      true,
+		Subject:      pkix.Name{CommonName: cn, OrganizationalUnit: []string{ou}},
+		KeyUsages:    keyUsages(rotation.KeyAlgorithm, x509.KeyUsageKeyEncipherment|x509.KeyUsageDigitalSignature|x509.KeyUsageCertSign),
+		Validity:     certs.ValidityTenYears,
+		IsCA:         true,
+		KeyAlgorithm: rotation.KeyAlgorithm,
 	}
 	key, crt, err := certs.GenerateSelfSignedCertificate(cfg)
 	if err != nil {
-		return fmt.Errorf("failed to generate CA (cn=%s,ou=%s): %w", cn, ou, err)
+		return nil, nil, fmt.Errorf("failed to generate CA (cn=%s,ou=%s): %w", cn, ou, err)
 	}
-	if secret.Data == nil {
-		secret.Data = map[string][]byte{}
+	keyBytes, err := certs.SignerToPem(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode CA key (cn=%s,ou=%s): %w", cn, ou, err)
 	}
-	secret.Data[CASignerCertMapKey] = certs.CertToPem(crt)
-	secret.Data[CASignerKeyMapKey] = certs.PrivateKeyToPem(key)
-	return nil
+	return keyBytes, crt, nil
+}
+
+func removeNextCA(secret *corev1.Secret) {
+	delete(secret.Data, nextCASignerCertMapKey)
+	delete(secret.Data, nextCASignerKeyMapKey)
+	delete(secret.Annotations, nextCASigningFromAnnotation)
 }
 
 func reconcileAggregateCA(configMap *corev1.ConfigMap, ownerRef config.OwnerRef, sources ...*corev1.Secret) error {
@@ -50,12 +148,12 @@ func reconcileAggregateCA(configMap *corev1.ConfigMap, ownerRef config.OwnerRef,
 	return nil
 }
 
-func ReconcileRootCA(secret *corev1.Secret, ownerRef config.OwnerRef) error {
-	return reconcileSelfSignedCA(secret, ownerRef, "root-ca", "openshift")
+func ReconcileRootCA(secret *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
+	return reconcileSelfSignedCA(secret, ownerRef, rotation, "root-ca", "openshift")
 }
 
-func ReconcileClusterSignerCA(secret *corev1.Secret, ownerRef config.OwnerRef) error {
-	return reconcileSelfSignedCA(secret, ownerRef, "cluster-signer", "openshift")
+func ReconcileClusterSignerCA(secret *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
+	return reconcileSelfSignedCA(secret, ownerRef, rotation, "cluster-signer", "openshift")
 }
 
 func ReconcileCombinedCA(cm *corev1.ConfigMap, ownerRef config.OwnerRef, rootCA, signerCA *corev1.Secret) error {
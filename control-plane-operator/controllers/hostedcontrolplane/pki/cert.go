@@ -21,19 +21,28 @@ var (
 	X509SignerUsage  = X509DefaultUsage | x509.KeyUsageCertSign
 )
 
-func reconcileSignedCert(secret *corev1.Secret, ca *corev1.Secret, ownerRef config.OwnerRef, cn, org string, usage x509.KeyUsage, extUsages []x509.ExtKeyUsage) error {
-	return reconcileSignedCertWithKeys(secret, ca, ownerRef, cn, org, usage, extUsages, corev1.TLSCertKey, corev1.TLSPrivateKeyKey, CASignerCertMapKey)
+// keyUsages returns usages without key encipherment for keys other than RSA,
+// since only RSA keys encipher keys directly (RFC 5480).
+func keyUsages(algorithm certs.KeyAlgorithm, usages x509.KeyUsage) x509.KeyUsage {
+	if algorithm != "" && algorithm != certs.KeyAlgorithmRSA {
+		return usages &^ x509.KeyUsageKeyEncipherment
+	}
+	return usages
+}
+
+func reconcileSignedCert(secret *corev1.Secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy, cn, org string, usage x509.KeyUsage, extUsages []x509.ExtKeyUsage) error {
+	return reconcileSignedCertWithKeys(secret, ca, ownerRef, rotation, cn, org, usage, extUsages, corev1.TLSCertKey, corev1.TLSPrivateKeyKey, CASignerCertMapKey)
 }
 
-func reconcileSignedCertWithKeys(secret, ca *corev1.Secret, ownerRef config.OwnerRef, cn, org string, usage x509.KeyUsage, extUsages []x509.ExtKeyUsage, crtKey, keyKey, caKey string) error {
-	return reconcileSignedCertWithKeysAndAddresses(secret, ca, ownerRef, cn, org, usage, extUsages, crtKey, keyKey, caKey, nil, nil)
+func reconcileSignedCertWithKeys(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy, cn, org string, usage x509.KeyUsage, extUsages []x509.ExtKeyUsage, crtKey, keyKey, caKey string) error {
+	return reconcileSignedCertWithKeysAndAddresses(secret, ca, ownerRef, rotation, cn, org, usage, extUsages, crtKey, keyKey, caKey, nil, nil)
 }
 
-func reconcileSignedCertWithAddresses(secret, ca *corev1.Secret, ownerRef config.OwnerRef, cn, org string, usage x509.KeyUsage, extUsages []x509.ExtKeyUsage, dnsNames []string, ips []string) error {
-	return reconcileSignedCertWithKeysAndAddresses(secret, ca, ownerRef, cn, org, usage, extUsages, corev1.TLSCertKey, corev1.TLSPrivateKeyKey, CASignerCertMapKey, dnsNames, ips)
+func reconcileSignedCertWithAddresses(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy, cn, org string, usage x509.KeyUsage, extUsages []x509.ExtKeyUsage, dnsNames []string, ips []string) error {
+	return reconcileSignedCertWithKeysAndAddresses(secret, ca, ownerRef, rotation, cn, org, usage, extUsages, corev1.TLSCertKey, corev1.TLSPrivateKeyKey, CASignerCertMapKey, dnsNames, ips)
 }
 
-func reconcileSignedCertWithKeysAndAddresses(secret *corev1.Secret, ca *corev1.Secret, ownerRef config.OwnerRef, cn, org string, usage x509.KeyUsage, extUsages []x509.ExtKeyUsage, crtKey, keyKey, caKey string, dnsNames []string, ips []string) error {
+func reconcileSignedCertWithKeysAndAddresses(secret *corev1.Secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy, cn, org string, usage x509.KeyUsage, extUsages []x509.ExtKeyUsage, crtKey, keyKey, caKey string, dnsNames []string, ips []string) error {
 	ownerRef.ApplyTo(secret)
 	if !ValidCA(ca) {
 		return fmt.Errorf("invalid CA signer secret %s for cert(cn=%s,o=%s)", ca.Name, cn, org)
@@ -41,7 +50,13 @@ func reconcileSignedCertWithKeysAndAddresses(secret *corev1.Secret, ca *corev1.S
 	expectedKeys := []string{crtKey, keyKey, caKey}
 	secret.Type = corev1.SecretTypeOpaque
 	if SignedSecretUpToDate(secret, ca, expectedKeys) {
-		return nil
+		if crt, err := certs.PemToCertificate(secret.Data[crtKey]); err == nil && !rotation.NeedsRenewal(crt) {
+			// The CA bundle changes without a new signer while a renewed CA is
+			// published or a replaced CA is still trusted
+			secret.Data[caKey] = ca.Data[CASignerCertMapKey]
+			rotation.annotateWithExpiry(secret, crt)
+			return nil
+		}
 	}
 	var ipAddresses []net.IP
 	for _, ip := range ips {
@@ -54,11 +69,12 @@ func reconcileSignedCertWithKeysAndAddresses(secret *corev1.Secret, ca *corev1.S
 
 	cfg := &certs.CertCfg{
 		Subject:      pkix.Name{CommonName: cn, Organization: []string{org}},
-		KeyUsages:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		KeyUsages:    keyUsages(rotation.KeyAlgorithm, x509.KeyUsageKeyEncipherment|x509.KeyUsageDigitalSignature),
 		ExtKeyUsages: extUsages,
 		Validity:     certs.ValidityOneYear,
 		DNSNames:     dnsNames,
 		IPAddresses:  ipAddresses,
+		KeyAlgorithm: rotation.KeyAlgorithm,
 	}
 	certBytes, keyBytes, caBytes, err := SignCertificate(cfg, ca)
 	if err != nil {
@@ -71,5 +87,10 @@ func reconcileSignedCertWithKeysAndAddresses(secret *corev1.Secret, ca *corev1.S
 	secret.Data[keyKey] = keyBytes
 	secret.Data[caKey] = caBytes
 	AnnotateWithCA(secret, ca)
+	crt, err := certs.PemToCertificate(certBytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse signed cert(cn=%s,o=%s): %w", cn, org, err)
+	}
+	rotation.annotateWithExpiry(secret, crt)
 	return nil
 }
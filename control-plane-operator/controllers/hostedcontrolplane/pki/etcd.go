@@ -23,11 +23,11 @@ const (
 	EtcdPeerCAKey  = "peer-ca.crt"
 )
 
-func ReconcileEtcdClientSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
-	return reconcileSignedCertWithKeys(secret, ca, ownerRef, "etcd-client", "kubernetes", X509DefaultUsage, X509UsageClientAuth, EtcdClientCrtKey, EtcdClientKeyKey, EtcdClientCAKey)
+func ReconcileEtcdClientSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
+	return reconcileSignedCertWithKeys(secret, ca, ownerRef, rotation, "etcd-client", "kubernetes", X509DefaultUsage, X509UsageClientAuth, EtcdClientCrtKey, EtcdClientKeyKey, EtcdClientCAKey)
 }
 
-func ReconcileEtcdServerSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
+func ReconcileEtcdServerSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
 	dnsNames := []string{
 		fmt.Sprintf("*.etcd.%s.svc", secret.Namespace),
 		fmt.Sprintf("etcd-client.%s.svc", secret.Namespace),
@@ -37,13 +37,13 @@ func ReconcileEtcdServerSecret(secret, ca *corev1.Secret, ownerRef config.OwnerR
 		"etcd-client",
 		"localhost",
 	}
-	return reconcileSignedCertWithKeysAndAddresses(secret, ca, ownerRef, "etcd-server", "kubernetes", X509DefaultUsage, X509UsageClientServerAuth, EtcdServerCrtKey, EtcdServerKeyKey, EtcdServerCAKey, dnsNames, nil)
+	return reconcileSignedCertWithKeysAndAddresses(secret, ca, ownerRef, rotation, "etcd-server", "kubernetes", X509DefaultUsage, X509UsageClientServerAuth, EtcdServerCrtKey, EtcdServerKeyKey, EtcdServerCAKey, dnsNames, nil)
 }
 
-func ReconcileEtcdPeerSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
+func ReconcileEtcdPeerSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
 	dnsNames := []string{
 		fmt.Sprintf("*.etcd.%s.svc", secret.Namespace),
 		fmt.Sprintf("*.etcd.%s.svc.cluster.local", secret.Namespace),
 	}
-	return reconcileSignedCertWithKeysAndAddresses(secret, ca, ownerRef, "etcd-peer", "kubernetes", X509DefaultUsage, X509UsageClientServerAuth, EtcdPeerCrtKey, EtcdPeerKeyKey, EtcdPeerCAKey, dnsNames, nil)
+	return reconcileSignedCertWithKeysAndAddresses(secret, ca, ownerRef, rotation, "etcd-peer", "kubernetes", X509DefaultUsage, X509UsageClientServerAuth, EtcdPeerCrtKey, EtcdPeerKeyKey, EtcdPeerCAKey, dnsNames, nil)
 }
@@ -0,0 +1,118 @@
+package pki
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+const (
+	DefaultExpiryWarningPeriod = 30 * 24 * time.Hour
+
+	CertificateReasonValid    = "CertificatesValid"
+	CertificateReasonExpiring = "CertificatesExpiring"
+	CertificateReasonExpired  = "CertificatesExpired"
+)
+
+// CertificateExpiry is when the earliest expiring certificate stored in a key
+// of a secret expires.
+type CertificateExpiry struct {
+	Secret   string
+	Key      string
+	NotAfter time.Time
+}
+
+// IsManagedSecret returns true if the secret is reconciled by the control
+// plane PKI.
+func IsManagedSecret(secret *corev1.Secret) bool {
+	_, managed := secret.Annotations[CertificateNotAfterAnnotation]
+	return managed
+}
+
+// CertificateExpiries parses the certificates stored in the secret and returns
+// the expiry of every key holding certificates, including CA bundles.
+func CertificateExpiries(secret *corev1.Secret) []CertificateExpiry {
+	var expiries []CertificateExpiry
+	for key, data := range secret.Data {
+		var notAfter time.Time
+		for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			crt, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				continue
+			}
+			if notAfter.IsZero() || crt.NotAfter.Before(notAfter) {
+				notAfter = crt.NotAfter
+			}
+		}
+		if !notAfter.IsZero() {
+			expiries = append(expiries, CertificateExpiry{Secret: secret.Name, Key: key, NotAfter: notAfter})
+		}
+	}
+	sort.Slice(expiries, func(i, j int) bool {
+		return expiries[i].Key < expiries[j].Key
+	})
+	return expiries
+}
+
+// CertificatesExpiringCondition computes the CertificatesExpiring condition
+// from the expiries of the control plane certificates. Certificates are
+// expiring once they are within the expiry warning period of the policy.
+func (p RotationPolicy) CertificatesExpiringCondition(expiries []CertificateExpiry) metav1.Condition {
+	var expired, expiring []CertificateExpiry
+	for _, expiry := range expiries {
+		switch {
+		case !p.Now.Before(expiry.NotAfter):
+			expired = append(expired, expiry)
+		case !p.Now.Add(p.ExpiryWarningPeriod).Before(expiry.NotAfter):
+			expiring = append(expiring, expiry)
+		}
+	}
+	switch {
+	case len(expired) > 0:
+		return metav1.Condition{
+			Type:    string(hyperv1.CertificatesExpiring),
+			Status:  metav1.ConditionTrue,
+			Reason:  CertificateReasonExpired,
+			Message: expiryMessage("expired", expired),
+		}
+	case len(expiring) > 0:
+		return metav1.Condition{
+			Type:    string(hyperv1.CertificatesExpiring),
+			Status:  metav1.ConditionTrue,
+			Reason:  CertificateReasonExpiring,
+			Message: expiryMessage("expires", expiring),
+		}
+	default:
+		return metav1.Condition{
+			Type:    string(hyperv1.CertificatesExpiring),
+			Status:  metav1.ConditionFalse,
+			Reason:  CertificateReasonValid,
+			Message: fmt.Sprintf("No certificates expire within %s", p.ExpiryWarningPeriod),
+		}
+	}
+}
+
+// expiryMessage describes the earliest of the given expiries.
+func expiryMessage(verb string, expiries []CertificateExpiry) string {
+	earliest := expiries[0]
+	for _, expiry := range expiries[1:] {
+		if expiry.NotAfter.Before(earliest.NotAfter) {
+			earliest = expiry
+		}
+	}
+	message := fmt.Sprintf("Certificate %s of secret %s %s at %s", earliest.Key, earliest.Secret, verb, earliest.NotAfter.UTC().Format(time.RFC3339))
+	if len(expiries) > 1 {
+		message += fmt.Sprintf(", along with %d other certificates", len(expiries)-1)
+	}
+	return message
+}
@@ -0,0 +1,78 @@
+package pki
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/support/certs"
+)
+
+func TestCertificateExpiries(t *testing.T) {
+	g := NewGomegaWithT(t)
+	rotation := RotationPolicy{RenewalPercentage: 80, CAOverlap: DefaultCAOverlap, Now: time.Now()}
+
+	ca := testSecret("root-ca")
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, rotation)).To(Succeed())
+	leaf := testSecret("leaf")
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	g.Expect(IsManagedSecret(leaf)).To(BeTrue())
+	g.Expect(IsManagedSecret(testSecret("pull-secret"))).To(BeFalse())
+
+	caCert, err := certs.PemToCertificate(ca.Data[CASignerCertMapKey])
+	g.Expect(err).ToNot(HaveOccurred())
+	leafCert, err := certs.PemToCertificate(leaf.Data[corev1.TLSCertKey])
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Keys without certificates are skipped
+	g.Expect(CertificateExpiries(leaf)).To(Equal([]CertificateExpiry{
+		{Secret: "leaf", Key: CASignerCertMapKey, NotAfter: caCert.NotAfter},
+		{Secret: "leaf", Key: corev1.TLSCertKey, NotAfter: leafCert.NotAfter},
+	}))
+}
+
+func TestCertificatesExpiringCondition(t *testing.T) {
+	now := time.Now()
+	policy := RotationPolicy{ExpiryWarningPeriod: DefaultExpiryWarningPeriod, Now: now}
+	valid := CertificateExpiry{Secret: "valid", Key: "tls.crt", NotAfter: now.Add(365 * 24 * time.Hour)}
+	expiring := CertificateExpiry{Secret: "expiring", Key: "tls.crt", NotAfter: now.Add(24 * time.Hour)}
+	expired := CertificateExpiry{Secret: "expired", Key: "tls.crt", NotAfter: now.Add(-time.Hour)}
+
+	testCases := []struct {
+		name           string
+		expiries       []CertificateExpiry
+		expectedStatus metav1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:           "valid certificates",
+			expiries:       []CertificateExpiry{valid},
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: CertificateReasonValid,
+		},
+		{
+			name:           "certificate within the warning period",
+			expiries:       []CertificateExpiry{valid, expiring},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: CertificateReasonExpiring,
+		},
+		{
+			name:           "expired certificate",
+			expiries:       []CertificateExpiry{expiring, expired, valid},
+			expectedStatus: metav1.ConditionTrue,
+			expectedReason: CertificateReasonExpired,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			condition := policy.CertificatesExpiringCondition(tc.expiries)
+			g.Expect(condition.Status).To(Equal(tc.expectedStatus))
+			g.Expect(condition.Reason).To(Equal(tc.expectedReason))
+		})
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
 )
 
-func ReconcileIngressCert(secret, ca *corev1.Secret, ownerRef config.OwnerRef, externalOAuthAddress, ingressSubdomain string) error {
+func ReconcileIngressCert(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy, externalOAuthAddress, ingressSubdomain string) error {
 	var ingressNumericIPs, ingressHostNames []string
 	if isNumericIP(externalOAuthAddress) {
 		ingressNumericIPs = append(ingressNumericIPs, externalOAuthAddress)
@@ -16,11 +16,17 @@ func ReconcileIngressCert(secret, ca *corev1.Secret, ownerRef config.OwnerRef, e
 		ingressHostNames = append(ingressHostNames, externalOAuthAddress)
 	}
 	ingressHostNames = append(ingressHostNames, fmt.Sprintf("*.%s", ingressSubdomain))
-	return reconcileSignedCertWithAddresses(secret, ca, ownerRef, "openshift-ingress", "openshift", X509DefaultUsage, X509UsageClientServerAuth, ingressHostNames, ingressNumericIPs)
+	return reconcileSignedCertWithAddresses(secret, ca, ownerRef, rotation, "openshift-ingress", "openshift", X509DefaultUsage, X509UsageClientServerAuth, ingressHostNames, ingressNumericIPs)
 }
 
 func (p *PKIParams) ReconcileOAuthServerCert(secret, sourceSecret, ca *corev1.Secret) error {
 	secret.Data = sourceSecret.Data
 	AnnotateWithCA(secret, ca)
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	for _, annotation := range []string{CertificateNotAfterAnnotation, CertificateRenewalTimeAnnotation} {
+		secret.Annotations[annotation] = sourceSecret.Annotations[annotation]
+	}
 	return nil
 }
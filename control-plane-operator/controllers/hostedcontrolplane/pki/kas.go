@@ -16,7 +16,7 @@ const (
 	ServiceSignerPublicKey  = "service-account.pub"
 )
 
-func ReconcileKASServerCertSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, externalAPIAddress, serviceCIDR string) error {
+func ReconcileKASServerCertSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy, externalAPIAddress, serviceCIDR string) error {
 	svc := manifests.KubeAPIServerService(secret.Namespace)
 	_, serviceIPNet, err := net.ParseCIDR(serviceCIDR)
 	if err != nil {
@@ -41,23 +41,23 @@ func ReconcileKASServerCertSecret(secret, ca *corev1.Secret, ownerRef config.Own
 	} else {
 		dnsNames = append(dnsNames, externalAPIAddress)
 	}
-	return reconcileSignedCertWithAddresses(secret, ca, ownerRef, "kubernetes", "kubernetes", X509DefaultUsage, X509UsageServerAuth, dnsNames, apiServerIPs)
+	return reconcileSignedCertWithAddresses(secret, ca, ownerRef, rotation, "kubernetes", "kubernetes", X509DefaultUsage, X509UsageServerAuth, dnsNames, apiServerIPs)
 }
 
-func ReconcileKASKubeletClientCertSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
-	return reconcileSignedCert(secret, ca, ownerRef, "system:kube-apiserver", "kubernetes", X509DefaultUsage, X509UsageClientAuth)
+func ReconcileKASKubeletClientCertSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
+	return reconcileSignedCert(secret, ca, ownerRef, rotation, "system:kube-apiserver", "kubernetes", X509DefaultUsage, X509UsageClientAuth)
 }
 
-func ReconcileKASMachineBootstrapClientCertSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
-	return reconcileSignedCert(secret, ca, ownerRef, "system:bootstrapper", "system:bootstrappers", X509DefaultUsage, X509UsageClientAuth)
+func ReconcileKASMachineBootstrapClientCertSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
+	return reconcileSignedCert(secret, ca, ownerRef, rotation, "system:bootstrapper", "system:bootstrappers", X509DefaultUsage, X509UsageClientAuth)
 }
 
-func ReconcileKASAggregatorCertSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
-	return reconcileSignedCert(secret, ca, ownerRef, "system:openshift-aggregator", "kubernetes", X509DefaultUsage, X509UsageClientServerAuth)
+func ReconcileKASAggregatorCertSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
+	return reconcileSignedCert(secret, ca, ownerRef, rotation, "system:openshift-aggregator", "kubernetes", X509DefaultUsage, X509UsageClientServerAuth)
 }
 
-func ReconcileKASAdminClientCertSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
-	return reconcileSignedCert(secret, ca, ownerRef, "system:admin", "system:masters", X509DefaultUsage, X509UsageClientServerAuth)
+func ReconcileKASAdminClientCertSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
+	return reconcileSignedCert(secret, ca, ownerRef, rotation, "system:admin", "system:masters", X509DefaultUsage, X509UsageClientServerAuth)
 }
 
 func nextIP(ip net.IP) net.IP {
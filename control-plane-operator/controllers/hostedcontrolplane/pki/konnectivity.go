@@ -9,16 +9,16 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
-func ReconcileKonnectivityServerSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
+func ReconcileKonnectivityServerSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
 	dnsNames := []string{
 		"konnectivity-server-local",
 		fmt.Sprintf("konnectivity-server-local.%s.svc", secret.Namespace),
 		fmt.Sprintf("konnectivity-server-local.%s.svc.cluster.local", secret.Namespace),
 	}
-	return reconcileSignedCertWithAddresses(secret, ca, ownerRef, "konnectivity-server-local", "kubernetes", X509DefaultUsage, X509UsageServerAuth, dnsNames, nil)
+	return reconcileSignedCertWithAddresses(secret, ca, ownerRef, rotation, "konnectivity-server-local", "kubernetes", X509DefaultUsage, X509UsageServerAuth, dnsNames, nil)
 }
 
-func ReconcileKonnectivityClusterSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, externalKconnectivityAddress string) error {
+func ReconcileKonnectivityClusterSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy, externalKconnectivityAddress string) error {
 	dnsNames := []string{
 		"konnectivity-server",
 		fmt.Sprintf("konnectivity-server.%s.svc", secret.Namespace),
@@ -30,21 +30,21 @@ func ReconcileKonnectivityClusterSecret(secret, ca *corev1.Secret, ownerRef conf
 	} else {
 		dnsNames = append(dnsNames, externalKconnectivityAddress)
 	}
-	return reconcileSignedCertWithAddresses(secret, ca, ownerRef, "konnectivity-server", "kubernetes", X509DefaultUsage, X509UsageServerAuth, dnsNames, ips)
+	return reconcileSignedCertWithAddresses(secret, ca, ownerRef, rotation, "konnectivity-server", "kubernetes", X509DefaultUsage, X509UsageServerAuth, dnsNames, ips)
 }
 
-func ReconcileKonnectivityClientSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
-	return reconcileSignedCert(secret, ca, ownerRef, "konnectivity-client", "kubernetes", X509DefaultUsage, X509UsageClientAuth)
+func ReconcileKonnectivityClientSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
+	return reconcileSignedCert(secret, ca, ownerRef, rotation, "konnectivity-client", "kubernetes", X509DefaultUsage, X509UsageClientAuth)
 }
 
-func ReconcileKonnectivityAgentSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
-	return reconcileSignedCert(secret, ca, ownerRef, "konnectivity-agent", "kubernetes", X509DefaultUsage, X509UsageClientAuth)
+func ReconcileKonnectivityAgentSecret(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
+	return reconcileSignedCert(secret, ca, ownerRef, rotation, "konnectivity-agent", "kubernetes", X509DefaultUsage, X509UsageClientAuth)
 }
 
-func ReconcileKonnectivityWorkerAgentSecret(cm *corev1.ConfigMap, ca *corev1.Secret, ownerRef config.OwnerRef) error {
+func ReconcileKonnectivityWorkerAgentSecret(cm *corev1.ConfigMap, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
 	ownerRef.ApplyTo(cm)
 	secret := manifests.KonnectivityAgentSecret("kube-system")
-	if err := ReconcileKonnectivityAgentSecret(secret, ca, config.OwnerRef{}); err != nil {
+	if err := ReconcileKonnectivityAgentSecret(secret, ca, config.OwnerRef{}, rotation); err != nil {
 		return err
 	}
 	return util.ReconcileWorkerManifest(cm, secret)
@@ -8,11 +8,11 @@ import (
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
 )
 
-func ReconcileMachineConfigServerCert(secret, ca *corev1.Secret, ownerRef config.OwnerRef) error {
+func ReconcileMachineConfigServerCert(secret, ca *corev1.Secret, ownerRef config.OwnerRef, rotation RotationPolicy) error {
 	hostNames := []string{
 		"machine-config-server",
 		fmt.Sprintf("machine-config-server.%s.svc", secret.Namespace),
 		fmt.Sprintf("machine-config-server.%s.svc.cluster.local", secret.Namespace),
 	}
-	return reconcileSignedCertWithAddresses(secret, ca, ownerRef, "machine-config-server", "openshift", X509DefaultUsage, X509UsageClientServerAuth, hostNames, nil)
+	return reconcileSignedCertWithAddresses(secret, ca, ownerRef, rotation, "machine-config-server", "openshift", X509DefaultUsage, X509UsageClientServerAuth, hostNames, nil)
 }
@@ -0,0 +1,143 @@
+package pki
+
+import (
+	"crypto/x509"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/support/certs"
+)
+
+const (
+	// CertificateNotAfterAnnotation is set on secrets of the control plane PKI
+	// to the expiration time of their certificate.
+	CertificateNotAfterAnnotation = "hypershift.openshift.io/certificate-not-after"
+
+	// CertificateRenewalTimeAnnotation is set on secrets of the control plane
+	// PKI to the time their certificate will be renewed.
+	CertificateRenewalTimeAnnotation = "hypershift.openshift.io/certificate-renewal-time"
+
+	// nextCASigningFromAnnotation is set on a CA secret while a renewed CA is
+	// included in its CA bundle, to the time the renewed CA starts signing
+	// certificates.
+	nextCASigningFromAnnotation = "hypershift.openshift.io/next-ca-signing-from"
+
+	// previousCATrustedUntilAnnotation is set on a CA secret while the CA it
+	// replaced is still included in its CA bundle.
+	previousCATrustedUntilAnnotation = "hypershift.openshift.io/previous-ca-trusted-until"
+
+	DefaultRenewalPercentage = 80
+	DefaultCAOverlap         = 7 * 24 * time.Hour
+)
+
+// RotationPolicy determines when certificates are renewed.
+type RotationPolicy struct {
+	// RenewalPercentage is the percentage of its lifetime after which a
+	// certificate is renewed.
+	RenewalPercentage int32
+
+	// CAOverlap is how long a renewed CA is trusted before it signs
+	// certificates, and how long the CA it replaces remains trusted after.
+	CAOverlap time.Duration
+
+	// ExpiryWarningPeriod is how long before they expire certificates are
+	// reported as expiring.
+	ExpiryWarningPeriod time.Duration
+
+	// KeyAlgorithm is the algorithm of generated keys, RSA if unset.
+	// Certificates with keys of another algorithm are renewed.
+	KeyAlgorithm certs.KeyAlgorithm
+
+	// Now is the time the certificates are evaluated at.
+	Now time.Time
+}
+
+// NewRotationPolicy returns the rotation policy configured on the control
+// plane, evaluated at the given time.
+func NewRotationPolicy(hcp *hyperv1.HostedControlPlane, now time.Time) RotationPolicy {
+	policy := RotationPolicy{
+		RenewalPercentage:   DefaultRenewalPercentage,
+		CAOverlap:           DefaultCAOverlap,
+		ExpiryWarningPeriod: DefaultExpiryWarningPeriod,
+		KeyAlgorithm:        certs.KeyAlgorithm(hcp.Spec.KeyAlgorithm),
+		Now:                 now,
+	}
+	if rotation := hcp.Spec.CertificateRotation; rotation != nil {
+		if rotation.RenewalPercentage > 0 {
+			policy.RenewalPercentage = rotation.RenewalPercentage
+		}
+		if rotation.CAOverlapDuration != nil {
+			policy.CAOverlap = rotation.CAOverlapDuration.Duration
+		}
+		if rotation.ExpiryWarningPeriod != nil {
+			policy.ExpiryWarningPeriod = rotation.ExpiryWarningPeriod.Duration
+		}
+	}
+	return policy
+}
+
+// RenewalTime returns when the certificate should be renewed.
+func (p RotationPolicy) RenewalTime(crt *x509.Certificate) time.Time {
+	lifetime := crt.NotAfter.Sub(crt.NotBefore)
+	return crt.NotBefore.Add(lifetime / 100 * time.Duration(p.RenewalPercentage))
+}
+
+// NeedsRenewal returns true if the certificate has reached its renewal time or
+// its key is not of the algorithm of the policy. CAs are renewed through the
+// same overlap in both cases, so an algorithm change doesn't break clients
+// which haven't picked up the renewed CAs yet.
+func (p RotationPolicy) NeedsRenewal(crt *x509.Certificate) bool {
+	expected := p.KeyAlgorithm
+	if expected == "" {
+		expected = certs.KeyAlgorithmRSA
+	}
+	if algorithm, err := certs.PublicKeyAlgorithm(crt.PublicKey); err != nil || algorithm != expected {
+		return true
+	}
+	return !p.Now.Before(p.RenewalTime(crt))
+}
+
+func (p RotationPolicy) annotateWithExpiry(secret *corev1.Secret, crt *x509.Certificate) {
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[CertificateNotAfterAnnotation] = crt.NotAfter.UTC().Format(time.RFC3339)
+	secret.Annotations[CertificateRenewalTimeAnnotation] = p.RenewalTime(crt).UTC().Format(time.RFC3339)
+}
+
+// CertificateStatus returns the expiry of the certificate in a secret of the
+// control plane PKI, or false if the secret holds no certificate.
+func CertificateStatus(secret *corev1.Secret) (hyperv1.CertificateStatus, bool) {
+	notAfter, err := time.Parse(time.RFC3339, secret.Annotations[CertificateNotAfterAnnotation])
+	if err != nil {
+		return hyperv1.CertificateStatus{}, false
+	}
+	renewalTime, err := time.Parse(time.RFC3339, secret.Annotations[CertificateRenewalTimeAnnotation])
+	if err != nil {
+		return hyperv1.CertificateStatus{}, false
+	}
+	return hyperv1.CertificateStatus{
+		Secret:      secret.Name,
+		NotAfter:    metav1.NewTime(notAfter),
+		RenewalTime: metav1.NewTime(renewalTime),
+	}, true
+}
+
+// NextRotation returns the next time the secret needs to be reconciled to
+// renew its certificate, sign with a renewed CA, or stop trusting a replaced
+// CA or signing key.
+func NextRotation(secret *corev1.Secret) (time.Time, bool) {
+	var next time.Time
+	if status, ok := CertificateStatus(secret); ok {
+		next = status.RenewalTime.Time
+	}
+	for _, annotation := range []string{nextCASigningFromAnnotation, previousCATrustedUntilAnnotation, previousSigningKeyTrustedUntilAnnotation} {
+		if trustedUntil, err := time.Parse(time.RFC3339, secret.Annotations[annotation]); err == nil && (next.IsZero() || trustedUntil.Before(next)) {
+			next = trustedUntil
+		}
+	}
+	return next, !next.IsZero()
+}
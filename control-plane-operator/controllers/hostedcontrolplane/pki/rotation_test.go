@@ -0,0 +1,175 @@
+package pki
+
+import (
+	"bytes"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/support/certs"
+)
+
+func testSecret(name string) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"}}
+}
+
+func reconcileTestCert(secret, ca *corev1.Secret, rotation RotationPolicy) error {
+	return reconcileSignedCert(secret, ca, config.OwnerRef{}, rotation, "test", "test", X509DefaultUsage, X509UsageClientAuth)
+}
+
+func TestLeafCertificateRenewal(t *testing.T) {
+	g := NewGomegaWithT(t)
+	rotation := RotationPolicy{RenewalPercentage: 80, CAOverlap: DefaultCAOverlap, Now: time.Now()}
+
+	ca := testSecret("root-ca")
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, rotation)).To(Succeed())
+	leaf := testSecret("leaf")
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	original := leaf.Data[corev1.TLSCertKey]
+	status, ok := CertificateStatus(leaf)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(status.Secret).To(Equal("leaf"))
+
+	// Before the renewal time the certificate is kept
+	rotation.Now = status.RenewalTime.Add(-time.Hour)
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	g.Expect(leaf.Data[corev1.TLSCertKey]).To(Equal(original))
+
+	// After the renewal time the certificate is signed again
+	rotation.Now = status.RenewalTime.Add(time.Hour)
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	g.Expect(leaf.Data[corev1.TLSCertKey]).ToNot(Equal(original))
+}
+
+func TestCARotation(t *testing.T) {
+	g := NewGomegaWithT(t)
+	rotation := RotationPolicy{RenewalPercentage: 80, CAOverlap: DefaultCAOverlap, Now: time.Now()}
+
+	ca := testSecret("root-ca")
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, rotation)).To(Succeed())
+	oldCA := ca.Data[CASignerCertMapKey]
+	leaf := testSecret("leaf")
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	oldLeaf := leaf.Data[corev1.TLSCertKey]
+
+	// A renewed CA is first only published in the CA bundle, the current CA
+	// keeps signing certificates
+	status, _ := CertificateStatus(ca)
+	renewal := rotation
+	renewal.Now = status.RenewalTime.Add(time.Hour)
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, renewal)).To(Succeed())
+	g.Expect(bytes.HasPrefix(ca.Data[CASignerCertMapKey], oldCA)).To(BeTrue())
+	g.Expect(bytes.Equal(ca.Data[CASignerCertMapKey], oldCA)).To(BeFalse())
+	g.Expect(ca.Annotations).To(HaveKeyWithValue(nextCASigningFromAnnotation, renewal.Now.Add(renewal.CAOverlap).UTC().Format(time.RFC3339)))
+	next, _ := NextRotation(ca)
+	g.Expect(next).To(Equal(renewal.Now.Add(renewal.CAOverlap).Truncate(time.Second).UTC()))
+	newCA := ca.Data[nextCASignerCertMapKey]
+
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	g.Expect(leaf.Data[corev1.TLSCertKey]).To(Equal(oldLeaf))
+	g.Expect(leaf.Data[CASignerCertMapKey]).To(Equal(ca.Data[CASignerCertMapKey]))
+
+	// Before the overlap has passed the renewed CA is not used
+	renewal.Now = renewal.Now.Add(renewal.CAOverlap - time.Hour)
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, renewal)).To(Succeed())
+	g.Expect(ca.Data).To(HaveKey(nextCASignerCertMapKey))
+
+	// Once the overlap has passed the renewed CA signs new certificates, while
+	// the replaced CA remains trusted
+	renewal.Now = renewal.Now.Add(2 * time.Hour)
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, renewal)).To(Succeed())
+	g.Expect(ca.Data[CASignerCertMapKey]).To(Equal(append(append([]byte{}, newCA...), oldCA...)))
+	g.Expect(ca.Data).ToNot(HaveKey(nextCASignerCertMapKey))
+	g.Expect(ca.Data).ToNot(HaveKey(nextCASignerKeyMapKey))
+	g.Expect(ca.Annotations).ToNot(HaveKey(nextCASigningFromAnnotation))
+	g.Expect(ca.Annotations).To(HaveKeyWithValue(previousCATrustedUntilAnnotation, renewal.Now.Add(renewal.CAOverlap).UTC().Format(time.RFC3339)))
+
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	g.Expect(leaf.Data[corev1.TLSCertKey]).ToNot(Equal(oldLeaf))
+	g.Expect(leaf.Data[CASignerCertMapKey]).To(Equal(ca.Data[CASignerCertMapKey]))
+	newLeaf := leaf.Data[corev1.TLSCertKey]
+
+	// Once the second overlap has passed, the replaced CA is no longer trusted
+	// and certificates of the renewed CA are kept
+	ca.Annotations[previousCATrustedUntilAnnotation] = rotation.Now.Add(-time.Hour).UTC().Format(time.RFC3339)
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, rotation)).To(Succeed())
+	g.Expect(ca.Data[CASignerCertMapKey]).To(Equal(newCA))
+	g.Expect(ca.Annotations).ToNot(HaveKey(previousCATrustedUntilAnnotation))
+
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	g.Expect(leaf.Data[corev1.TLSCertKey]).To(Equal(newLeaf))
+	g.Expect(leaf.Data[CASignerCertMapKey]).To(Equal(ca.Data[CASignerCertMapKey]))
+}
+
+func TestKeyAlgorithmChange(t *testing.T) {
+	g := NewGomegaWithT(t)
+	rotation := RotationPolicy{RenewalPercentage: 80, CAOverlap: DefaultCAOverlap, Now: time.Now()}
+
+	ca := testSecret("root-ca")
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, rotation)).To(Succeed())
+	leaf := testSecret("leaf")
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+
+	// Changing the algorithm renews the CA through the same overlap as any
+	// other renewal, and renews the certificates it signed once the renewed
+	// CA signs
+	rotation.KeyAlgorithm = certs.KeyAlgorithmECDSAP256
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, rotation)).To(Succeed())
+	expectKeyAlgorithm(g, ca.Data[CASignerCertMapKey], certs.KeyAlgorithmRSA)
+	expectKeyAlgorithm(g, ca.Data[nextCASignerCertMapKey], certs.KeyAlgorithmECDSAP256)
+
+	ca.Annotations[nextCASigningFromAnnotation] = rotation.Now.Add(-time.Hour).UTC().Format(time.RFC3339)
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, rotation)).To(Succeed())
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	expectKeyAlgorithm(g, ca.Data[CASignerCertMapKey], certs.KeyAlgorithmECDSAP256)
+	expectKeyAlgorithm(g, leaf.Data[corev1.TLSCertKey], certs.KeyAlgorithmECDSAP256)
+	_, err := certs.PemToSigner(leaf.Data[corev1.TLSPrivateKeyKey])
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func expectKeyAlgorithm(g *WithT, crtPEM []byte, algorithm certs.KeyAlgorithm) {
+	crt, err := certs.PemToCertificate(crtPEM)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(certs.PublicKeyAlgorithm(crt.PublicKey)).To(Equal(algorithm))
+	g.Expect(crt.KeyUsage&x509.KeyUsageKeyEncipherment != 0).To(Equal(algorithm == certs.KeyAlgorithmRSA))
+}
+
+func TestKeyAlgorithmChangeKeepsCertificatesTrusted(t *testing.T) {
+	g := NewGomegaWithT(t)
+	rotation := RotationPolicy{RenewalPercentage: 80, CAOverlap: DefaultCAOverlap, Now: time.Now()}
+
+	ca := testSecret("root-ca")
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, rotation)).To(Succeed())
+	trusted := ca.Data[CASignerCertMapKey]
+
+	// Clients which only trust the CA bundle from before the change keep
+	// trusting the certificates signed while the renewed CA is published
+	rotation.KeyAlgorithm = certs.KeyAlgorithmECDSAP384
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, rotation)).To(Succeed())
+	leaf := testSecret("leaf")
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	expectTrusted(g, leaf.Data[corev1.TLSCertKey], trusted)
+	expectTrusted(g, leaf.Data[corev1.TLSCertKey], leaf.Data[CASignerCertMapKey])
+
+	// Certificates signed by the renewed CA are trusted by the bundle
+	// published in the previous step
+	published := ca.Data[CASignerCertMapKey]
+	ca.Annotations[nextCASigningFromAnnotation] = rotation.Now.Add(-time.Hour).UTC().Format(time.RFC3339)
+	g.Expect(ReconcileRootCA(ca, config.OwnerRef{}, rotation)).To(Succeed())
+	g.Expect(reconcileTestCert(leaf, ca, rotation)).To(Succeed())
+	expectTrusted(g, leaf.Data[corev1.TLSCertKey], published)
+}
+
+func expectTrusted(g *WithT, crtPEM, bundle []byte) {
+	crt, err := certs.PemToCertificate(crtPEM)
+	g.Expect(err).ToNot(HaveOccurred())
+	roots := x509.NewCertPool()
+	g.Expect(roots.AppendCertsFromPEM(bundle)).To(BeTrue())
+	_, err = crt.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+	g.Expect(err).ToNot(HaveOccurred())
+}
@@ -1,38 +1,86 @@
 package pki
 
 import (
+	"crypto/md5"
 	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
 	"fmt"
+	"time"
 
+	jose "gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
 	corev1 "k8s.io/api/core/v1"
 
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
 	"github.com/openshift/hypershift/support/certs"
 )
 
-func ReconcileServiceAccountSigningKeySecret(secret, signingKey *corev1.Secret, ownerRef config.OwnerRef) error {
+const (
+	// ServiceAccountSigningKeyHashAnnotation is set on the pods of components
+	// using the service account signing key, so that they are rolled out when
+	// the key is rotated.
+	ServiceAccountSigningKeyHashAnnotation = "hypershift.openshift.io/service-account-signing-key-hash"
+
+	// previousSigningKeyTrustedUntilAnnotation is set on the service account
+	// signing key secret while the public keys of replaced signing keys are
+	// still trusted.
+	previousSigningKeyTrustedUntilAnnotation = "hypershift.openshift.io/previous-signing-key-trusted-until"
+
+	DefaultSigningKeyOverlap = 24 * time.Hour
+)
+
+// SigningKeyOverlap returns how long replaced service account signing keys
+// remain trusted.
+func SigningKeyOverlap(hcp *hyperv1.HostedControlPlane) time.Duration {
+	if hcp.Spec.SigningKeyOverlapDuration != nil {
+		return hcp.Spec.SigningKeyOverlapDuration.Duration
+	}
+	return DefaultSigningKeyOverlap
+}
+
+// ReconcileServiceAccountSigningKeySecret reconciles the key service account
+// tokens are signed with, taken from the provided signing key or generated.
+// When the provided signing key changes, tokens are signed with the new key
+// right away, while the public keys of the replaced keys remain trusted until
+// the overlap has passed.
+func ReconcileServiceAccountSigningKeySecret(secret, signingKey *corev1.Secret, ownerRef config.OwnerRef, overlap time.Duration, now time.Time) error {
 	ownerRef.ApplyTo(secret)
 	secret.Type = corev1.SecretTypeOpaque
-	expectedKeys := []string{ServiceSignerPrivateKey, ServiceSignerPublicKey}
-	if !SecretUpToDate(secret, expectedKeys) {
-		var key *rsa.PrivateKey
-		var err error
-		if signingKey != nil {
-			signingKeySecretData, hasSigningKeySecretData := signingKey.Data["key"]
-			if !hasSigningKeySecretData {
-				return fmt.Errorf("signing key secret %s is missing the key key", signingKey.Name)
-			}
-			key, err = certs.PemToPrivateKey(signingKeySecretData)
-			if err != nil {
-				return fmt.Errorf("failed to PEM decode private key %s: %w", signingKey.Name, err)
-			}
-		} else {
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	var current *rsa.PrivateKey
+	if SecretUpToDate(secret, []string{ServiceSignerPrivateKey, ServiceSignerPublicKey}) {
+		if key, err := certs.PemToPrivateKey(secret.Data[ServiceSignerPrivateKey]); err == nil {
+			current = key
+		}
+	}
+	var desired *rsa.PrivateKey
+	if signingKey != nil {
+		signingKeySecretData, hasSigningKeySecretData := signingKey.Data["key"]
+		if !hasSigningKeySecretData {
+			return fmt.Errorf("signing key secret %s is missing the key key", signingKey.Name)
+		}
+		key, err := certs.PemToPrivateKey(signingKeySecretData)
+		if err != nil {
+			return fmt.Errorf("failed to PEM decode private key %s: %w", signingKey.Name, err)
+		}
+		desired = key
+	}
+
+	switch {
+	case current == nil:
+		key := desired
+		if key == nil {
+			var err error
 			key, err = certs.PrivateKey()
 			if err != nil {
 				return fmt.Errorf("failed generating a private key: %w", err)
 			}
 		}
-		keyBytes := certs.PrivateKeyToPem(key)
 		publicKeyBytes, err := certs.PublicKeyToPem(&key.PublicKey)
 		if err != nil {
 			return fmt.Errorf("failed to generate public key from private key: %w", err)
@@ -40,8 +88,100 @@ func ReconcileServiceAccountSigningKeySecret(secret, signingKey *corev1.Secret,
 		if secret.Data == nil {
 			secret.Data = map[string][]byte{}
 		}
-		secret.Data[ServiceSignerPrivateKey] = keyBytes
+		secret.Data[ServiceSignerPrivateKey] = certs.PrivateKeyToPem(key)
 		secret.Data[ServiceSignerPublicKey] = publicKeyBytes
+		delete(secret.Annotations, previousSigningKeyTrustedUntilAnnotation)
+	case desired != nil && !desired.Equal(current):
+		// Tokens signed by the replaced keys are verified with the public keys
+		// which are already trusted
+		publicKeyBytes, err := certs.PublicKeyToPem(&desired.PublicKey)
+		if err != nil {
+			return fmt.Errorf("failed to generate public key from private key: %w", err)
+		}
+		secret.Data[ServiceSignerPrivateKey] = certs.PrivateKeyToPem(desired)
+		secret.Data[ServiceSignerPublicKey] = append(publicKeyBytes, secret.Data[ServiceSignerPublicKey]...)
+		secret.Annotations[previousSigningKeyTrustedUntilAnnotation] = now.Add(overlap).UTC().Format(time.RFC3339)
+	default:
+		trustedUntil, hasPrevious := secret.Annotations[previousSigningKeyTrustedUntilAnnotation]
+		if !hasPrevious {
+			return nil
+		}
+		if until, err := time.Parse(time.RFC3339, trustedUntil); err == nil && now.Before(until) {
+			return nil
+		}
+		publicKeyBytes, err := certs.PublicKeyToPem(&current.PublicKey)
+		if err != nil {
+			return fmt.Errorf("failed to generate public key from private key: %w", err)
+		}
+		secret.Data[ServiceSignerPublicKey] = publicKeyBytes
+		delete(secret.Annotations, previousSigningKeyTrustedUntilAnnotation)
 	}
 	return nil
 }
+
+// ServiceAccountSigningKeyHash returns a hash of the signing key and the
+// trusted public keys in the service account signing key secret.
+func ServiceAccountSigningKeyHash(secret *corev1.Secret) string {
+	return fmt.Sprintf("%x", md5.Sum(append(append([]byte{}, secret.Data[ServiceSignerPrivateKey]...), secret.Data[ServiceSignerPublicKey]...)))
+}
+
+// ServiceAccountToken returns a token for the given service account of the
+// hosted cluster, signed with the key in the service account signing key
+// secret. It is meant for control plane components which authenticate to
+// systems trusting the cluster's service account issuer before the kube
+// apiserver is available to issue tokens.
+func ServiceAccountToken(signingKeySecret *corev1.Secret, issuer, namespace, name, audience string, issuedAt, expiration time.Time) (string, error) {
+	key, err := certs.PemToPrivateKey(signingKeySecret.Data[ServiceSignerPrivateKey])
+	if err != nil {
+		return "", fmt.Errorf("failed to PEM decode service account signing key: %w", err)
+	}
+	keyID, err := serviceAccountKeyID(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{
+		Algorithm: jose.RS256,
+		Key:       jose.JSONWebKey{Key: key, KeyID: keyID},
+	}, (&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token signer: %w", err)
+	}
+	claims := jwt.Claims{
+		Issuer:    issuer,
+		Subject:   fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name),
+		Audience:  jwt.Audience{audience},
+		IssuedAt:  jwt.NewNumericDate(issuedAt),
+		NotBefore: jwt.NewNumericDate(issuedAt),
+		Expiry:    jwt.NewNumericDate(expiration),
+	}
+	privateClaims := serviceAccountTokenClaims{}
+	privateClaims.Kubernetes.Namespace = namespace
+	privateClaims.Kubernetes.ServiceAccount.Name = name
+	token, err := jwt.Signed(signer).Claims(claims).Claims(privateClaims).CompactSerialize()
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return token, nil
+}
+
+// serviceAccountTokenClaims are the claims the kube apiserver adds to the
+// tokens of service accounts.
+type serviceAccountTokenClaims struct {
+	Kubernetes struct {
+		Namespace      string `json:"namespace"`
+		ServiceAccount struct {
+			Name string `json:"name"`
+		} `json:"serviceaccount"`
+	} `json:"kubernetes.io"`
+}
+
+// serviceAccountKeyID derives the key ID the kube apiserver publishes for the
+// key in its JWKS document, so that tokens can be verified with it.
+func serviceAccountKeyID(publicKey *rsa.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize service account public key: %w", err)
+	}
+	hash := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
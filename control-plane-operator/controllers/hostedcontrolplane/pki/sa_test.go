@@ -0,0 +1,95 @@
+package pki
+
+import (
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"gopkg.in/square/go-jose.v2/jwt"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/util/keyutil"
+
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/config"
+	"github.com/openshift/hypershift/support/certs"
+)
+
+func signingKeySource(t *testing.T) (*corev1.Secret, *rsa.PrivateKey) {
+	key, err := certs.PrivateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return &corev1.Secret{Data: map[string][]byte{"key": certs.PrivateKeyToPem(key)}}, key
+}
+
+func trustedPublicKeys(g *WithT, secret *corev1.Secret) []*rsa.PublicKey {
+	keys, err := keyutil.ParsePublicKeysPEM(secret.Data[ServiceSignerPublicKey])
+	g.Expect(err).ToNot(HaveOccurred())
+	var publicKeys []*rsa.PublicKey
+	for _, key := range keys {
+		publicKeys = append(publicKeys, key.(*rsa.PublicKey))
+	}
+	return publicKeys
+}
+
+func TestServiceAccountSigningKeyRotation(t *testing.T) {
+	g := NewGomegaWithT(t)
+	now := time.Now()
+	secret := testSecret("sa-signing-key")
+
+	oldSource, oldKey := signingKeySource(t)
+	g.Expect(ReconcileServiceAccountSigningKeySecret(secret, oldSource, config.OwnerRef{}, DefaultSigningKeyOverlap, now)).To(Succeed())
+	g.Expect(trustedPublicKeys(g, secret)).To(Equal([]*rsa.PublicKey{&oldKey.PublicKey}))
+	oldHash := ServiceAccountSigningKeyHash(secret)
+
+	// A new key signs tokens right away while the old key remains trusted
+	newSource, newKey := signingKeySource(t)
+	g.Expect(ReconcileServiceAccountSigningKeySecret(secret, newSource, config.OwnerRef{}, DefaultSigningKeyOverlap, now)).To(Succeed())
+	g.Expect(secret.Data[ServiceSignerPrivateKey]).To(Equal(certs.PrivateKeyToPem(newKey)))
+	g.Expect(trustedPublicKeys(g, secret)).To(Equal([]*rsa.PublicKey{&newKey.PublicKey, &oldKey.PublicKey}))
+	g.Expect(ServiceAccountSigningKeyHash(secret)).ToNot(Equal(oldHash))
+	next, ok := NextRotation(secret)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(next).To(Equal(now.Add(DefaultSigningKeyOverlap).UTC().Truncate(time.Second)))
+
+	// The old key is trusted until the overlap has passed
+	g.Expect(ReconcileServiceAccountSigningKeySecret(secret, newSource, config.OwnerRef{}, DefaultSigningKeyOverlap, now.Add(time.Hour))).To(Succeed())
+	g.Expect(trustedPublicKeys(g, secret)).To(HaveLen(2))
+
+	g.Expect(ReconcileServiceAccountSigningKeySecret(secret, newSource, config.OwnerRef{}, DefaultSigningKeyOverlap, now.Add(DefaultSigningKeyOverlap+time.Hour))).To(Succeed())
+	g.Expect(trustedPublicKeys(g, secret)).To(Equal([]*rsa.PublicKey{&newKey.PublicKey}))
+	_, ok = NextRotation(secret)
+	g.Expect(ok).To(BeFalse())
+}
+
+func TestGeneratedServiceAccountSigningKey(t *testing.T) {
+	g := NewGomegaWithT(t)
+	secret := testSecret("sa-signing-key")
+	g.Expect(ReconcileServiceAccountSigningKeySecret(secret, nil, config.OwnerRef{}, DefaultSigningKeyOverlap, time.Now())).To(Succeed())
+	key := secret.Data[ServiceSignerPrivateKey]
+	g.Expect(key).ToNot(BeEmpty())
+
+	// A generated key is kept
+	g.Expect(ReconcileServiceAccountSigningKeySecret(secret, nil, config.OwnerRef{}, DefaultSigningKeyOverlap, time.Now())).To(Succeed())
+	g.Expect(secret.Data[ServiceSignerPrivateKey]).To(Equal(key))
+	g.Expect(trustedPublicKeys(g, secret)).To(HaveLen(1))
+}
+
+func TestServiceAccountToken(t *testing.T) {
+	g := NewGomegaWithT(t)
+	secret := testSecret("sa-signing-key")
+	now := time.Now().Truncate(time.Second)
+	g.Expect(ReconcileServiceAccountSigningKeySecret(secret, nil, config.OwnerRef{}, DefaultSigningKeyOverlap, now)).To(Succeed())
+	token, err := ServiceAccountToken(secret, "https://issuer", "kube-system", "kms", "sts.amazonaws.com", now, now.Add(time.Hour))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	parsed, err := jwt.ParseSigned(token)
+	g.Expect(err).ToNot(HaveOccurred())
+	keyID, err := serviceAccountKeyID(trustedPublicKeys(g, secret)[0])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(parsed.Headers[0].KeyID).To(Equal(keyID))
+	claims, privateClaims := jwt.Claims{}, serviceAccountTokenClaims{}
+	g.Expect(parsed.Claims(trustedPublicKeys(g, secret)[0], &claims, &privateClaims)).To(Succeed())
+	g.Expect(claims.Validate(jwt.Expected{Issuer: "https://issuer", Subject: "system:serviceaccount:kube-system:kms", Audience: jwt.Audience{"sts.amazonaws.com"}, Time: now})).To(Succeed())
+	g.Expect(privateClaims.Kubernetes.ServiceAccount.Name).To(Equal("kms"))
+}
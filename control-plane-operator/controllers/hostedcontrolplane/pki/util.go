@@ -1,9 +1,10 @@
 package pki
 
 import (
+	"crypto"
 	"crypto/md5"
-	"crypto/rsa"
 	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -36,6 +37,9 @@ func SignedSecretUpToDate(secret, ca *corev1.Secret, keys []string) bool {
 	return SecretUpToDate(secret, keys) && hasCAHash(secret, ca)
 }
 
+// SignCertificate signs a certificate with the CA of the secret. The returned CA
+// bytes are the CA bundle of the secret, which includes renewed CAs which are
+// still trusted.
 func SignCertificate(cfg *certs.CertCfg, ca *corev1.Secret) (crtBytes []byte, keyBytes []byte, caBytes []byte, err error) {
 	caCert, caKey, err := decodeCA(ca)
 	if err != nil {
@@ -45,7 +49,11 @@ func SignCertificate(cfg *certs.CertCfg, ca *corev1.Secret) (crtBytes []byte, ke
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to generate etcd client secret: %w", err)
 	}
-	return certs.CertToPem(crt), certs.PrivateKeyToPem(key), certs.CertToPem(caCert), nil
+	keyBytes, err = certs.SignerToPem(key)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	return certs.CertToPem(crt), keyBytes, ca.Data[CASignerCertMapKey], nil
 }
 
 func hasCAHash(secret *corev1.Secret, ca *corev1.Secret) bool {
@@ -60,16 +68,28 @@ func hasCAHash(secret *corev1.Secret, ca *corev1.Secret) bool {
 	return desiredHash == actualHash
 }
 
+// computeCAHash hashes the signer of the CA secret, so that certificates are
+// only signed again when the signer changes rather than its CA bundle.
 func computeCAHash(ca *corev1.Secret) string {
-	return fmt.Sprintf("%x", md5.Sum(append(ca.Data[CASignerCertMapKey], ca.Data[CASignerKeyMapKey]...)))
+	return fmt.Sprintf("%x", md5.Sum(append(signerCertPEM(ca.Data[CASignerCertMapKey]), ca.Data[CASignerKeyMapKey]...)))
+}
+
+// signerCertPEM returns the first certificate of a CA bundle, which is the
+// certificate of the signer.
+func signerCertPEM(bundle []byte) []byte {
+	block, _ := pem.Decode(bundle)
+	if block == nil {
+		return bundle
+	}
+	return pem.EncodeToMemory(block)
 }
 
-func decodeCA(ca *corev1.Secret) (*x509.Certificate, *rsa.PrivateKey, error) {
+func decodeCA(ca *corev1.Secret) (*x509.Certificate, crypto.Signer, error) {
 	crt, err := certs.PemToCertificate(ca.Data[CASignerCertMapKey])
 	if err != nil {
 		return nil, nil, err
 	}
-	key, err := certs.PemToPrivateKey(ca.Data[CASignerKeyMapKey])
+	key, err := certs.PemToSigner(ca.Data[CASignerKeyMapKey])
 	if err != nil {
 		return nil, nil, err
 	}
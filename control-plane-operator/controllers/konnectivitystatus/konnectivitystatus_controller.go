@@ -0,0 +1,99 @@
+package konnectivitystatus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/konnectivity"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+)
+
+const (
+	// statusInterval is how often the agents connected to the konnectivity
+	// servers are counted.
+	statusInterval = time.Minute
+
+	// metricsTimeout bounds reading the metrics of a konnectivity server.
+	metricsTimeout = 5 * time.Second
+)
+
+// KonnectivityStatusReconciler periodically counts the agents connected to
+// each konnectivity server and reports them in the KonnectivityAvailable
+// condition of the HostedControlPlane.
+type KonnectivityStatusReconciler struct {
+	client.Client
+
+	Log logr.Logger
+}
+
+func (r *KonnectivityStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Status updates made by this controller must not retrigger it.
+	_, err := ctrl.NewControllerManagedBy(mgr).
+		Named("konnectivity-status").
+		For(&hyperv1.HostedControlPlane{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Build(r)
+	if err != nil {
+		return fmt.Errorf("failed setting up with a controller manager %w", err)
+	}
+	return nil
+}
+
+func (r *KonnectivityStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r.Log = ctrl.LoggerFrom(ctx)
+
+	hcp := &hyperv1.HostedControlPlane{}
+	if err := r.Get(ctx, req.NamespacedName, hcp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if !hcp.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	deployment := manifests.KonnectivityServerDeployment(hcp.Namespace)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(deployment), deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{RequeueAfter: statusInterval}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get konnectivity server deployment: %w", err)
+	}
+	serverCount := 1
+	if deployment.Spec.Replicas != nil {
+		serverCount = int(*deployment.Spec.Replicas)
+	}
+
+	pods, err := konnectivity.ListServerPods(ctx, r, hcp.Namespace)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	connections := konnectivity.ServerAgentConnections(ctx, &http.Client{Timeout: metricsTimeout}, pods)
+	for _, c := range connections {
+		if c.Err != nil {
+			r.Log.Error(c.Err, "failed to read konnectivity server metrics", "server", c.Server)
+		}
+	}
+
+	condition := konnectivity.ComputeKonnectivityAvailableCondition(serverCount, connections)
+	condition.ObservedGeneration = hcp.Generation
+	meta.SetStatusCondition(&hcp.Status.Conditions, condition)
+	if err := r.Status().Update(ctx, hcp); err != nil {
+		if apierrors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to update status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: statusInterval}, nil
+}
@@ -16,10 +16,13 @@ require (
 	github.com/openshift/hypershift/api v0.0.0-00010101000000-000000000000
 	github.com/openshift/hypershift/support v0.0.0-00010101000000-000000000000
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.7.1
+	github.com/prometheus/common v0.10.0
 	github.com/spf13/cobra v1.1.1
 	github.com/vincent-petithory/dataurl v0.0.0-20191104211930-d1553a71de50
 	golang.org/x/crypto v0.0.0-20201002170205-7f63de1d35b0
 	gopkg.in/ini.v1 v1.51.0
+	gopkg.in/square/go-jose.v2 v2.3.1
 	k8s.io/api v0.20.2
 	k8s.io/apiextensions-apiserver v0.20.2
 	k8s.io/apimachinery v0.20.2
@@ -30,4 +33,5 @@ require (
 	k8s.io/kube-scheduler v0.20.2
 	k8s.io/utils v0.0.0-20210111153108-fddb29f9d009
 	sigs.k8s.io/controller-runtime v0.8.2
+	sigs.k8s.io/yaml v1.2.0
 )
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/openshift/hypershift/control-plane-operator/controllers/etcdmaintenance"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedapicache"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane/manifests"
+	"github.com/openshift/hypershift/control-plane-operator/controllers/konnectivitystatus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -18,9 +20,11 @@ import (
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 	hyperapi "github.com/openshift/hypershift/control-plane-operator/api"
 	"github.com/openshift/hypershift/control-plane-operator/controllers/hostedcontrolplane"
 	"github.com/openshift/hypershift/support/releaseinfo"
+	"github.com/openshift/hypershift/support/thirdparty/library-go/pkg/image/reference"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
@@ -48,13 +52,10 @@ func main() {
 	}
 }
 
-const (
-	// FIXME: Set to upstream image when DNS resolution is fixed for etcd service
-	etcdOperatorImage = "quay.io/hypershift/etcd-operator:v0.9.4-patched"
-	// TODO: Include konnectivity image in release payload
-	konnectivityServerImage = "registry.ci.openshift.org/hypershift/apiserver-network-proxy:latest"
-	konnectivityAgentImage  = "registry.ci.openshift.org/hypershift/apiserver-network-proxy:latest"
-)
+// etcdOperatorImage runs managed etcd clusters created before managed etcd
+// moved to a StatefulSet.
+// FIXME: Set to upstream image when DNS resolution is fixed for etcd service
+const etcdOperatorImage = "quay.io/hypershift/etcd-operator:v0.9.4-patched"
 
 func NewStartCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -67,6 +68,7 @@ func NewStartCommand() *cobra.Command {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var hostedClusterConfigOperatorImage string
+	var konnectivityImage string
 	var inCluster bool
 
 	cmd.Flags().StringVar(&namespace, "namespace", "", "The namespace this operator lives in (required)")
@@ -75,6 +77,7 @@ func NewStartCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	cmd.Flags().StringVar(&konnectivityImage, "konnectivity-image", hyperv1.DefaultKonnectivityImage, "The konnectivity image used when the release image does not include one. It must be pinned by digest.")
 	cmd.Flags().StringVar(&hostedClusterConfigOperatorImage, "hosted-cluster-config-operator-image", "", "A specific operator image. (defaults to match this operator if running in a deployment)")
 	cmd.Flags().BoolVar(&inCluster, "in-cluster", true, "If false, the operator will be assumed to be running outside a kube "+
 		"cluster and will make some internal decisions to ease local development (e.g. using external endpoints where possible"+
@@ -148,9 +151,8 @@ func NewStartCommand() *cobra.Command {
 			},
 			ComponentImages: map[string]string{
 				"hosted-cluster-config-operator": hostedClusterConfigOperatorImage,
+				"hypershift":                     hostedClusterConfigOperatorImage,
 				"etcd-operator":                  etcdOperatorImage,
-				"konnectivity-server":            konnectivityServerImage,
-				"konnectivity-agent":             konnectivityAgentImage,
 			},
 		}
 
@@ -166,15 +168,42 @@ func NewStartCommand() *cobra.Command {
 			os.Exit(1)
 		}
 
+		// A tag could resolve to a different image on every restart, and can't
+		// be mirrored reliably for disconnected environments
+		if len(konnectivityImage) > 0 {
+			if ref, err := reference.Parse(konnectivityImage); err != nil {
+				setupLog.Error(err, "invalid konnectivity image", "image", konnectivityImage)
+				os.Exit(1)
+			} else if len(ref.ID) == 0 {
+				setupLog.Error(fmt.Errorf("image is not pinned by digest"), "invalid konnectivity image", "image", konnectivityImage)
+				os.Exit(1)
+			}
+		}
+
 		if err := (&hostedcontrolplane.HostedControlPlaneReconciler{
-			Client:          mgr.GetClient(),
-			ReleaseProvider: releaseProvider,
-			HostedAPICache:  apiCacheController.GetCache(),
+			Client:                   mgr.GetClient(),
+			ReleaseProvider:          releaseProvider,
+			HostedAPICache:           apiCacheController.GetCache(),
+			DefaultKonnectivityImage: konnectivityImage,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "hosted-control-plane")
 			os.Exit(1)
 		}
 
+		if err := (&etcdmaintenance.EtcdMaintenanceReconciler{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "etcd-maintenance")
+			os.Exit(1)
+		}
+
+		if err := (&konnectivitystatus.KonnectivityStatusReconciler{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "konnectivity-status")
+			os.Exit(1)
+		}
+
 		setupLog.Info("starting manager")
 		if err := mgr.Start(ctx); err != nil {
 			setupLog.Error(err, "problem running manager")
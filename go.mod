@@ -8,6 +8,7 @@ replace github.com/openshift/hypershift/support => ./support
 
 require (
 	github.com/aws/aws-sdk-go v1.35.0
+	github.com/blang/semver v3.5.1+incompatible
 	github.com/bombsimon/logrusr v1.0.0
 	github.com/coreos/ignition/v2 v2.10.1
 	github.com/go-logr/logr v0.4.0
@@ -19,6 +20,8 @@ require (
 	github.com/openshift/hypershift/api v0.0.0-00010101000000-000000000000
 	github.com/openshift/hypershift/support v0.0.0-00010101000000-000000000000
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.7.1
+	github.com/prometheus/client_model v0.2.0
 	github.com/sirupsen/logrus v1.6.0
 	github.com/spf13/cobra v1.1.1
 	github.com/stretchr/testify v1.7.0
@@ -27,10 +30,12 @@ require (
 	go.opentelemetry.io/otel/exporters/otlp v0.19.0
 	go.opentelemetry.io/otel/sdk v0.19.0
 	go.opentelemetry.io/otel/trace v0.19.0
+	google.golang.org/grpc v1.36.0
 	gopkg.in/square/go-jose.v2 v2.3.1
 	k8s.io/api v0.20.2
 	k8s.io/apiextensions-apiserver v0.20.2
 	k8s.io/apimachinery v0.20.2
+	k8s.io/apiserver v0.20.2
 	k8s.io/client-go v0.20.2
 	k8s.io/utils v0.0.0-20210111153108-fddb29f9d009
 	sigs.k8s.io/controller-runtime v0.8.2
@@ -0,0 +1,149 @@
+package inplaceupgrader
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/hypershift/api/inplaceupgrade"
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
+)
+
+const (
+	// configVersionLabel is the config version an agent pod applies.
+	configVersionLabel = "hypershift.openshift.io/configVersion"
+
+	agentPayloadDir = "/etc/hypershift/payload"
+
+	// agentScript applies the payload and reboots the node. The marker in
+	// the host filesystem makes the agent succeed once the node comes back
+	// from the reboot and the agent container is restarted.
+	agentScript = `#!/bin/bash
+set -euo pipefail
+marker="/rootfs/var/lib/hypershift/inplace-upgrade/${CONFIG_VERSION}"
+if [[ -f "${marker}" ]]; then
+  echo "Config version ${CONFIG_VERSION} is applied"
+  exit 0
+fi
+machine-config-daemon start --node-name "${NODE_NAME}" --root-mount /rootfs --once-from "%s/%s" --skip-reboot
+mkdir -p "$(dirname "${marker}")"
+touch "${marker}"
+echo "Rebooting into config version ${CONFIG_VERSION}"
+chroot /rootfs systemctl reboot
+`
+)
+
+func agentPodName(nodeName string) string {
+	return fmt.Sprintf("inplace-upgrade-%s", nodeName)
+}
+
+// reconcileAgentPod runs the agent which updates a node to the target config
+// version.
+func (r *InPlaceUpgrader) reconcileAgentPod(ctx context.Context, machineSet *capiv1.MachineSet, nodeName, targetConfigVersion, payloadSecretName string) error {
+	if err := r.reconcileAgentServiceAccount(ctx); err != nil {
+		return err
+	}
+	pod := agentPod(nodeName, machineSet.Name, targetConfigVersion, payloadSecretName, machineSet.Annotations[inplaceupgrade.MachineSetAnnotationMachineConfigDaemonImage])
+	if _, err := r.TargetKubeClient.CoreV1().Pods(upgradeNamespace).Create(ctx, pod, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create agent pod: %w", err)
+	}
+	return nil
+}
+
+func agentPod(nodeName, machineSetName, targetConfigVersion, payloadSecretName, image string) *corev1.Pod {
+	hostPathDirectory := corev1.HostPathDirectory
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: upgradeNamespace,
+			Name:      agentPodName(nodeName),
+			Labels: map[string]string{
+				machineSetLabel:    machineSetName,
+				configVersionLabel: targetConfigVersion,
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:           nodeName,
+			ServiceAccountName: upgradeServiceAccount,
+			HostPID:            true,
+			RestartPolicy:      corev1.RestartPolicyOnFailure,
+			PriorityClassName:  "system-node-critical",
+			Tolerations: []corev1.Toleration{
+				{
+					Operator: corev1.TolerationOpExists,
+				},
+			},
+			Containers: []corev1.Container{
+				{
+					Name:    "machine-config-daemon",
+					Image:   image,
+					Command: []string{"/bin/bash", "-c", fmt.Sprintf(agentScript, agentPayloadDir, inplaceupgrade.PayloadSecretKey)},
+					Env: []corev1.EnvVar{
+						{
+							Name:  "NODE_NAME",
+							Value: nodeName,
+						},
+						{
+							Name:  "CONFIG_VERSION",
+							Value: targetConfigVersion,
+						},
+					},
+					SecurityContext: &corev1.SecurityContext{
+						Privileged: pointer.BoolPtr(true),
+					},
+					VolumeMounts: []corev1.VolumeMount{
+						{
+							Name:      "rootfs",
+							MountPath: "/rootfs",
+						},
+						{
+							Name:      "payload",
+							MountPath: agentPayloadDir,
+						},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "rootfs",
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{
+							Path: "/",
+							Type: &hostPathDirectory,
+						},
+					},
+				},
+				{
+					Name: "payload",
+					VolumeSource: corev1.VolumeSource{
+						Secret: &corev1.SecretVolumeSource{
+							SecretName: payloadSecretName,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// agentPodFailed returns whether the agent failed to apply the payload more
+// often than a reboot explains, along with the reason of the last failure.
+func agentPodFailed(pod *corev1.Pod) (bool, string) {
+	if pod.Status.Phase == corev1.PodFailed {
+		return true, fmt.Sprintf("agent pod %s failed: %s", pod.Name, pod.Status.Message)
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.RestartCount < agentMaxRestarts {
+			continue
+		}
+		message := fmt.Sprintf("agent pod %s restarted %d times", pod.Name, status.RestartCount)
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			message = fmt.Sprintf("%s, last exit code %d: %s", message, terminated.ExitCode, terminated.Message)
+		}
+		return true, message
+	}
+	return false, ""
+}
@@ -0,0 +1,406 @@
+package inplaceupgrader
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	kubeclient "k8s.io/client-go/kubernetes"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/hypershift/api/inplaceupgrade"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
+)
+
+const (
+	nodeOwnerKindAnnotation = "cluster.x-k8s.io/owner-kind"
+	nodeOwnerNameAnnotation = "cluster.x-k8s.io/owner-name"
+
+	// nodeBootIDAnnotation records the boot of a node before it is updated,
+	// so the reboot into the new config can be told apart.
+	nodeBootIDAnnotation = "hypershift.openshift.io/inPlaceUpgradeBootID"
+
+	// machineSetLabel labels the guest cluster resources created to update the
+	// nodes of a MachineSet.
+	machineSetLabel = "hypershift.openshift.io/machineSet"
+
+	upgradeNamespace      = "openshift-machine-config-operator"
+	upgradeServiceAccount = "machine-config-daemon-inplace-upgrade"
+	privilegedSCCRole     = "system:openshift:scc:privileged"
+
+	// agentMaxRestarts is how many times an agent pod can fail before its node
+	// is reported degraded. A restart is expected when the node reboots.
+	agentMaxRestarts = 3
+)
+
+// InPlaceUpgrader updates the nodes of InPlace NodePools to the config version
+// targeted by their MachineSet. Nodes are drained and an agent pod applies
+// the ignition payload of the target config version with the
+// machine-config-daemon before rebooting the node.
+type InPlaceUpgrader struct {
+	Client           client.Client
+	TargetKubeClient kubeclient.Interface
+	NodeLister       corev1lister.NodeLister
+	PodLister        corev1lister.PodLister
+	Log              logr.Logger
+}
+
+func (r *InPlaceUpgrader) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := r.Log.WithValues("machineset", req.NamespacedName.String())
+
+	machineSet := &capiv1.MachineSet{}
+	if err := r.Client.Get(ctx, req.NamespacedName, machineSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, r.deleteStalePayloads(ctx, req.Name, "")
+		}
+		return ctrl.Result{}, err
+	}
+	targetConfigVersion := machineSet.Annotations[inplaceupgrade.MachineSetAnnotationTargetConfigVersion]
+	if targetConfigVersion == "" {
+		return ctrl.Result{}, nil
+	}
+	logger.Info("Start reconcile", "target", targetConfigVersion)
+
+	payloadSecretName := machineSet.Annotations[inplaceupgrade.MachineSetAnnotationUpgradePayloadSecret]
+	if err := r.reconcilePayload(ctx, machineSet, payloadSecretName); err != nil {
+		return ctrl.Result{}, err
+	}
+	machines := &capiv1.MachineList{}
+	if err := r.Client.List(ctx, machines, client.InNamespace(machineSet.Namespace), client.MatchingLabels(machineSet.Spec.Selector.MatchLabels)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list machines: %w", err)
+	}
+	sort.Slice(machines.Items, func(i, j int) bool {
+		return machines.Items[i].Name < machines.Items[j].Name
+	})
+
+	targetDataSecretName := pointer.StringPtrDerefOr(machineSet.Spec.Template.Spec.Bootstrap.DataSecretName, "")
+	var inProgress, pending []*capiv1.Machine
+	for i := range machines.Items {
+		machine := &machines.Items[i]
+		if machine.Status.NodeRef == nil {
+			continue
+		}
+		if inplaceupgrade.MachineConfigVersion(machine, targetDataSecretName, targetConfigVersion) == targetConfigVersion {
+			if err := r.setMachineState(ctx, machine, targetConfigVersion, hyperv1.NodeUpgradeStateDone, ""); err != nil {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		switch hyperv1.NodeUpgradeState(machine.Annotations[inplaceupgrade.MachineAnnotationUpgradeState]) {
+		case hyperv1.NodeUpgradeStateDraining, hyperv1.NodeUpgradeStateUpdating, hyperv1.NodeUpgradeStateDegraded:
+			inProgress = append(inProgress, machine)
+		default:
+			pending = append(pending, machine)
+		}
+	}
+
+	maxUnavailable := resolveMaxUnavailable(machineSet)
+	for len(inProgress) < maxUnavailable && len(pending) > 0 {
+		logger.Info("Starting node update", "machine", pending[0].Name, "node", pending[0].Status.NodeRef.Name)
+		inProgress = append(inProgress, pending[0])
+		pending = pending[1:]
+	}
+
+	for _, machine := range inProgress {
+		if err := r.updateNode(ctx, machineSet, machine, targetConfigVersion, payloadSecretName); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to update node of machine %s: %w", machine.Name, err)
+		}
+	}
+
+	if err := r.deleteStalePayloads(ctx, machineSet.Name, payloadSecretName); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Draining is retried until the evicted pods are gone.
+	if len(inProgress) > 0 {
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// resolveMaxUnavailable returns how many nodes of a MachineSet are updated at
+// the same time. At least one node is updated so updates make progress.
+func resolveMaxUnavailable(machineSet *capiv1.MachineSet) int {
+	maxUnavailable := intstr.Parse(machineSet.Annotations[inplaceupgrade.MachineSetAnnotationUpgradeMaxUnavailable])
+	replicas := int(pointer.Int32PtrDerefOr(machineSet.Spec.Replicas, 0))
+	value, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, replicas, false)
+	if err != nil || value < 1 {
+		return 1
+	}
+	return value
+}
+
+// updateNode advances the update of the node of a Machine:
+// the node is cordoned and drained, an agent pod applies the target config and
+// reboots the node, and the node is uncordoned once it is back and ready.
+func (r *InPlaceUpgrader) updateNode(ctx context.Context, machineSet *capiv1.MachineSet, machine *capiv1.Machine, targetConfigVersion, payloadSecretName string) error {
+	currentConfigVersion := machine.Annotations[inplaceupgrade.MachineAnnotationCurrentConfigVersion]
+	node, err := r.NodeLister.Get(machine.Status.NodeRef.Name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	switch hyperv1.NodeUpgradeState(machine.Annotations[inplaceupgrade.MachineAnnotationUpgradeState]) {
+	case hyperv1.NodeUpgradeStateDraining:
+		drained, err := r.drainNode(ctx, node.Name)
+		if err != nil {
+			return err
+		}
+		if !drained {
+			return nil
+		}
+		if err := r.reconcileAgentPod(ctx, machineSet, node.Name, targetConfigVersion, payloadSecretName); err != nil {
+			return err
+		}
+		return r.setMachineState(ctx, machine, currentConfigVersion, hyperv1.NodeUpgradeStateUpdating, "")
+
+	case hyperv1.NodeUpgradeStateUpdating, hyperv1.NodeUpgradeStateDegraded:
+		pod, err := r.PodLister.Pods(upgradeNamespace).Get(agentPodName(node.Name))
+		if apierrors.IsNotFound(err) {
+			// Removing the agent pod of a degraded node retries its update.
+			if err := r.reconcileAgentPod(ctx, machineSet, node.Name, targetConfigVersion, payloadSecretName); err != nil {
+				return err
+			}
+			return r.setMachineState(ctx, machine, currentConfigVersion, hyperv1.NodeUpgradeStateUpdating, "")
+		} else if err != nil {
+			return err
+		}
+		if pod.Labels[configVersionLabel] != targetConfigVersion {
+			// The target changed during the update, start over with the new one.
+			return r.TargetKubeClient.CoreV1().Pods(upgradeNamespace).Delete(ctx, pod.Name, metav1.DeleteOptions{})
+		}
+		if failed, message := agentPodFailed(pod); failed {
+			return r.setMachineState(ctx, machine, currentConfigVersion, hyperv1.NodeUpgradeStateDegraded, message)
+		}
+		if pod.Status.Phase != corev1.PodSucceeded || node.Status.NodeInfo.BootID == node.Annotations[nodeBootIDAnnotation] || !nodeReady(node) {
+			return r.setMachineState(ctx, machine, currentConfigVersion, hyperv1.NodeUpgradeStateUpdating, "")
+		}
+		if err := r.uncordonNode(ctx, node); err != nil {
+			return err
+		}
+		if err := r.TargetKubeClient.CoreV1().Pods(upgradeNamespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		r.Log.Info("Node update complete", "node", node.Name, "configVersion", targetConfigVersion)
+		return r.setMachineState(ctx, machine, targetConfigVersion, hyperv1.NodeUpgradeStateDone, "")
+
+	default:
+		if err := r.cordonNode(ctx, node); err != nil {
+			return err
+		}
+		return r.setMachineState(ctx, machine, currentConfigVersion, hyperv1.NodeUpgradeStateDraining, "")
+	}
+}
+
+// setMachineState reports the config version and update state of the node of
+// a Machine.
+func (r *InPlaceUpgrader) setMachineState(ctx context.Context, machine *capiv1.Machine, configVersion string, state hyperv1.NodeUpgradeState, message string) error {
+	if machine.Annotations[inplaceupgrade.MachineAnnotationCurrentConfigVersion] == configVersion &&
+		machine.Annotations[inplaceupgrade.MachineAnnotationUpgradeState] == string(state) &&
+		machine.Annotations[inplaceupgrade.MachineAnnotationUpgradeMessage] == message {
+		return nil
+	}
+	original := machine.DeepCopy()
+	if machine.Annotations == nil {
+		machine.Annotations = map[string]string{}
+	}
+	if configVersion == "" {
+		delete(machine.Annotations, inplaceupgrade.MachineAnnotationCurrentConfigVersion)
+	} else {
+		machine.Annotations[inplaceupgrade.MachineAnnotationCurrentConfigVersion] = configVersion
+	}
+	machine.Annotations[inplaceupgrade.MachineAnnotationUpgradeState] = string(state)
+	if message == "" {
+		delete(machine.Annotations, inplaceupgrade.MachineAnnotationUpgradeMessage)
+	} else {
+		machine.Annotations[inplaceupgrade.MachineAnnotationUpgradeMessage] = message
+	}
+	if err := r.Client.Patch(ctx, machine, client.MergeFrom(original)); err != nil {
+		return fmt.Errorf("failed to report node update state on machine %s: %w", machine.Name, err)
+	}
+	return nil
+}
+
+func (r *InPlaceUpgrader) cordonNode(ctx context.Context, node *corev1.Node) error {
+	node = node.DeepCopy()
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[nodeBootIDAnnotation] = node.Status.NodeInfo.BootID
+	node.Spec.Unschedulable = true
+	_, err := r.TargetKubeClient.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	return err
+}
+
+func (r *InPlaceUpgrader) uncordonNode(ctx context.Context, node *corev1.Node) error {
+	node = node.DeepCopy()
+	delete(node.Annotations, nodeBootIDAnnotation)
+	node.Spec.Unschedulable = false
+	_, err := r.TargetKubeClient.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	return err
+}
+
+// drainNode evicts the pods of a node and returns whether all of them are gone.
+// Evictions blocked by pod disruption budgets are retried on the next call.
+func (r *InPlaceUpgrader) drainNode(ctx context.Context, nodeName string) (bool, error) {
+	pods, err := r.TargetKubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list pods of node %s: %w", nodeName, err)
+	}
+	drained := true
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !needsEviction(pod) {
+			continue
+		}
+		drained = false
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		err := r.TargetKubeClient.CoreV1().Pods(pod.Namespace).Evict(ctx, &policyv1beta1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Name: pod.Name, Namespace: pod.Namespace},
+		})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsTooManyRequests(err) {
+			return false, fmt.Errorf("failed to evict pod %s/%s: %w", pod.Namespace, pod.Name, err)
+		}
+	}
+	return drained, nil
+}
+
+// needsEviction returns whether a pod has to be evicted to drain its node.
+// Pods of DaemonSets and static pods stay on the node, and completed pods
+// don't run anymore.
+func needsEviction(pod *corev1.Pod) bool {
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return false
+	}
+	if _, isMirror := pod.Annotations[corev1.MirrorPodAnnotationKey]; isMirror {
+		return false
+	}
+	if controller := metav1.GetControllerOf(pod); controller != nil && controller.Kind == "DaemonSet" {
+		return false
+	}
+	return true
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// reconcilePayload copies the ignition payload of the target config version
+// into the guest cluster so agent pods can mount it.
+func (r *InPlaceUpgrader) reconcilePayload(ctx context.Context, machineSet *capiv1.MachineSet, payloadSecretName string) error {
+	if _, err := r.TargetKubeClient.CoreV1().Secrets(upgradeNamespace).Get(ctx, payloadSecretName, metav1.GetOptions{}); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	source := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: machineSet.Namespace, Name: payloadSecretName}, source); err != nil {
+		return fmt.Errorf("failed to get upgrade payload: %w", err)
+	}
+	if err := r.ensureNamespace(ctx); err != nil {
+		return err
+	}
+	payload := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: upgradeNamespace,
+			Name:      payloadSecretName,
+			Labels: map[string]string{
+				machineSetLabel: machineSet.Name,
+			},
+		},
+		Immutable: pointer.BoolPtr(true),
+		Data: map[string][]byte{
+			inplaceupgrade.PayloadSecretKey: source.Data[inplaceupgrade.PayloadSecretKey],
+		},
+	}
+	if _, err := r.TargetKubeClient.CoreV1().Secrets(upgradeNamespace).Create(ctx, payload, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create upgrade payload: %w", err)
+	}
+	return nil
+}
+
+// deleteStalePayloads removes the payloads of a MachineSet other than the
+// current one from the guest cluster.
+func (r *InPlaceUpgrader) deleteStalePayloads(ctx context.Context, machineSetName, currentPayloadSecretName string) error {
+	secrets, err := r.TargetKubeClient.CoreV1().Secrets(upgradeNamespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{machineSetLabel: machineSetName}).String(),
+	})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to list upgrade payloads: %w", err)
+	}
+	for _, secret := range secrets.Items {
+		if secret.Name == currentPayloadSecretName {
+			continue
+		}
+		if err := r.TargetKubeClient.CoreV1().Secrets(upgradeNamespace).Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete upgrade payload %s: %w", secret.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *InPlaceUpgrader) ensureNamespace(ctx context.Context) error {
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: upgradeNamespace}}
+	if _, err := r.TargetKubeClient.CoreV1().Namespaces().Create(ctx, namespace, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create namespace %s: %w", upgradeNamespace, err)
+	}
+	return nil
+}
+
+// reconcileAgentServiceAccount ensures agent pods are allowed to run
+// privileged.
+func (r *InPlaceUpgrader) reconcileAgentServiceAccount(ctx context.Context) error {
+	serviceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: upgradeNamespace, Name: upgradeServiceAccount}}
+	if _, err := r.TargetKubeClient.CoreV1().ServiceAccounts(upgradeNamespace).Create(ctx, serviceAccount, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create agent service account: %w", err)
+	}
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Namespace: upgradeNamespace, Name: upgradeServiceAccount},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     privilegedSCCRole,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Namespace: upgradeNamespace,
+				Name:      upgradeServiceAccount,
+			},
+		},
+	}
+	if _, err := r.TargetKubeClient.RbacV1().RoleBindings(upgradeNamespace).Create(ctx, roleBinding, metav1.CreateOptions{}); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create agent role binding: %w", err)
+	}
+	return nil
+}
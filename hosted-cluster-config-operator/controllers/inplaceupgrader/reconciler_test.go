@@ -0,0 +1,104 @@
+package inplaceupgrader
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	"github.com/openshift/hypershift/api/inplaceupgrade"
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
+)
+
+func TestResolveMaxUnavailable(t *testing.T) {
+	tests := []struct {
+		name           string
+		maxUnavailable string
+		replicas       int32
+		expected       int
+	}{
+		{name: "number", maxUnavailable: "2", replicas: 5, expected: 2},
+		{name: "percentage", maxUnavailable: "50%", replicas: 5, expected: 2},
+		{name: "percentage rounding down to zero", maxUnavailable: "10%", replicas: 5, expected: 1},
+		{name: "zero", maxUnavailable: "0", replicas: 5, expected: 1},
+		{name: "unset", replicas: 5, expected: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			machineSet := &capiv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{inplaceupgrade.MachineSetAnnotationUpgradeMaxUnavailable: test.maxUnavailable},
+				},
+				Spec: capiv1.MachineSetSpec{Replicas: pointer.Int32Ptr(test.replicas)},
+			}
+			if actual := resolveMaxUnavailable(machineSet); actual != test.expected {
+				t.Errorf("Expected %d, got %d", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNeedsEviction(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      *corev1.Pod
+		expected bool
+	}{
+		{
+			name:     "running pod",
+			pod:      &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodRunning}},
+			expected: true,
+		},
+		{
+			name: "completed pod",
+			pod:  &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodSucceeded}},
+		},
+		{
+			name: "static pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{corev1.MirrorPodAnnotationKey: "hash"}},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+		},
+		{
+			name: "daemonset pod",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{
+					{Kind: "DaemonSet", Name: "dns-default", Controller: pointer.BoolPtr(true)},
+				}},
+				Status: corev1.PodStatus{Phase: corev1.PodRunning},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := needsEviction(test.pod); actual != test.expected {
+				t.Errorf("Expected %t, got %t", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAgentPodFailed(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "inplace-upgrade-node"},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{RestartCount: 1}},
+		},
+	}
+	if failed, _ := agentPodFailed(pod); failed {
+		t.Fatalf("Expected a restart caused by the reboot not to fail the agent")
+	}
+
+	pod.Status.ContainerStatuses[0].RestartCount = agentMaxRestarts
+	pod.Status.ContainerStatuses[0].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{ExitCode: 1, Message: "failed to apply"}
+	failed, message := agentPodFailed(pod)
+	if !failed {
+		t.Fatalf("Expected the agent to fail")
+	}
+	if message != "agent pod inplace-upgrade-node restarted 3 times, last exit code 1: failed to apply" {
+		t.Errorf("Unexpected message %q", message)
+	}
+}
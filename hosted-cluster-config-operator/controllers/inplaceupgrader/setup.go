@@ -0,0 +1,85 @@
+package inplaceupgrader
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
+	"github.com/openshift/hypershift/hosted-cluster-config-operator/controllers"
+	"github.com/openshift/hypershift/hosted-cluster-config-operator/operator"
+)
+
+func Setup(cfg *operator.HostedClusterConfigOperatorConfig) error {
+	informerFactory := informers.NewSharedInformerFactory(cfg.TargetKubeClient(), controllers.DefaultResync)
+	cfg.Manager().Add(manager.RunnableFunc(func(ctx context.Context) error {
+		informerFactory.Start(ctx.Done())
+		return nil
+	}))
+	nodes := informerFactory.Core().V1().Nodes()
+	pods := cfg.TargetKubeInformersForNamespace(upgradeNamespace).Core().V1().Pods()
+
+	reconciler := &InPlaceUpgrader{
+		Client:           cfg.ManagementClient(),
+		TargetKubeClient: cfg.TargetKubeClient(),
+		NodeLister:       nodes.Lister(),
+		PodLister:        pods.Lister(),
+		Log:              cfg.Logger().WithName("InPlaceUpgrader"),
+	}
+	c, err := controller.New("inplace-upgrader", cfg.Manager(), controller.Options{Reconciler: reconciler})
+	if err != nil {
+		return err
+	}
+	if err := c.Watch(source.NewKindWithCache(&capiv1.MachineSet{}, cfg.ManagementCache()), &handler.EnqueueRequestForObject{}); err != nil {
+		return err
+	}
+	if err := c.Watch(source.NewKindWithCache(&capiv1.Machine{}, cfg.ManagementCache()), handler.EnqueueRequestsFromMapFunc(machineSetForMachine)); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Informer{Informer: nodes.Informer()}, handler.EnqueueRequestsFromMapFunc(machineSetForNode(cfg.Namespace()))); err != nil {
+		return err
+	}
+	if err := c.Watch(&source.Informer{Informer: pods.Informer()}, handler.EnqueueRequestsFromMapFunc(machineSetForAgentPod(cfg.Namespace()))); err != nil {
+		return err
+	}
+	return nil
+}
+
+func machineSetForMachine(obj client.Object) []reconcile.Request {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Kind == "MachineSet" && ref.Controller != nil && *ref.Controller {
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: ref.Name}}}
+		}
+	}
+	return nil
+}
+
+func machineSetForNode(namespace string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		if obj.GetAnnotations()[nodeOwnerKindAnnotation] != "MachineSet" {
+			return nil
+		}
+		name := obj.GetAnnotations()[nodeOwnerNameAnnotation]
+		if name == "" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+	}
+}
+
+func machineSetForAgentPod(namespace string) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		name := obj.GetLabels()[machineSetLabel]
+		if name == "" {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}}}
+	}
+}
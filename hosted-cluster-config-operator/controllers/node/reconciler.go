@@ -2,14 +2,24 @@ package node
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kubeclient "k8s.io/client-go/kubernetes"
 	corev1lister "k8s.io/client-go/listers/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/hypershift/api/nodesync"
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
 )
 
 var requiredLabels = map[string]string{
@@ -17,12 +27,32 @@ var requiredLabels = map[string]string{
 	"node-role.kubernetes.io/master": "",
 }
 
-const masterTaint = "node-role.kubernetes.io/master"
+const (
+	masterTaint = "node-role.kubernetes.io/master"
+
+	// nodeMachineAnnotation is set on nodes by cluster api and names the
+	// Machine backing a node.
+	nodeMachineAnnotation = "cluster.x-k8s.io/machine"
+)
+
+// nodePoolNodeConfig holds the labels and taints of the nodes of a NodePool.
+type nodePoolNodeConfig struct {
+	labels map[string]string
+	taints []corev1.Taint
+	// registeredLabels and registeredTaints are the labels and taints new
+	// nodes register with.
+	registeredLabels map[string]string
+	registeredTaints []corev1.Taint
+}
 
 type NodeReconciler struct {
 	Lister     corev1lister.NodeLister
 	KubeClient kubeclient.Interface
-	Log        logr.Logger
+	// Client reads the cluster api resources of the hosted cluster in the
+	// management cluster.
+	Client    client.Client
+	Namespace string
+	Log       logr.Logger
 }
 
 func (a *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -30,48 +60,207 @@ func (a *NodeReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	logger.Info("Start reconcile")
 	node, err := a.Lister.Get(req.Name)
 	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
 		return ctrl.Result{}, err
 	}
-	if hasRequiredLabels(node) && !hasMasterTaint(node) {
-		return ctrl.Result{}, nil
+
+	nodePoolConfig, err := a.getNodePoolNodeConfig(ctx, node)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
+	updated := node.DeepCopy()
 	for k := range requiredLabels {
-		if _, hasLabel := node.Labels[k]; hasLabel {
+		if _, hasLabel := updated.Labels[k]; hasLabel {
 			continue
 		}
-		if node.Labels == nil {
-			node.Labels = map[string]string{}
+		if updated.Labels == nil {
+			updated.Labels = map[string]string{}
 		}
-		node.Labels[k] = requiredLabels[k]
+		updated.Labels[k] = requiredLabels[k]
+	}
+	removeMasterTaint(updated)
+	if nodePoolConfig != nil {
+		applyNodePoolLabels(updated, nodePoolConfig.labels, nodePoolConfig.registeredLabels)
+		applyNodePoolTaints(updated, nodePoolConfig.taints, nodePoolConfig.registeredTaints)
+	}
+	if equality.Semantic.DeepEqual(node, updated) {
+		return ctrl.Result{}, nil
 	}
-
-	removeMasterTaint(node)
 
 	logger.Info("Updating node")
-	_, err = a.KubeClient.CoreV1().Nodes().Update(ctx, node, metav1.UpdateOptions{})
+	_, err = a.KubeClient.CoreV1().Nodes().Update(ctx, updated, metav1.UpdateOptions{})
 	if err != nil {
 		a.Log.Error(err, "failed to update node")
 	}
 	return ctrl.Result{}, err
 }
 
-func hasRequiredLabels(node *corev1.Node) bool {
-	for k := range requiredLabels {
-		if _, hasLabel := node.Labels[k]; !hasLabel {
-			return false
+// getNodePoolNodeConfig returns the labels and taints of the NodePool a node
+// belongs to. The node is mapped to its NodePool through its Machine and the
+// MachineSet or MachineDeployment the Machine is part of. It returns nil
+// when the node does not belong to a NodePool (yet).
+func (a *NodeReconciler) getNodePoolNodeConfig(ctx context.Context, node *corev1.Node) (*nodePoolNodeConfig, error) {
+	machineName := node.Annotations[nodeMachineAnnotation]
+	if machineName == "" {
+		return nil, nil
+	}
+	machine := &capiv1.Machine{}
+	if err := a.Client.Get(ctx, client.ObjectKey{Namespace: a.Namespace, Name: machineName}, machine); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get machine %s: %w", machineName, err)
+	}
+	machineSetRef := metav1.GetControllerOf(machine)
+	if machineSetRef == nil || machineSetRef.Kind != "MachineSet" {
+		return nil, nil
+	}
+	machineSet := &capiv1.MachineSet{}
+	if err := a.Client.Get(ctx, client.ObjectKey{Namespace: a.Namespace, Name: machineSetRef.Name}, machineSet); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get machineset %s: %w", machineSetRef.Name, err)
+	}
+
+	// InPlace NodePools are backed by a MachineSet, the others by a
+	// MachineDeployment.
+	annotations := machineSet.Annotations
+	if _, ok := annotations[nodesync.MachineSetAnnotationNodeLabels]; !ok {
+		machineDeploymentRef := metav1.GetControllerOf(machineSet)
+		if machineDeploymentRef == nil || machineDeploymentRef.Kind != "MachineDeployment" {
+			return nil, nil
+		}
+		machineDeployment := &capiv1.MachineDeployment{}
+		if err := a.Client.Get(ctx, client.ObjectKey{Namespace: a.Namespace, Name: machineDeploymentRef.Name}, machineDeployment); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get machinedeployment %s: %w", machineDeploymentRef.Name, err)
+		}
+		annotations = machineDeployment.Annotations
+		if _, ok := annotations[nodesync.MachineSetAnnotationNodeLabels]; !ok {
+			return nil, nil
+		}
+	}
+
+	config := &nodePoolNodeConfig{}
+	if err := decodeAnnotation(annotations, nodesync.MachineSetAnnotationNodeLabels, &config.labels); err != nil {
+		return nil, fmt.Errorf("failed to decode node labels: %w", err)
+	}
+	if err := decodeAnnotation(annotations, nodesync.MachineSetAnnotationTaints, &config.taints); err != nil {
+		return nil, fmt.Errorf("failed to decode taints: %w", err)
+	}
+	if err := decodeAnnotation(annotations, nodesync.MachineSetAnnotationRegisteredNodeLabels, &config.registeredLabels); err != nil {
+		return nil, fmt.Errorf("failed to decode registered node labels: %w", err)
+	}
+	if err := decodeAnnotation(annotations, nodesync.MachineSetAnnotationRegisteredTaints, &config.registeredTaints); err != nil {
+		return nil, fmt.Errorf("failed to decode registered taints: %w", err)
+	}
+	return config, nil
+}
+
+// decodeAnnotation decodes the JSON value of an annotation into v. It leaves
+// v untouched when the annotation is not set.
+func decodeAnnotation(annotations map[string]string, key string, v interface{}) error {
+	value, ok := annotations[key]
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal([]byte(value), v)
+}
+
+// applyNodePoolLabels sets the NodePool labels on a node and removes the ones
+// previously applied or registered which are no longer part of the NodePool.
+func applyNodePoolLabels(node *corev1.Node, labels, registeredLabels map[string]string) {
+	applied := splitApplied(node.Annotations[nodesync.NodeAnnotationAppliedNodeLabels])
+	for key := range registeredLabels {
+		applied = append(applied, key)
+	}
+	for _, key := range applied {
+		if _, ok := labels[key]; !ok {
+			delete(node.Labels, key)
 		}
 	}
-	return true
+	keys := make([]string, 0, len(labels))
+	for key, value := range labels {
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		node.Labels[key] = value
+		keys = append(keys, key)
+	}
+	setApplied(node, nodesync.NodeAnnotationAppliedNodeLabels, keys)
 }
 
-func hasMasterTaint(node *corev1.Node) bool {
+// applyNodePoolTaints sets the NodePool taints on a node and removes the ones
+// previously applied or registered which are no longer part of the NodePool.
+// Taints are identified by their key and effect.
+func applyNodePoolTaints(node *corev1.Node, taints, registeredTaints []corev1.Taint) {
+	desired := map[string]corev1.Taint{}
+	for _, taint := range taints {
+		desired[taintID(taint)] = taint
+	}
+	applied := map[string]bool{}
+	for _, id := range splitApplied(node.Annotations[nodesync.NodeAnnotationAppliedTaints]) {
+		applied[id] = true
+	}
+	for _, taint := range registeredTaints {
+		applied[taintID(taint)] = true
+	}
+
+	result := make([]corev1.Taint, 0, len(node.Spec.Taints)+len(taints))
 	for _, taint := range node.Spec.Taints {
-		if taint.Key == masterTaint {
-			return true
+		id := taintID(taint)
+		if want, ok := desired[id]; ok {
+			if taint.Value != want.Value {
+				taint.Value = want.Value
+				taint.TimeAdded = nil
+			}
+			delete(desired, id)
+		} else if applied[id] {
+			continue
+		}
+		result = append(result, taint)
+	}
+	for _, taint := range taints {
+		if _, missing := desired[taintID(taint)]; missing {
+			result = append(result, corev1.Taint{Key: taint.Key, Value: taint.Value, Effect: taint.Effect})
 		}
 	}
-	return false
+	node.Spec.Taints = result
+
+	ids := make([]string, 0, len(taints))
+	for _, taint := range taints {
+		ids = append(ids, taintID(taint))
+	}
+	setApplied(node, nodesync.NodeAnnotationAppliedTaints, ids)
+}
+
+func taintID(taint corev1.Taint) string {
+	return fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+}
+
+func splitApplied(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+func setApplied(node *corev1.Node, annotation string, values []string) {
+	if len(values) == 0 {
+		delete(node.Annotations, annotation)
+		return
+	}
+	sort.Strings(values)
+	if node.Annotations == nil {
+		node.Annotations = map[string]string{}
+	}
+	node.Annotations[annotation] = strings.Join(values, ",")
 }
 
 func removeMasterTaint(node *corev1.Node) {
@@ -0,0 +1,128 @@
+package node
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/hypershift/api/nodesync"
+)
+
+func TestApplyNodePoolLabels(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"kubernetes.io/hostname": "node",
+				"team":                   "a",
+				"removed":                "",
+			},
+			Annotations: map[string]string{nodesync.NodeAnnotationAppliedNodeLabels: "removed,team"},
+		},
+	}
+	applyNodePoolLabels(node, map[string]string{"team": "b", "added": "true"}, nil)
+
+	expectedLabels := map[string]string{
+		"kubernetes.io/hostname": "node",
+		"team":                   "b",
+		"added":                  "true",
+	}
+	if !reflect.DeepEqual(node.Labels, expectedLabels) {
+		t.Errorf("Expected labels %v, got %v", expectedLabels, node.Labels)
+	}
+	if actual := node.Annotations[nodesync.NodeAnnotationAppliedNodeLabels]; actual != "added,team" {
+		t.Errorf("Expected applied labels %q, got %q", "added,team", actual)
+	}
+
+	applyNodePoolLabels(node, nil, nil)
+	if _, ok := node.Annotations[nodesync.NodeAnnotationAppliedNodeLabels]; ok {
+		t.Errorf("Expected the applied labels annotation to be removed")
+	}
+	if _, ok := node.Labels["team"]; ok {
+		t.Errorf("Expected the team label to be removed")
+	}
+}
+
+func TestApplyNodePoolLabelsRemovesRegisteredLabels(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				"kubernetes.io/hostname": "node",
+				"team":                   "a",
+				"removed":                "",
+			},
+		},
+	}
+	applyNodePoolLabels(node, map[string]string{"team": "a", "node-role.kubernetes.io/infra": ""}, map[string]string{"team": "a", "removed": ""})
+
+	expectedLabels := map[string]string{
+		"kubernetes.io/hostname":        "node",
+		"team":                          "a",
+		"node-role.kubernetes.io/infra": "",
+	}
+	if !reflect.DeepEqual(node.Labels, expectedLabels) {
+		t.Errorf("Expected labels %v, got %v", expectedLabels, node.Labels)
+	}
+	if actual := node.Annotations[nodesync.NodeAnnotationAppliedNodeLabels]; actual != "node-role.kubernetes.io/infra,team" {
+		t.Errorf("Expected applied labels %q, got %q", "node-role.kubernetes.io/infra,team", actual)
+	}
+}
+
+func TestApplyNodePoolTaints(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{nodesync.NodeAnnotationAppliedTaints: "dedicated:NoSchedule,removed:NoExecute"},
+		},
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{Key: "node.kubernetes.io/unreachable", Effect: corev1.TaintEffectNoExecute},
+				{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "removed", Effect: corev1.TaintEffectNoExecute},
+			},
+		},
+	}
+	applyNodePoolTaints(node, []corev1.Taint{
+		{Key: "dedicated", Value: "b", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "added", Effect: corev1.TaintEffectPreferNoSchedule},
+	}, nil)
+
+	expectedTaints := []corev1.Taint{
+		{Key: "node.kubernetes.io/unreachable", Effect: corev1.TaintEffectNoExecute},
+		{Key: "dedicated", Value: "b", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "added", Effect: corev1.TaintEffectPreferNoSchedule},
+	}
+	if !reflect.DeepEqual(node.Spec.Taints, expectedTaints) {
+		t.Errorf("Expected taints %v, got %v", expectedTaints, node.Spec.Taints)
+	}
+	expectedApplied := "added:PreferNoSchedule,dedicated:NoSchedule"
+	if actual := node.Annotations[nodesync.NodeAnnotationAppliedTaints]; actual != expectedApplied {
+		t.Errorf("Expected applied taints %q, got %q", expectedApplied, actual)
+	}
+}
+
+func TestApplyNodePoolTaintsRemovesRegisteredTaints(t *testing.T) {
+	node := &corev1.Node{
+		Spec: corev1.NodeSpec{
+			Taints: []corev1.Taint{
+				{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "removed", Effect: corev1.TaintEffectNoExecute},
+			},
+		},
+	}
+	applyNodePoolTaints(node, []corev1.Taint{
+		{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+	}, []corev1.Taint{
+		{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "removed", Effect: corev1.TaintEffectNoExecute},
+	})
+
+	expectedTaints := []corev1.Taint{
+		{Key: "node.kubernetes.io/not-ready", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+	}
+	if !reflect.DeepEqual(node.Spec.Taints, expectedTaints) {
+		t.Errorf("Expected taints %v, got %v", expectedTaints, node.Spec.Taints)
+	}
+}
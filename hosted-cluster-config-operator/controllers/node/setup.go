@@ -3,16 +3,27 @@ package node
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/informers"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
 	"github.com/openshift/hypershift/hosted-cluster-config-operator/controllers"
 	"github.com/openshift/hypershift/hosted-cluster-config-operator/operator"
 )
 
+const (
+	nodeOwnerKindAnnotation = "cluster.x-k8s.io/owner-kind"
+	nodeOwnerNameAnnotation = "cluster.x-k8s.io/owner-name"
+)
+
 func Setup(cfg *operator.HostedClusterConfigOperatorConfig) error {
 	informerFactory := informers.NewSharedInformerFactory(cfg.TargetKubeClient(), controllers.DefaultResync)
 	cfg.Manager().Add(manager.RunnableFunc(func(ctx context.Context) error {
@@ -23,6 +34,8 @@ func Setup(cfg *operator.HostedClusterConfigOperatorConfig) error {
 	reconciler := &NodeReconciler{
 		Lister:     nodes.Lister(),
 		KubeClient: cfg.TargetKubeClient(),
+		Client:     cfg.ManagementClient(),
+		Namespace:  cfg.Namespace(),
 		Log:        cfg.Logger().WithName("Node"),
 	}
 	c, err := controller.New("node", cfg.Manager(), controller.Options{Reconciler: reconciler})
@@ -32,5 +45,50 @@ func Setup(cfg *operator.HostedClusterConfigOperatorConfig) error {
 	if err := c.Watch(&source.Informer{Informer: nodes.Informer()}, &handler.EnqueueRequestForObject{}); err != nil {
 		return err
 	}
+	if err := c.Watch(source.NewKindWithCache(&capiv1.MachineSet{}, cfg.ManagementCache()), handler.EnqueueRequestsFromMapFunc(nodesForMachineSet(nodes.Lister()))); err != nil {
+		return err
+	}
+	if err := c.Watch(source.NewKindWithCache(&capiv1.MachineDeployment{}, cfg.ManagementCache()), handler.EnqueueRequestsFromMapFunc(nodesForMachineDeployment(cfg.ManagementClient(), nodes.Lister()))); err != nil {
+		return err
+	}
 	return nil
 }
+
+// nodesForMachineSet enqueues the nodes of a MachineSet so they pick up
+// changes to the NodePool labels and taints recorded on it.
+func nodesForMachineSet(lister corev1lister.NodeLister) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		return nodeRequests(lister, map[string]bool{obj.GetName(): true})
+	}
+}
+
+// nodesForMachineDeployment enqueues the nodes of the MachineSets of a
+// MachineDeployment.
+func nodesForMachineDeployment(c client.Client, lister corev1lister.NodeLister) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		machineSets := &capiv1.MachineSetList{}
+		if err := c.List(context.Background(), machineSets, client.InNamespace(obj.GetNamespace()), client.MatchingLabels{capiv1.MachineDeploymentLabelName: obj.GetName()}); err != nil {
+			return nil
+		}
+		names := map[string]bool{}
+		for _, machineSet := range machineSets.Items {
+			names[machineSet.Name] = true
+		}
+		return nodeRequests(lister, names)
+	}
+}
+
+func nodeRequests(lister corev1lister.NodeLister, machineSetNames map[string]bool) []reconcile.Request {
+	nodes, err := lister.List(labels.Everything())
+	if err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, node := range nodes {
+		if node.Annotations[nodeOwnerKindAnnotation] != "MachineSet" || !machineSetNames[node.Annotations[nodeOwnerNameAnnotation]] {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}})
+	}
+	return requests
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/openshift/hypershift/hosted-cluster-config-operator/controllers/clusterversion"
 	"github.com/openshift/hypershift/hosted-cluster-config-operator/controllers/cmca"
 	"github.com/openshift/hypershift/hosted-cluster-config-operator/controllers/infrastatus"
+	"github.com/openshift/hypershift/hosted-cluster-config-operator/controllers/inplaceupgrader"
 	"github.com/openshift/hypershift/hosted-cluster-config-operator/controllers/kubeadminpwd"
 	"github.com/openshift/hypershift/hosted-cluster-config-operator/controllers/kubeletservingca"
 	"github.com/openshift/hypershift/hosted-cluster-config-operator/controllers/node"
@@ -50,8 +51,9 @@ var controllerFuncs = map[string]operator.ControllerSetupFunc{
 	"openshift-apiserver-monitor": openshiftapiservermonitor.Setup,
 	// TODO: non-essential, can't statically link to operator
 	//"openshift-controller-manager": openshiftcontrollermanager.Setup,
-	"infrastatus": infrastatus.Setup,
-	"node":        node.Setup,
+	"infrastatus":      infrastatus.Setup,
+	"node":             node.Setup,
+	"inplace-upgrader": inplaceupgrader.Setup,
 }
 
 type HostedClusterConfigOperator struct {
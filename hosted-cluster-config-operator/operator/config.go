@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-logr/logr"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	kubeclient "k8s.io/client-go/kubernetes"
@@ -14,11 +15,14 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	configclient "github.com/openshift/client-go/config/clientset/versioned"
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
 
+	hyperapi "github.com/openshift/hypershift/api"
 	common "github.com/openshift/hypershift/hosted-cluster-config-operator/controllers"
 )
 
@@ -41,6 +45,8 @@ type HostedClusterConfigOperatorConfig struct {
 	targetConfig     *rest.Config
 	targetKubeClient kubeclient.Interface
 	kubeClient       kubeclient.Interface
+	managementCache  cache.Cache
+	managementClient client.Client
 	logger           logr.Logger
 	scheme           *runtime.Scheme
 
@@ -168,6 +174,46 @@ func (c *HostedClusterConfigOperatorConfig) KubeClient() kubeclient.Interface {
 	return c.kubeClient
 }
 
+// ManagementCache returns a cache of the management cluster resources in the
+// control plane namespace. It is started by the manager.
+func (c *HostedClusterConfigOperatorConfig) ManagementCache() cache.Cache {
+	if c.managementCache == nil {
+		var err error
+		c.managementCache, err = cache.New(c.Config(), cache.Options{
+			Scheme:    hyperapi.Scheme,
+			Namespace: c.Namespace(),
+		})
+		if err != nil {
+			c.Fatal(err, "cannot get management cache")
+		}
+		if err := c.Manager().Add(c.managementCache); err != nil {
+			c.Fatal(err, "cannot add management cache to the manager")
+		}
+	}
+	return c.managementCache
+}
+
+// ManagementClient returns a client for the management cluster resources in
+// the control plane namespace which reads from the ManagementCache. Secrets
+// are read directly from the management cluster instead of being cached.
+func (c *HostedClusterConfigOperatorConfig) ManagementClient() client.Client {
+	if c.managementClient == nil {
+		directClient, err := client.New(c.Config(), client.Options{Scheme: hyperapi.Scheme})
+		if err != nil {
+			c.Fatal(err, "cannot get management client")
+		}
+		c.managementClient, err = client.NewDelegatingClient(client.NewDelegatingClientInput{
+			CacheReader:     c.ManagementCache(),
+			Client:          directClient,
+			UncachedObjects: []client.Object{&corev1.Secret{}},
+		})
+		if err != nil {
+			c.Fatal(err, "cannot get management client")
+		}
+	}
+	return c.managementClient
+}
+
 func (c *HostedClusterConfigOperatorConfig) Versions() map[string]string {
 	return c.versions
 }
@@ -39,6 +39,7 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/openshift/hypershift/hypershift-operator/controllers/manifests/ignitionserver"
 	"github.com/openshift/hypershift/support/certs"
+	supportetcd "github.com/openshift/hypershift/support/etcd"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -96,6 +97,10 @@ type HostedClusterReconciler struct {
 	// IgnitionServerImage is the image used to deploy the ignition server.
 	IgnitionServerImage string
 
+	// KonnectivityImage is the konnectivity image the control plane operator
+	// uses for release images which don't include one.
+	KonnectivityImage string
+
 	// Log is a thread-safe logger.
 	Log logr.Logger
 
@@ -125,6 +130,8 @@ func (r *HostedClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&source.Kind{Type: &capiv1.Cluster{}}, handler.EnqueueRequestsFromMapFunc(enqueueParentHostedCluster)).
 		Watches(&source.Kind{Type: &routev1.Route{}}, handler.EnqueueRequestsFromMapFunc(enqueueParentHostedCluster)).
 		Watches(&source.Kind{Type: &appsv1.Deployment{}}, handler.EnqueueRequestsFromMapFunc(enqueueParentHostedCluster)).
+		Watches(&source.Kind{Type: &hyperv1.NodePool{}}, handler.EnqueueRequestsFromMapFunc(enqueueNodePoolHostedCluster)).
+		Watches(&source.Kind{Type: &hyperv1.ClusterSizingConfiguration{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllHostedClusters)).
 		WithOptions(controller.Options{
 			RateLimiter: workqueue.NewItemExponentialFailureRateLimiter(1*time.Second, 10*time.Second),
 		}).
@@ -188,6 +195,13 @@ func (r *HostedClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	// Part one: update status
 
+	// Look up the HostedControlPlane the status is computed from. It is nil
+	// until the HostedControlPlane is created.
+	hcp, err := r.getHostedControlPlane(ctx, hcluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Set kubeconfig status
 	{
 		kubeConfigSecret := manifests.KubeConfigSecret(hcluster.Namespace, hcluster.Name)
@@ -202,73 +216,76 @@ func (r *HostedClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	// Set version status
+	hcluster.Status.Version = computeClusterVersionStatus(r.Clock, hcluster, hcp)
+
+	// Set the Available condition
+	// TODO: This is really setting something that could be more granular like
+	// HostedControlPlaneAvailable, and then the HostedCluster high-level Available
+	// condition could be computed as a function of the granular ThingAvailable
+	// conditions (so that it could incorporate e.g. HostedControlPlane and IgnitionServer
+	// availability in the ultimate HostedCluster Available condition)
 	{
-		controlPlaneNamespace := manifests.HostedControlPlaneNamespace(hcluster.Namespace, hcluster.Name)
-		hcp := controlplaneoperator.HostedControlPlane(controlPlaneNamespace.Name, hcluster.Name)
-		err := r.Client.Get(ctx, client.ObjectKeyFromObject(hcp), hcp)
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				hcp = nil
-			} else {
-				return ctrl.Result{}, fmt.Errorf("failed to get hostedcontrolplane: %w", err)
+		meta.SetStatusCondition(&hcluster.Status.Conditions, computeHostedClusterAvailability(hcluster, hcp))
+	}
+
+	// Set the CertificatesExpiring condition from the control plane
+	{
+		condition := metav1.Condition{
+			Type:   string(hyperv1.HostedClusterCertificatesExpiring),
+			Status: metav1.ConditionUnknown,
+			Reason: "StatusUnknown",
+		}
+		if hcp != nil {
+			if hcpCondition := meta.FindStatusCondition(hcp.Status.Conditions, string(hyperv1.CertificatesExpiring)); hcpCondition != nil {
+				condition.Status = hcpCondition.Status
+				condition.Reason = hcpCondition.Reason
+				condition.Message = hcpCondition.Message
 			}
 		}
-		hcluster.Status.Version = computeClusterVersionStatus(r.Clock, hcluster, hcp)
+		condition.ObservedGeneration = hcluster.Generation
+		meta.SetStatusCondition(&hcluster.Status.Conditions, condition)
 	}
 
-	// Reconcile unmanaged etcd client tls secret validation error status. Note only update status on validation error case to
-	// provide clear status to the user on the resource without having to look at operator logs.
+	// Set the konnectivity images and the KonnectivityAvailable condition from the control plane
 	{
-		if hcluster.Spec.Etcd.ManagementType == hyperv1.Unmanaged {
-			unmanagedEtcdTLSClientSecret := &corev1.Secret{
-				ObjectMeta: metav1.ObjectMeta{
-					Namespace: hcluster.GetNamespace(),
-					Name:      hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name,
-				},
-			}
-			if err := r.Client.Get(ctx, client.ObjectKeyFromObject(unmanagedEtcdTLSClientSecret), unmanagedEtcdTLSClientSecret); err != nil {
-				if apierrors.IsNotFound(err) {
-					unmanagedEtcdTLSClientSecret = nil
-				} else {
-					return ctrl.Result{}, fmt.Errorf("failed to get unmanaged etcd tls secret: %w", err)
-				}
+		condition := metav1.Condition{
+			Type:   string(hyperv1.HostedClusterKonnectivityAvailable),
+			Status: metav1.ConditionUnknown,
+			Reason: "StatusUnknown",
+		}
+		if hcp != nil {
+			hcluster.Status.KonnectivityImages = hcp.Status.KonnectivityImages.DeepCopy()
+			if hcpCondition := meta.FindStatusCondition(hcp.Status.Conditions, string(hyperv1.KonnectivityAvailable)); hcpCondition != nil {
+				condition.Status = hcpCondition.Status
+				condition.Reason = hcpCondition.Reason
+				condition.Message = hcpCondition.Message
 			}
-			meta.SetStatusCondition(&hcluster.Status.Conditions, computeUnmanagedEtcdAvailability(hcluster, unmanagedEtcdTLSClientSecret))
 		}
+		condition.ObservedGeneration = hcluster.Generation
+		meta.SetStatusCondition(&hcluster.Status.Conditions, condition)
 	}
 
-	// Set the Available condition
-	// TODO: This is really setting something that could be more granular like
-	// HostedControlPlaneAvailable, and then the HostedCluster high-level Available
-	// condition could be computed as a function of the granular ThingAvailable
-	// conditions (so that it could incorporate e.g. HostedControlPlane and IgnitionServer
-	// availability in the ultimate HostedCluster Available condition)
+	// Set the ValidAuditPolicy condition from the control plane
 	{
-		controlPlaneNamespace := manifests.HostedControlPlaneNamespace(hcluster.Namespace, hcluster.Name)
-		hcp := controlplaneoperator.HostedControlPlane(controlPlaneNamespace.Name, hcluster.Name)
-		err := r.Client.Get(ctx, client.ObjectKeyFromObject(hcp), hcp)
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				hcp = nil
-			} else {
-				return ctrl.Result{}, fmt.Errorf("failed to get hostedcontrolplane: %w", err)
+		condition := metav1.Condition{
+			Type:   string(hyperv1.HostedClusterValidAuditPolicy),
+			Status: metav1.ConditionUnknown,
+			Reason: "StatusUnknown",
+		}
+		if hcp != nil {
+			if hcpCondition := meta.FindStatusCondition(hcp.Status.Conditions, string(hyperv1.ValidAuditPolicy)); hcpCondition != nil {
+				condition.Status = hcpCondition.Status
+				condition.Reason = hcpCondition.Reason
+				condition.Message = hcpCondition.Message
 			}
 		}
-		meta.SetStatusCondition(&hcluster.Status.Conditions, computeHostedClusterAvailability(hcluster, hcp))
+		condition.ObservedGeneration = hcluster.Generation
+		meta.SetStatusCondition(&hcluster.Status.Conditions, condition)
 	}
 
 	// Set ValidConfiguration condition
+	immutableFieldsChanged := false
 	{
-		controlPlaneNamespace := manifests.HostedControlPlaneNamespace(hcluster.Namespace, hcluster.Name)
-		hcp := controlplaneoperator.HostedControlPlane(controlPlaneNamespace.Name, hcluster.Name)
-		err := r.Client.Get(ctx, client.ObjectKeyFromObject(hcp), hcp)
-		if err != nil {
-			if apierrors.IsNotFound(err) {
-				hcp = nil
-			} else {
-				return ctrl.Result{}, fmt.Errorf("failed to get hostedcontrolplane: %w", err)
-			}
-		}
 		condition := metav1.Condition{
 			Type:   string(hyperv1.ValidHostedClusterConfiguration),
 			Status: metav1.ConditionUnknown,
@@ -281,6 +298,14 @@ func (r *HostedClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 				condition.Message = validConfigHCPCondition.Message
 				condition.Reason = validConfigHCPCondition.Reason
 			}
+			// An immutable field change which got past admission (e.g. because the
+			// webhook wasn't running) takes precedence over the control plane state.
+			if err := validateImmutableFieldsUnchanged(hcluster, hcp); err != nil {
+				condition.Status = metav1.ConditionFalse
+				condition.Message = err.Error()
+				condition.Reason = hyperv1.ImmutableFieldChangedReason
+				immutableFieldsChanged = true
+			}
 		}
 		meta.SetStatusCondition(&hcluster.Status.Conditions, condition)
 	}
@@ -374,6 +399,29 @@ func (r *HostedClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		span.AddEvent("updated ignition endpoint condition", trace.WithAttributes(attribute.String(newCondition.Type, string(newCondition.Status))))
 	}
 
+	// Set the size of the cluster from its number of nodes. The overrides of the size are
+	// applied to the HostedControlPlane together with the overrides of the HostedCluster.
+	var sizeOverrides []hyperv1.ControlPlaneComponentOverride
+	var downsizeRequeue time.Duration
+	{
+		sizing, err := r.getClusterSizingConfiguration(ctx)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to get cluster sizing configuration: %w", err)
+		}
+		if sizing == nil {
+			hcluster.Status.Size = nil
+		} else {
+			nodePools, err := r.listNodePools(hcluster.Namespace, hcluster.Name)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			hcluster.Status.Size, downsizeRequeue = computeClusterSize(&sizing.Spec, hcluster.Status.Size, clusterNodeCount(hcp, nodePools), r.Clock.Now())
+			if hcluster.Status.Size != nil {
+				sizeOverrides = sizeComponentOverrides(&sizing.Spec, hcluster.Status.Size.Name)
+			}
+		}
+	}
+
 	// Persist status updates
 	if err := r.Client.Status().Update(ctx, hcluster); err != nil {
 		if apierrors.IsConflict(err) {
@@ -384,6 +432,14 @@ func (r *HostedClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	// Part two: reconcile the state of the world
 
+	// Don't propagate changes to immutable fields, they would regenerate PKI and
+	// CAPI resources and break the guest cluster. The ValidConfiguration condition
+	// reports the problem. An update event will trigger reconciliation.
+	if immutableFieldsChanged {
+		r.Log.Info("hostedcluster has changes to immutable fields, skipping reconciliation")
+		return ctrl.Result{}, nil
+	}
+
 	// Ensure the cluster has a finalizer for cleanup and update right away.
 	if !controllerutil.ContainsFinalizer(hcluster, finalizer) {
 		controllerutil.AddFinalizer(hcluster, finalizer)
@@ -516,6 +572,80 @@ func (r *HostedClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}
 
+	// Reconcile the HostedControlPlane audit policy if specified by resolving the source
+	// config map reference from the HostedCluster and syncing it in the control plane namespace.
+	if hcluster.Spec.Audit != nil && hcluster.Spec.Audit.Policy != nil && len(hcluster.Spec.Audit.Policy.Name) > 0 {
+		var src corev1.ConfigMap
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: hcluster.GetNamespace(), Name: hcluster.Spec.Audit.Policy.Name}, &src); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to get audit policy %s: %w", hcluster.Spec.Audit.Policy.Name, err)
+		}
+		policy, ok := src.Data[hyperv1.AuditPolicyConfigMapKey]
+		if !ok {
+			return ctrl.Result{}, fmt.Errorf("audit policy config map does not contain key %s", hyperv1.AuditPolicyConfigMapKey)
+		}
+		dest := controlplaneoperator.UserAuditPolicy(controlPlaneNamespace.Name)
+		_, err = controllerutil.CreateOrUpdate(ctx, r.Client, dest, func() error {
+			dest.Data = map[string]string{hyperv1.AuditPolicyConfigMapKey: policy}
+			return nil
+		})
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed reconciling audit policy config map: %w", err)
+		}
+	}
+
+	// Reconcile the CA and credentials of the HostedControlPlane audit log backend
+	// if specified by syncing them in the control plane namespace.
+	if hcluster.Spec.Audit != nil && hcluster.Spec.Audit.LogBackend != nil {
+		backend := hcluster.Spec.Audit.LogBackend
+		if backend.CA != nil && len(backend.CA.Name) > 0 {
+			var src corev1.ConfigMap
+			if err := r.Client.Get(ctx, client.ObjectKey{Namespace: hcluster.GetNamespace(), Name: backend.CA.Name}, &src); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to get audit log CA %s: %w", backend.CA.Name, err)
+			}
+			ca, ok := src.Data[hyperv1.AuditLogCAConfigMapKey]
+			if !ok {
+				return ctrl.Result{}, fmt.Errorf("audit log CA config map does not contain key %s", hyperv1.AuditLogCAConfigMapKey)
+			}
+			dest := controlplaneoperator.UserAuditLogCA(controlPlaneNamespace.Name)
+			_, err = controllerutil.CreateOrUpdate(ctx, r.Client, dest, func() error {
+				dest.Data = map[string]string{hyperv1.AuditLogCAConfigMapKey: ca}
+				return nil
+			})
+			if err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed reconciling audit log CA config map: %w", err)
+			}
+		}
+		if backend.Credentials != nil && len(backend.Credentials.Name) > 0 {
+			var src corev1.Secret
+			if err := r.Client.Get(ctx, client.ObjectKey{Namespace: hcluster.GetNamespace(), Name: backend.Credentials.Name}, &src); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to get audit log credentials %s: %w", backend.Credentials.Name, err)
+			}
+			data := map[string][]byte{}
+			for _, key := range []string{hyperv1.AuditLogTokenSecretKey, corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+				if value, ok := src.Data[key]; ok {
+					data[key] = value
+				}
+			}
+			_, hasCert := data[corev1.TLSCertKey]
+			_, hasKey := data[corev1.TLSPrivateKeyKey]
+			if hasCert != hasKey {
+				return ctrl.Result{}, fmt.Errorf("audit log credentials secret must contain both keys %s and %s", corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+			}
+			if len(data) == 0 {
+				return ctrl.Result{}, fmt.Errorf("audit log credentials secret must contain key %s or keys %s and %s", hyperv1.AuditLogTokenSecretKey, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+			}
+			dest := controlplaneoperator.UserAuditLogCredentials(controlPlaneNamespace.Name)
+			_, err = controllerutil.CreateOrUpdate(ctx, r.Client, dest, func() error {
+				dest.Type = corev1.SecretTypeOpaque
+				dest.Data = data
+				return nil
+			})
+			if err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed reconciling audit log credentials secret: %w", err)
+			}
+		}
+	}
+
 	// Reconcile the HostedControlPlane signing key by resolving the source secret
 	// reference from the HostedCluster and syncing the secret in the control plane namespace.
 	if len(hcluster.Spec.SigningKey.Name) > 0 {
@@ -598,6 +728,29 @@ func (r *HostedClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}
 
+	// Reconcile etcd backup credentials if managed etcd is backed up to S3
+	if hcluster.Spec.Etcd.ManagementType == hyperv1.Managed && hcluster.Spec.Etcd.Managed != nil &&
+		hcluster.Spec.Etcd.Managed.Backup != nil && hcluster.Spec.Etcd.Managed.Backup.Destination.S3 != nil {
+		credentialsName := hcluster.Spec.Etcd.Managed.Backup.Destination.S3.Credentials.Name
+		src := &corev1.Secret{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: hcluster.Namespace, Name: credentialsName}, src); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to get etcd backup credentials secret %s: %w", credentialsName, err)
+		}
+		dest := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: controlPlaneNamespace.Name,
+				Name:      credentialsName,
+			},
+		}
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, dest, func() error {
+			dest.Data = src.Data
+			dest.Type = corev1.SecretTypeOpaque
+			return nil
+		}); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to reconcile etcd backup credentials secret: %w", err)
+		}
+	}
+
 	// Reconcile global config related configmaps and secrets
 	{
 		if hcluster.Spec.Configuration != nil {
@@ -644,14 +797,31 @@ func (r *HostedClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	// Reconcile the HostedControlPlane
-	hcp := controlplaneoperator.HostedControlPlane(controlPlaneNamespace.Name, hcluster.Name)
+	hcp = controlplaneoperator.HostedControlPlane(controlPlaneNamespace.Name, hcluster.Name)
 	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, hcp, func() error {
-		return reconcileHostedControlPlane(hcp, hcluster)
+		if err := reconcileHostedControlPlane(hcp, hcluster); err != nil {
+			return err
+		}
+		hcp.Spec.ComponentOverrides = mergeComponentOverrides(sizeOverrides, hcluster.Spec.ComponentOverrides)
+		return nil
 	})
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to reconcile hostedcontrolplane: %w", err)
 	}
 
+	// The escape hatch for immutable fields only covers the change it was set
+	// with. Now that the change has been propagated, remove it so that later
+	// changes are validated again.
+	if _, ok := hcluster.Annotations[hyperv1.SkipImmutableFieldValidationAnnotation]; ok {
+		delete(hcluster.Annotations, hyperv1.SkipImmutableFieldValidationAnnotation)
+		if err := r.Update(ctx, hcluster); err != nil {
+			if apierrors.IsConflict(err) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("failed to remove the %s annotation: %w", hyperv1.SkipImmutableFieldValidationAnnotation, err)
+		}
+	}
+
 	var infraCR client.Object
 	switch hcluster.Spec.Platform.Type {
 	// We run the AWS controller for NonePlatform for now
@@ -772,7 +942,12 @@ func (r *HostedClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	r.Log.Info("successfully reconciled")
-	return ctrl.Result{}, nil
+	result := ctrl.Result{}
+	if downsizeRequeue > 0 && (result.RequeueAfter == 0 || downsizeRequeue < result.RequeueAfter) {
+		// Move the cluster to a smaller size once the downsize delay has passed
+		result.RequeueAfter = downsizeRequeue
+	}
+	return result, nil
 }
 
 // reconcileHostedControlPlane reconciles the given HostedControlPlane, which
@@ -796,7 +971,7 @@ func reconcileHostedControlPlane(hcp *hyperv1.HostedControlPlane, hcluster *hype
 			hcp.Annotations[annotationKey] = hcluster.Annotations[annotationKey]
 		} else if annotationKey == hyperv1.KonnectivityAgentImageAnnotation || annotationKey == hyperv1.KonnectivityServerImageAnnotation {
 			hcp.Annotations[annotationKey] = hcluster.Annotations[annotationKey]
-		} else if annotationKey == hyperv1.RestartDateAnnotation {
+		} else if annotationKey == hyperv1.RestartDateAnnotation || annotationKey == hyperv1.EncryptionKeyRotationAnnotation {
 			hcp.Annotations[annotationKey] = hcluster.Annotations[annotationKey]
 		}
 	}
@@ -810,6 +985,21 @@ func reconcileHostedControlPlane(hcp *hyperv1.HostedControlPlane, hcluster *hype
 	if hcluster.Spec.AuditWebhook != nil && len(hcluster.Spec.AuditWebhook.Name) > 0 {
 		hcp.Spec.AuditWebhook = hcluster.Spec.AuditWebhook.DeepCopy()
 	}
+	hcp.Spec.Audit = hcluster.Spec.Audit.DeepCopy()
+	if audit := hcp.Spec.Audit; audit != nil {
+		if audit.Policy != nil && len(audit.Policy.Name) > 0 {
+			audit.Policy = &corev1.LocalObjectReference{Name: controlplaneoperator.UserAuditPolicy(hcp.Namespace).Name}
+		}
+		if backend := audit.LogBackend; backend != nil {
+			if backend.CA != nil && len(backend.CA.Name) > 0 {
+				backend.CA = &corev1.LocalObjectReference{Name: controlplaneoperator.UserAuditLogCA(hcp.Namespace).Name}
+			}
+			if backend.Credentials != nil && len(backend.Credentials.Name) > 0 {
+				backend.Credentials = &corev1.LocalObjectReference{Name: controlplaneoperator.UserAuditLogCredentials(hcp.Namespace).Name}
+			}
+		}
+	}
+	hcp.Spec.ImageOverrides = hcluster.Spec.ImageOverrides
 	hcp.Spec.FIPS = hcluster.Spec.FIPS
 	hcp.Spec.IssuerURL = hcluster.Spec.IssuerURL
 	hcp.Spec.ServiceCIDR = hcluster.Spec.Networking.ServiceCIDR
@@ -863,6 +1053,10 @@ func reconcileHostedControlPlane(hcp *hyperv1.HostedControlPlane, hcluster *hype
 	}
 
 	hcp.Spec.Configuration = hcluster.Spec.Configuration.DeepCopy()
+	hcp.Spec.SecretEncryption = hcluster.Spec.SecretEncryption.DeepCopy()
+	hcp.Spec.CertificateRotation = hcluster.Spec.CertificateRotation.DeepCopy()
+	hcp.Spec.KeyAlgorithm = hcluster.Spec.KeyAlgorithm
+	hcp.Spec.SigningKeyOverlapDuration = hcluster.Spec.SigningKeyOverlapDuration.DeepCopy()
 	return nil
 }
 
@@ -897,11 +1091,15 @@ func (r *HostedClusterReconciler) reconcileCAPIManager(ctx context.Context, hclu
 		if err != nil {
 			return fmt.Errorf("failed to generate CA (cn=%s,ou=%s): %w", cn, ou, err)
 		}
+		keyBytes, err := certs.SignerToPem(key)
+		if err != nil {
+			return fmt.Errorf("failed to encode CA key (cn=%s,ou=%s): %w", cn, ou, err)
+		}
 		if capiWebhooksTLSSecret.Data == nil {
 			capiWebhooksTLSSecret.Data = map[string][]byte{}
 		}
 		capiWebhooksTLSSecret.Data[corev1.TLSCertKey] = certs.CertToPem(crt)
-		capiWebhooksTLSSecret.Data[corev1.TLSPrivateKeyKey] = certs.PrivateKeyToPem(key)
+		capiWebhooksTLSSecret.Data[corev1.TLSPrivateKeyKey] = keyBytes
 		return nil
 	})
 	if err != nil {
@@ -952,10 +1150,7 @@ func (r *HostedClusterReconciler) reconcileCAPIManager(ctx context.Context, hclu
 	}
 
 	// Reconcile CAPI manager deployment
-	capiImage := imageCAPI
-	if _, ok := hcluster.Annotations[hyperv1.ClusterAPIManagerImage]; ok {
-		capiImage = hcluster.Annotations[hyperv1.ClusterAPIManagerImage]
-	}
+	capiImage := componentImage(hcluster, hyperv1.ClusterAPIImageComponent, hyperv1.ClusterAPIManagerImage, imageCAPI)
 	capiManagerDeployment := clusterapi.ClusterAPIManagerDeployment(controlPlaneNamespace.Name)
 	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, capiManagerDeployment, func() error {
 		// TODO (alberto): This image builds from https://github.com/kubernetes-sigs/cluster-api/pull/4709
@@ -1008,7 +1203,7 @@ func (r *HostedClusterReconciler) reconcileCAPIAWSProvider(ctx context.Context,
 	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, capiAwsProviderDeployment, func() error {
 		// TODO (alberto): This image builds from https://github.com/kubernetes-sigs/cluster-api-provider-aws/pull/2453
 		// We need to build from main branch and push to quay.io/hypershift once this is merged or otherwise enable webhooks.
-		return reconcileCAPIAWSProviderDeployment(capiAwsProviderDeployment, capiAwsProviderServiceAccount)
+		return reconcileCAPIAWSProviderDeployment(capiAwsProviderDeployment, capiAwsProviderServiceAccount, componentImage(hcluster, hyperv1.ClusterAPIProviderAWSImageComponent, "", imageCAPA))
 	})
 	if err != nil {
 		return fmt.Errorf("failed to reconcile capi aws provider deployment: %w", err)
@@ -1076,7 +1271,7 @@ func (r *HostedClusterReconciler) reconcileControlPlaneOperator(ctx context.Cont
 		restartDateAnnotation = hcluster.Annotations[hyperv1.RestartDateAnnotation]
 	}
 	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, controlPlaneOperatorDeployment, func() error {
-		return reconcileControlPlaneOperatorDeployment(controlPlaneOperatorDeployment, r.HostedControlPlaneOperatorImage, controlPlaneOperatorServiceAccount, restartDateAnnotation)
+		return reconcileControlPlaneOperatorDeployment(controlPlaneOperatorDeployment, r.HostedControlPlaneOperatorImage, r.KonnectivityImage, controlPlaneOperatorServiceAccount, restartDateAnnotation)
 	})
 	if err != nil {
 		return fmt.Errorf("failed to reconcile controlplane operator deployment: %w", err)
@@ -1085,6 +1280,35 @@ func (r *HostedClusterReconciler) reconcileControlPlaneOperator(ctx context.Cont
 	return nil
 }
 
+// validateImmutableFieldsUnchanged returns an error if any of the HostedCluster
+// fields which are immutable after creation differ from what was propagated to
+// the HostedControlPlane, unless the escape hatch annotation is set.
+func validateImmutableFieldsUnchanged(hcluster *hyperv1.HostedCluster, hcp *hyperv1.HostedControlPlane) error {
+	if _, skip := hcluster.Annotations[hyperv1.SkipImmutableFieldValidationAnnotation]; skip {
+		return nil
+	}
+	previous := &hyperv1.HostedCluster{
+		Spec: hyperv1.HostedClusterSpec{
+			Networking: hyperv1.ClusterNetworking{
+				ServiceCIDR: hcp.Spec.ServiceCIDR,
+				PodCIDR:     hcp.Spec.PodCIDR,
+				MachineCIDR: hcp.Spec.MachineCIDR,
+			},
+			Platform: hyperv1.PlatformSpec{Type: hcp.Spec.Platform.Type},
+			InfraID:  hcp.Spec.InfraID,
+			DNS:      hyperv1.DNSSpec{BaseDomain: hcp.Spec.DNS.BaseDomain},
+		},
+	}
+	var errs []string
+	for _, change := range immutableFields(hcluster, previous) {
+		errs = append(errs, fmt.Sprintf("%s is immutable, changed from %q to %q", change.path, change.oldValue, change.newValue))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s; set the %s annotation to force the change", strings.Join(errs, ", "), hyperv1.SkipImmutableFieldValidationAnnotation)
+	}
+	return nil
+}
+
 func servicePublishingStrategyByType(hcp *hyperv1.HostedCluster, svcType hyperv1.ServiceType) *hyperv1.ServicePublishingStrategy {
 	for _, mapping := range hcp.Spec.Services {
 		if mapping.Service == svcType {
@@ -1201,10 +1425,14 @@ func (r *HostedClusterReconciler) reconcileIgnitionServer(ctx context.Context, h
 			if err != nil {
 				return fmt.Errorf("failed to generate CA: %w", err)
 			}
+			keyBytes, err := certs.SignerToPem(key)
+			if err != nil {
+				return fmt.Errorf("failed to encode CA key: %w", err)
+			}
 			caCertSecret.Type = corev1.SecretTypeTLS
 			caCertSecret.Data = map[string][]byte{
 				corev1.TLSCertKey:       certs.CertToPem(crt),
-				corev1.TLSPrivateKeyKey: certs.PrivateKeyToPem(key),
+				corev1.TLSPrivateKeyKey: keyBytes,
 			}
 		}
 		return nil
@@ -1224,7 +1452,7 @@ func (r *HostedClusterReconciler) reconcileIgnitionServer(ctx context.Context, h
 			if err != nil {
 				return fmt.Errorf("couldn't get ca cert: %w", err)
 			}
-			caKey, err := certs.PemToPrivateKey(caCertSecret.Data[corev1.TLSPrivateKeyKey])
+			caKey, err := certs.PemToSigner(caCertSecret.Data[corev1.TLSPrivateKeyKey])
 			if err != nil {
 				return fmt.Errorf("couldn't get ca key: %w", err)
 			}
@@ -1238,10 +1466,14 @@ func (r *HostedClusterReconciler) reconcileIgnitionServer(ctx context.Context, h
 			if err != nil {
 				return fmt.Errorf("failed to generate ignition serving cert: %w", err)
 			}
+			keyBytes, err := certs.SignerToPem(key)
+			if err != nil {
+				return fmt.Errorf("failed to encode ignition serving key: %w", err)
+			}
 			servingCertSecret.Type = corev1.SecretTypeTLS
 			servingCertSecret.Data = map[string][]byte{
 				corev1.TLSCertKey:       certs.CertToPem(crt),
-				corev1.TLSPrivateKeyKey: certs.PrivateKeyToPem(key),
+				corev1.TLSPrivateKeyKey: keyBytes,
 			}
 		}
 		return nil
@@ -1484,10 +1716,7 @@ func (r *HostedClusterReconciler) reconcileAutoscaler(ctx context.Context, hclus
 		}
 
 		// Reconcile autoscaler deployment
-		clusterAutoScalerImage := imageClusterAutoscaler
-		if _, ok := hcluster.Annotations[hyperv1.ClusterAutoscalerImage]; ok {
-			clusterAutoScalerImage = hcluster.Annotations[hyperv1.ClusterAutoscalerImage]
-		}
+		clusterAutoScalerImage := componentImage(hcluster, hyperv1.ClusterAutoscalerImageComponent, hyperv1.ClusterAutoscalerImage, imageClusterAutoscaler)
 		autoScalerDeployment := autoscaler.AutoScalerDeployment(controlPlaneNamespace.Name)
 		_, err = controllerutil.CreateOrUpdate(ctx, r.Client, autoScalerDeployment, func() error {
 			return reconcileAutoScalerDeployment(autoScalerDeployment, autoScalerServiceAccount, capiKubeConfigSecret, hcluster.Spec.Autoscaling, clusterAutoScalerImage)
@@ -1500,7 +1729,7 @@ func (r *HostedClusterReconciler) reconcileAutoscaler(ctx context.Context, hclus
 	return nil
 }
 
-func reconcileControlPlaneOperatorDeployment(deployment *appsv1.Deployment, image string, sa *corev1.ServiceAccount, restartDateAnnotation string) error {
+func reconcileControlPlaneOperatorDeployment(deployment *appsv1.Deployment, image, konnectivityImage string, sa *corev1.ServiceAccount, restartDateAnnotation string) error {
 	deployment.Spec = appsv1.DeploymentSpec{
 		Replicas: k8sutilspointer.Int32Ptr(1),
 		Selector: &metav1.LabelSelector{
@@ -1542,6 +1771,10 @@ func reconcileControlPlaneOperatorDeployment(deployment *appsv1.Deployment, imag
 			},
 		},
 	}
+	if len(konnectivityImage) > 0 {
+		container := &deployment.Spec.Template.Spec.Containers[0]
+		container.Args = append(container.Args, "--konnectivity-image", konnectivityImage)
+	}
 	if len(restartDateAnnotation) > 0 {
 		if deployment.Spec.Template.Annotations == nil {
 			deployment.Spec.Template.Annotations = make(map[string]string)
@@ -1635,14 +1868,30 @@ func reconcileControlPlaneOperatorRole(role *rbacv1.Role) error {
 				"nodes",
 				"serviceaccounts",
 				"services",
+				"persistentvolumeclaims",
 			},
 			Verbs: []string{"*"},
 		},
 		{
 			APIGroups: []string{"apps"},
-			Resources: []string{"deployments"},
+			Resources: []string{
+				"deployments",
+				"statefulsets",
+			},
+			Verbs: []string{"*"},
+		},
+		{
+			APIGroups: []string{"batch"},
+			Resources: []string{"cronjobs", "jobs"},
+			Verbs:     []string{"*"},
+		},
+		{
+			APIGroups: []string{"policy"},
+			Resources: []string{"poddisruptionbudgets"},
 			Verbs:     []string{"*"},
 		},
+		// Managed etcd clusters created by the etcd operator keep running
+		// with it.
 		{
 			APIGroups: []string{"etcd.database.coreos.com"},
 			Resources: []string{"*"},
@@ -1747,6 +1996,21 @@ func reconcileCAPICluster(cluster *capiv1.Cluster, hcluster *hyperv1.HostedClust
 	return nil
 }
 
+// componentImage returns the image of a component the HostedCluster runs in
+// its control plane namespace. An image override takes precedence over the
+// deprecated image annotation of the component, if it has one.
+func componentImage(hcluster *hyperv1.HostedCluster, component hyperv1.ImageOverrideComponent, annotation string, defaultImage string) string {
+	for _, override := range hcluster.Spec.ImageOverrides {
+		if override.Component == component {
+			return override.Image
+		}
+	}
+	if image, ok := hcluster.Annotations[annotation]; ok && len(annotation) > 0 {
+		return image
+	}
+	return defaultImage
+}
+
 func reconcileCAPIManagerDeployment(deployment *appsv1.Deployment, sa *corev1.ServiceAccount, capiManagerImage string) error {
 	defaultMode := int32(420)
 	deployment.Spec = appsv1.DeploymentSpec{
@@ -1900,7 +2164,7 @@ func reconcileCAPIManagerRoleBinding(binding *rbacv1.RoleBinding, role *rbacv1.R
 	return nil
 }
 
-func reconcileCAPIAWSProviderDeployment(deployment *appsv1.Deployment, sa *corev1.ServiceAccount) error {
+func reconcileCAPIAWSProviderDeployment(deployment *appsv1.Deployment, sa *corev1.ServiceAccount, capaImage string) error {
 	defaultMode := int32(420)
 	deployment.Spec = appsv1.DeploymentSpec{
 		Replicas: k8sutilspointer.Int32Ptr(1),
@@ -1946,7 +2210,7 @@ func reconcileCAPIAWSProviderDeployment(deployment *appsv1.Deployment, sa *corev
 				Containers: []corev1.Container{
 					{
 						Name:            "manager",
-						Image:           imageCAPA,
+						Image:           capaImage,
 						ImagePullPolicy: corev1.PullAlways,
 						VolumeMounts: []corev1.VolumeMount{
 							{
@@ -2329,52 +2593,43 @@ func computeHostedClusterAvailability(hcluster *hyperv1.HostedCluster, hcp *hype
 }
 
 // computeUnmanagedEtcdAvailability calculates the current status of unmanaged etcd.
-func computeUnmanagedEtcdAvailability(hcluster *hyperv1.HostedCluster, unmanagedEtcdTLSClientSecret *corev1.Secret) metav1.Condition {
-	if unmanagedEtcdTLSClientSecret == nil {
-		return metav1.Condition{
-			Type:    string(hyperv1.UnmanagedEtcdAvailable),
-			Status:  metav1.ConditionFalse,
-			Reason:  hyperv1.UnmanagedEtcdMisconfiguredReason,
-			Message: fmt.Sprintf("missing TLS client secret %s", hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name),
-		}
-	}
+// Once the client tls secret is validated, the etcd endpoint is probed with it.
+func computeUnmanagedEtcdAvailability(ctx context.Context, hcluster *hyperv1.HostedCluster, unmanagedEtcdTLSClientSecret *corev1.Secret, now metav1.Time) (metav1.Condition, *hyperv1.UnmanagedEtcdStatus) {
 	if hcluster.Spec.Etcd.Unmanaged == nil || len(hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name) == 0 || len(hcluster.Spec.Etcd.Unmanaged.Endpoint) == 0 {
-		return metav1.Condition{
-			Type:    string(hyperv1.UnmanagedEtcdAvailable),
-			Status:  metav1.ConditionFalse,
-			Reason:  hyperv1.UnmanagedEtcdMisconfiguredReason,
-			Message: "etcd metadata not specified for unmanaged deployment",
-		}
+		return unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdMisconfiguredReason, "etcd metadata not specified for unmanaged deployment"), nil
+	}
+	if unmanagedEtcdTLSClientSecret == nil {
+		return unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdMisconfiguredReason, fmt.Sprintf("missing TLS client secret %s", hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name)), nil
 	}
 	if _, ok := unmanagedEtcdTLSClientSecret.Data["etcd-client.crt"]; !ok {
-		return metav1.Condition{
-			Type:    string(hyperv1.UnmanagedEtcdAvailable),
-			Status:  metav1.ConditionFalse,
-			Reason:  hyperv1.UnmanagedEtcdMisconfiguredReason,
-			Message: fmt.Sprintf("etcd secret %s does not have client cert", hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name),
-		}
+		return unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdMisconfiguredReason, fmt.Sprintf("etcd secret %s does not have client cert", hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name)), nil
 	}
 	if _, ok := unmanagedEtcdTLSClientSecret.Data["etcd-client.key"]; !ok {
-		return metav1.Condition{
-			Type:    string(hyperv1.UnmanagedEtcdAvailable),
-			Status:  metav1.ConditionFalse,
-			Reason:  hyperv1.UnmanagedEtcdMisconfiguredReason,
-			Message: fmt.Sprintf("etcd secret %s does not have client key", hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name),
-		}
+		return unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdMisconfiguredReason, fmt.Sprintf("etcd secret %s does not have client key", hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name)), nil
 	}
 	if _, ok := unmanagedEtcdTLSClientSecret.Data["etcd-client-ca.crt"]; !ok {
-		return metav1.Condition{
-			Type:    string(hyperv1.UnmanagedEtcdAvailable),
-			Status:  metav1.ConditionFalse,
-			Reason:  hyperv1.UnmanagedEtcdMisconfiguredReason,
-			Message: fmt.Sprintf("etcd secret %s does not have client ca", hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name),
-		}
+		return unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdMisconfiguredReason, fmt.Sprintf("etcd secret %s does not have client ca", hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name)), nil
 	}
-	return metav1.Condition{
-		Type:   string(hyperv1.UnmanagedEtcdAvailable),
-		Status: metav1.ConditionTrue,
-		Reason: hyperv1.UnmanagedEtcdAsExpected,
+	tlsConfig, err := supportetcd.NewTLSConfig(unmanagedEtcdTLSClientSecret.Data["etcd-client.crt"], unmanagedEtcdTLSClientSecret.Data["etcd-client.key"], unmanagedEtcdTLSClientSecret.Data["etcd-client-ca.crt"])
+	if err != nil {
+		return unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdMisconfiguredReason, fmt.Sprintf("etcd secret %s is invalid: %v", hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name, err)), nil
+	}
+	status, condition := probeUnmanagedEtcd(ctx, hcluster.Spec.Etcd.Unmanaged.Endpoint, tlsConfig, now)
+	return condition, status
+}
+
+// getHostedControlPlane returns the HostedControlPlane of the cluster, or nil
+// if it doesn't exist.
+func (r *HostedClusterReconciler) getHostedControlPlane(ctx context.Context, hcluster *hyperv1.HostedCluster) (*hyperv1.HostedControlPlane, error) {
+	controlPlaneNamespace := manifests.HostedControlPlaneNamespace(hcluster.Namespace, hcluster.Name)
+	hcp := controlplaneoperator.HostedControlPlane(controlPlaneNamespace.Name, hcluster.Name)
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(hcp), hcp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get hostedcontrolplane: %w", err)
 	}
+	return hcp, nil
 }
 
 func (r *HostedClusterReconciler) listNodePools(clusterNamespace, clusterName string) ([]hyperv1.NodePool, error) {
@@ -685,3 +685,91 @@ func TestReconcileIgnitionServerServiceRoute(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateImmutableFieldsUnchanged(t *testing.T) {
+	hcp := &hyperv1.HostedControlPlane{
+		Spec: hyperv1.HostedControlPlaneSpec{
+			ServiceCIDR: "172.31.0.0/16",
+			PodCIDR:     "10.132.0.0/14",
+			MachineCIDR: "10.0.0.0/16",
+			InfraID:     "infra",
+			Platform:    hyperv1.PlatformSpec{Type: hyperv1.AWSPlatform},
+			DNS:         hyperv1.DNSSpec{BaseDomain: "example.com"},
+		},
+	}
+	hcluster := func(annotations map[string]string, machineCIDR string) *hyperv1.HostedCluster {
+		return &hyperv1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+			Spec: hyperv1.HostedClusterSpec{
+				Networking: hyperv1.ClusterNetworking{
+					ServiceCIDR: "172.31.0.0/16",
+					PodCIDR:     "10.132.0.0/14",
+					MachineCIDR: machineCIDR,
+				},
+				InfraID:  "infra",
+				Platform: hyperv1.PlatformSpec{Type: hyperv1.AWSPlatform},
+				DNS:      hyperv1.DNSSpec{BaseDomain: "example.com"},
+			},
+		}
+	}
+	tests := map[string]struct {
+		Cluster     *hyperv1.HostedCluster
+		ExpectError bool
+	}{
+		"unchanged fields are valid": {
+			Cluster:     hcluster(nil, "10.0.0.0/16"),
+			ExpectError: false,
+		},
+		"changed machine CIDR is invalid": {
+			Cluster:     hcluster(nil, "10.1.0.0/16"),
+			ExpectError: true,
+		},
+		"changed machine CIDR with escape hatch annotation is valid": {
+			Cluster:     hcluster(map[string]string{hyperv1.SkipImmutableFieldValidationAnnotation: "true"}, "10.1.0.0/16"),
+			ExpectError: false,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := validateImmutableFieldsUnchanged(test.Cluster, hcp)
+			if test.ExpectError != (err != nil) {
+				t.Errorf("expected error: %t, got: %v", test.ExpectError, err)
+			}
+		})
+	}
+}
+
+func TestComponentImage(t *testing.T) {
+	tests := map[string]struct {
+		Annotations    map[string]string
+		ImageOverrides []hyperv1.ImageOverride
+		ExpectedImage  string
+	}{
+		"default image": {
+			ExpectedImage: imageClusterAutoscaler,
+		},
+		"deprecated annotation": {
+			Annotations:   map[string]string{hyperv1.ClusterAutoscalerImage: "quay.io/example/autoscaler:annotation"},
+			ExpectedImage: "quay.io/example/autoscaler:annotation",
+		},
+		"image override takes precedence over the annotation": {
+			Annotations: map[string]string{hyperv1.ClusterAutoscalerImage: "quay.io/example/autoscaler:annotation"},
+			ImageOverrides: []hyperv1.ImageOverride{
+				{Component: hyperv1.ClusterAPIImageComponent, Image: "quay.io/example/capi:override"},
+				{Component: hyperv1.ClusterAutoscalerImageComponent, Image: "quay.io/example/autoscaler:override"},
+			},
+			ExpectedImage: "quay.io/example/autoscaler:override",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			hcluster := &hyperv1.HostedCluster{}
+			hcluster.Annotations = test.Annotations
+			hcluster.Spec.ImageOverrides = test.ImageOverrides
+			image := componentImage(hcluster, hyperv1.ClusterAutoscalerImageComponent, hyperv1.ClusterAutoscalerImage, imageClusterAutoscaler)
+			if image != test.ExpectedImage {
+				t.Errorf("expected image %s, got %s", test.ExpectedImage, image)
+			}
+		})
+	}
+}
@@ -0,0 +1,536 @@
+package hostedcluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/hypershift-operator/webhooks"
+	"github.com/openshift/hypershift/support/thirdparty/library-go/pkg/image/reference"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhookWithManager registers the HostedCluster and
+// ClusterSizingConfiguration admission webhooks with the manager's webhook
+// server.
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	server := mgr.GetWebhookServer()
+	server.Register(webhooks.HostedClusterMutatingPath, &webhook.Admission{Handler: &hostedClusterDefaulter{}})
+	server.Register(webhooks.HostedClusterValidatingPath, &webhook.Admission{Handler: &hostedClusterValidator{}})
+	server.Register(webhooks.ClusterSizingConfigurationValidatingPath, &webhook.Admission{Handler: &clusterSizingValidator{}})
+	return nil
+}
+
+// hostedClusterDefaulter applies defaults to HostedClusters at admission time.
+type hostedClusterDefaulter struct {
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &hostedClusterDefaulter{}
+var _ admission.DecoderInjector = &hostedClusterDefaulter{}
+
+func (d *hostedClusterDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+func (d *hostedClusterDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	hcluster := &hyperv1.HostedCluster{}
+	if err := d.decoder.Decode(req, hcluster); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	defaultHostedCluster(hcluster)
+	marshaled, err := json.Marshal(hcluster)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// hostedClusterValidator rejects invalid HostedClusters at admission time.
+type hostedClusterValidator struct {
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &hostedClusterValidator{}
+var _ admission.DecoderInjector = &hostedClusterValidator{}
+
+func (v *hostedClusterValidator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+func (v *hostedClusterValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	hcluster := &hyperv1.HostedCluster{}
+	if err := v.decoder.Decode(req, hcluster); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	var errs field.ErrorList
+	if req.Operation == admissionv1.Update {
+		oldHCluster := &hyperv1.HostedCluster{}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldHCluster); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// Finalizers are removed with updates, so HostedClusters being
+		// deleted or only changing their metadata are not held to rules
+		// they may predate.
+		if hcluster.DeletionTimestamp != nil || equality.Semantic.DeepEqual(oldHCluster.Spec, hcluster.Spec) {
+			return admission.Allowed("")
+		}
+		errs = append(errs, validateHostedClusterUpdate(hcluster, oldHCluster)...)
+	}
+	errs = append(validateHostedCluster(hcluster), errs...)
+	if len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// defaultServicePublishingStrategies returns the service publishing strategies
+// used when a HostedCluster doesn't specify any.
+func defaultServicePublishingStrategies() []hyperv1.ServicePublishingStrategyMapping {
+	return []hyperv1.ServicePublishingStrategyMapping{
+		{
+			Service:                   hyperv1.APIServer,
+			ServicePublishingStrategy: hyperv1.ServicePublishingStrategy{Type: hyperv1.LoadBalancer},
+		},
+		{
+			Service:                   hyperv1.OAuthServer,
+			ServicePublishingStrategy: hyperv1.ServicePublishingStrategy{Type: hyperv1.Route},
+		},
+		{
+			Service:                   hyperv1.OIDC,
+			ServicePublishingStrategy: hyperv1.ServicePublishingStrategy{Type: hyperv1.Route},
+		},
+		{
+			Service:                   hyperv1.Konnectivity,
+			ServicePublishingStrategy: hyperv1.ServicePublishingStrategy{Type: hyperv1.LoadBalancer},
+		},
+		{
+			Service:                   hyperv1.Ignition,
+			ServicePublishingStrategy: hyperv1.ServicePublishingStrategy{Type: hyperv1.Route},
+		},
+	}
+}
+
+// defaultHostedCluster fills in unset fields of a HostedCluster with the same
+// defaults the CLI and the API fixtures use.
+func defaultHostedCluster(hcluster *hyperv1.HostedCluster) {
+	if len(hcluster.Spec.Networking.NetworkType) == 0 {
+		hcluster.Spec.Networking.NetworkType = hyperv1.OpenShiftSDN
+	}
+	if len(hcluster.Spec.Etcd.ManagementType) == 0 {
+		hcluster.Spec.Etcd.ManagementType = hyperv1.Managed
+	}
+	if hcluster.Spec.Etcd.ManagementType == hyperv1.Managed && hcluster.Spec.Etcd.Managed == nil {
+		hcluster.Spec.Etcd.Managed = &hyperv1.ManagedEtcdSpec{}
+	}
+	// Only default the full set of services. A partial list is most likely a
+	// mistake and is left for validation to reject.
+	if len(hcluster.Spec.Services) == 0 {
+		hcluster.Spec.Services = defaultServicePublishingStrategies()
+	}
+}
+
+// requiredServices are the services every HostedCluster must specify a
+// publishing strategy for.
+var requiredServices = []hyperv1.ServiceType{
+	hyperv1.APIServer,
+	hyperv1.OAuthServer,
+	hyperv1.Konnectivity,
+	hyperv1.Ignition,
+}
+
+// validateHostedCluster returns every problem found in the spec of a
+// HostedCluster which would otherwise only surface during reconciliation.
+func validateHostedCluster(hcluster *hyperv1.HostedCluster) field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if len(hcluster.Spec.Release.Image) == 0 {
+		errs = append(errs, field.Required(specPath.Child("release", "image"), "a release image is required"))
+	}
+	errs = append(errs, validateNetworking(&hcluster.Spec.Networking, specPath.Child("networking"))...)
+	errs = append(errs, validateServices(hcluster.Spec.Services, specPath.Child("services"))...)
+	errs = append(errs, validateEtcd(&hcluster.Spec.Etcd, specPath.Child("etcd"))...)
+	errs = append(errs, validatePlatform(&hcluster.Spec.Platform, specPath.Child("platform"))...)
+	errs = append(errs, validateComponentOverrides(hcluster.Spec.ComponentOverrides, specPath.Child("componentOverrides"))...)
+	errs = append(errs, validateImageOverrides(hcluster.Spec.ImageOverrides, specPath.Child("imageOverrides"))...)
+	if hcluster.Spec.Audit != nil && hcluster.Spec.Audit.LogBackend != nil {
+		errs = append(errs, validateAuditLogBackend(hcluster.Spec.Audit.LogBackend, specPath.Child("audit", "logBackend"))...)
+	}
+	if hcluster.Spec.SecretEncryption != nil && hcluster.Spec.SecretEncryption.KMS != nil {
+		errs = append(errs, validateKMS(hcluster.Spec.SecretEncryption.KMS, specPath.Child("secretEncryption", "kms"))...)
+	}
+
+	return errs
+}
+
+func validateNetworking(networking *hyperv1.ClusterNetworking, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	for _, cidr := range []struct {
+		name  string
+		value string
+	}{
+		{name: "serviceCIDR", value: networking.ServiceCIDR},
+		{name: "podCIDR", value: networking.PodCIDR},
+		{name: "machineCIDR", value: networking.MachineCIDR},
+	} {
+		if len(cidr.value) == 0 {
+			errs = append(errs, field.Required(path.Child(cidr.name), ""))
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr.value); err != nil {
+			errs = append(errs, field.Invalid(path.Child(cidr.name), cidr.value, err.Error()))
+		}
+	}
+	switch networking.NetworkType {
+	case hyperv1.OpenShiftSDN, hyperv1.Calico:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("networkType"), networking.NetworkType,
+			[]string{string(hyperv1.OpenShiftSDN), string(hyperv1.Calico)}))
+	}
+	return errs
+}
+
+func validateServices(services []hyperv1.ServicePublishingStrategyMapping, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	seen := map[hyperv1.ServiceType]bool{}
+	for i, mapping := range services {
+		idxPath := path.Index(i)
+		switch mapping.Service {
+		case hyperv1.APIServer, hyperv1.OAuthServer, hyperv1.OIDC, hyperv1.Konnectivity, hyperv1.Ignition:
+		default:
+			errs = append(errs, field.NotSupported(idxPath.Child("service"), mapping.Service,
+				[]string{string(hyperv1.APIServer), string(hyperv1.OAuthServer), string(hyperv1.OIDC), string(hyperv1.Konnectivity), string(hyperv1.Ignition)}))
+			continue
+		}
+		if seen[mapping.Service] {
+			errs = append(errs, field.Duplicate(idxPath.Child("service"), mapping.Service))
+			continue
+		}
+		seen[mapping.Service] = true
+
+		strategyPath := idxPath.Child("servicePublishingStrategy")
+		switch mapping.Type {
+		case hyperv1.LoadBalancer, hyperv1.Route, hyperv1.None:
+		case hyperv1.NodePort:
+			if mapping.NodePort == nil || len(mapping.NodePort.Address) == 0 {
+				errs = append(errs, field.Required(strategyPath.Child("nodePort", "address"),
+					fmt.Sprintf("the %s publishing strategy requires an address", hyperv1.NodePort)))
+			}
+		default:
+			errs = append(errs, field.NotSupported(strategyPath.Child("type"), mapping.Type,
+				[]string{string(hyperv1.LoadBalancer), string(hyperv1.NodePort), string(hyperv1.Route), string(hyperv1.None)}))
+			continue
+		}
+
+		// The ignition server can only be exposed through a route or a node port,
+		// see reconcileIgnitionServerService.
+		if mapping.Service == hyperv1.Ignition && mapping.Type != hyperv1.Route && mapping.Type != hyperv1.NodePort {
+			errs = append(errs, field.NotSupported(strategyPath.Child("type"), mapping.Type,
+				[]string{string(hyperv1.Route), string(hyperv1.NodePort)}))
+		}
+	}
+	for _, svcType := range requiredServices {
+		if !seen[svcType] {
+			errs = append(errs, field.Required(path, fmt.Sprintf("a publishing strategy for the %s service is required", svcType)))
+		}
+	}
+	return errs
+}
+
+func validateEtcd(etcd *hyperv1.EtcdSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	switch etcd.ManagementType {
+	case hyperv1.Managed:
+		if etcd.Managed != nil {
+			errs = append(errs, validateManagedEtcd(etcd.Managed, path.Child("managed"))...)
+		}
+	case hyperv1.Unmanaged:
+		if etcd.Unmanaged == nil {
+			errs = append(errs, field.Required(path.Child("unmanaged"), "unmanaged etcd requires an endpoint and TLS configuration"))
+			break
+		}
+		if len(etcd.Unmanaged.Endpoint) == 0 {
+			errs = append(errs, field.Required(path.Child("unmanaged", "endpoint"), ""))
+		}
+		if len(etcd.Unmanaged.TLS.ClientSecret.Name) == 0 {
+			errs = append(errs, field.Required(path.Child("unmanaged", "tls", "clientSecret", "name"), ""))
+		}
+	default:
+		errs = append(errs, field.NotSupported(path.Child("managementType"), etcd.ManagementType,
+			[]string{string(hyperv1.Managed), string(hyperv1.Unmanaged)}))
+	}
+	return errs
+}
+
+func validateManagedEtcd(managed *hyperv1.ManagedEtcdSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if storage := managed.Storage; storage != nil {
+		storagePath := path.Child("storage")
+		switch storage.Type {
+		case hyperv1.PersistentVolumeEtcdStorage:
+			if pv := storage.PersistentVolume; pv != nil && pv.Size != nil && pv.Size.Sign() <= 0 {
+				errs = append(errs, field.Invalid(storagePath.Child("persistentVolume", "size"), pv.Size.String(), "must be greater than zero"))
+			}
+		case hyperv1.EphemeralEtcdStorage:
+			if storage.PersistentVolume != nil {
+				errs = append(errs, field.Forbidden(storagePath.Child("persistentVolume"), fmt.Sprintf("not allowed with %s storage", hyperv1.EphemeralEtcdStorage)))
+			}
+		default:
+			errs = append(errs, field.NotSupported(storagePath.Child("type"), storage.Type,
+				[]string{string(hyperv1.PersistentVolumeEtcdStorage), string(hyperv1.EphemeralEtcdStorage)}))
+		}
+	}
+	if backup := managed.Backup; backup != nil {
+		backupPath := path.Child("backup")
+		if backup.Schedule.Duration <= 0 {
+			errs = append(errs, field.Invalid(backupPath.Child("schedule"), backup.Schedule.Duration.String(), "must be greater than zero"))
+		}
+		destinationPath := backupPath.Child("destination")
+		switch backup.Destination.Type {
+		case hyperv1.S3EtcdBackupDestination:
+			s3 := backup.Destination.S3
+			if s3 == nil {
+				errs = append(errs, field.Required(destinationPath.Child("s3"), fmt.Sprintf("the %s destination requires S3 configuration", hyperv1.S3EtcdBackupDestination)))
+				break
+			}
+			if len(s3.Bucket) == 0 {
+				errs = append(errs, field.Required(destinationPath.Child("s3", "bucket"), ""))
+			}
+			if len(s3.Credentials.Name) == 0 {
+				errs = append(errs, field.Required(destinationPath.Child("s3", "credentials", "name"), ""))
+			}
+		case hyperv1.PersistentVolumeEtcdBackupDestination:
+			pv := backup.Destination.PersistentVolume
+			if pv == nil {
+				errs = append(errs, field.Required(destinationPath.Child("persistentVolume"), fmt.Sprintf("the %s destination requires persistent volume configuration", hyperv1.PersistentVolumeEtcdBackupDestination)))
+				break
+			}
+			if pv.Size.Sign() <= 0 {
+				errs = append(errs, field.Invalid(destinationPath.Child("persistentVolume", "size"), pv.Size.String(), "must be greater than zero"))
+			}
+		default:
+			errs = append(errs, field.NotSupported(destinationPath.Child("type"), backup.Destination.Type,
+				[]string{string(hyperv1.S3EtcdBackupDestination), string(hyperv1.PersistentVolumeEtcdBackupDestination)}))
+		}
+	}
+	if managed.Restore != nil && managed.Backup == nil {
+		errs = append(errs, field.Invalid(path.Child("restore"), managed.Restore.SnapshotName, "restoring requires a backup policy"))
+	}
+	return errs
+}
+
+func validatePlatform(platform *hyperv1.PlatformSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	switch platform.Type {
+	case hyperv1.AWSPlatform:
+		if platform.AWS == nil {
+			errs = append(errs, field.Required(path.Child("aws"), fmt.Sprintf("the %s platform requires AWS configuration", hyperv1.AWSPlatform)))
+		}
+	case hyperv1.NonePlatform, hyperv1.IBMCloudPlatform:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("type"), platform.Type,
+			[]string{string(hyperv1.AWSPlatform), string(hyperv1.NonePlatform), string(hyperv1.IBMCloudPlatform)}))
+	}
+	return errs
+}
+
+func validateComponentOverrides(overrides []hyperv1.ControlPlaneComponentOverride, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	known := sets.NewString(hyperv1.ControlPlaneComponents...)
+	for i, override := range overrides {
+		idxPath := path.Index(i)
+		if !known.Has(override.Name) {
+			errs = append(errs, field.NotSupported(idxPath.Child("name"), override.Name, hyperv1.ControlPlaneComponents))
+			continue
+		}
+		// The size of etcd follows the availability policy, members are not
+		// added or removed to match an override
+		if override.Name == hyperv1.EtcdComponentName && override.Replicas != nil {
+			errs = append(errs, field.Forbidden(idxPath.Child("replicas"), "the replicas of etcd can't be overridden"))
+		}
+	}
+	return errs
+}
+
+func validateImageOverrides(overrides []hyperv1.ImageOverride, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	components := make([]string, 0, len(hyperv1.ImageOverrideComponents))
+	for _, component := range hyperv1.ImageOverrideComponents {
+		components = append(components, string(component))
+	}
+	known := sets.NewString(components...)
+	seen := map[hyperv1.ImageOverrideComponent]bool{}
+	for i, override := range overrides {
+		idxPath := path.Index(i)
+		if !known.Has(string(override.Component)) {
+			errs = append(errs, field.NotSupported(idxPath.Child("component"), override.Component, components))
+			continue
+		}
+		if seen[override.Component] {
+			errs = append(errs, field.Duplicate(idxPath.Child("component"), override.Component))
+			continue
+		}
+		seen[override.Component] = true
+		if len(override.Image) == 0 {
+			errs = append(errs, field.Required(idxPath.Child("image"), ""))
+			continue
+		}
+		if _, err := reference.Parse(override.Image); err != nil {
+			errs = append(errs, field.Invalid(idxPath.Child("image"), override.Image, err.Error()))
+		}
+	}
+	return errs
+}
+
+func validateAuditLogBackend(backend *hyperv1.AuditLogBackendSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	sinkPath := path.Child("sink")
+	switch backend.Sink.Type {
+	case hyperv1.PersistentVolumeAuditSink:
+		pv := backend.Sink.PersistentVolume
+		if pv == nil {
+			errs = append(errs, field.Required(sinkPath.Child("persistentVolume"), fmt.Sprintf("the %s sink requires persistent volume configuration", hyperv1.PersistentVolumeAuditSink)))
+			break
+		}
+		// The volume is mounted by every apiserver replica, the default storage
+		// class can't be assumed to support that
+		if len(pv.StorageClassName) == 0 {
+			errs = append(errs, field.Required(sinkPath.Child("persistentVolume", "storageClassName"), "a storage class supporting the ReadWriteMany access mode is required"))
+		}
+		if pv.Size.Sign() <= 0 {
+			errs = append(errs, field.Invalid(sinkPath.Child("persistentVolume", "size"), pv.Size.String(), "must be greater than zero"))
+		}
+		if backend.CA != nil {
+			errs = append(errs, field.Forbidden(path.Child("ca"), fmt.Sprintf("not allowed with the %s sink", hyperv1.PersistentVolumeAuditSink)))
+		}
+		if backend.Credentials != nil {
+			errs = append(errs, field.Forbidden(path.Child("credentials"), fmt.Sprintf("not allowed with the %s sink", hyperv1.PersistentVolumeAuditSink)))
+		}
+	case hyperv1.SyslogAuditSink:
+		syslog := backend.Sink.Syslog
+		if syslog == nil {
+			errs = append(errs, field.Required(sinkPath.Child("syslog"), fmt.Sprintf("the %s sink requires syslog configuration", hyperv1.SyslogAuditSink)))
+			break
+		}
+		if _, _, err := net.SplitHostPort(syslog.Address); err != nil {
+			errs = append(errs, field.Invalid(sinkPath.Child("syslog", "address"), syslog.Address, err.Error()))
+		}
+		if backend.CA != nil && syslog.Protocol == corev1.ProtocolUDP {
+			errs = append(errs, field.Invalid(sinkPath.Child("syslog", "protocol"), syslog.Protocol, "syslog over TLS requires TCP"))
+		}
+		if backend.Credentials != nil && backend.CA == nil {
+			errs = append(errs, field.Required(path.Child("ca"), "client certificates are only presented to syslog servers reached over TLS"))
+		}
+	case hyperv1.HTTPAuditSink:
+		if backend.Sink.HTTP == nil {
+			errs = append(errs, field.Required(sinkPath.Child("http"), fmt.Sprintf("the %s sink requires HTTP configuration", hyperv1.HTTPAuditSink)))
+			break
+		}
+		endpoint, err := url.Parse(backend.Sink.HTTP.URL)
+		if err != nil {
+			errs = append(errs, field.Invalid(sinkPath.Child("http", "url"), backend.Sink.HTTP.URL, err.Error()))
+			break
+		}
+		switch endpoint.Scheme {
+		case "https":
+		case "http":
+			if backend.CA != nil || backend.Credentials != nil {
+				errs = append(errs, field.Invalid(sinkPath.Child("http", "url"), backend.Sink.HTTP.URL, "a CA or credentials require an https URL"))
+			}
+		default:
+			errs = append(errs, field.Invalid(sinkPath.Child("http", "url"), backend.Sink.HTTP.URL, "must be an http or https URL"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(sinkPath.Child("type"), backend.Sink.Type,
+			[]string{string(hyperv1.PersistentVolumeAuditSink), string(hyperv1.SyslogAuditSink), string(hyperv1.HTTPAuditSink)}))
+	}
+	return errs
+}
+
+// validateKMS mirrors the checks the control plane operator applies to the KMS
+// configuration before encrypting resources with it.
+func validateKMS(kms *hyperv1.KMSSpec, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	switch kms.Provider {
+	case hyperv1.AWSKMSProvider:
+		if kms.AWS == nil {
+			errs = append(errs, field.Required(path.Child("aws"), fmt.Sprintf("the %s provider requires AWS configuration", hyperv1.AWSKMSProvider)))
+			break
+		}
+		awsPath := path.Child("aws")
+		if len(kms.AWS.KeyARN) == 0 {
+			errs = append(errs, field.Required(awsPath.Child("keyARN"), ""))
+		}
+		if len(kms.AWS.Region) == 0 {
+			errs = append(errs, field.Required(awsPath.Child("region"), ""))
+		}
+		if len(kms.AWS.Auth.RoleARN) == 0 {
+			errs = append(errs, field.Required(awsPath.Child("auth", "roleARN"), ""))
+		}
+	default:
+		errs = append(errs, field.NotSupported(path.Child("provider"), kms.Provider, []string{string(hyperv1.AWSKMSProvider)}))
+	}
+	return errs
+}
+
+// validateHostedClusterUpdate rejects changes to fields which can't be changed
+// once a HostedCluster has been created, unless the escape hatch annotation is
+// set on the updated HostedCluster.
+func validateHostedClusterUpdate(hcluster, oldHCluster *hyperv1.HostedCluster) field.ErrorList {
+	var errs field.ErrorList
+	// The volumes of the managed etcd members are claimed when the etcd
+	// StatefulSet is created and can't be changed afterwards, so there is no
+	// escape hatch for the etcd storage.
+	if oldManaged, managed := oldHCluster.Spec.Etcd.Managed, hcluster.Spec.Etcd.Managed; oldManaged != nil && managed != nil &&
+		!equality.Semantic.DeepEqual(oldManaged.Storage, managed.Storage) {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "etcd", "managed", "storage"), "the storage of managed etcd is immutable"))
+	}
+	if _, skip := hcluster.Annotations[hyperv1.SkipImmutableFieldValidationAnnotation]; skip {
+		return errs
+	}
+	for _, f := range immutableFields(hcluster, oldHCluster) {
+		errs = append(errs, field.Forbidden(f.path, fmt.Sprintf("field is immutable, changed from %q to %q", f.oldValue, f.newValue)))
+	}
+	return errs
+}
+
+type immutableFieldChange struct {
+	path     *field.Path
+	oldValue string
+	newValue string
+}
+
+// immutableFields returns the immutable fields that differ between a
+// HostedCluster and its previous version.
+func immutableFields(hcluster, oldHCluster *hyperv1.HostedCluster) []immutableFieldChange {
+	specPath := field.NewPath("spec")
+	candidates := []immutableFieldChange{
+		{path: specPath.Child("networking", "serviceCIDR"), oldValue: oldHCluster.Spec.Networking.ServiceCIDR, newValue: hcluster.Spec.Networking.ServiceCIDR},
+		{path: specPath.Child("networking", "podCIDR"), oldValue: oldHCluster.Spec.Networking.PodCIDR, newValue: hcluster.Spec.Networking.PodCIDR},
+		{path: specPath.Child("networking", "machineCIDR"), oldValue: oldHCluster.Spec.Networking.MachineCIDR, newValue: hcluster.Spec.Networking.MachineCIDR},
+		{path: specPath.Child("platform", "type"), oldValue: string(oldHCluster.Spec.Platform.Type), newValue: string(hcluster.Spec.Platform.Type)},
+		{path: specPath.Child("infraID"), oldValue: oldHCluster.Spec.InfraID, newValue: hcluster.Spec.InfraID},
+		{path: specPath.Child("dns", "baseDomain"), oldValue: oldHCluster.Spec.DNS.BaseDomain, newValue: hcluster.Spec.DNS.BaseDomain},
+	}
+	var changes []immutableFieldChange
+	for _, c := range candidates {
+		if c.oldValue != c.newValue {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
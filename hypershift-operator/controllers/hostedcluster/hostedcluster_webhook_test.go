@@ -0,0 +1,557 @@
+package hostedcluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+func validHostedCluster() *hyperv1.HostedCluster {
+	hcluster := &hyperv1.HostedCluster{
+		Spec: hyperv1.HostedClusterSpec{
+			Release: hyperv1.Release{
+				Image: "quay.io/openshift-release-dev/ocp-release:4.8.6-x86_64",
+			},
+			Networking: hyperv1.ClusterNetworking{
+				ServiceCIDR: "172.31.0.0/16",
+				PodCIDR:     "10.132.0.0/14",
+				MachineCIDR: "10.0.0.0/16",
+			},
+			Platform: hyperv1.PlatformSpec{
+				Type: hyperv1.AWSPlatform,
+				AWS:  &hyperv1.AWSPlatformSpec{Region: "us-east-1"},
+			},
+		},
+	}
+	defaultHostedCluster(hcluster)
+	return hcluster
+}
+
+func validS3EtcdBackupPolicy() *hyperv1.EtcdBackupPolicy {
+	return &hyperv1.EtcdBackupPolicy{
+		Schedule:   metav1.Duration{Duration: time.Hour},
+		MaxBackups: 3,
+		Destination: hyperv1.EtcdBackupDestination{
+			Type: hyperv1.S3EtcdBackupDestination,
+			S3: &hyperv1.EtcdBackupS3Destination{
+				Bucket:      "backups",
+				Prefix:      "etcd",
+				Credentials: corev1.LocalObjectReference{Name: "etcd-backup-credentials"},
+			},
+		},
+	}
+}
+
+func TestDefaultHostedCluster(t *testing.T) {
+	g := NewWithT(t)
+	hcluster := &hyperv1.HostedCluster{}
+	defaultHostedCluster(hcluster)
+	g.Expect(hcluster.Spec.Networking.NetworkType).To(Equal(hyperv1.OpenShiftSDN))
+	g.Expect(hcluster.Spec.Etcd.ManagementType).To(Equal(hyperv1.Managed))
+	g.Expect(hcluster.Spec.Etcd.Managed).ToNot(BeNil())
+	g.Expect(hcluster.Spec.Services).To(Equal(defaultServicePublishingStrategies()))
+
+	// Explicit settings are preserved.
+	hcluster = &hyperv1.HostedCluster{
+		Spec: hyperv1.HostedClusterSpec{
+			Networking: hyperv1.ClusterNetworking{NetworkType: hyperv1.Calico},
+			Etcd:       hyperv1.EtcdSpec{ManagementType: hyperv1.Unmanaged},
+			Services: []hyperv1.ServicePublishingStrategyMapping{
+				{
+					Service:                   hyperv1.APIServer,
+					ServicePublishingStrategy: hyperv1.ServicePublishingStrategy{Type: hyperv1.NodePort},
+				},
+			},
+		},
+	}
+	defaultHostedCluster(hcluster)
+	g.Expect(hcluster.Spec.Networking.NetworkType).To(Equal(hyperv1.Calico))
+	g.Expect(hcluster.Spec.Etcd.ManagementType).To(Equal(hyperv1.Unmanaged))
+	g.Expect(hcluster.Spec.Etcd.Managed).To(BeNil())
+	g.Expect(hcluster.Spec.Services).To(HaveLen(1))
+}
+
+func TestValidateHostedCluster(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(*hyperv1.HostedCluster)
+		error  bool
+	}{
+		{
+			name:   "it passes with a valid hostedCluster",
+			mutate: func(*hyperv1.HostedCluster) {},
+			error:  false,
+		},
+		{
+			name: "it fails with no release image",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Release.Image = ""
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a malformed service CIDR",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Networking.ServiceCIDR = "172.31.0.0"
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a missing machine CIDR",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Networking.MachineCIDR = ""
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an unknown network type",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Networking.NetworkType = "bad"
+			},
+			error: true,
+		},
+		{
+			name: "it fails with no ignition service strategy",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Services = hcluster.Spec.Services[:len(hcluster.Spec.Services)-1]
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an unknown publishing strategy",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Services[0].Type = "bad"
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a load balancer ignition service",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Services[4].Type = hyperv1.LoadBalancer
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a duplicate service",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Services = append(hcluster.Spec.Services, hcluster.Spec.Services[0])
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a node port strategy and no address",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Services[0].Type = hyperv1.NodePort
+			},
+			error: true,
+		},
+		{
+			name: "it passes with a node port strategy and an address",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Services[0].Type = hyperv1.NodePort
+				hcluster.Spec.Services[0].NodePort = &hyperv1.NodePortPublishingStrategy{Address: "10.0.0.1"}
+			},
+			error: false,
+		},
+		{
+			name: "it fails with unmanaged etcd and no endpoint",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd = hyperv1.EtcdSpec{
+					ManagementType: hyperv1.Unmanaged,
+					Unmanaged: &hyperv1.UnmanagedEtcdSpec{
+						TLS: hyperv1.EtcdTLSConfig{ClientSecret: corev1.LocalObjectReference{Name: "etcd-client"}},
+					},
+				}
+			},
+			error: true,
+		},
+		{
+			name: "it passes with unmanaged etcd",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd = hyperv1.EtcdSpec{
+					ManagementType: hyperv1.Unmanaged,
+					Unmanaged: &hyperv1.UnmanagedEtcdSpec{
+						Endpoint: "https://etcd:2379",
+						TLS:      hyperv1.EtcdTLSConfig{ClientSecret: corev1.LocalObjectReference{Name: "etcd-client"}},
+					},
+				}
+			},
+			error: false,
+		},
+		{
+			name: "it passes with persistent etcd storage",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				size := resource.MustParse("8Gi")
+				hcluster.Spec.Etcd.Managed.Storage = &hyperv1.ManagedEtcdStorageSpec{
+					Type: hyperv1.PersistentVolumeEtcdStorage,
+					PersistentVolume: &hyperv1.PersistentVolumeEtcdStorageSpec{
+						StorageClassName: pointer.StringPtr("gp3"),
+						Size:             &size,
+					},
+				}
+			},
+			error: false,
+		},
+		{
+			name: "it fails with a zero etcd storage size",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				size := resource.MustParse("0")
+				hcluster.Spec.Etcd.Managed.Storage = &hyperv1.ManagedEtcdStorageSpec{
+					Type:             hyperv1.PersistentVolumeEtcdStorage,
+					PersistentVolume: &hyperv1.PersistentVolumeEtcdStorageSpec{Size: &size},
+				}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with ephemeral etcd storage and volume settings",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd.Managed.Storage = &hyperv1.ManagedEtcdStorageSpec{
+					Type:             hyperv1.EphemeralEtcdStorage,
+					PersistentVolume: &hyperv1.PersistentVolumeEtcdStorageSpec{StorageClassName: pointer.StringPtr("gp3")},
+				}
+			},
+			error: true,
+		},
+		{
+			name: "it passes with an S3 etcd backup and restore",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd.Managed.Backup = validS3EtcdBackupPolicy()
+				hcluster.Spec.Etcd.Managed.Restore = &hyperv1.EtcdRestoreSpec{SnapshotName: "etcd/etcd-snapshot_v1_2021-09-01-00:00:00"}
+			},
+			error: false,
+		},
+		{
+			name: "it fails with an S3 etcd backup and no bucket",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd.Managed.Backup = validS3EtcdBackupPolicy()
+				hcluster.Spec.Etcd.Managed.Backup.Destination.S3.Bucket = ""
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an etcd backup and no schedule",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd.Managed.Backup = validS3EtcdBackupPolicy()
+				hcluster.Spec.Etcd.Managed.Backup.Schedule.Duration = 0
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an etcd backup destination type that does not match its settings",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd.Managed.Backup = validS3EtcdBackupPolicy()
+				hcluster.Spec.Etcd.Managed.Backup.Destination.Type = hyperv1.PersistentVolumeEtcdBackupDestination
+			},
+			error: true,
+		},
+		{
+			name: "it passes with a persistent volume etcd backup",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd.Managed.Backup = &hyperv1.EtcdBackupPolicy{
+					Schedule: metav1.Duration{Duration: time.Hour},
+					Destination: hyperv1.EtcdBackupDestination{
+						Type:             hyperv1.PersistentVolumeEtcdBackupDestination,
+						PersistentVolume: &hyperv1.EtcdBackupPersistentVolumeDestination{Size: resource.MustParse("4Gi")},
+					},
+				}
+			},
+			error: false,
+		},
+		{
+			name: "it passes with an etcd restore from a persistent volume backup",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd.Managed.Backup = &hyperv1.EtcdBackupPolicy{
+					Schedule: metav1.Duration{Duration: time.Hour},
+					Destination: hyperv1.EtcdBackupDestination{
+						Type:             hyperv1.PersistentVolumeEtcdBackupDestination,
+						PersistentVolume: &hyperv1.EtcdBackupPersistentVolumeDestination{Size: resource.MustParse("4Gi")},
+					},
+				}
+				hcluster.Spec.Etcd.Managed.Restore = &hyperv1.EtcdRestoreSpec{SnapshotName: "snapshot.db"}
+			},
+			error: false,
+		},
+		{
+			name: "it fails with an etcd restore without a backup policy",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd.Managed.Restore = &hyperv1.EtcdRestoreSpec{SnapshotName: "snapshot.db"}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with AWS platform and no AWS settings",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Platform.AWS = nil
+			},
+			error: true,
+		},
+		{
+			name: "it passes with image overrides",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.ImageOverrides = []hyperv1.ImageOverride{
+					{Component: hyperv1.KonnectivityServerImageComponent, Image: "quay.io/example/konnectivity:fix"},
+					{Component: hyperv1.ClusterAutoscalerImageComponent, Image: "quay.io/example/autoscaler@sha256:56f8925ad141a545f9db1e8c2d4bb2f33d99145abe80e6950a134b490c82ae4b"},
+				}
+			},
+			error: false,
+		},
+		{
+			name: "it fails with an image override of an unknown component",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.ImageOverrides = []hyperv1.ImageOverride{{Component: "bad", Image: "quay.io/example/bad:latest"}}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with duplicate image overrides",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.ImageOverrides = []hyperv1.ImageOverride{
+					{Component: hyperv1.EtcdImageComponent, Image: "quay.io/example/etcd:a"},
+					{Component: hyperv1.EtcdImageComponent, Image: "quay.io/example/etcd:b"},
+				}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a malformed override image",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.ImageOverrides = []hyperv1.ImageOverride{{Component: hyperv1.EtcdImageComponent, Image: "quay.io/Example/etcd:"}}
+			},
+			error: true,
+		},
+		{
+			name: "it passes with component overrides",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.ComponentOverrides = []hyperv1.ControlPlaneComponentOverride{
+					{Name: "kube-apiserver", Replicas: pointer.Int32Ptr(3)},
+					{Name: "etcd", PriorityClassName: "etcd-critical"},
+				}
+			},
+			error: false,
+		},
+		{
+			name: "it fails with an override of an unknown component",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.ComponentOverrides = []hyperv1.ControlPlaneComponentOverride{{Name: "kube-apiservre", Replicas: pointer.Int32Ptr(3)}}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an override of the etcd replicas",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.ComponentOverrides = []hyperv1.ControlPlaneComponentOverride{{Name: "etcd", Replicas: pointer.Int32Ptr(5)}}
+			},
+			error: true,
+		},
+		{
+			name: "it passes with a persistent volume audit sink",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Audit = &hyperv1.AuditSpec{LogBackend: &hyperv1.AuditLogBackendSpec{
+					Sink: hyperv1.AuditSinkSpec{
+						Type:             hyperv1.PersistentVolumeAuditSink,
+						PersistentVolume: &hyperv1.AuditPersistentVolumeSinkSpec{StorageClassName: "nfs", Size: resource.MustParse("10Gi")},
+					},
+				}}
+			},
+			error: false,
+		},
+		{
+			name: "it fails with a persistent volume audit sink without a storage class",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Audit = &hyperv1.AuditSpec{LogBackend: &hyperv1.AuditLogBackendSpec{
+					Sink: hyperv1.AuditSinkSpec{
+						Type:             hyperv1.PersistentVolumeAuditSink,
+						PersistentVolume: &hyperv1.AuditPersistentVolumeSinkSpec{Size: resource.MustParse("10Gi")},
+					},
+				}}
+			},
+			error: true,
+		},
+		{
+			name: "it passes with an https audit sink with a CA and credentials",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Audit = &hyperv1.AuditSpec{LogBackend: &hyperv1.AuditLogBackendSpec{
+					Sink: hyperv1.AuditSinkSpec{
+						Type: hyperv1.HTTPAuditSink,
+						HTTP: &hyperv1.AuditHTTPSinkSpec{URL: "https://audit.example.com/events"},
+					},
+					CA:          &corev1.LocalObjectReference{Name: "audit-ca"},
+					Credentials: &corev1.LocalObjectReference{Name: "audit-credentials"},
+				}}
+			},
+			error: false,
+		},
+		{
+			name: "it fails with an http audit sink with credentials",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Audit = &hyperv1.AuditSpec{LogBackend: &hyperv1.AuditLogBackendSpec{
+					Sink: hyperv1.AuditSinkSpec{
+						Type: hyperv1.HTTPAuditSink,
+						HTTP: &hyperv1.AuditHTTPSinkSpec{URL: "http://audit.example.com/events"},
+					},
+					Credentials: &corev1.LocalObjectReference{Name: "audit-credentials"},
+				}}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a UDP syslog audit sink with a CA",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Audit = &hyperv1.AuditSpec{LogBackend: &hyperv1.AuditLogBackendSpec{
+					Sink: hyperv1.AuditSinkSpec{
+						Type:   hyperv1.SyslogAuditSink,
+						Syslog: &hyperv1.AuditSyslogSinkSpec{Address: "syslog.example.com:6514", Protocol: corev1.ProtocolUDP},
+					},
+					CA: &corev1.LocalObjectReference{Name: "audit-ca"},
+				}}
+			},
+			error: true,
+		},
+		{
+			name: "it passes with an AWS KMS key",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.SecretEncryption = &hyperv1.SecretEncryptionSpec{KMS: &hyperv1.KMSSpec{
+					Provider: hyperv1.AWSKMSProvider,
+					AWS: &hyperv1.AWSKMSSpec{
+						Region: "us-east-1",
+						KeyARN: "arn:aws:kms:us-east-1:123456789012:key/1234",
+						Auth:   hyperv1.AWSKMSAuthSpec{RoleARN: "arn:aws:iam::123456789012:role/kms"},
+					},
+				}}
+			},
+		},
+		{
+			name: "it fails with an AWS KMS provider and no AWS settings",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.SecretEncryption = &hyperv1.SecretEncryptionSpec{KMS: &hyperv1.KMSSpec{Provider: hyperv1.AWSKMSProvider}}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an AWS KMS key without a role",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.SecretEncryption = &hyperv1.SecretEncryptionSpec{KMS: &hyperv1.KMSSpec{
+					Provider: hyperv1.AWSKMSProvider,
+					AWS: &hyperv1.AWSKMSSpec{
+						Region: "us-east-1",
+						KeyARN: "arn:aws:kms:us-east-1:123456789012:key/1234",
+					},
+				}}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an unknown KMS provider",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.SecretEncryption = &hyperv1.SecretEncryptionSpec{KMS: &hyperv1.KMSSpec{Provider: "Vault"}}
+			},
+			error: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			hcluster := validHostedCluster()
+			tc.mutate(hcluster)
+			errs := validateHostedCluster(hcluster)
+			if tc.error {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
+
+func TestValidateHostedClusterUpdate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(*hyperv1.HostedCluster)
+		error  bool
+	}{
+		{
+			name: "it allows changing mutable fields",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Release.Image = "quay.io/openshift-release-dev/ocp-release:4.8.7-x86_64"
+			},
+			error: false,
+		},
+		{
+			name: "it rejects changing the service CIDR",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Networking.ServiceCIDR = "172.30.0.0/16"
+			},
+			error: true,
+		},
+		{
+			name: "it rejects changing the platform type",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Platform.Type = hyperv1.NonePlatform
+			},
+			error: true,
+		},
+		{
+			name: "it rejects changing the infraID",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.InfraID = "other"
+			},
+			error: true,
+		},
+		{
+			name: "it rejects changing the base domain",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.DNS.BaseDomain = "other.example.com"
+			},
+			error: true,
+		},
+		{
+			name: "it allows changing immutable fields with the escape hatch annotation",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Annotations = map[string]string{hyperv1.SkipImmutableFieldValidationAnnotation: "true"}
+				hcluster.Spec.InfraID = "other"
+			},
+			error: false,
+		},
+		{
+			name: "it rejects changing the managed etcd storage",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Spec.Etcd.Managed.Storage = &hyperv1.ManagedEtcdStorageSpec{Type: hyperv1.PersistentVolumeEtcdStorage}
+			},
+			error: true,
+		},
+		{
+			name: "it rejects changing the managed etcd storage with the escape hatch annotation",
+			mutate: func(hcluster *hyperv1.HostedCluster) {
+				hcluster.Annotations = map[string]string{hyperv1.SkipImmutableFieldValidationAnnotation: "true"}
+				hcluster.Spec.Etcd.Managed.Storage = &hyperv1.ManagedEtcdStorageSpec{Type: hyperv1.PersistentVolumeEtcdStorage}
+			},
+			error: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			oldHCluster := validHostedCluster()
+			oldHCluster.Spec.InfraID = "infra"
+			oldHCluster.Spec.DNS.BaseDomain = "example.com"
+			hcluster := oldHCluster.DeepCopy()
+			tc.mutate(hcluster)
+			errs := validateHostedClusterUpdate(hcluster, oldHCluster)
+			if tc.error {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
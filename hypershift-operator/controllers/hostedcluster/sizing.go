@@ -0,0 +1,190 @@
+package hostedcluster
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+const defaultDownsizeDelay = 30 * time.Minute
+
+// getClusterSizingConfiguration returns the cluster sizing configuration, or
+// nil if there is none.
+func (r *HostedClusterReconciler) getClusterSizingConfiguration(ctx context.Context) (*hyperv1.ClusterSizingConfiguration, error) {
+	sizing := &hyperv1.ClusterSizingConfiguration{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: hyperv1.ClusterSizingConfigurationName}, sizing); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return sizing, nil
+}
+
+// clusterNodeCount is the number of nodes of a hosted cluster. It is the
+// number of nodes the control plane observed in the hosted API server, or the
+// sum of the node counts of the node pools until the control plane has
+// reported it.
+func clusterNodeCount(hcp *hyperv1.HostedControlPlane, nodePools []hyperv1.NodePool) int32 {
+	if hcp != nil && hcp.Status.NodeCount != nil {
+		return *hcp.Status.NodeCount
+	}
+	var count int32
+	for _, nodePool := range nodePools {
+		count += nodePool.Status.NodeCount
+	}
+	return count
+}
+
+// computeClusterSize classifies a cluster with the given number of nodes into
+// one of the sizes of the sizing configuration. A cluster moves to a larger
+// size right away, but only moves to a smaller size once it has stayed more
+// than the downsize margin below the threshold of its current size for the
+// downsize delay. The delay starts over whenever the number of nodes recovers.
+// When a downsize is only held back by the delay, the time left until it can
+// happen is returned as well.
+func computeClusterSize(sizing *hyperv1.ClusterSizingConfigurationSpec, current *hyperv1.ClusterSizeStatus, nodeCount int32, now time.Time) (*hyperv1.ClusterSizeStatus, time.Duration) {
+	if len(sizing.Sizes) == 0 {
+		return nil, 0
+	}
+	sizes := make([]hyperv1.ClusterSize, len(sizing.Sizes))
+	copy(sizes, sizing.Sizes)
+	sort.SliceStable(sizes, func(i, j int) bool {
+		return sizes[i].FromNodeCount < sizes[j].FromNodeCount
+	})
+	target := 0
+	for i, size := range sizes {
+		if size.FromNodeCount <= nodeCount {
+			target = i
+		}
+	}
+
+	transition := &hyperv1.ClusterSizeStatus{
+		Name:               sizes[target].Name,
+		NodeCount:          nodeCount,
+		LastTransitionTime: metav1.NewTime(now),
+	}
+	if current == nil {
+		return transition, 0
+	}
+	currentIndex := -1
+	for i, size := range sizes {
+		if size.Name == current.Name {
+			currentIndex = i
+		}
+	}
+	if currentIndex < 0 || target > currentIndex {
+		return transition, 0
+	}
+
+	status := current.DeepCopy()
+	status.NodeCount = nodeCount
+	if target == currentIndex || nodeCount >= sizes[currentIndex].FromNodeCount-sizing.DownsizeMargin {
+		status.BelowThresholdSince = nil
+		return status, 0
+	}
+	if status.BelowThresholdSince == nil {
+		belowSince := metav1.NewTime(now)
+		status.BelowThresholdSince = &belowSince
+	}
+	delay := defaultDownsizeDelay
+	if sizing.DownsizeDelay != nil {
+		delay = sizing.DownsizeDelay.Duration
+	}
+	if remaining := status.BelowThresholdSince.Add(delay).Sub(now); remaining > 0 {
+		return status, remaining
+	}
+	return transition, 0
+}
+
+// sizeComponentOverrides returns the component overrides of the named size.
+func sizeComponentOverrides(sizing *hyperv1.ClusterSizingConfigurationSpec, name string) []hyperv1.ControlPlaneComponentOverride {
+	for _, size := range sizing.Sizes {
+		if size.Name == name {
+			return size.ComponentOverrides
+		}
+	}
+	return nil
+}
+
+// mergeComponentOverrides merges the component overrides of a HostedCluster
+// into the overrides of its size. Fields set by the HostedCluster take
+// precedence, tolerations of both are kept.
+func mergeComponentOverrides(sizeOverrides, clusterOverrides []hyperv1.ControlPlaneComponentOverride) []hyperv1.ControlPlaneComponentOverride {
+	if len(sizeOverrides) == 0 {
+		return clusterOverrides
+	}
+	var merged []hyperv1.ControlPlaneComponentOverride
+	index := map[string]int{}
+	for _, override := range sizeOverrides {
+		index[override.Name] = len(merged)
+		merged = append(merged, *override.DeepCopy())
+	}
+	for _, override := range clusterOverrides {
+		i, ok := index[override.Name]
+		if !ok {
+			merged = append(merged, *override.DeepCopy())
+			continue
+		}
+		base := &merged[i]
+		if override.Replicas != nil {
+			base.Replicas = override.Replicas
+		}
+		for _, resources := range override.Resources {
+			replaced := false
+			for j := range base.Resources {
+				if base.Resources[j].Container == resources.Container {
+					base.Resources[j] = *resources.DeepCopy()
+					replaced = true
+				}
+			}
+			if !replaced {
+				base.Resources = append(base.Resources, *resources.DeepCopy())
+			}
+		}
+		if len(override.NodeSelector) > 0 {
+			base.NodeSelector = override.NodeSelector
+		}
+		base.Tolerations = append(base.Tolerations, override.Tolerations...)
+		if override.PriorityClassName != "" {
+			base.PriorityClassName = override.PriorityClassName
+		}
+	}
+	return merged
+}
+
+// enqueueNodePoolHostedCluster enqueues the HostedCluster of a NodePool, so
+// the size of the cluster follows its number of nodes.
+func enqueueNodePoolHostedCluster(obj client.Object) []reconcile.Request {
+	nodePool, ok := obj.(*hyperv1.NodePool)
+	if !ok || nodePool.Spec.ClusterName == "" {
+		return []reconcile.Request{}
+	}
+	return []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: nodePool.Namespace, Name: nodePool.Spec.ClusterName}},
+	}
+}
+
+// enqueueAllHostedClusters enqueues every HostedCluster when the sizing
+// configuration changes.
+func (r *HostedClusterReconciler) enqueueAllHostedClusters(obj client.Object) []reconcile.Request {
+	hostedClusters := &hyperv1.HostedClusterList{}
+	if err := r.Client.List(context.TODO(), hostedClusters); err != nil {
+		ctrl.Log.Error(err, "failed to list hosted clusters")
+		return []reconcile.Request{}
+	}
+	requests := make([]reconcile.Request, 0, len(hostedClusters.Items))
+	for _, hcluster := range hostedClusters.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&hcluster)})
+	}
+	return requests
+}
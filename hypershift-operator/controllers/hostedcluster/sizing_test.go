@@ -0,0 +1,195 @@
+package hostedcluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+func TestComputeClusterSize(t *testing.T) {
+	now := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+	sizing := &hyperv1.ClusterSizingConfigurationSpec{
+		Sizes: []hyperv1.ClusterSize{
+			{Name: "large", FromNodeCount: 100},
+			{Name: "small", FromNodeCount: 0},
+			{Name: "medium", FromNodeCount: 10},
+		},
+		DownsizeMargin: 2,
+		DownsizeDelay:  &metav1.Duration{Duration: 10 * time.Minute},
+	}
+	sizedAt := func(name string, nodeCount int32, ago time.Duration) *hyperv1.ClusterSizeStatus {
+		return &hyperv1.ClusterSizeStatus{Name: name, NodeCount: nodeCount, LastTransitionTime: metav1.NewTime(now.Add(-ago))}
+	}
+	belowThresholdFor := func(status *hyperv1.ClusterSizeStatus, ago time.Duration) *hyperv1.ClusterSizeStatus {
+		since := metav1.NewTime(now.Add(-ago))
+		status.BelowThresholdSince = &since
+		return status
+	}
+
+	tests := []struct {
+		name            string
+		current         *hyperv1.ClusterSizeStatus
+		nodeCount       int32
+		expectedSize    string
+		expectedMoved   bool
+		expectedRequeue time.Duration
+		// expectedBelowSince is how long ago the cluster went below the
+		// downsize margin, nil if it isn't below it
+		expectedBelowSince *time.Duration
+	}{
+		{
+			name:          "new cluster",
+			nodeCount:     12,
+			expectedSize:  "medium",
+			expectedMoved: true,
+		},
+		{
+			name:          "upsize right away",
+			current:       sizedAt("small", 5, time.Minute),
+			nodeCount:     150,
+			expectedSize:  "large",
+			expectedMoved: true,
+		},
+		{
+			name:         "same size",
+			current:      sizedAt("medium", 12, time.Hour),
+			nodeCount:    50,
+			expectedSize: "medium",
+		},
+		{
+			name:         "within the downsize margin",
+			current:      sizedAt("medium", 12, time.Hour),
+			nodeCount:    8,
+			expectedSize: "medium",
+		},
+		{
+			name:               "downsize delay starts when the nodes drop below the margin",
+			current:            sizedAt("medium", 12, time.Hour),
+			nodeCount:          3,
+			expectedSize:       "medium",
+			expectedRequeue:    10 * time.Minute,
+			expectedBelowSince: durationPtr(0),
+		},
+		{
+			name:               "downsize held back by the delay",
+			current:            belowThresholdFor(sizedAt("medium", 3, time.Hour), 4*time.Minute),
+			nodeCount:          3,
+			expectedSize:       "medium",
+			expectedRequeue:    6 * time.Minute,
+			expectedBelowSince: durationPtr(4 * time.Minute),
+		},
+		{
+			name:         "downsize delay reset when the nodes recover",
+			current:      belowThresholdFor(sizedAt("medium", 3, time.Hour), 4*time.Minute),
+			nodeCount:    9,
+			expectedSize: "medium",
+		},
+		{
+			name:          "downsize after the delay",
+			current:       belowThresholdFor(sizedAt("medium", 3, 2*time.Hour), time.Hour),
+			nodeCount:     3,
+			expectedSize:  "small",
+			expectedMoved: true,
+		},
+		{
+			name:          "size removed from the configuration",
+			current:       sizedAt("huge", 500, time.Minute),
+			nodeCount:     500,
+			expectedSize:  "large",
+			expectedMoved: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			size, requeue := computeClusterSize(sizing, test.current, test.nodeCount, now)
+			g.Expect(size.Name).To(Equal(test.expectedSize))
+			g.Expect(size.NodeCount).To(Equal(test.nodeCount))
+			g.Expect(size.LastTransitionTime.Time.Equal(now)).To(Equal(test.expectedMoved))
+			g.Expect(requeue).To(Equal(test.expectedRequeue))
+			if test.expectedBelowSince == nil {
+				g.Expect(size.BelowThresholdSince).To(BeNil())
+			} else {
+				g.Expect(size.BelowThresholdSince.Time.Equal(now.Add(-*test.expectedBelowSince))).To(BeTrue())
+			}
+		})
+	}
+
+	// Without sizes no size is set
+	size, _ := computeClusterSize(&hyperv1.ClusterSizingConfigurationSpec{}, nil, 10, now)
+	NewGomegaWithT(t).Expect(size).To(BeNil())
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestClusterNodeCount(t *testing.T) {
+	g := NewGomegaWithT(t)
+	nodePools := []hyperv1.NodePool{
+		{Status: hyperv1.NodePoolStatus{NodeCount: 3}},
+		{Status: hyperv1.NodePoolStatus{NodeCount: 2}},
+	}
+
+	// Until the control plane reports the nodes of the hosted cluster, the
+	// node pools are counted
+	hcp := &hyperv1.HostedControlPlane{}
+	g.Expect(clusterNodeCount(nil, nodePools)).To(Equal(int32(5)))
+	g.Expect(clusterNodeCount(hcp, nodePools)).To(Equal(int32(5)))
+
+	hcp.Status.NodeCount = pointer.Int32Ptr(7)
+	g.Expect(clusterNodeCount(hcp, nodePools)).To(Equal(int32(7)))
+}
+
+func TestMergeComponentOverrides(t *testing.T) {
+	g := NewGomegaWithT(t)
+	sizeToleration := corev1.Toleration{Key: "size", Operator: corev1.TolerationOpExists}
+	clusterToleration := corev1.Toleration{Key: "cluster", Operator: corev1.TolerationOpExists}
+	sizeOverrides := []hyperv1.ControlPlaneComponentOverride{
+		{
+			Name:     "kube-apiserver",
+			Replicas: pointer.Int32Ptr(5),
+			Resources: []hyperv1.ContainerResourcesOverride{
+				{Container: "kube-apiserver"},
+				{Container: "konnectivity-server"},
+			},
+			Tolerations:       []corev1.Toleration{sizeToleration},
+			PriorityClassName: "size",
+		},
+		{Name: "etcd", PriorityClassName: "size"},
+	}
+	clusterOverrides := []hyperv1.ControlPlaneComponentOverride{
+		{
+			Name: "kube-apiserver",
+			Resources: []hyperv1.ContainerResourcesOverride{
+				{Container: "kube-apiserver", Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{}}},
+			},
+			Tolerations:       []corev1.Toleration{clusterToleration},
+			PriorityClassName: "cluster",
+		},
+		{Name: "openshift-apiserver", Replicas: pointer.Int32Ptr(2)},
+	}
+
+	merged := mergeComponentOverrides(sizeOverrides, clusterOverrides)
+	g.Expect(merged).To(HaveLen(3))
+	g.Expect(*merged[0].Replicas).To(Equal(int32(5)))
+	g.Expect(merged[0].Resources).To(HaveLen(2))
+	g.Expect(merged[0].Resources[0].Resources.Limits).ToNot(BeNil())
+	g.Expect(merged[0].Tolerations).To(Equal([]corev1.Toleration{sizeToleration, clusterToleration}))
+	g.Expect(merged[0].PriorityClassName).To(Equal("cluster"))
+	g.Expect(merged[1].Name).To(Equal("etcd"))
+	g.Expect(merged[2].Name).To(Equal("openshift-apiserver"))
+
+	// The overrides of the size are left untouched
+	g.Expect(sizeOverrides[0].Tolerations).To(HaveLen(1))
+	g.Expect(sizeOverrides[0].Resources[0].Resources.Limits).To(BeNil())
+
+	g.Expect(mergeComponentOverrides(nil, clusterOverrides)).To(Equal(clusterOverrides))
+}
@@ -0,0 +1,82 @@
+package hostedcluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+// clusterSizingValidator rejects invalid ClusterSizingConfigurations at
+// admission time.
+type clusterSizingValidator struct {
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &clusterSizingValidator{}
+var _ admission.DecoderInjector = &clusterSizingValidator{}
+
+func (v *clusterSizingValidator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+func (v *clusterSizingValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	sizing := &hyperv1.ClusterSizingConfiguration{}
+	if err := v.decoder.Decode(req, sizing); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if sizing.DeletionTimestamp != nil {
+		return admission.Allowed("")
+	}
+	if errs := validateClusterSizingConfiguration(sizing); len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// validateClusterSizingConfiguration returns every problem found in a
+// ClusterSizingConfiguration which would otherwise make the sizes of hosted
+// clusters ambiguous or fail their reconciliation.
+func validateClusterSizingConfiguration(sizing *hyperv1.ClusterSizingConfiguration) field.ErrorList {
+	var errs field.ErrorList
+	// Only the configuration with the well known name is used
+	if sizing.Name != hyperv1.ClusterSizingConfigurationName {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), sizing.Name,
+			fmt.Sprintf("the sizing configuration must be named %s", hyperv1.ClusterSizingConfigurationName)))
+	}
+
+	specPath := field.NewPath("spec")
+	if sizing.Spec.DownsizeMargin < 0 {
+		errs = append(errs, field.Invalid(specPath.Child("downsizeMargin"), sizing.Spec.DownsizeMargin, "must not be negative"))
+	}
+	if delay := sizing.Spec.DownsizeDelay; delay != nil && delay.Duration < 0 {
+		errs = append(errs, field.Invalid(specPath.Child("downsizeDelay"), delay.Duration.String(), "must not be negative"))
+	}
+	sizesPath := specPath.Child("sizes")
+	names := map[string]bool{}
+	nodeCounts := map[int32]bool{}
+	for i, size := range sizing.Spec.Sizes {
+		idxPath := sizesPath.Index(i)
+		if len(size.Name) == 0 {
+			errs = append(errs, field.Required(idxPath.Child("name"), ""))
+		} else if names[size.Name] {
+			errs = append(errs, field.Duplicate(idxPath.Child("name"), size.Name))
+		}
+		names[size.Name] = true
+		// Clusters are classified by node count, two sizes starting at the
+		// same count would make the size of a cluster ambiguous
+		if size.FromNodeCount < 0 {
+			errs = append(errs, field.Invalid(idxPath.Child("fromNodeCount"), size.FromNodeCount, "must not be negative"))
+		} else if nodeCounts[size.FromNodeCount] {
+			errs = append(errs, field.Duplicate(idxPath.Child("fromNodeCount"), size.FromNodeCount))
+		}
+		nodeCounts[size.FromNodeCount] = true
+		errs = append(errs, validateComponentOverrides(size.ComponentOverrides, idxPath.Child("componentOverrides"))...)
+	}
+	return errs
+}
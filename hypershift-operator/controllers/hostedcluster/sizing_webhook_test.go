@@ -0,0 +1,100 @@
+package hostedcluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+)
+
+func validClusterSizingConfiguration() *hyperv1.ClusterSizingConfiguration {
+	return &hyperv1.ClusterSizingConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: hyperv1.ClusterSizingConfigurationName},
+		Spec: hyperv1.ClusterSizingConfigurationSpec{
+			Sizes: []hyperv1.ClusterSize{
+				{Name: "small", FromNodeCount: 0},
+				{
+					Name:               "large",
+					FromNodeCount:      10,
+					ComponentOverrides: []hyperv1.ControlPlaneComponentOverride{{Name: "kube-apiserver", Replicas: pointer.Int32Ptr(3)}},
+				},
+			},
+			DownsizeMargin: 2,
+			DownsizeDelay:  &metav1.Duration{Duration: time.Hour},
+		},
+	}
+}
+
+func TestValidateClusterSizingConfiguration(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(*hyperv1.ClusterSizingConfiguration)
+		error  bool
+	}{
+		{
+			name:   "it passes with a valid configuration",
+			mutate: func(*hyperv1.ClusterSizingConfiguration) {},
+			error:  false,
+		},
+		{
+			name: "it fails when not named cluster",
+			mutate: func(sizing *hyperv1.ClusterSizingConfiguration) {
+				sizing.Name = "sizes"
+			},
+			error: true,
+		},
+		{
+			name: "it fails with duplicate size names",
+			mutate: func(sizing *hyperv1.ClusterSizingConfiguration) {
+				sizing.Spec.Sizes[1].Name = "small"
+			},
+			error: true,
+		},
+		{
+			name: "it fails with duplicate node counts",
+			mutate: func(sizing *hyperv1.ClusterSizingConfiguration) {
+				sizing.Spec.Sizes[1].FromNodeCount = 0
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a negative downsize margin",
+			mutate: func(sizing *hyperv1.ClusterSizingConfiguration) {
+				sizing.Spec.DownsizeMargin = -1
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an unknown component override",
+			mutate: func(sizing *hyperv1.ClusterSizingConfiguration) {
+				sizing.Spec.Sizes[1].ComponentOverrides = []hyperv1.ControlPlaneComponentOverride{{Name: "kube-apiservre", Replicas: pointer.Int32Ptr(3)}}
+			},
+			error: true,
+		},
+		{
+			name: "it fails when overriding the replicas of etcd",
+			mutate: func(sizing *hyperv1.ClusterSizingConfiguration) {
+				sizing.Spec.Sizes[1].ComponentOverrides = []hyperv1.ControlPlaneComponentOverride{{Name: "etcd", Replicas: pointer.Int32Ptr(5)}}
+			},
+			error: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			sizing := validClusterSizingConfiguration()
+			tc.mutate(sizing)
+			errs := validateClusterSizingConfiguration(sizing)
+			if tc.error {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
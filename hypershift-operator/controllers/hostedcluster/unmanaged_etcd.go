@@ -0,0 +1,218 @@
+package hostedcluster
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	supportetcd "github.com/openshift/hypershift/support/etcd"
+)
+
+const (
+	// unmanagedEtcdProbeInterval is how often the endpoint of an unmanaged etcd
+	// cluster is probed to refresh its status.
+	unmanagedEtcdProbeInterval = time.Minute
+
+	// unmanagedEtcdProbeTimeout bounds the time spent probing the endpoint and
+	// the members of an unmanaged etcd cluster.
+	unmanagedEtcdProbeTimeout = 10 * time.Second
+)
+
+// UnmanagedEtcdReconciler probes the endpoint of the unmanaged etcd cluster of
+// a HostedCluster and reports its state in the HostedCluster status. Probing
+// runs in its own controller so that an unresponsive etcd endpoint doesn't hold
+// up the HostedCluster reconcile of other clusters.
+type UnmanagedEtcdReconciler struct {
+	client.Client
+
+	// Clock is used to determine the time in a testable way.
+	Clock clock.Clock
+}
+
+func (r *UnmanagedEtcdReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Clock == nil {
+		r.Clock = clock.RealClock{}
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("unmanaged-etcd").
+		For(&hyperv1.HostedCluster{}).
+		Complete(r)
+}
+
+func (r *UnmanagedEtcdReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	hcluster := &hyperv1.HostedCluster{}
+	if err := r.Get(ctx, req.NamespacedName, hcluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("failed to get cluster %q: %w", req.NamespacedName, err)
+	}
+	if !hcluster.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	original := hcluster.DeepCopy()
+
+	if hcluster.Spec.Etcd.ManagementType != hyperv1.Unmanaged {
+		if hcluster.Status.UnmanagedEtcd == nil && meta.FindStatusCondition(hcluster.Status.Conditions, string(hyperv1.UnmanagedEtcdAvailable)) == nil {
+			return ctrl.Result{}, nil
+		}
+		hcluster.Status.UnmanagedEtcd = nil
+		meta.RemoveStatusCondition(&hcluster.Status.Conditions, string(hyperv1.UnmanagedEtcdAvailable))
+		return ctrl.Result{}, r.patchStatus(ctx, original, hcluster)
+	}
+	if wait := untilNextUnmanagedEtcdProbe(hcluster.Status.UnmanagedEtcd, r.Clock.Now()); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	// The condition explains why etcd is unavailable to the user on the
+	// resource without having to look at operator logs.
+	var unmanagedEtcdTLSClientSecret *corev1.Secret
+	if hcluster.Spec.Etcd.Unmanaged != nil {
+		unmanagedEtcdTLSClientSecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: hcluster.GetNamespace(),
+				Name:      hcluster.Spec.Etcd.Unmanaged.TLS.ClientSecret.Name,
+			},
+		}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(unmanagedEtcdTLSClientSecret), unmanagedEtcdTLSClientSecret); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, fmt.Errorf("failed to get unmanaged etcd tls secret: %w", err)
+			}
+			unmanagedEtcdTLSClientSecret = nil
+		}
+	}
+	now := metav1.NewTime(r.Clock.Now())
+	condition, status := computeUnmanagedEtcdAvailability(ctx, hcluster, unmanagedEtcdTLSClientSecret, now)
+	if status == nil {
+		// The cluster is misconfigured and wasn't probed, still record when
+		// it was checked to wait for the next probe.
+		status = &hyperv1.UnmanagedEtcdStatus{LastProbeTime: now}
+	}
+	hcluster.Status.UnmanagedEtcd = status
+	meta.SetStatusCondition(&hcluster.Status.Conditions, condition)
+	if err := r.patchStatus(ctx, original, hcluster); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: unmanagedEtcdProbeInterval}, nil
+}
+
+// patchStatus patches the unmanaged etcd status of the cluster. The patch is
+// rejected if the HostedCluster reconciler updated the status in the meantime,
+// so that its conditions aren't overwritten.
+func (r *UnmanagedEtcdReconciler) patchStatus(ctx context.Context, original, hcluster *hyperv1.HostedCluster) error {
+	if err := r.Status().Patch(ctx, hcluster, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})); err != nil {
+		return fmt.Errorf("failed to update unmanaged etcd status: %w", err)
+	}
+	return nil
+}
+
+// untilNextUnmanagedEtcdProbe returns how long to wait before the endpoint of
+// an unmanaged etcd cluster is probed again, or zero if a probe is due. Writing
+// the status of a probe triggers another reconcile, so probing on every
+// reconcile would probe the cluster in a loop.
+func untilNextUnmanagedEtcdProbe(status *hyperv1.UnmanagedEtcdStatus, now time.Time) time.Duration {
+	if status == nil {
+		return 0
+	}
+	if wait := unmanagedEtcdProbeInterval - now.Sub(status.LastProbeTime.Time); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// probeUnmanagedEtcd connects to the endpoint of an unmanaged etcd cluster with
+// the given client TLS configuration and reports the state of the cluster and
+// of each of its members. The returned condition explains why the cluster is
+// unavailable: the endpoint can't be reached, the TLS handshake fails, or a
+// member is unhealthy.
+func probeUnmanagedEtcd(ctx context.Context, endpoint string, tlsConfig *tls.Config, now metav1.Time) (*hyperv1.UnmanagedEtcdStatus, metav1.Condition) {
+	ctx, cancel := context.WithTimeout(ctx, unmanagedEtcdProbeTimeout)
+	defer cancel()
+
+	status := &hyperv1.UnmanagedEtcdStatus{LastProbeTime: now}
+	etcdClient := supportetcd.NewClient([]string{endpoint}, tlsConfig)
+	defer etcdClient.Close()
+	if err := etcdClient.Handshake(ctx, endpoint); err != nil {
+		var handshakeErr *supportetcd.HandshakeError
+		if errors.As(err, &handshakeErr) {
+			return status, unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdTLSHandshakeFailedReason, err.Error())
+		}
+		return status, unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdEndpointUnreachableReason, fmt.Sprintf("failed to connect to %s: %v", endpoint, err))
+	}
+	status.Reachable = true
+
+	endpointStatus, err := etcdClient.Status(ctx, endpoint)
+	if err != nil {
+		return status, unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdUnhealthyMemberReason, err.Error())
+	}
+	status.Version = endpointStatus.Version
+	status.DBSizeBytes = endpointStatus.DBSize
+
+	members, err := etcdClient.MemberList(ctx)
+	if err != nil {
+		return status, unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdUnhealthyMemberReason, err.Error())
+	}
+	var unhealthy []string
+	for _, member := range members {
+		memberStatus := hyperv1.UnmanagedEtcdMemberStatus{
+			ID:         fmt.Sprintf("%x", member.ID),
+			Name:       member.Name,
+			ClientURLs: member.ClientURLs,
+		}
+		if member.ID == endpointStatus.Leader {
+			status.Leader = member.Name
+		}
+		if !member.Started() {
+			memberStatus.Message = "member has not started"
+		} else if err := etcdClient.Health(ctx, member.ClientURLs[0]); err != nil {
+			memberStatus.Message = err.Error()
+		} else {
+			memberStatus.Healthy = true
+		}
+		if !memberStatus.Healthy {
+			name := member.Name
+			if len(name) == 0 {
+				name = memberStatus.ID
+			}
+			unhealthy = append(unhealthy, name)
+		}
+		status.Members = append(status.Members, memberStatus)
+	}
+
+	if err := etcdClient.Health(ctx, endpoint); err != nil {
+		return status, unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdUnhealthyMemberReason, err.Error())
+	}
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		return status, unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdUnhealthyMemberReason, fmt.Sprintf("unhealthy etcd members: %s", strings.Join(unhealthy, ", ")))
+	}
+	status.Healthy = true
+	return status, metav1.Condition{
+		Type:    string(hyperv1.UnmanagedEtcdAvailable),
+		Status:  metav1.ConditionTrue,
+		Reason:  hyperv1.UnmanagedEtcdAsExpected,
+		Message: fmt.Sprintf("etcd cluster with %d members is healthy, leader is %s", len(members), status.Leader),
+	}
+}
+
+func unmanagedEtcdUnavailable(reason, message string) metav1.Condition {
+	return metav1.Condition{
+		Type:    string(hyperv1.UnmanagedEtcdAvailable),
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	}
+}
@@ -0,0 +1,347 @@
+package hostedcluster
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/hypershift/api"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/support/certs"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	pem  []byte
+	sign func(cfg *certs.CertCfg) (certPEM, keyPEM []byte)
+}
+
+func newTestCA(t *testing.T, name string) *testCA {
+	key, cert, err := certs.GenerateSelfSignedCertificate(&certs.CertCfg{
+		Subject:   pkix.Name{CommonName: name, OrganizationalUnit: []string{"test"}},
+		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  certs.ValidityOneDay,
+		IsCA:      true,
+	})
+	if err != nil {
+		t.Fatalf("failed to generate CA: %v", err)
+	}
+	return &testCA{
+		cert: cert,
+		pem:  certs.CertToPem(cert),
+		sign: func(cfg *certs.CertCfg) ([]byte, []byte) {
+			cfg.KeyUsages = x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature
+			cfg.Validity = certs.ValidityOneDay
+			signedKey, signedCert, err := certs.GenerateSignedCertificate(key, cert, cfg)
+			if err != nil {
+				t.Fatalf("failed to generate certificate: %v", err)
+			}
+			keyPEM, err := certs.SignerToPem(signedKey)
+			if err != nil {
+				t.Fatalf("failed to encode key: %v", err)
+			}
+			return certs.CertToPem(signedCert), keyPEM
+		},
+	}
+}
+
+// fakeEtcd serves the subset of the etcd JSON gateway used to probe unmanaged
+// etcd, and requires clients to present a certificate signed by clientCA.
+type fakeEtcd struct {
+	*httptest.Server
+	healthy         bool
+	unstartedMember bool
+}
+
+func newFakeEtcd(t *testing.T, serverCA, clientCA *testCA) *fakeEtcd {
+	etcd := &fakeEtcd{healthy: true}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/maintenance/status", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version":"3.4.14","dbSize":"2097152","dbSizeInUse":"1048576","leader":"10","raftIndex":"9","raftTerm":"2"}`)
+	})
+	mux.HandleFunc("/v3/cluster/member/list", func(w http.ResponseWriter, r *http.Request) {
+		members := fmt.Sprintf(`{"ID":"10","name":"etcd-0","peerURLs":["https://etcd-0:2380"],"clientURLs":["%s"]}`, etcd.URL)
+		if etcd.unstartedMember {
+			members += `,{"ID":"11","peerURLs":["https://etcd-1:2380"]}`
+		}
+		fmt.Fprintf(w, `{"members":[%s]}`, members)
+	})
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"health":"%t"}`, etcd.healthy)
+	})
+	etcd.Server = httptest.NewUnstartedServer(mux)
+
+	certPEM, keyPEM := serverCA.sign(&certs.CertCfg{
+		Subject:      pkix.Name{CommonName: "etcd-server", OrganizationalUnit: []string{"test"}},
+		ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	})
+	serverCert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("invalid server certificate: %v", err)
+	}
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCA.cert)
+	etcd.TLS = &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+	}
+	etcd.StartTLS()
+	return etcd
+}
+
+func unmanagedEtcdClientSecret(trustedCA, clientCA *testCA) *corev1.Secret {
+	certPEM, keyPEM := clientCA.sign(&certs.CertCfg{
+		Subject:      pkix.Name{CommonName: "etcd-client", OrganizationalUnit: []string{"test"}},
+		ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "etcd-client"},
+		Data: map[string][]byte{
+			"etcd-client.crt":    certPEM,
+			"etcd-client.key":    keyPEM,
+			"etcd-client-ca.crt": trustedCA.pem,
+		},
+	}
+}
+
+func TestComputeUnmanagedEtcdAvailability(t *testing.T) {
+	serverCA := newTestCA(t, "etcd-server-ca")
+	clientCA := newTestCA(t, "etcd-client-ca")
+	otherCA := newTestCA(t, "other-ca")
+
+	etcd := newFakeEtcd(t, serverCA, clientCA)
+	defer etcd.Close()
+
+	unreachable := httptest.NewUnstartedServer(http.NotFoundHandler())
+	unreachableURL := "https://" + unreachable.Listener.Addr().String()
+	unreachable.Close()
+
+	tests := map[string]struct {
+		endpoint        string
+		secret          *corev1.Secret
+		healthy         bool
+		unstartedMember bool
+		expectedStatus  metav1.ConditionStatus
+		expectedReason  string
+		expectReachable bool
+	}{
+		"healthy cluster is available": {
+			secret:          unmanagedEtcdClientSecret(serverCA, clientCA),
+			healthy:         true,
+			expectedStatus:  metav1.ConditionTrue,
+			expectedReason:  hyperv1.UnmanagedEtcdAsExpected,
+			expectReachable: true,
+		},
+		"unhealthy endpoint is unavailable": {
+			secret:          unmanagedEtcdClientSecret(serverCA, clientCA),
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  hyperv1.UnmanagedEtcdUnhealthyMemberReason,
+			expectReachable: true,
+		},
+		"member which has not started is unhealthy": {
+			secret:          unmanagedEtcdClientSecret(serverCA, clientCA),
+			healthy:         true,
+			unstartedMember: true,
+			expectedStatus:  metav1.ConditionFalse,
+			expectedReason:  hyperv1.UnmanagedEtcdUnhealthyMemberReason,
+			expectReachable: true,
+		},
+		"untrusted server certificate fails the handshake": {
+			secret:         unmanagedEtcdClientSecret(otherCA, clientCA),
+			healthy:        true,
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: hyperv1.UnmanagedEtcdTLSHandshakeFailedReason,
+		},
+		"rejected client certificate fails the handshake": {
+			secret:         unmanagedEtcdClientSecret(serverCA, otherCA),
+			healthy:        true,
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: hyperv1.UnmanagedEtcdTLSHandshakeFailedReason,
+		},
+		"closed endpoint is unreachable": {
+			endpoint:       unreachableURL,
+			secret:         unmanagedEtcdClientSecret(serverCA, clientCA),
+			expectedStatus: metav1.ConditionFalse,
+			expectedReason: hyperv1.UnmanagedEtcdEndpointUnreachableReason,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			etcd.healthy = test.healthy
+			etcd.unstartedMember = test.unstartedMember
+			endpoint := test.endpoint
+			if len(endpoint) == 0 {
+				endpoint = etcd.URL
+			}
+			hcluster := &hyperv1.HostedCluster{
+				Spec: hyperv1.HostedClusterSpec{
+					Etcd: hyperv1.EtcdSpec{
+						ManagementType: hyperv1.Unmanaged,
+						Unmanaged: &hyperv1.UnmanagedEtcdSpec{
+							Endpoint: endpoint,
+							TLS:      hyperv1.EtcdTLSConfig{ClientSecret: corev1.LocalObjectReference{Name: "etcd-client"}},
+						},
+					},
+				},
+			}
+			condition, status := computeUnmanagedEtcdAvailability(context.Background(), hcluster, test.secret, Now)
+			g.Expect(condition.Type).To(Equal(string(hyperv1.UnmanagedEtcdAvailable)))
+			g.Expect(condition.Status).To(Equal(test.expectedStatus), condition.Message)
+			g.Expect(condition.Reason).To(Equal(test.expectedReason), condition.Message)
+			g.Expect(status).ToNot(BeNil())
+			g.Expect(status.LastProbeTime).To(Equal(Now))
+			g.Expect(status.Reachable).To(Equal(test.expectReachable))
+			g.Expect(status.Healthy).To(Equal(test.expectedStatus == metav1.ConditionTrue))
+			if test.expectReachable {
+				g.Expect(status.Version).To(Equal("3.4.14"))
+				g.Expect(status.DBSizeBytes).To(Equal(int64(2097152)))
+				g.Expect(status.Leader).To(Equal("etcd-0"))
+				g.Expect(status.Members[0]).To(Equal(hyperv1.UnmanagedEtcdMemberStatus{
+					ID:         "a",
+					Name:       "etcd-0",
+					ClientURLs: []string{etcd.URL},
+					Healthy:    test.healthy,
+					Message:    status.Members[0].Message,
+				}))
+			}
+			if test.unstartedMember {
+				g.Expect(status.Members).To(HaveLen(2))
+				g.Expect(status.Members[1].Healthy).To(BeFalse())
+				g.Expect(condition.Message).To(ContainSubstring("unhealthy etcd members: b"))
+			}
+		})
+	}
+}
+
+func TestComputeUnmanagedEtcdAvailabilityMisconfigured(t *testing.T) {
+	g := NewGomegaWithT(t)
+	hcluster := &hyperv1.HostedCluster{
+		Spec: hyperv1.HostedClusterSpec{
+			Etcd: hyperv1.EtcdSpec{
+				ManagementType: hyperv1.Unmanaged,
+				Unmanaged: &hyperv1.UnmanagedEtcdSpec{
+					Endpoint: "https://etcd-client:2379",
+					TLS:      hyperv1.EtcdTLSConfig{ClientSecret: corev1.LocalObjectReference{Name: "etcd-client"}},
+				},
+			},
+		},
+	}
+
+	condition, status := computeUnmanagedEtcdAvailability(context.Background(), hcluster, nil, Now)
+	g.Expect(condition.Reason).To(Equal(hyperv1.UnmanagedEtcdMisconfiguredReason))
+	g.Expect(status).To(BeNil())
+
+	secret := &corev1.Secret{Data: map[string][]byte{
+		"etcd-client.crt":    []byte("invalid"),
+		"etcd-client.key":    []byte("invalid"),
+		"etcd-client-ca.crt": []byte("invalid"),
+	}}
+	condition, status = computeUnmanagedEtcdAvailability(context.Background(), hcluster, secret, Now)
+	g.Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	g.Expect(condition.Reason).To(Equal(hyperv1.UnmanagedEtcdMisconfiguredReason))
+	g.Expect(status).To(BeNil())
+}
+
+func TestUntilNextUnmanagedEtcdProbe(t *testing.T) {
+	g := NewGomegaWithT(t)
+	g.Expect(untilNextUnmanagedEtcdProbe(nil, Now.Time)).To(BeZero())
+
+	status := &hyperv1.UnmanagedEtcdStatus{LastProbeTime: metav1.NewTime(Now.Add(-unmanagedEtcdProbeInterval / 4))}
+	g.Expect(untilNextUnmanagedEtcdProbe(status, Now.Time)).To(Equal(unmanagedEtcdProbeInterval * 3 / 4))
+
+	status.LastProbeTime = metav1.NewTime(Now.Add(-unmanagedEtcdProbeInterval))
+	g.Expect(untilNextUnmanagedEtcdProbe(status, Now.Time)).To(BeZero())
+}
+
+func TestUnmanagedEtcdReconciler(t *testing.T) {
+	// Times in the status are stored with second precision.
+	now := metav1.NewTime(Now.Truncate(time.Second))
+	newCluster := func(status *hyperv1.UnmanagedEtcdStatus) *hyperv1.HostedCluster {
+		return &hyperv1.HostedCluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "clusters", Name: "hc"},
+			Spec: hyperv1.HostedClusterSpec{
+				Etcd: hyperv1.EtcdSpec{
+					ManagementType: hyperv1.Unmanaged,
+					Unmanaged: &hyperv1.UnmanagedEtcdSpec{
+						Endpoint: "https://etcd-client:2379",
+						TLS:      hyperv1.EtcdTLSConfig{ClientSecret: corev1.LocalObjectReference{Name: "etcd-client"}},
+					},
+				},
+			},
+			Status: hyperv1.HostedClusterStatus{UnmanagedEtcd: status},
+		}
+	}
+	tests := map[string]struct {
+		cluster             *hyperv1.HostedCluster
+		expectedRequeue     time.Duration
+		expectedReason      string
+		expectStatusCleared bool
+	}{
+		"probe is not due yet": {
+			cluster:         newCluster(&hyperv1.UnmanagedEtcdStatus{LastProbeTime: metav1.NewTime(now.Add(-unmanagedEtcdProbeInterval / 4))}),
+			expectedRequeue: unmanagedEtcdProbeInterval * 3 / 4,
+		},
+		"missing client secret is reported": {
+			cluster:         newCluster(nil),
+			expectedRequeue: unmanagedEtcdProbeInterval,
+			expectedReason:  hyperv1.UnmanagedEtcdMisconfiguredReason,
+		},
+		"status is cleared for managed etcd": {
+			cluster: func() *hyperv1.HostedCluster {
+				hcluster := newCluster(&hyperv1.UnmanagedEtcdStatus{LastProbeTime: now})
+				hcluster.Spec.Etcd = hyperv1.EtcdSpec{ManagementType: hyperv1.Managed}
+				meta.SetStatusCondition(&hcluster.Status.Conditions, unmanagedEtcdUnavailable(hyperv1.UnmanagedEtcdMisconfiguredReason, "test"))
+				return hcluster
+			}(),
+			expectStatusCleared: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			g := NewGomegaWithT(t)
+			r := &UnmanagedEtcdReconciler{
+				Client: fake.NewClientBuilder().WithScheme(api.Scheme).WithObjects(test.cluster).Build(),
+				Clock:  clock.NewFakeClock(now.Time),
+			}
+			key := types.NamespacedName{Namespace: test.cluster.Namespace, Name: test.cluster.Name}
+			result, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: key})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result.RequeueAfter).To(Equal(test.expectedRequeue))
+
+			hcluster := &hyperv1.HostedCluster{}
+			g.Expect(r.Get(context.Background(), key, hcluster)).To(Succeed())
+			condition := meta.FindStatusCondition(hcluster.Status.Conditions, string(hyperv1.UnmanagedEtcdAvailable))
+			if test.expectStatusCleared {
+				g.Expect(hcluster.Status.UnmanagedEtcd).To(BeNil())
+				g.Expect(condition).To(BeNil())
+				return
+			}
+			g.Expect(hcluster.Status.UnmanagedEtcd).ToNot(BeNil())
+			if len(test.expectedReason) > 0 {
+				g.Expect(condition).ToNot(BeNil())
+				g.Expect(condition.Reason).To(Equal(test.expectedReason))
+				g.Expect(hcluster.Status.UnmanagedEtcd.LastProbeTime.Equal(&now)).To(BeTrue())
+			}
+		})
+	}
+}
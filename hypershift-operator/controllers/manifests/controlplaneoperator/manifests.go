@@ -109,6 +109,33 @@ func SigningKey(controlPlaneNamespace string) *corev1.Secret {
 	}
 }
 
+func UserAuditPolicy(controlPlaneNamespace string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: controlPlaneNamespace,
+			Name:      "user-audit-policy",
+		},
+	}
+}
+
+func UserAuditLogCA(controlPlaneNamespace string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: controlPlaneNamespace,
+			Name:      "user-audit-log-ca",
+		},
+	}
+}
+
+func UserAuditLogCredentials(controlPlaneNamespace string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: controlPlaneNamespace,
+			Name:      "user-audit-log-credentials",
+		},
+	}
+}
+
 func SSHKey(controlPlaneNamespace string) *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,312 @@
+package nodepool
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	api "github.com/openshift/hypershift/api"
+	"github.com/openshift/hypershift/api/inplaceupgrade"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sutilspointer "k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// nodePoolLabel labels the in-place upgrade payload Secrets of a NodePool
+	// so they can be removed together with it.
+	nodePoolLabel = "hypershift.openshift.io/nodePool"
+
+	machineConfigOperatorComponent = "machine-config-operator"
+)
+
+// inPlaceUpgradeMaxUnavailable returns how many nodes of an InPlace NodePool
+// can be updated at the same time.
+func inPlaceUpgradeMaxUnavailable(nodePool *hyperv1.NodePool) intstr.IntOrString {
+	if nodePool.Spec.Management.InPlace != nil && nodePool.Spec.Management.InPlace.MaxUnavailable != nil {
+		return *nodePool.Spec.Management.InPlace.MaxUnavailable
+	}
+	return intstr.FromInt(1)
+}
+
+// reconcileInPlaceUpgradePayload stores the ignition payload of the target
+// config version in a Secret which the in-place upgrader applies to existing
+// nodes. The payload is fetched from the ignition server the same way new
+// nodes fetch it. Payload Secrets are immutable, so they are only fetched
+// once.
+func (r *NodePoolReconciler) reconcileInPlaceUpgradePayload(ctx context.Context, payloadSecret *corev1.Secret, nodePool *hyperv1.NodePool, caCert, token []byte, ignEndpoint string) error {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(payloadSecret), payloadSecret); err == nil {
+		return nil
+	} else if !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get in-place upgrade payload Secret: %w", err)
+	}
+
+	payload, err := fetchIgnitionPayload(ctx, ignEndpoint, caCert, token)
+	if err != nil {
+		return err
+	}
+	payloadSecret.Immutable = k8sutilspointer.BoolPtr(true)
+	payloadSecret.Annotations = map[string]string{
+		nodePoolAnnotation: client.ObjectKeyFromObject(nodePool).String(),
+	}
+	payloadSecret.Labels = map[string]string{
+		nodePoolLabel: nodePool.Name,
+	}
+	payloadSecret.Data = map[string][]byte{
+		inplaceupgrade.PayloadSecretKey: payload,
+	}
+	if err := r.Create(ctx, payloadSecret); err != nil {
+		return fmt.Errorf("failed to create in-place upgrade payload Secret: %w", err)
+	}
+	return nil
+}
+
+// fetchIgnitionPayload gets the ignition payload served for a token. The
+// ignition server only serves it once it finished generating it.
+func fetchIgnitionPayload(ctx context.Context, ignEndpoint string, caCert, token []byte) ([]byte, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("failed to parse the ignition CA certificate")
+	}
+	httpClient := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s/ignition", ignEndpoint), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", base64.StdEncoding.EncodeToString(token)))
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ignition payload: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ignition payload is not available yet: %s", resp.Status)
+	}
+	payload, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ignition payload: %w", err)
+	}
+	return payload, nil
+}
+
+func (r *NodePoolReconciler) reconcileMachineSet(log logr.Logger,
+	machineSet *capiv1.MachineSet,
+	nodePool *hyperv1.NodePool,
+	tokenSecret *corev1.Secret,
+	userDataSecret *corev1.Secret,
+	payloadSecret *corev1.Secret,
+	machineTemplateCR client.Object,
+	machines []capiv1.Machine,
+	CAPIClusterName string,
+	machineConfigDaemonImage,
+	targetVersion,
+	targetConfigHash, targetConfigVersionHash string) error {
+
+	// Set annotations and labels
+	if machineSet.GetAnnotations() == nil {
+		machineSet.Annotations = map[string]string{}
+	}
+	machineSet.Annotations[nodePoolAnnotation] = client.ObjectKeyFromObject(nodePool).String()
+	if err := setNodeLabelsAndTaintsAnnotations(nodePool, tokenSecret, &machineSet.ObjectMeta); err != nil {
+		return err
+	}
+	if machineSet.GetLabels() == nil {
+		machineSet.Labels = map[string]string{}
+	}
+	machineSet.Labels[capiv1.ClusterLabelName] = CAPIClusterName
+
+	resourcesName := generateName(CAPIClusterName, nodePool.Spec.ClusterName, nodePool.GetName())
+	gvk, err := apiutil.GVKForObject(machineTemplateCR, api.Scheme)
+	if err != nil {
+		return err
+	}
+
+	// Set selector and template. Unlike a MachineDeployment, a MachineSet does
+	// not replace its machines when the template changes, so the template
+	// always points to the target user data which only new machines boot
+	// from. Existing machines are updated in place.
+	machineSet.Spec.ClusterName = CAPIClusterName
+	if machineSet.Spec.Selector.MatchLabels == nil {
+		machineSet.Spec.Selector.MatchLabels = map[string]string{}
+	}
+	machineSet.Spec.Selector.MatchLabels[resourcesName] = resourcesName
+	if machineSet.Spec.Template.Spec.Bootstrap.DataSecretName != nil && userDataSecret.Name != *machineSet.Spec.Template.Spec.Bootstrap.DataSecretName {
+		log.Info("Starting in-place update: Propagating new user data Secret to the MachineSet",
+			"current", *machineSet.Spec.Template.Spec.Bootstrap.DataSecretName,
+			"target", userDataSecret.Name)
+	}
+	machineSet.Spec.Template = capiv1.MachineTemplateSpec{
+		ObjectMeta: capiv1.ObjectMeta{
+			Labels: map[string]string{
+				resourcesName:           resourcesName,
+				capiv1.ClusterLabelName: CAPIClusterName,
+			},
+			Annotations: map[string]string{
+				"machine.cluster.x-k8s.io/exclude-node-draining": "true",
+				// Machines report the progress of their nodes, so changes to
+				// them are reconciled by the NodePool.
+				nodePoolAnnotation: client.ObjectKeyFromObject(nodePool).String(),
+			},
+		},
+		Spec: capiv1.MachineSpec{
+			ClusterName: CAPIClusterName,
+			Bootstrap: capiv1.Bootstrap{
+				DataSecretName: k8sutilspointer.StringPtr(userDataSecret.Name),
+			},
+			InfrastructureRef: corev1.ObjectReference{
+				Kind:       gvk.Kind,
+				APIVersion: gvk.GroupVersion().String(),
+				Namespace:  machineTemplateCR.GetNamespace(),
+				Name:       machineTemplateCR.GetName(),
+			},
+			Version: k8sutilspointer.StringPtr(targetVersion),
+		},
+	}
+
+	// Only hand over the target config version to the in-place upgrader once
+	// its payload is available.
+	if payloadSecret != nil {
+		machineSet.Annotations[inplaceupgrade.MachineSetAnnotationTargetConfigVersion] = targetConfigVersionHash
+		machineSet.Annotations[inplaceupgrade.MachineSetAnnotationUpgradePayloadSecret] = payloadSecret.Name
+		machineSet.Annotations[inplaceupgrade.MachineSetAnnotationMachineConfigDaemonImage] = machineConfigDaemonImage
+	}
+	maxUnavailable := inPlaceUpgradeMaxUnavailable(nodePool)
+	machineSet.Annotations[inplaceupgrade.MachineSetAnnotationUpgradeMaxUnavailable] = maxUnavailable.String()
+
+	setMachineSetReplicas(nodePool, machineSet)
+
+	nodePool.Status.Nodes = inPlaceNodeStatus(machines, userDataSecret.Name, targetConfigVersionHash)
+	if inPlaceUpgradeComplete(machineSet, nodePool.Status.Nodes, targetConfigVersionHash) {
+		if nodePool.Status.Version != targetVersion {
+			log.Info("Version update complete",
+				"previous", nodePool.Status.Version, "new", targetVersion)
+			nodePool.Status.Version = targetVersion
+		}
+
+		if nodePool.Annotations[nodePoolAnnotationCurrentConfig] != targetConfigHash {
+			log.Info("Config update complete",
+				"previous", nodePool.Annotations[nodePoolAnnotationCurrentConfig], "new", targetConfigHash)
+			nodePool.Annotations[nodePoolAnnotationCurrentConfig] = targetConfigHash
+		}
+		nodePool.Annotations[nodePoolAnnotationCurrentConfigVersion] = targetConfigVersionHash
+	}
+
+	nodePool.Status.NodeCount = machineSet.Status.AvailableReplicas
+	return nil
+}
+
+// listMachines returns the Machines of a NodePool.
+func (r *NodePoolReconciler) listMachines(ctx context.Context, nodePool *hyperv1.NodePool, CAPIClusterName, controlPlaneNamespace string) ([]capiv1.Machine, error) {
+	resourcesName := generateName(CAPIClusterName, nodePool.Spec.ClusterName, nodePool.GetName())
+	machines := &capiv1.MachineList{}
+	if err := r.List(ctx, machines, client.InNamespace(controlPlaneNamespace), client.MatchingLabels{resourcesName: resourcesName}); err != nil {
+		return nil, fmt.Errorf("failed to list Machines: %w", err)
+	}
+	return machines.Items, nil
+}
+
+// inPlaceNodeStatus returns the upgrade progress of the nodes of the given
+// Machines.
+func inPlaceNodeStatus(machines []capiv1.Machine, userDataSecretName, targetConfigVersionHash string) []hyperv1.NodePoolNodeStatus {
+	var nodes []hyperv1.NodePoolNodeStatus
+	for i := range machines {
+		machine := &machines[i]
+		if machine.Status.NodeRef == nil {
+			continue
+		}
+		node := hyperv1.NodePoolNodeStatus{
+			Name:          machine.Status.NodeRef.Name,
+			ConfigVersion: inplaceupgrade.MachineConfigVersion(machine, userDataSecretName, targetConfigVersionHash),
+			State:         hyperv1.NodeUpgradeState(machine.Annotations[inplaceupgrade.MachineAnnotationUpgradeState]),
+			Message:       machine.Annotations[inplaceupgrade.MachineAnnotationUpgradeMessage],
+		}
+		if node.ConfigVersion == targetConfigVersionHash {
+			node.State = hyperv1.NodeUpgradeStateDone
+			node.Message = ""
+		} else if node.State == "" || node.State == hyperv1.NodeUpgradeStateDone {
+			node.State = hyperv1.NodeUpgradeStatePending
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+	return nodes
+}
+
+// inPlaceUpgradeComplete considers an in-place upgrade to be complete once
+// all of the desired machines of the MachineSet are available and their nodes
+// run the target config version.
+func inPlaceUpgradeComplete(machineSet *capiv1.MachineSet, nodes []hyperv1.NodePoolNodeStatus, targetConfigVersionHash string) bool {
+	replicas := k8sutilspointer.Int32PtrDerefOr(machineSet.Spec.Replicas, 0)
+	if machineSet.Status.ObservedGeneration < machineSet.Generation ||
+		machineSet.Status.Replicas != replicas ||
+		machineSet.Status.AvailableReplicas != replicas ||
+		int32(len(nodes)) != replicas {
+		return false
+	}
+	for _, node := range nodes {
+		if node.ConfigVersion != targetConfigVersionHash {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteInPlaceUpgradePayloads removes the in-place upgrade payload Secrets of
+// a NodePool.
+func (r *NodePoolReconciler) deleteInPlaceUpgradePayloads(ctx context.Context, nodePool *hyperv1.NodePool, controlPlaneNamespace string) error {
+	secrets := &corev1.SecretList{}
+	if err := r.List(ctx, secrets, client.InNamespace(controlPlaneNamespace), client.MatchingLabels{nodePoolLabel: nodePool.Name}); err != nil {
+		return fmt.Errorf("failed to list in-place upgrade payload Secrets: %w", err)
+	}
+	for i := range secrets.Items {
+		if err := r.Delete(ctx, &secrets.Items[i]); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete in-place upgrade payload Secret: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateInPlaceUpgrade validates the InPlace upgrade settings of a NodePool.
+func validateInPlaceUpgrade(nodePool *hyperv1.NodePool) error {
+	if nodePool.Spec.Platform.AWS != nil && len(nodePool.Spec.Platform.AWS.Zones) > 0 {
+		return fmt.Errorf("this is unsupported. %q upgrade type does not support NodePools spread across zones",
+			hyperv1.UpgradeTypeInPlace)
+	}
+	maxUnavailable := inPlaceUpgradeMaxUnavailable(nodePool)
+	if maxUnavailable.Type == intstr.String {
+		value, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, 100, true)
+		if err != nil {
+			return fmt.Errorf("this is unsupported. %q upgrade type requires maxUnavailable to be a number or a percentage: %w",
+				hyperv1.UpgradeTypeInPlace, err)
+		}
+		if value <= 0 {
+			return fmt.Errorf("this is unsupported. %q upgrade type requires a maxUnavailable percentage greater than 0",
+				hyperv1.UpgradeTypeInPlace)
+		}
+		return nil
+	}
+	if maxUnavailable.IntValue() < 1 {
+		return fmt.Errorf("this is unsupported. %q upgrade type requires a maxUnavailable of at least 1",
+			hyperv1.UpgradeTypeInPlace)
+	}
+	return nil
+}
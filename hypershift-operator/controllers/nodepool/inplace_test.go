@@ -0,0 +1,147 @@
+package nodepool
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/openshift/hypershift/api/inplaceupgrade"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func inPlaceMachine(name, dataSecretName string, annotations map[string]string) capiv1.Machine {
+	return capiv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: annotations,
+		},
+		Spec: capiv1.MachineSpec{
+			Bootstrap: capiv1.Bootstrap{DataSecretName: pointer.StringPtr(dataSecretName)},
+		},
+		Status: capiv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{Name: name},
+		},
+	}
+}
+
+func TestInPlaceNodeStatus(t *testing.T) {
+	g := NewWithT(t)
+	machines := []capiv1.Machine{
+		inPlaceMachine("node-c", "user-data-old", map[string]string{
+			inplaceupgrade.MachineAnnotationCurrentConfigVersion: "old",
+			inplaceupgrade.MachineAnnotationUpgradeState:         string(hyperv1.NodeUpgradeStateDegraded),
+			inplaceupgrade.MachineAnnotationUpgradeMessage:       "agent failed",
+		}),
+		inPlaceMachine("node-a", "user-data-target", nil),
+		inPlaceMachine("node-b", "user-data-old", map[string]string{
+			inplaceupgrade.MachineAnnotationCurrentConfigVersion: "target",
+			inplaceupgrade.MachineAnnotationUpgradeState:         string(hyperv1.NodeUpgradeStateDone),
+		}),
+		inPlaceMachine("node-d", "user-data-old", map[string]string{
+			inplaceupgrade.MachineAnnotationCurrentConfigVersion: "old",
+			inplaceupgrade.MachineAnnotationUpgradeState:         string(hyperv1.NodeUpgradeStateDone),
+		}),
+		{ObjectMeta: metav1.ObjectMeta{Name: "provisioning"}},
+	}
+
+	g.Expect(inPlaceNodeStatus(machines, "user-data-target", "target")).To(Equal([]hyperv1.NodePoolNodeStatus{
+		{Name: "node-a", ConfigVersion: "target", State: hyperv1.NodeUpgradeStateDone},
+		{Name: "node-b", ConfigVersion: "target", State: hyperv1.NodeUpgradeStateDone},
+		{Name: "node-c", ConfigVersion: "old", State: hyperv1.NodeUpgradeStateDegraded, Message: "agent failed"},
+		{Name: "node-d", ConfigVersion: "old", State: hyperv1.NodeUpgradeStatePending},
+	}))
+}
+
+func TestInPlaceUpgradeComplete(t *testing.T) {
+	done := []hyperv1.NodePoolNodeStatus{
+		{Name: "node-a", ConfigVersion: "target", State: hyperv1.NodeUpgradeStateDone},
+		{Name: "node-b", ConfigVersion: "target", State: hyperv1.NodeUpgradeStateDone},
+	}
+	testCases := []struct {
+		name       string
+		machineSet *capiv1.MachineSet
+		nodes      []hyperv1.NodePoolNodeStatus
+		expect     bool
+	}{
+		{
+			name: "it is complete when all nodes are available at the target",
+			machineSet: &capiv1.MachineSet{
+				Spec:   capiv1.MachineSetSpec{Replicas: pointer.Int32Ptr(2)},
+				Status: capiv1.MachineSetStatus{Replicas: 2, AvailableReplicas: 2},
+			},
+			nodes:  done,
+			expect: true,
+		},
+		{
+			name: "it is not complete when a node is not at the target",
+			machineSet: &capiv1.MachineSet{
+				Spec:   capiv1.MachineSetSpec{Replicas: pointer.Int32Ptr(2)},
+				Status: capiv1.MachineSetStatus{Replicas: 2, AvailableReplicas: 2},
+			},
+			nodes: []hyperv1.NodePoolNodeStatus{
+				done[0],
+				{Name: "node-b", ConfigVersion: "old", State: hyperv1.NodeUpgradeStateUpdating},
+			},
+			expect: false,
+		},
+		{
+			name: "it is not complete when nodes are unavailable",
+			machineSet: &capiv1.MachineSet{
+				Spec:   capiv1.MachineSetSpec{Replicas: pointer.Int32Ptr(3)},
+				Status: capiv1.MachineSetStatus{Replicas: 3, AvailableReplicas: 2},
+			},
+			nodes:  done,
+			expect: false,
+		},
+		{
+			name: "it is not complete when the MachineSet changes are not observed",
+			machineSet: &capiv1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{Generation: 2},
+				Spec:       capiv1.MachineSetSpec{Replicas: pointer.Int32Ptr(2)},
+				Status:     capiv1.MachineSetStatus{Replicas: 2, AvailableReplicas: 2, ObservedGeneration: 1},
+			},
+			nodes:  done,
+			expect: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(inPlaceUpgradeComplete(tc.machineSet, tc.nodes, "target")).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestFetchIgnitionPayload(t *testing.T) {
+	g := NewWithT(t)
+	token := []byte("token")
+	available := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ignition" || r.Header.Get("Authorization") != "Bearer "+base64.StdEncoding.EncodeToString(token) || !available {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("payload"))
+	}))
+	defer server.Close()
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	ignEndpoint := strings.TrimPrefix(server.URL, "https://")
+
+	_, err := fetchIgnitionPayload(context.Background(), ignEndpoint, caCert, token)
+	g.Expect(err).To(HaveOccurred())
+
+	available = true
+	payload, err := fetchIgnitionPayload(context.Background(), ignEndpoint, caCert, token)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(payload)).To(Equal("payload"))
+}
@@ -1,6 +1,7 @@
 package nodepool
 
 import (
+	"encoding/json"
 	"fmt"
 
 	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
@@ -12,8 +13,11 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func machineDeployment(nodePool *hyperv1.NodePool, clusterName string, controlPlaneNamespace string) *capiv1.MachineDeployment {
+func machineDeployment(nodePool *hyperv1.NodePool, zone nodePoolZone, clusterName string, controlPlaneNamespace string) *capiv1.MachineDeployment {
 	resourcesName := generateName(clusterName, nodePool.Spec.ClusterName, nodePool.GetName())
+	if zone.name != "" {
+		resourcesName = generateName(clusterName, nodePool.Spec.ClusterName, fmt.Sprintf("%s-%s", nodePool.GetName(), zone.name))
+	}
 	return &capiv1.MachineDeployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      resourcesName,
@@ -32,15 +36,15 @@ func machineHealthCheck(nodePool *hyperv1.NodePool, controlPlaneNamespace string
 	}
 }
 
-func AWSMachineTemplate(infraName, ami string, nodePool *hyperv1.NodePool, controlPlaneNamespace string) (*capiaws.AWSMachineTemplate, string) {
+func AWSMachineTemplate(infraName, ami string, nodePool *hyperv1.NodePool, zone nodePoolZone, controlPlaneNamespace string) (*capiaws.AWSMachineTemplate, string) {
 	subnet := &capiaws.AWSResourceReference{}
-	if nodePool.Spec.Platform.AWS.Subnet != nil {
-		subnet.ID = nodePool.Spec.Platform.AWS.Subnet.ID
-		subnet.ARN = nodePool.Spec.Platform.AWS.Subnet.ARN
-		for k := range nodePool.Spec.Platform.AWS.Subnet.Filters {
+	if zone.subnet != nil {
+		subnet.ID = zone.subnet.ID
+		subnet.ARN = zone.subnet.ARN
+		for k := range zone.subnet.Filters {
 			filter := capiaws.Filter{
-				Name:   nodePool.Spec.Platform.AWS.Subnet.Filters[k].Name,
-				Values: nodePool.Spec.Platform.AWS.Subnet.Filters[k].Values,
+				Name:   zone.subnet.Filters[k].Name,
+				Values: zone.subnet.Filters[k].Values,
 			}
 			subnet.Filters = append(subnet.Filters, filter)
 		}
@@ -69,6 +73,15 @@ func AWSMachineTemplate(infraName, ami string, nodePool *hyperv1.NodePool, contr
 
 	instanceType := nodePool.Spec.Platform.AWS.InstanceType
 
+	var rootVolume *capiaws.Volume
+	if nodePool.Spec.Platform.AWS.RootVolume != nil {
+		rootVolume = awsVolume(*nodePool.Spec.Platform.AWS.RootVolume)
+	}
+	var nonRootVolumes []*capiaws.Volume
+	for _, volume := range nodePool.Spec.Platform.AWS.AdditionalVolumes {
+		nonRootVolumes = append(nonRootVolumes, awsVolume(volume))
+	}
+
 	awsMachineTemplate := &capiaws.AWSMachineTemplate{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
@@ -92,16 +105,41 @@ func AWSMachineTemplate(infraName, ami string, nodePool *hyperv1.NodePool, contr
 					},
 					AdditionalSecurityGroups: securityGroups,
 					Subnet:                   subnet,
+					RootVolume:               rootVolume,
+					NonRootVolumes:           nonRootVolumes,
+					Tenancy:                  nodePool.Spec.Platform.AWS.Tenancy,
+					// TODO: enforce IMDSv2 through the instance metadata options once imageCAPA
+					// and the vendored AWSMachineSpec are bumped to a cluster-api-provider-aws
+					// release which has them. The pinned build drops unknown fields, so
+					// setting it before would silently leave IMDSv1 enabled.
 				},
 			},
 		},
 	}
-	specHash := hashStruct(awsMachineTemplate.Spec.Template.Spec)
-	awsMachineTemplate.SetName(fmt.Sprintf("%s-%s", nodePool.GetName(), specHash))
+	// The spec is hashed through its JSON encoding, formatting it would hash the
+	// addresses of its pointers and give every reconcile a new template name.
+	spec, _ := json.Marshal(awsMachineTemplate.Spec.Template.Spec)
+	specHash := hashStruct(string(spec))
+	if zone.name != "" {
+		awsMachineTemplate.SetName(fmt.Sprintf("%s-%s-%s", nodePool.GetName(), zone.name, specHash))
+	} else {
+		awsMachineTemplate.SetName(fmt.Sprintf("%s-%s", nodePool.GetName(), specHash))
+	}
 
 	return awsMachineTemplate, specHash
 }
 
+func awsVolume(volume hyperv1.Volume) *capiaws.Volume {
+	return &capiaws.Volume{
+		DeviceName:    volume.DeviceName,
+		Size:          volume.Size,
+		Type:          volume.Type,
+		IOPS:          volume.IOPS,
+		Encrypted:     volume.Encrypted,
+		EncryptionKey: volume.EncryptionKey,
+	}
+}
+
 func IgnitionUserDataSecret(namespace, name, payloadInputHash string) *corev1.Secret {
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
@@ -119,3 +157,22 @@ func TokenSecret(namespace, name, payloadInputHash string) *corev1.Secret {
 		},
 	}
 }
+
+func machineSet(nodePool *hyperv1.NodePool, clusterName string, controlPlaneNamespace string) *capiv1.MachineSet {
+	resourcesName := generateName(clusterName, nodePool.Spec.ClusterName, nodePool.GetName())
+	return &capiv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resourcesName,
+			Namespace: controlPlaneNamespace,
+		},
+	}
+}
+
+func InPlaceUpgradePayloadSecret(namespace, name, payloadInputHash string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      fmt.Sprintf("inplace-upgrade-%s-%s", name, payloadInputHash),
+		},
+	}
+}
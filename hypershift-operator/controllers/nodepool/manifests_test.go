@@ -0,0 +1,49 @@
+package nodepool
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	capiaws "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapiprovideraws/v1alpha4"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestAWSMachineTemplate(t *testing.T) {
+	g := NewWithT(t)
+
+	nodePool := &hyperv1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodepool", Namespace: "clusters"},
+		Spec: hyperv1.NodePoolSpec{
+			Platform: hyperv1.NodePoolPlatform{
+				Type: hyperv1.AWSPlatform,
+				AWS: &hyperv1.AWSNodePoolPlatform{
+					InstanceType: "m5.large",
+					Subnet:       &hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet")},
+					RootVolume:   &hyperv1.Volume{Size: 120, Type: "io1", IOPS: 3000, Encrypted: true, EncryptionKey: "key"},
+					AdditionalVolumes: []hyperv1.Volume{
+						{DeviceName: "/dev/sdb", Size: 200, Type: "gp3"},
+					},
+					Tenancy: "dedicated",
+				},
+			},
+		},
+	}
+	template, _ := AWSMachineTemplate("infra", "ami", nodePool, nodePoolZones(nodePool)[0], "clusters-cluster")
+	spec := template.Spec.Template.Spec
+	g.Expect(spec.RootVolume).To(Equal(&capiaws.Volume{Size: 120, Type: "io1", IOPS: 3000, Encrypted: true, EncryptionKey: "key"}))
+	g.Expect(spec.NonRootVolumes).To(Equal([]*capiaws.Volume{{DeviceName: "/dev/sdb", Size: 200, Type: "gp3"}}))
+	g.Expect(spec.Tenancy).To(Equal("dedicated"))
+
+	// The same spec always gets the same template name.
+	again, _ := AWSMachineTemplate("infra", "ami", nodePool, nodePoolZones(nodePool)[0], "clusters-cluster")
+	g.Expect(again.Name).To(Equal(template.Name))
+
+	// Changing a volume changes the template spec, so a new template is
+	// rolled out.
+	nodePool.Spec.Platform.AWS.RootVolume.Size = 240
+	updated, _ := AWSMachineTemplate("infra", "ami", nodePool, nodePoolZones(nodePool)[0], "clusters-cluster")
+	g.Expect(equality.Semantic.DeepEqual(spec, updated.Spec.Template.Spec)).To(BeFalse())
+}
@@ -0,0 +1,156 @@
+package nodepool
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift/hypershift/api/nodesync"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// TokenSecretKubeletArgsKey holds the kubelet arguments the nodes of a
+	// NodePool register with.
+	TokenSecretKubeletArgsKey = "kubeletArgs"
+)
+
+// kubeletLabelNamespaces are the label namespaces within kubernetes.io and
+// k8s.io the kubelet can set labels in.
+var kubeletLabelNamespaces = []string{
+	"kubelet.kubernetes.io",
+	"node.kubernetes.io",
+}
+
+// kubeletLabels are the labels of the kubernetes.io and k8s.io namespaces
+// outside of kubeletLabelNamespaces the kubelet can set.
+var kubeletLabels = map[string]bool{
+	"kubernetes.io/hostname":                   true,
+	"kubernetes.io/arch":                       true,
+	"kubernetes.io/os":                         true,
+	"topology.kubernetes.io/zone":              true,
+	"topology.kubernetes.io/region":            true,
+	"node.kubernetes.io/instance-type":         true,
+	"beta.kubernetes.io/arch":                  true,
+	"beta.kubernetes.io/os":                    true,
+	"beta.kubernetes.io/instance-type":         true,
+	"failure-domain.beta.kubernetes.io/zone":   true,
+	"failure-domain.beta.kubernetes.io/region": true,
+}
+
+// isKubeletLabel returns whether the kubelet can register a node with the
+// label key. The kubelet refuses to start with labels of the kubernetes.io
+// and k8s.io namespaces it doesn't own, e.g. node-role.kubernetes.io/infra.
+func isKubeletLabel(key string) bool {
+	namespace := ""
+	if i := strings.Index(key, "/"); i >= 0 {
+		namespace = key[:i]
+	}
+	if !isKubernetesNamespace(namespace, "kubernetes.io") && !isKubernetesNamespace(namespace, "k8s.io") {
+		return true
+	}
+	if kubeletLabels[key] {
+		return true
+	}
+	for _, kubeletNamespace := range kubeletLabelNamespaces {
+		if isKubernetesNamespace(namespace, kubeletNamespace) {
+			return true
+		}
+	}
+	return false
+}
+
+func isKubernetesNamespace(namespace, domain string) bool {
+	return namespace == domain || strings.HasSuffix(namespace, "."+domain)
+}
+
+// registrationNodeLabels returns the node labels of a NodePool the kubelet
+// registers nodes with. The other ones are applied by the hosted cluster
+// config operator once the nodes registered.
+func registrationNodeLabels(nodePool *hyperv1.NodePool) map[string]string {
+	labels := map[string]string{}
+	for key, value := range nodePool.Spec.NodeLabels {
+		if isKubeletLabel(key) {
+			labels[key] = value
+		}
+	}
+	return labels
+}
+
+// kubeletArgs returns the kubelet arguments which register the nodes of a
+// NodePool with its labels and taints.
+func kubeletArgs(nodePool *hyperv1.NodePool) string {
+	var args []string
+	if registrationLabels := registrationNodeLabels(nodePool); len(registrationLabels) > 0 {
+		labels := make([]string, 0, len(registrationLabels))
+		for key, value := range registrationLabels {
+			labels = append(labels, fmt.Sprintf("%s=%s", key, value))
+		}
+		sort.Strings(labels)
+		args = append(args, "--node-labels="+strings.Join(labels, ","))
+	}
+	if len(nodePool.Spec.Taints) > 0 {
+		taints := make([]string, 0, len(nodePool.Spec.Taints))
+		for _, taint := range nodePool.Spec.Taints {
+			if taint.Value == "" {
+				taints = append(taints, fmt.Sprintf("%s:%s", taint.Key, taint.Effect))
+			} else {
+				taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+			}
+		}
+		sort.Strings(taints)
+		args = append(args, "--register-with-taints="+strings.Join(taints, ","))
+	}
+	return strings.Join(args, " ")
+}
+
+// setRegisteredNodeLabelsAndTaintsAnnotations records the node labels and
+// taints the kubelet arguments of a token Secret register nodes with.
+func setRegisteredNodeLabelsAndTaintsAnnotations(nodePool *hyperv1.NodePool, tokenSecret *corev1.Secret) error {
+	if tokenSecret.Annotations == nil {
+		tokenSecret.Annotations = map[string]string{}
+	}
+	labels, err := json.Marshal(registrationNodeLabels(nodePool))
+	if err != nil {
+		return fmt.Errorf("failed to encode node labels: %w", err)
+	}
+	tokenSecret.Annotations[nodesync.MachineSetAnnotationRegisteredNodeLabels] = string(labels)
+	taints, err := json.Marshal(nodePool.Spec.Taints)
+	if err != nil {
+		return fmt.Errorf("failed to encode taints: %w", err)
+	}
+	tokenSecret.Annotations[nodesync.MachineSetAnnotationRegisteredTaints] = string(taints)
+	return nil
+}
+
+// setNodeLabelsAndTaintsAnnotations records the node labels and taints of a
+// NodePool, and the ones new nodes register with, in the annotations of obj.
+func setNodeLabelsAndTaintsAnnotations(nodePool *hyperv1.NodePool, tokenSecret *corev1.Secret, obj *metav1.ObjectMeta) error {
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	labels, err := json.Marshal(nodePool.Spec.NodeLabels)
+	if err != nil {
+		return fmt.Errorf("failed to encode node labels: %w", err)
+	}
+	obj.Annotations[nodesync.MachineSetAnnotationNodeLabels] = string(labels)
+	taints, err := json.Marshal(nodePool.Spec.Taints)
+	if err != nil {
+		return fmt.Errorf("failed to encode taints: %w", err)
+	}
+	obj.Annotations[nodesync.MachineSetAnnotationTaints] = string(taints)
+
+	// Token Secrets created before the registered labels and taints were
+	// recorded don't have them.
+	for _, key := range []string{nodesync.MachineSetAnnotationRegisteredNodeLabels, nodesync.MachineSetAnnotationRegisteredTaints} {
+		if value, ok := tokenSecret.Annotations[key]; ok {
+			obj.Annotations[key] = value
+		} else {
+			delete(obj.Annotations, key)
+		}
+	}
+	return nil
+}
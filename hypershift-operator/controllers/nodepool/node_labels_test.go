@@ -0,0 +1,122 @@
+package nodepool
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/openshift/hypershift/api/nodesync"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKubeletArgs(t *testing.T) {
+	testCases := []struct {
+		name       string
+		nodeLabels map[string]string
+		taints     []hyperv1.Taint
+		expect     string
+	}{
+		{
+			name:   "it is empty without labels and taints",
+			expect: "",
+		},
+		{
+			name:       "it registers nodes with sorted labels",
+			nodeLabels: map[string]string{"team": "a", "node.kubernetes.io/pool": "a"},
+			expect:     "--node-labels=node.kubernetes.io/pool=a,team=a",
+		},
+		{
+			name:       "it leaves out labels the kubelet can't set",
+			nodeLabels: map[string]string{"team": "a", "node-role.kubernetes.io/infra": "", "example.k8s.io/a": "b"},
+			expect:     "--node-labels=team=a",
+		},
+		{
+			name:       "it is empty with only labels the kubelet can't set",
+			nodeLabels: map[string]string{"node-role.kubernetes.io/infra": ""},
+			expect:     "",
+		},
+		{
+			name:       "it registers nodes with labels and taints",
+			nodeLabels: map[string]string{"team": "a"},
+			taints: []hyperv1.Taint{
+				{Key: "node-role.kubernetes.io/infra", Effect: corev1.TaintEffectNoSchedule},
+				{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoExecute},
+			},
+			expect: "--node-labels=team=a --register-with-taints=dedicated=a:NoExecute,node-role.kubernetes.io/infra:NoSchedule",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			nodePool := &hyperv1.NodePool{
+				Spec: hyperv1.NodePoolSpec{
+					NodeLabels: tc.nodeLabels,
+					Taints:     tc.taints,
+				},
+			}
+			g.Expect(kubeletArgs(nodePool)).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestIsKubeletLabel(t *testing.T) {
+	testCases := []struct {
+		key    string
+		expect bool
+	}{
+		{key: "team", expect: true},
+		{key: "example.com/team", expect: true},
+		{key: "kubernetes.io.example.com/team", expect: true},
+		{key: "kubernetes.io/hostname", expect: true},
+		{key: "topology.kubernetes.io/zone", expect: true},
+		{key: "node.kubernetes.io/pool", expect: true},
+		{key: "pool.node.kubernetes.io/a", expect: true},
+		{key: "kubelet.kubernetes.io/a", expect: true},
+		{key: "node-role.kubernetes.io/infra", expect: false},
+		{key: "node-restriction.kubernetes.io/a", expect: false},
+		{key: "kubernetes.io/team", expect: false},
+		{key: "example.k8s.io/a", expect: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.key, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isKubeletLabel(tc.key)).To(Equal(tc.expect))
+		})
+	}
+}
+
+func TestSetNodeLabelsAndTaintsAnnotations(t *testing.T) {
+	g := NewWithT(t)
+	nodePool := &hyperv1.NodePool{
+		Spec: hyperv1.NodePoolSpec{
+			NodeLabels: map[string]string{"team": "a", "node-role.kubernetes.io/infra": ""},
+			Taints:     []hyperv1.Taint{{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+	tokenSecret := &corev1.Secret{}
+	g.Expect(setRegisteredNodeLabelsAndTaintsAnnotations(nodePool, tokenSecret)).To(Succeed())
+	g.Expect(tokenSecret.Annotations).To(Equal(map[string]string{
+		nodesync.MachineSetAnnotationRegisteredNodeLabels: `{"team":"a"}`,
+		nodesync.MachineSetAnnotationRegisteredTaints:     `[{"key":"dedicated","value":"a","effect":"NoSchedule"}]`,
+	}))
+
+	obj := &metav1.ObjectMeta{}
+	g.Expect(setNodeLabelsAndTaintsAnnotations(nodePool, tokenSecret, obj)).To(Succeed())
+	g.Expect(obj.Annotations).To(Equal(map[string]string{
+		nodesync.MachineSetAnnotationNodeLabels:           `{"node-role.kubernetes.io/infra":"","team":"a"}`,
+		nodesync.MachineSetAnnotationTaints:               `[{"key":"dedicated","value":"a","effect":"NoSchedule"}]`,
+		nodesync.MachineSetAnnotationRegisteredNodeLabels: `{"team":"a"}`,
+		nodesync.MachineSetAnnotationRegisteredTaints:     `[{"key":"dedicated","value":"a","effect":"NoSchedule"}]`,
+	}))
+
+	nodePool.Spec.NodeLabels = nil
+	nodePool.Spec.Taints = nil
+	g.Expect(setNodeLabelsAndTaintsAnnotations(nodePool, &corev1.Secret{}, obj)).To(Succeed())
+	g.Expect(obj.Annotations).To(Equal(map[string]string{
+		nodesync.MachineSetAnnotationNodeLabels: "null",
+		nodesync.MachineSetAnnotationTaints:     "null",
+	}))
+}
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/blang/semver"
 	ignitionapi "github.com/coreos/ignition/v2/config/v3_1/types"
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
@@ -35,6 +36,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	serializer "k8s.io/apimachinery/pkg/runtime/serializer/json"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -50,6 +52,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -80,6 +83,9 @@ func (r *NodePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		// We want to reconcile when the HostedCluster IgnitionEndpoint is available.
 		Watches(&source.Kind{Type: &hyperv1.HostedCluster{}}, handler.EnqueueRequestsFromMapFunc(r.enqueueNodePoolsForHostedCluster)).
 		Watches(&source.Kind{Type: &capiv1.MachineDeployment{}}, handler.EnqueueRequestsFromMapFunc(enqueueParentNodePool)).
+		// We want to reconcile when InPlace NodePools are scaled or their nodes report upgrade progress.
+		Watches(&source.Kind{Type: &capiv1.MachineSet{}}, handler.EnqueueRequestsFromMapFunc(enqueueParentNodePool)).
+		Watches(&source.Kind{Type: &capiv1.Machine{}}, handler.EnqueueRequestsFromMapFunc(enqueueParentNodePool)).
 		Watches(&source.Kind{Type: &capiaws.AWSMachineTemplate{}}, handler.EnqueueRequestsFromMapFunc(enqueueParentNodePool)).
 		// We want to reconcile when the user data Secret or the token Secret is unexpectedly changed out of band.
 		Watches(&source.Kind{Type: &corev1.Secret{}}, handler.EnqueueRequestsFromMapFunc(enqueueParentNodePool)).
@@ -131,7 +137,7 @@ func (r *NodePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	// before the targeted version is the one in the status then the tokenSecret and userDataSecret would be leaked.
 	tokenSecret := TokenSecret(controlPlaneNamespace, nodePool.Name, nodePool.GetAnnotations()[nodePoolAnnotationCurrentConfigVersion])
 	userDataSecret := IgnitionUserDataSecret(controlPlaneNamespace, nodePool.GetName(), nodePool.GetAnnotations()[nodePoolAnnotationCurrentConfigVersion])
-	md := machineDeployment(nodePool, hcluster.Spec.InfraID, controlPlaneNamespace)
+	ms := machineSet(nodePool, hcluster.Spec.InfraID, controlPlaneNamespace)
 	mhc := machineHealthCheck(nodePool, controlPlaneNamespace)
 
 	if !nodePool.DeletionTimestamp.IsZero() {
@@ -150,8 +156,22 @@ func (r *NodePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			return reconcile.Result{}, fmt.Errorf("failed to delete token Secret: %w", err)
 		}
 
-		if err := r.Delete(ctx, md); err != nil && !apierrors.IsNotFound(err) {
-			return reconcile.Result{}, fmt.Errorf("failed to delete MachineDeployment: %w", err)
+		machineDeployments, err := r.listMachineDeployments(ctx, nodePool, controlPlaneNamespace)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		for k := range machineDeployments {
+			if err := r.Delete(ctx, &machineDeployments[k]); err != nil && !apierrors.IsNotFound(err) {
+				return reconcile.Result{}, fmt.Errorf("failed to delete MachineDeployment: %w", err)
+			}
+		}
+
+		if err := r.Delete(ctx, ms); err != nil && !apierrors.IsNotFound(err) {
+			return reconcile.Result{}, fmt.Errorf("failed to delete MachineSet: %w", err)
+		}
+
+		if err := r.deleteInPlaceUpgradePayloads(ctx, nodePool, controlPlaneNamespace); err != nil {
+			return reconcile.Result{}, err
 		}
 
 		if err := r.Delete(ctx, userDataSecret); err != nil && !apierrors.IsNotFound(err) {
@@ -205,7 +225,7 @@ func (r *NodePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	}
 
 	log.Info("Successfully reconciled")
-	return ctrl.Result{}, nil
+	return result, nil
 }
 
 func (r *NodePoolReconciler) reconcile(ctx context.Context, hcluster *hyperv1.HostedCluster, nodePool *hyperv1.NodePool) (ctrl.Result, error) {
@@ -377,7 +397,7 @@ func (r *NodePoolReconciler) reconcile(ctx context.Context, hcluster *hyperv1.Ho
 	})
 
 	// Validate config input.
-	config, err := r.getConfig(ctx, nodePool)
+	config, err := r.getConfig(ctx, nodePool, releaseImage.Version())
 	if err != nil {
 		meta.SetStatusCondition(&nodePool.Status.Conditions, metav1.Condition{
 			Type:               hyperv1.NodePoolConfigValidConfigConditionType,
@@ -396,6 +416,8 @@ func (r *NodePoolReconciler) reconcile(ctx context.Context, hcluster *hyperv1.Ho
 	})
 
 	// Check if config needs to be updated.
+	// Node labels and taints are not part of the config, the hosted cluster
+	// config operator keeps existing nodes in sync with them.
 	targetConfigHash := hashStruct(config)
 	isUpdatingConfig := isUpdatingConfig(nodePool, targetConfigHash)
 	if isUpdatingConfig {
@@ -477,31 +499,102 @@ func (r *NodePoolReconciler) reconcile(ctx context.Context, hcluster *hyperv1.Ho
 		span.AddEvent("reconciled ignition user data secret", trace.WithAttributes(attribute.String("result", string(result))))
 	}
 
-	var machineTemplate client.Object
-	switch nodePool.Spec.Platform.Type {
-	case hyperv1.AWSPlatform:
-		machineTemplate, err = r.reconcileAWSMachineTemplate(ctx, nodePool, infraID, ami, controlPlaneNamespace)
+	zones := nodePoolZones(nodePool)
+	var result ctrl.Result
+	switch nodePool.Spec.Management.UpgradeType {
+	case hyperv1.UpgradeTypeInPlace:
+		// InPlace NodePools are not spread across zones.
+		machineTemplate, err := r.reconcileMachineTemplate(ctx, nodePool, zones[0], infraID, ami, controlPlaneNamespace,
+			fmt.Sprintf("%s-%s", nodePool.GetName(), nodePool.GetAnnotations()[nodePoolAnnotationCurrentProviderConfig]))
 		if err != nil {
-			return ctrl.Result{}, fmt.Errorf("failed to reconcile AWSMachineTemplate: %w", err)
+			return ctrl.Result{}, err
 		}
-		span.AddEvent("reconciled awsmachinetemplate", trace.WithAttributes(attribute.String("name", machineTemplate.GetName())))
-	}
-
-	md := machineDeployment(nodePool, infraID, controlPlaneNamespace)
-	if result, err := controllerutil.CreateOrPatch(ctx, r.Client, md, func() error {
-		return r.reconcileMachineDeployment(
-			log,
-			md, nodePool,
-			userDataSecret,
-			machineTemplate,
-			infraID,
-			targetVersion, targetConfigHash, targetConfigVersionHash)
-	}); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to reconcile MachineDeployment %q: %w",
-			client.ObjectKeyFromObject(md).String(), err)
-	} else {
-		log.Info("Reconciled MachineDeployment", "result", result)
-		span.AddEvent("reconciled machinedeployment", trace.WithAttributes(attribute.String("result", string(result))))
+
+		// In-place upgrade payloads are immutable and follow "prefixName-configVersionHash" naming convention.
+		if isUpdatingVersion || isUpdatingConfig {
+			payloadSecret := InPlaceUpgradePayloadSecret(controlPlaneNamespace, nodePool.GetName(), nodePool.GetAnnotations()[nodePoolAnnotationCurrentConfigVersion])
+			if err := r.Delete(ctx, payloadSecret); err != nil && !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, fmt.Errorf("failed to delete in-place upgrade payload Secret: %w", err)
+			}
+		}
+
+		// The payload is only available once the ignition server generated it for the new token,
+		// until then existing nodes are not updated.
+		payloadSecret := InPlaceUpgradePayloadSecret(controlPlaneNamespace, nodePool.GetName(), targetConfigVersionHash)
+		if err := r.reconcileInPlaceUpgradePayload(ctx, payloadSecret, nodePool, caCertBytes, tokenBytes, ignEndpoint); err != nil {
+			log.Info("In-place upgrade payload not available yet, waiting", "reason", err.Error())
+			payloadSecret = nil
+			result.RequeueAfter = 30 * time.Second
+		} else {
+			span.AddEvent("reconciled in-place upgrade payload secret", trace.WithAttributes(attribute.String("name", payloadSecret.Name)))
+		}
+
+		machines, err := r.listMachines(ctx, nodePool, infraID, controlPlaneNamespace)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		ms := machineSet(nodePool, infraID, controlPlaneNamespace)
+		if result, err := controllerutil.CreateOrPatch(ctx, r.Client, ms, func() error {
+			return r.reconcileMachineSet(
+				log,
+				ms, nodePool,
+				tokenSecret,
+				userDataSecret,
+				payloadSecret,
+				machineTemplate,
+				machines,
+				infraID,
+				releaseImage.ComponentImages()[machineConfigOperatorComponent],
+				targetVersion, targetConfigHash, targetConfigVersionHash)
+		}); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to reconcile MachineSet %q: %w",
+				client.ObjectKeyFromObject(ms).String(), err)
+		} else {
+			log.Info("Reconciled MachineSet", "result", result)
+			span.AddEvent("reconciled machineset", trace.WithAttributes(attribute.String("result", string(result))))
+		}
+	default:
+		// Every zone of the NodePool is backed by its own MachineDeployment.
+		var mds []*capiv1.MachineDeployment
+		for _, zone := range zones {
+			md := machineDeployment(nodePool, zone, infraID, controlPlaneNamespace)
+			if err := r.Get(ctx, client.ObjectKeyFromObject(md), md); err != nil && !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, fmt.Errorf("failed to get MachineDeployment %q: %w", client.ObjectKeyFromObject(md).String(), err)
+			}
+			// The current machine template of a zone is the one its MachineDeployment references.
+			currentTemplateName := md.Spec.Template.Spec.InfrastructureRef.Name
+			if zone.name == "" {
+				currentTemplateName = fmt.Sprintf("%s-%s", nodePool.GetName(), nodePool.GetAnnotations()[nodePoolAnnotationCurrentProviderConfig])
+			}
+			machineTemplate, err := r.reconcileMachineTemplate(ctx, nodePool, zone, infraID, ami, controlPlaneNamespace, currentTemplateName)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			if result, err := controllerutil.CreateOrPatch(ctx, r.Client, md, func() error {
+				return r.reconcileMachineDeployment(
+					log,
+					md, nodePool,
+					zone,
+					tokenSecret,
+					userDataSecret,
+					machineTemplate,
+					infraID,
+					targetVersion, targetConfigHash)
+			}); err != nil {
+				return ctrl.Result{}, fmt.Errorf("failed to reconcile MachineDeployment %q: %w",
+					client.ObjectKeyFromObject(md).String(), err)
+			} else {
+				log.Info("Reconciled MachineDeployment", "name", md.Name, "result", result)
+				span.AddEvent("reconciled machinedeployment", trace.WithAttributes(attribute.String("name", md.Name), attribute.String("result", string(result))))
+			}
+			mds = append(mds, md)
+		}
+		if err := r.deleteStaleMachineDeployments(ctx, nodePool, controlPlaneNamespace, mds); err != nil {
+			return ctrl.Result{}, err
+		}
+		reconcileMachineDeploymentsStatus(log, nodePool, mds, userDataSecret, targetVersion, targetConfigHash, targetConfigVersionHash)
 	}
 
 	mhc := machineHealthCheck(nodePool, controlPlaneNamespace)
@@ -535,27 +628,45 @@ func (r *NodePoolReconciler) reconcile(ctx context.Context, hcluster *hyperv1.Ho
 		})
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// reconcileMachineTemplate reconciles the platform specific machine template
+// of a zone of a NodePool. currentTemplateName is the template the zone uses
+// so far.
+func (r NodePoolReconciler) reconcileMachineTemplate(ctx context.Context,
+	nodePool *hyperv1.NodePool, zone nodePoolZone, infraID, ami, controlPlaneNamespace, currentTemplateName string) (client.Object, error) {
+	switch nodePool.Spec.Platform.Type {
+	case hyperv1.AWSPlatform:
+		machineTemplate, err := r.reconcileAWSMachineTemplate(ctx, nodePool, zone, infraID, ami, controlPlaneNamespace, currentTemplateName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reconcile AWSMachineTemplate: %w", err)
+		}
+		trace.SpanFromContext(ctx).AddEvent("reconciled awsmachinetemplate", trace.WithAttributes(attribute.String("name", machineTemplate.GetName())))
+		return machineTemplate, nil
+	}
+	return nil, nil
 }
 
 func (r NodePoolReconciler) reconcileAWSMachineTemplate(ctx context.Context,
-	nodePool *hyperv1.NodePool, infraID, ami, controlPlaneNamespace string) (*capiaws.AWSMachineTemplate, error) {
+	nodePool *hyperv1.NodePool, zone nodePoolZone, infraID, ami, controlPlaneNamespace, currentTemplateName string) (*capiaws.AWSMachineTemplate, error) {
 
 	log := ctrl.LoggerFrom(ctx)
 	// Get target template and hash.
-	targetAWSMachineTemplate, targetTemplateHash := AWSMachineTemplate(infraID, ami, nodePool, controlPlaneNamespace)
+	targetAWSMachineTemplate, targetTemplateHash := AWSMachineTemplate(infraID, ami, nodePool, zone, controlPlaneNamespace)
 
-	// Get current template and hash.
-	currentTemplateHash := nodePool.GetAnnotations()[nodePoolAnnotationCurrentProviderConfig]
+	// Get current template.
 	currentAWSMachineTemplate := &capiaws.AWSMachineTemplate{
 		TypeMeta: metav1.TypeMeta{},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s-%s", nodePool.GetName(), currentTemplateHash),
+			Name:      currentTemplateName,
 			Namespace: controlPlaneNamespace,
 		},
 	}
-	if err := r.Get(ctx, client.ObjectKeyFromObject(currentAWSMachineTemplate), currentAWSMachineTemplate); err != nil && !apierrors.IsNotFound(err) {
-		return nil, fmt.Errorf("error getting existing AWSMachineTemplate: %w", err)
+	if currentTemplateName != "" {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(currentAWSMachineTemplate), currentAWSMachineTemplate); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("error getting existing AWSMachineTemplate: %w", err)
+		}
 	}
 
 	// Template has not changed, return early.
@@ -567,7 +678,14 @@ func (r NodePoolReconciler) reconcileAWSMachineTemplate(ctx context.Context,
 	// Otherwise create new template.
 	log.Info("The AWSMachineTemplate referenced by this NodePool has changed. Creating a new one")
 	if err := r.Create(ctx, targetAWSMachineTemplate); err != nil {
-		return nil, fmt.Errorf("error creating new AWSMachineTemplate: %w", err)
+		if !apierrors.IsAlreadyExists(err) {
+			return nil, fmt.Errorf("error creating new AWSMachineTemplate: %w", err)
+		}
+		// A previous reconcile created the template but failed to reference it.
+		// Templates are named by the hash of their spec, so the existing one is reused.
+		if err := r.Get(ctx, client.ObjectKeyFromObject(targetAWSMachineTemplate), targetAWSMachineTemplate); err != nil {
+			return nil, fmt.Errorf("error getting existing AWSMachineTemplate: %w", err)
+		}
 	}
 
 	// TODO (alberto): Create a mechanism to cleanup old machineTemplates.
@@ -577,11 +695,13 @@ func (r NodePoolReconciler) reconcileAWSMachineTemplate(ctx context.Context,
 	// May be consider one single template the whole NodePool lifecycle. Modify it in place
 	// and trigger rolling update by e.g annotating the machineDeployment.
 
-	// Store new template hash.
-	if nodePool.Annotations == nil {
-		nodePool.Annotations = make(map[string]string)
+	// Store new template hash. MachineDeployments of zones reference their current template instead.
+	if zone.name == "" {
+		if nodePool.Annotations == nil {
+			nodePool.Annotations = make(map[string]string)
+		}
+		nodePool.Annotations[nodePoolAnnotationCurrentProviderConfig] = targetTemplateHash
 	}
-	nodePool.Annotations[nodePoolAnnotationCurrentProviderConfig] = targetTemplateHash
 
 	return targetAWSMachineTemplate, nil
 }
@@ -620,6 +740,14 @@ func reconcileTokenSecret(tokenSecret *corev1.Secret, nodePool *hyperv1.NodePool
 		tokenSecret.Data[TokenSecretTokenKey] = []byte(uuid.New().String())
 		tokenSecret.Data[TokenSecretReleaseKey] = []byte(nodePool.Spec.Release.Image)
 		tokenSecret.Data[TokenSecretConfigKey] = compressedConfig
+		// New nodes register with the node labels and taints the NodePool has
+		// when the token Secret is created.
+		if kubeletArgs := kubeletArgs(nodePool); kubeletArgs != "" {
+			tokenSecret.Data[TokenSecretKubeletArgsKey] = []byte(kubeletArgs)
+		}
+		if err := setRegisteredNodeLabelsAndTaintsAnnotations(nodePool, tokenSecret); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -627,17 +755,22 @@ func reconcileTokenSecret(tokenSecret *corev1.Secret, nodePool *hyperv1.NodePool
 func (r *NodePoolReconciler) reconcileMachineDeployment(log logr.Logger,
 	machineDeployment *capiv1.MachineDeployment,
 	nodePool *hyperv1.NodePool,
+	zone nodePoolZone,
+	tokenSecret *corev1.Secret,
 	userDataSecret *corev1.Secret,
 	machineTemplateCR client.Object,
 	CAPIClusterName string,
 	targetVersion,
-	targetConfigHash, targetConfigVersionHash string) error {
+	targetConfigHash string) error {
 
 	// Set annotations and labels
 	if machineDeployment.GetAnnotations() == nil {
 		machineDeployment.Annotations = map[string]string{}
 	}
 	machineDeployment.Annotations[nodePoolAnnotation] = client.ObjectKeyFromObject(nodePool).String()
+	if err := setNodeLabelsAndTaintsAnnotations(nodePool, tokenSecret, &machineDeployment.ObjectMeta); err != nil {
+		return err
+	}
 	if machineDeployment.GetLabels() == nil {
 		machineDeployment.Labels = map[string]string{}
 	}
@@ -657,12 +790,18 @@ func (r *NodePoolReconciler) reconcileMachineDeployment(log logr.Logger,
 		machineDeployment.Spec.Selector.MatchLabels = map[string]string{}
 	}
 	machineDeployment.Spec.Selector.MatchLabels[resourcesName] = resourcesName
+	templateLabels := map[string]string{
+		resourcesName:           resourcesName,
+		capiv1.ClusterLabelName: CAPIClusterName,
+	}
+	// The MachineDeployments of the zones of a NodePool only select the Machines of their zone.
+	if zone.name != "" {
+		machineDeployment.Spec.Selector.MatchLabels[nodePoolZoneLabel] = zone.name
+		templateLabels[nodePoolZoneLabel] = zone.name
+	}
 	machineDeployment.Spec.Template = capiv1.MachineTemplateSpec{
 		ObjectMeta: capiv1.ObjectMeta{
-			Labels: map[string]string{
-				resourcesName:           resourcesName,
-				capiv1.ClusterLabelName: CAPIClusterName,
-			},
+			Labels: templateLabels,
 			// TODO (alberto): drop/expose this annotation at the nodePool API
 			Annotations: map[string]string{
 				"machine.cluster.x-k8s.io/exclude-node-draining": "true",
@@ -720,14 +859,40 @@ func (r *NodePoolReconciler) reconcileMachineDeployment(log logr.Logger,
 		// Before persisting if the NodePool is brand new we want to make sure the replica number is set so the machineDeployment controller
 		// does not panic.
 		if machineDeployment.Spec.Replicas == nil {
-			machineDeployment.Spec.Replicas = k8sutilspointer.Int32Ptr(k8sutilspointer.Int32PtrDerefOr(nodePool.Spec.NodeCount, 1))
+			replicas := int32(1)
+			if nodePool.Spec.NodeCount != nil {
+				replicas = zone.share(*nodePool.Spec.NodeCount)
+			}
+			machineDeployment.Spec.Replicas = k8sutilspointer.Int32Ptr(replicas)
 		}
 		return nil
 	}
 
-	// If the MachineDeployment is no processing we know
-	// is at the expected version (spec.version) and config (userData Secret) so we reconcile status and annotation.
-	if MachineDeploymentComplete(machineDeployment) {
+	setMachineDeploymentReplicas(nodePool, zone, machineDeployment)
+	return nil
+}
+
+// reconcileMachineDeploymentsStatus aggregates the status of the MachineDeployments of
+// the zones of a NodePool into the NodePool.
+func reconcileMachineDeploymentsStatus(log logr.Logger,
+	nodePool *hyperv1.NodePool,
+	machineDeployments []*capiv1.MachineDeployment,
+	userDataSecret *corev1.Secret,
+	targetVersion,
+	targetConfigHash, targetConfigVersionHash string) {
+
+	// If no MachineDeployment is processing we know
+	// they are at the expected version (spec.version) and config (userData Secret) so we reconcile status and annotation.
+	complete := true
+	var nodeCount int32
+	for _, machineDeployment := range machineDeployments {
+		if k8sutilspointer.StringPtrDerefOr(machineDeployment.Spec.Template.Spec.Bootstrap.DataSecretName, "") != userDataSecret.Name ||
+			!MachineDeploymentComplete(machineDeployment) {
+			complete = false
+		}
+		nodeCount += machineDeployment.Status.AvailableReplicas
+	}
+	if complete {
 		if nodePool.Status.Version != targetVersion {
 			log.Info("Version update complete",
 				"previous", nodePool.Status.Version, "new", targetVersion)
@@ -742,10 +907,7 @@ func (r *NodePoolReconciler) reconcileMachineDeployment(log logr.Logger,
 		nodePool.Annotations[nodePoolAnnotationCurrentConfigVersion] = targetConfigVersionHash
 	}
 
-	setMachineDeploymentReplicas(nodePool, machineDeployment)
-
-	nodePool.Status.NodeCount = machineDeployment.Status.AvailableReplicas
-	return nil
+	nodePool.Status.NodeCount = nodeCount
 }
 
 func (r *NodePoolReconciler) reconcileMachineHealthCheck(mhc *capiv1.MachineHealthCheck,
@@ -787,29 +949,41 @@ func (r *NodePoolReconciler) reconcileMachineHealthCheck(mhc *capiv1.MachineHeal
 	return nil
 }
 
-// setMachineDeploymentReplicas sets wanted replicas:
+// setMachineDeploymentReplicas sets wanted replicas for the MachineDeployment of a zone:
 // If autoscaling is enabled we reconcile min/max annotations and leave replicas untouched.
-func setMachineDeploymentReplicas(nodePool *hyperv1.NodePool, machineDeployment *capiv1.MachineDeployment) {
-	if machineDeployment.Annotations == nil {
-		machineDeployment.Annotations = make(map[string]string)
+func setMachineDeploymentReplicas(nodePool *hyperv1.NodePool, zone nodePoolZone, machineDeployment *capiv1.MachineDeployment) {
+	machineDeployment.Spec.Replicas = machineReplicas(nodePool, zone, &machineDeployment.ObjectMeta, machineDeployment.Spec.Replicas)
+}
+
+// setMachineSetReplicas sets wanted replicas for the MachineSet of InPlace NodePools
+// the same way setMachineDeploymentReplicas does for the MachineDeployment.
+func setMachineSetReplicas(nodePool *hyperv1.NodePool, machineSet *capiv1.MachineSet) {
+	machineSet.Spec.Replicas = machineReplicas(nodePool, nodePoolZones(nodePool)[0], &machineSet.ObjectMeta, machineSet.Spec.Replicas)
+}
+
+// machineReplicas reconciles the autoscaler annotations of a MachineDeployment or MachineSet
+// and returns its wanted replicas. The NodeCount and the autoscaling min/max of the NodePool
+// are distributed across its zones.
+func machineReplicas(nodePool *hyperv1.NodePool, zone nodePoolZone, objectMeta *metav1.ObjectMeta, replicas *int32) *int32 {
+	if objectMeta.Annotations == nil {
+		objectMeta.Annotations = make(map[string]string)
 	}
 
 	if isAutoscalingEnabled(nodePool) {
-		if machineDeployment.CreationTimestamp.IsZero() {
-			// if autoscaling is enabled and the machineDeployment does not exist yet and so it has nil/0 replicas
+		if objectMeta.CreationTimestamp.IsZero() {
+			// if autoscaling is enabled and the resource does not exist yet and so it has nil/0 replicas
 			// we start with 1 replica as the autoscaler does not support scaling from zero yet.
-			machineDeployment.Spec.Replicas = k8sutilspointer.Int32Ptr(int32(1))
+			replicas = k8sutilspointer.Int32Ptr(int32(1))
 		}
-		machineDeployment.Annotations[autoscalerMaxAnnotation] = strconv.Itoa(int(nodePool.Spec.AutoScaling.Max))
-		machineDeployment.Annotations[autoscalerMinAnnotation] = strconv.Itoa(int(nodePool.Spec.AutoScaling.Min))
+		objectMeta.Annotations[autoscalerMaxAnnotation] = strconv.Itoa(int(zone.share(nodePool.Spec.AutoScaling.Max)))
+		objectMeta.Annotations[autoscalerMinAnnotation] = strconv.Itoa(int(zone.share(nodePool.Spec.AutoScaling.Min)))
+		return replicas
 	}
 
 	// If autoscaling is NOT enabled we reset min/max annotations and reconcile replicas.
-	if !isAutoscalingEnabled(nodePool) {
-		machineDeployment.Annotations[autoscalerMaxAnnotation] = "0"
-		machineDeployment.Annotations[autoscalerMinAnnotation] = "0"
-		machineDeployment.Spec.Replicas = k8sutilspointer.Int32Ptr(k8sutilspointer.Int32PtrDerefOr(nodePool.Spec.NodeCount, 0))
-	}
+	objectMeta.Annotations[autoscalerMaxAnnotation] = "0"
+	objectMeta.Annotations[autoscalerMinAnnotation] = "0"
+	return k8sutilspointer.Int32Ptr(zone.share(k8sutilspointer.Int32PtrDerefOr(nodePool.Spec.NodeCount, 0)))
 }
 
 func getAMI(nodePool *hyperv1.NodePool, region string, releaseImage *releaseinfo.ReleaseImage) (string, error) {
@@ -850,7 +1024,7 @@ func ignConfig(encodedCACert, encodedToken, endpoint string) ignitionapi.Config
 	}
 }
 
-func (r *NodePoolReconciler) getConfig(ctx context.Context, nodePool *hyperv1.NodePool) (string, error) {
+func (r *NodePoolReconciler) getConfig(ctx context.Context, nodePool *hyperv1.NodePool, releaseVersion string) (string, error) {
 	if nodePool.Spec.Config == nil {
 		return "", nil
 	}
@@ -869,13 +1043,13 @@ func (r *NodePoolReconciler) getConfig(ctx context.Context, nodePool *hyperv1.No
 			continue
 		}
 
-		manifest := configConfigMap.Data[TokenSecretConfigKey]
-		if err := validateConfigManifest([]byte(manifest)); err != nil {
+		manifest, err := defaultAndValidateConfigManifest([]byte(configConfigMap.Data[TokenSecretConfigKey]), releaseVersion)
+		if err != nil {
 			errors = append(errors, fmt.Errorf("configmap %q failed validation: %w", configConfigMap.Name, err))
 			continue
 		}
 
-		allConfigPlainText = allConfigPlainText + "\n---\n" + manifest
+		allConfigPlainText = allConfigPlainText + "\n---\n" + string(manifest)
 	}
 
 	return allConfigPlainText, utilerrors.NewAggregate(errors)
@@ -884,7 +1058,10 @@ func (r *NodePoolReconciler) getConfig(ctx context.Context, nodePool *hyperv1.No
 // validateManagement does additional backend validation. API validation/default should
 // prevent this from ever fail.
 func validateManagement(nodePool *hyperv1.NodePool) error {
-	// Only upgradeType "Replace" is supported atm.
+	if nodePool.Spec.Management.UpgradeType == hyperv1.UpgradeTypeInPlace {
+		return validateInPlaceUpgrade(nodePool)
+	}
+
 	if nodePool.Spec.Management.UpgradeType != hyperv1.UpgradeTypeReplace ||
 		nodePool.Spec.Management.Replace == nil {
 		return fmt.Errorf("this is unsupported. %q upgrade type and a strategy: %q or %q are required",
@@ -906,7 +1083,19 @@ func validateManagement(nodePool *hyperv1.NodePool) error {
 
 	return nil
 }
-func validateConfigManifest(manifest []byte) error {
+
+// minBootstrapKubeletConfigVersion is the first release whose machine-config-operator
+// renders KubeletConfigs and ContainerRuntimeConfigs in bootstrap mode. Older releases
+// silently drop them from the ignition payload.
+// https://github.com/openshift/machine-config-operator/pull/2547
+var minBootstrapKubeletConfigVersion = semver.MustParse("4.9.0")
+
+// defaultAndValidateConfigManifest validates a NodePool config manifest and returns
+// the manifest to be rendered into the ignition payload.
+// KubeletConfigs and ContainerRuntimeConfigs are made to select the MachineConfigPool
+// whose generated MachineConfigs end up in NodePool payloads, and are only accepted
+// for releases that render them.
+func defaultAndValidateConfigManifest(manifest []byte, releaseVersion string) ([]byte, error) {
 	scheme := runtime.NewScheme()
 	mcfgv1.Install(scheme)
 
@@ -917,23 +1106,75 @@ func validateConfigManifest(manifest []byte) error {
 
 	cr, _, err := YamlSerializer.Decode(manifest, nil, nil)
 	if err != nil {
-		return fmt.Errorf("error decoding config: %w", err)
+		return nil, fmt.Errorf("error decoding config: %w", err)
 	}
 
 	switch obj := cr.(type) {
 	case *mcfgv1.MachineConfig:
-	//	TODO (alberto): enable this kinds when they are supported in mcs bootstrap mode
-	// since our mcsIgnitionProvider implementation uses bootstrap mode to render the ignition payload out of an input.
-	// https://github.com/openshift/machine-config-operator/pull/2547
-	//case *mcfgv1.KubeletConfig:
-	//case *mcfgv1.ContainerRuntimeConfig:
+		// MachineConfigs are passed through untouched so existing config hashes don't change.
+		return manifest, nil
+	case *mcfgv1.KubeletConfig:
+		if err := validateBootstrapConfigSupported("KubeletConfig", releaseVersion); err != nil {
+			return nil, err
+		}
+	case *mcfgv1.ContainerRuntimeConfig:
+		if err := validateBootstrapConfigSupported("ContainerRuntimeConfig", releaseVersion); err != nil {
+			return nil, err
+		}
 	default:
-		return fmt.Errorf("unsupported config type: %T", obj)
+		return nil, fmt.Errorf("unsupported config type: %T", obj)
+	}
+
+	// Only the pool selector is set, the rest of the manifest is kept as written
+	// rather than round tripped through its type, which would add empty fields.
+	config := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(manifest, &config.Object); err != nil {
+		return nil, fmt.Errorf("error decoding config: %w", err)
+	}
+	selector, err := runtime.DefaultUnstructuredConverter.ToUnstructured(nodePoolMachineConfigPoolSelector())
+	if err != nil {
+		return nil, fmt.Errorf("error encoding machine config pool selector: %w", err)
 	}
+	if err := unstructured.SetNestedMap(config.Object, selector, "spec", "machineConfigPoolSelector"); err != nil {
+		return nil, fmt.Errorf("error setting machine config pool selector: %w", err)
+	}
+	out, err := yaml.Marshal(config.Object)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding config: %w", err)
+	}
+	return out, nil
+}
 
+// validateBootstrapConfigSupported returns an error if the machine-config-operator
+// of the given release doesn't render configs of the given kind in bootstrap mode.
+func validateBootstrapConfigSupported(kind, releaseVersion string) error {
+	version, err := semver.Parse(releaseVersion)
+	if err != nil {
+		return fmt.Errorf("%s requires a release version of at least %s, cannot parse release version %q: %w", kind, minBootstrapKubeletConfigVersion, releaseVersion, err)
+	}
+	// Pre-releases of a version, e.g. nightlies, already carry its features.
+	version.Pre = nil
+	version.Build = nil
+	if version.LT(minBootstrapKubeletConfigVersion) {
+		return fmt.Errorf("%s requires a release version of at least %s, got %s", kind, minBootstrapKubeletConfigVersion, releaseVersion)
+	}
 	return nil
 }
 
+// nodePoolMachineConfigPoolSelector selects the MachineConfigPool KubeletConfigs and
+// ContainerRuntimeConfigs must target to end up in NodePool payloads.
+// The machine-config-operator labels the MachineConfigs it generates for a pool with
+// the name of the pool as role. The ignition server serves the pool named master,
+// which selects MachineConfigs with the worker role, so the generated MachineConfigs
+// must be made for the pool named worker.
+func nodePoolMachineConfigPoolSelector() *metav1.LabelSelector {
+	return &metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			"pools.operator.machineconfiguration.openshift.io/worker": "",
+		},
+	}
+}
+
 func (r *NodePoolReconciler) getReleaseImage(ctx context.Context, hostedCluster *hyperv1.HostedCluster, releaseImage string) (*releaseinfo.ReleaseImage, error) {
 	pullSecret := &corev1.Secret{}
 	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: hostedCluster.Namespace, Name: hostedCluster.Spec.PullSecret.Name}, pullSecret); err != nil {
@@ -2,6 +2,8 @@ package nodepool
 
 import (
 	"context"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -12,9 +14,14 @@ import (
 	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	hyperapi "github.com/openshift/hypershift/api"
+	mcfgv1 "github.com/openshift/hypershift/thirdparty/machineconfigoperator/pkg/apis/machineconfiguration.openshift.io/v1"
 )
 
 func TestIsUpdatingConfig(t *testing.T) {
@@ -276,13 +283,53 @@ spec:
     maxPods: 100
 `
 
+	containerRuntimeConfig1 := `
+apiVersion: machineconfiguration.openshift.io/v1
+kind: ContainerRuntimeConfig
+metadata:
+  name: set-pids-limit
+spec:
+  containerRuntimeConfig:
+    pidsLimit: 2048
+`
+	machineConfigPool1 := `
+apiVersion: machineconfiguration.openshift.io/v1
+kind: MachineConfigPool
+metadata:
+  name: custom
+`
+	// KubeletConfigs and ContainerRuntimeConfigs select the MachineConfigPool whose generated MachineConfigs end up in NodePool payloads.
+	defaultedKubeletConfig1 := `apiVersion: machineconfiguration.openshift.io/v1
+kind: KubeletConfig
+metadata:
+  name: set-max-pods
+spec:
+  kubeletConfig:
+    maxPods: 100
+  machineConfigPoolSelector:
+    matchLabels:
+      pools.operator.machineconfiguration.openshift.io/worker: ""
+`
+	defaultedContainerRuntimeConfig1 := `apiVersion: machineconfiguration.openshift.io/v1
+kind: ContainerRuntimeConfig
+metadata:
+  name: set-pids-limit
+spec:
+  containerRuntimeConfig:
+    pidsLimit: 2048
+  machineConfigPoolSelector:
+    matchLabels:
+      pools.operator.machineconfiguration.openshift.io/worker: ""
+`
+
 	namespace := "test"
 	testCases := []struct {
-		name     string
-		nodePool *hyperv1.NodePool
-		config   []client.Object
-		expect   string
-		error    bool
+		name           string
+		nodePool       *hyperv1.NodePool
+		config         []client.Object
+		releaseVersion string
+		expect         string
+		error          bool
 	}{
 		{
 			name: "gets a single valid MachineConfig",
@@ -375,7 +422,60 @@ spec:
 			error:  true,
 		},
 		{
-			name: "fails if a non supported config kind",
+			name: "gets a MachineConfig, a KubeletConfig and a ContainerRuntimeConfig",
+			nodePool: &hyperv1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+				},
+				Spec: hyperv1.NodePoolSpec{
+					Config: []corev1.LocalObjectReference{
+						{
+							Name: "machineconfig-1",
+						},
+						{
+							Name: "kubeletconfig-1",
+						},
+						{
+							Name: "containerruntimeconfig-1",
+						},
+					},
+				},
+			},
+			config: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "machineconfig-1",
+						Namespace: namespace,
+					},
+					Data: map[string]string{
+						TokenSecretConfigKey: machineConfig1,
+					},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kubeletconfig-1",
+						Namespace: namespace,
+					},
+					Data: map[string]string{
+						TokenSecretConfigKey: kubeletConfig1,
+					},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "containerruntimeconfig-1",
+						Namespace: namespace,
+					},
+					Data: map[string]string{
+						TokenSecretConfigKey: containerRuntimeConfig1,
+					},
+				},
+			},
+			releaseVersion: "4.9.0-0.nightly-2021-08-10-182033",
+			expect:         "\n---\n" + machineConfig1 + "\n---\n" + defaultedKubeletConfig1 + "\n---\n" + defaultedContainerRuntimeConfig1,
+			error:          false,
+		},
+		{
+			name: "fails if a KubeletConfig is not rendered by the release",
 			nodePool: &hyperv1.NodePool{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: namespace,
@@ -399,6 +499,64 @@ spec:
 					},
 				},
 			},
+			releaseVersion: "4.8.12",
+			expect:         "",
+			error:          true,
+		},
+		{
+			name: "fails if a ContainerRuntimeConfig is not rendered by the release",
+			nodePool: &hyperv1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+				},
+				Spec: hyperv1.NodePoolSpec{
+					Config: []corev1.LocalObjectReference{
+						{
+							Name: "containerruntimeconfig-1",
+						},
+					},
+				},
+			},
+			config: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "containerruntimeconfig-1",
+						Namespace: namespace,
+					},
+					Data: map[string]string{
+						TokenSecretConfigKey: containerRuntimeConfig1,
+					},
+				},
+			},
+			releaseVersion: "4.8.12",
+			expect:         "",
+			error:          true,
+		},
+		{
+			name: "fails if a non supported config kind",
+			nodePool: &hyperv1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+				},
+				Spec: hyperv1.NodePoolSpec{
+					Config: []corev1.LocalObjectReference{
+						{
+							Name: "machineconfigpool-1",
+						},
+					},
+				},
+			},
+			config: []client.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "machineconfigpool-1",
+						Namespace: namespace,
+					},
+					Data: map[string]string{
+						TokenSecretConfigKey: machineConfigPool1,
+					},
+				},
+			},
 			expect: "",
 			error:  true,
 		},
@@ -411,7 +569,7 @@ spec:
 			r := NodePoolReconciler{
 				Client: fake.NewClientBuilder().WithObjects(tc.config...).Build(),
 			}
-			got, err := r.getConfig(context.Background(), tc.nodePool)
+			got, err := r.getConfig(context.Background(), tc.nodePool, tc.releaseVersion)
 			if tc.error {
 				g.Expect(err).To(HaveOccurred())
 				return
@@ -422,10 +580,110 @@ spec:
 	}
 }
 
+func zonedNodePool(nodeCount *int32, autoScaling *hyperv1.NodePoolAutoScaling) *hyperv1.NodePool {
+	return &hyperv1.NodePool{
+		Spec: hyperv1.NodePoolSpec{
+			NodeCount:   nodeCount,
+			AutoScaling: autoScaling,
+			Platform: hyperv1.NodePoolPlatform{
+				Type: hyperv1.AWSPlatform,
+				AWS: &hyperv1.AWSNodePoolPlatform{
+					Zones: []hyperv1.AWSNodePoolZone{
+						{Name: "us-east-1a", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-a")}},
+						{Name: "us-east-1b", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-b")}},
+						{Name: "us-east-1c", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-c")}},
+					},
+				},
+			},
+		},
+	}
+}
+
+// TestBootstrapConfigRendersIntoPayload checks KubeletConfigs and ContainerRuntimeConfigs
+// end up in NodePool payloads. Like the machine-config-operator in bootstrap mode, a
+// MachineConfig is generated for every MachineConfigPool given to the machine config
+// server which the config selects, labeled with the name of the pool as role.
+// The payload is rendered from the pool named master.
+func TestBootstrapConfigRendersIntoPayload(t *testing.T) {
+	poolDir := filepath.Join("..", "..", "..", "control-plane-operator", "controllers", "hostedcontrolplane", "assets", "machine-config-server")
+	pools := map[string]*mcfgv1.MachineConfigPool{}
+	for _, file := range []string{"master.machineconfigpool.yaml", "worker.machineconfigpool.yaml"} {
+		data, err := ioutil.ReadFile(filepath.Join(poolDir, file))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		pool := &mcfgv1.MachineConfigPool{}
+		if err := yaml.Unmarshal(data, pool); err != nil {
+			t.Fatalf("failed to decode %s: %v", file, err)
+		}
+		pools[pool.Name] = pool
+	}
+
+	testCases := []struct {
+		name     string
+		manifest string
+	}{
+		{
+			name: "KubeletConfig",
+			manifest: `
+apiVersion: machineconfiguration.openshift.io/v1
+kind: KubeletConfig
+metadata:
+  name: set-max-pods
+spec:
+  kubeletConfig:
+    maxPods: 100
+`,
+		},
+		{
+			name: "ContainerRuntimeConfig",
+			manifest: `
+apiVersion: machineconfiguration.openshift.io/v1
+kind: ContainerRuntimeConfig
+metadata:
+  name: set-pids-limit
+spec:
+  containerRuntimeConfig:
+    pidsLimit: 2048
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			manifest, err := defaultAndValidateConfigManifest([]byte(tc.manifest), "4.9.0")
+			g.Expect(err).ToNot(HaveOccurred())
+			config := &struct {
+				Spec struct {
+					MachineConfigPoolSelector *metav1.LabelSelector `json:"machineConfigPoolSelector"`
+				} `json:"spec"`
+			}{}
+			g.Expect(yaml.Unmarshal(manifest, config)).To(Succeed())
+			poolSelector, err := metav1.LabelSelectorAsSelector(config.Spec.MachineConfigPoolSelector)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			servedSelector, err := metav1.LabelSelectorAsSelector(pools["master"].Spec.MachineConfigSelector)
+			g.Expect(err).ToNot(HaveOccurred())
+			var rendered []string
+			for _, pool := range pools {
+				if !poolSelector.Matches(labels.Set(pool.Labels)) {
+					continue
+				}
+				generatedLabels := labels.Set{"machineconfiguration.openshift.io/role": pool.Name}
+				if servedSelector.Matches(generatedLabels) {
+					rendered = append(rendered, pool.Name)
+				}
+			}
+			g.Expect(rendered).ToNot(BeEmpty(), "no MachineConfig generated for %s is rendered into the payload", tc.name)
+		})
+	}
+}
+
 func TestSetMachineDeploymentReplicas(t *testing.T) {
 	testCases := []struct {
 		name                        string
 		nodePool                    *hyperv1.NodePool
+		zoneIndex                   int
 		machineDeployment           *capiv1.MachineDeployment
 		expectReplicas              int32
 		expectAutoscalerAnnotations map[string]string
@@ -493,12 +751,60 @@ func TestSetMachineDeploymentReplicas(t *testing.T) {
 				autoscalerMaxAnnotation: "5",
 			},
 		},
+		{
+			name:      "it gives the first zones the remainder of the replicas when autoscaling is disabled",
+			nodePool:  zonedNodePool(pointer.Int32Ptr(5), nil),
+			zoneIndex: 1,
+			machineDeployment: &capiv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.Now(),
+				},
+			},
+			expectReplicas: 2,
+			expectAutoscalerAnnotations: map[string]string{
+				autoscalerMinAnnotation: "0",
+				autoscalerMaxAnnotation: "0",
+			},
+		},
+		{
+			name:      "it distributes replicas across zones when autoscaling is disabled",
+			nodePool:  zonedNodePool(pointer.Int32Ptr(5), nil),
+			zoneIndex: 2,
+			machineDeployment: &capiv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.Now(),
+				},
+			},
+			expectReplicas: 1,
+			expectAutoscalerAnnotations: map[string]string{
+				autoscalerMinAnnotation: "0",
+				autoscalerMaxAnnotation: "0",
+			},
+		},
+		{
+			name:      "it distributes min and max across zones when autoscaling is enabled",
+			nodePool:  zonedNodePool(nil, &hyperv1.NodePoolAutoScaling{Min: 4, Max: 7}),
+			zoneIndex: 2,
+			machineDeployment: &capiv1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.Now(),
+				},
+				Spec: capiv1.MachineDeploymentSpec{
+					Replicas: pointer.Int32Ptr(2),
+				},
+			},
+			expectReplicas: 2,
+			expectAutoscalerAnnotations: map[string]string{
+				autoscalerMinAnnotation: "1",
+				autoscalerMaxAnnotation: "2",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			g := NewWithT(t)
-			setMachineDeploymentReplicas(tc.nodePool, tc.machineDeployment)
+			setMachineDeploymentReplicas(tc.nodePool, nodePoolZones(tc.nodePool)[tc.zoneIndex], tc.machineDeployment)
 			g.Expect(*tc.machineDeployment.Spec.Replicas).To(Equal(tc.expectReplicas))
 			g.Expect(tc.machineDeployment.Annotations).To(Equal(tc.expectAutoscalerAnnotations))
 		})
@@ -507,6 +813,7 @@ func TestSetMachineDeploymentReplicas(t *testing.T) {
 
 func TestValidateManagement(t *testing.T) {
 	intstrPointer1 := intstr.FromInt(1)
+	intstrBadPercentage := intstr.FromString("0%")
 	testCases := []struct {
 		name     string
 		nodePool *hyperv1.NodePool
@@ -594,6 +901,33 @@ func TestValidateManagement(t *testing.T) {
 			},
 			error: false,
 		},
+		{
+			name: "it passes with InPlace type and no InPlace settings",
+			nodePool: &hyperv1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: hyperv1.NodePoolSpec{
+					Management: hyperv1.NodePoolManagement{
+						UpgradeType: hyperv1.UpgradeTypeInPlace,
+					},
+				},
+			},
+			error: false,
+		},
+		{
+			name: "it fails with InPlace type and a bad maxUnavailable percentage",
+			nodePool: &hyperv1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{},
+				Spec: hyperv1.NodePoolSpec{
+					Management: hyperv1.NodePoolManagement{
+						UpgradeType: hyperv1.UpgradeTypeInPlace,
+						InPlace: &hyperv1.InPlaceUpgrade{
+							MaxUnavailable: &intstrBadPercentage,
+						},
+					},
+				},
+			},
+			error: true,
+		},
 		{
 			name: "it passes with Replace type and OnDelete strategy",
 			nodePool: &hyperv1.NodePool{
@@ -623,3 +957,34 @@ func TestValidateManagement(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileAWSMachineTemplateReusesExistingTemplate(t *testing.T) {
+	g := NewWithT(t)
+	nodePool := &hyperv1.NodePool{
+		ObjectMeta: metav1.ObjectMeta{Name: "nodepool", Namespace: "clusters"},
+		Spec: hyperv1.NodePoolSpec{
+			Platform: hyperv1.NodePoolPlatform{
+				Type: hyperv1.AWSPlatform,
+				AWS: &hyperv1.AWSNodePoolPlatform{
+					InstanceType: "m5.large",
+					Zones: []hyperv1.AWSNodePoolZone{
+						{Name: "us-east-1a", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-a")}},
+						{Name: "us-east-1b", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-b")}},
+					},
+				},
+			},
+		},
+	}
+	zone := nodePoolZones(nodePool)[1]
+
+	// A previous reconcile created the template of the zone, but failed to
+	// create the MachineDeployment referencing it.
+	existing, _ := AWSMachineTemplate("infra", "ami", nodePool, zone, "clusters-cluster")
+	r := NodePoolReconciler{
+		Client: fake.NewClientBuilder().WithScheme(hyperapi.Scheme).WithObjects(existing).Build(),
+	}
+	template, err := r.reconcileAWSMachineTemplate(context.Background(), nodePool, zone, "infra", "ami", "clusters-cluster", "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(template.Name).To(Equal(existing.Name))
+	g.Expect(template.Spec).To(Equal(existing.Spec))
+}
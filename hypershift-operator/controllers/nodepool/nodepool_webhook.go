@@ -0,0 +1,303 @@
+package nodepool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	"github.com/openshift/hypershift/hypershift-operator/webhooks"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// SetupWebhookWithManager registers the NodePool admission webhooks with the
+// manager's webhook server.
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	server := mgr.GetWebhookServer()
+	server.Register(webhooks.NodePoolMutatingPath, &webhook.Admission{Handler: &nodePoolDefaulter{}})
+	server.Register(webhooks.NodePoolValidatingPath, &webhook.Admission{Handler: &nodePoolValidator{}})
+	return nil
+}
+
+// nodePoolDefaulter applies defaults to NodePools at admission time.
+type nodePoolDefaulter struct {
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &nodePoolDefaulter{}
+var _ admission.DecoderInjector = &nodePoolDefaulter{}
+
+func (d *nodePoolDefaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.decoder = decoder
+	return nil
+}
+
+func (d *nodePoolDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	nodePool := &hyperv1.NodePool{}
+	if err := d.decoder.Decode(req, nodePool); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	defaultNodePool(nodePool)
+	marshaled, err := json.Marshal(nodePool)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// nodePoolValidator rejects invalid NodePools at admission time.
+type nodePoolValidator struct {
+	decoder *admission.Decoder
+}
+
+var _ admission.Handler = &nodePoolValidator{}
+var _ admission.DecoderInjector = &nodePoolValidator{}
+
+func (v *nodePoolValidator) InjectDecoder(decoder *admission.Decoder) error {
+	v.decoder = decoder
+	return nil
+}
+
+func (v *nodePoolValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	nodePool := &hyperv1.NodePool{}
+	if err := v.decoder.Decode(req, nodePool); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	var errs field.ErrorList
+	if req.Operation == admissionv1.Update {
+		oldNodePool := &hyperv1.NodePool{}
+		if err := v.decoder.DecodeRaw(req.OldObject, oldNodePool); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		// Finalizers are removed with updates, so NodePools being deleted or
+		// only changing their metadata are not held to rules they may
+		// predate.
+		if nodePool.DeletionTimestamp != nil || equality.Semantic.DeepEqual(oldNodePool.Spec, nodePool.Spec) {
+			return admission.Allowed("")
+		}
+		errs = append(errs, validateNodePoolUpdate(oldNodePool, nodePool)...)
+	}
+	errs = append(validateNodePool(nodePool), errs...)
+	if len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+	return admission.Allowed("")
+}
+
+// defaultNodePool fills in unset fields of a NodePool with the same defaults
+// the CLI and the API fixtures use.
+func defaultNodePool(nodePool *hyperv1.NodePool) {
+	if len(nodePool.Spec.Management.UpgradeType) == 0 {
+		nodePool.Spec.Management.UpgradeType = hyperv1.UpgradeTypeReplace
+	}
+	if nodePool.Spec.Management.UpgradeType == hyperv1.UpgradeTypeReplace && nodePool.Spec.Management.Replace == nil {
+		maxSurge := intstr.FromInt(1)
+		maxUnavailable := intstr.FromInt(0)
+		nodePool.Spec.Management.Replace = &hyperv1.ReplaceUpgrade{
+			Strategy: hyperv1.UpgradeStrategyRollingUpdate,
+			RollingUpdate: &hyperv1.RollingUpdate{
+				MaxSurge:       &maxSurge,
+				MaxUnavailable: &maxUnavailable,
+			},
+		}
+	}
+	if nodePool.Spec.Management.UpgradeType == hyperv1.UpgradeTypeInPlace && nodePool.Spec.Management.InPlace == nil {
+		maxUnavailable := intstr.FromInt(1)
+		nodePool.Spec.Management.InPlace = &hyperv1.InPlaceUpgrade{
+			MaxUnavailable: &maxUnavailable,
+		}
+	}
+}
+
+// validateNodePool returns every problem found in the spec of a NodePool which
+// would otherwise only surface during reconciliation.
+func validateNodePool(nodePool *hyperv1.NodePool) field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	if len(nodePool.Spec.ClusterName) == 0 {
+		errs = append(errs, field.Required(specPath.Child("clusterName"), ""))
+	}
+	if len(nodePool.Spec.Release.Image) == 0 {
+		errs = append(errs, field.Required(specPath.Child("release", "image"), "a release image is required"))
+	}
+	if err := validateAutoscaling(nodePool); err != nil {
+		errs = append(errs, field.Invalid(specPath.Child("autoScaling"), nodePool.Spec.AutoScaling, err.Error()))
+	}
+	switch nodePool.Spec.Management.UpgradeType {
+	case hyperv1.UpgradeTypeReplace, hyperv1.UpgradeTypeInPlace:
+		if err := validateManagement(nodePool); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("management"), nodePool.Spec.Management, err.Error()))
+		}
+	default:
+		errs = append(errs, field.NotSupported(specPath.Child("management", "upgradeType"), nodePool.Spec.Management.UpgradeType,
+			[]string{string(hyperv1.UpgradeTypeReplace), string(hyperv1.UpgradeTypeInPlace)}))
+	}
+	switch nodePool.Spec.Platform.Type {
+	case hyperv1.AWSPlatform:
+		if nodePool.Spec.Platform.AWS == nil {
+			errs = append(errs, field.Required(specPath.Child("platform", "aws"), fmt.Sprintf("the %s platform requires AWS configuration", hyperv1.AWSPlatform)))
+		} else {
+			errs = append(errs, validateAWSZones(nodePool.Spec.Platform.AWS, nodePool.Spec.AutoScaling, specPath)...)
+			errs = append(errs, validateAWSVolumes(nodePool.Spec.Platform.AWS, specPath.Child("platform", "aws"))...)
+			errs = append(errs, validateAWSTenancy(nodePool.Spec.Platform.AWS.Tenancy, specPath.Child("platform", "aws", "tenancy"))...)
+		}
+	case hyperv1.NonePlatform, hyperv1.IBMCloudPlatform:
+	default:
+		errs = append(errs, field.NotSupported(specPath.Child("platform", "type"), nodePool.Spec.Platform.Type,
+			[]string{string(hyperv1.AWSPlatform), string(hyperv1.NonePlatform), string(hyperv1.IBMCloudPlatform)}))
+	}
+	errs = append(errs, metav1validation.ValidateLabels(nodePool.Spec.NodeLabels, specPath.Child("nodeLabels"))...)
+	errs = append(errs, validateTaints(nodePool.Spec.Taints, specPath.Child("taints"))...)
+
+	return errs
+}
+
+// validateAWSZones rejects zones which can't be mapped to a MachineDeployment
+// each. Every zone needs at least one node when autoscaling, since the
+// autoscaler can't scale MachineDeployments from zero.
+func validateAWSZones(aws *hyperv1.AWSNodePoolPlatform, autoScaling *hyperv1.NodePoolAutoScaling, specPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if len(aws.Zones) == 0 {
+		return errs
+	}
+	if autoScaling != nil && autoScaling.Min < int32(len(aws.Zones)) {
+		errs = append(errs, field.Invalid(specPath.Child("autoScaling", "min"), autoScaling.Min,
+			fmt.Sprintf("must be at least the number of zones (%d)", len(aws.Zones))))
+	}
+	fldPath := specPath.Child("platform", "aws")
+	if aws.Subnet != nil {
+		errs = append(errs, field.Forbidden(fldPath.Child("subnet"), "subnet can't be combined with zones, set the subnet of each zone instead"))
+	}
+	seen := map[string]bool{}
+	for i, zone := range aws.Zones {
+		idxPath := fldPath.Child("zones").Index(i)
+		if zone.Name == "" {
+			errs = append(errs, field.Required(idxPath.Child("name"), ""))
+		} else {
+			for _, msg := range validation.IsDNS1123Label(zone.Name) {
+				errs = append(errs, field.Invalid(idxPath.Child("name"), zone.Name, msg))
+			}
+		}
+		if seen[zone.Name] {
+			errs = append(errs, field.Duplicate(idxPath.Child("name"), zone.Name))
+		}
+		seen[zone.Name] = true
+		if zone.Subnet.ID == nil && zone.Subnet.ARN == nil && len(zone.Subnet.Filters) == 0 {
+			errs = append(errs, field.Required(idxPath.Child("subnet"), "a subnet ID, ARN or filters are required"))
+		}
+	}
+	return errs
+}
+
+// validateAWSVolumes rejects volumes EC2 would refuse to launch instances
+// with.
+func validateAWSVolumes(aws *hyperv1.AWSNodePoolPlatform, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if aws.RootVolume != nil {
+		rootPath := fldPath.Child("rootVolume")
+		if aws.RootVolume.DeviceName != "" {
+			errs = append(errs, field.Forbidden(rootPath.Child("deviceName"), "the device name of the root volume is given by the AMI"))
+		}
+		errs = append(errs, validateAWSVolume(*aws.RootVolume, rootPath)...)
+	}
+	seen := map[string]bool{}
+	for i, volume := range aws.AdditionalVolumes {
+		idxPath := fldPath.Child("additionalVolumes").Index(i)
+		if volume.DeviceName == "" {
+			errs = append(errs, field.Required(idxPath.Child("deviceName"), ""))
+		} else if seen[volume.DeviceName] {
+			errs = append(errs, field.Duplicate(idxPath.Child("deviceName"), volume.DeviceName))
+		}
+		seen[volume.DeviceName] = true
+		errs = append(errs, validateAWSVolume(volume, idxPath)...)
+	}
+	return errs
+}
+
+func validateAWSVolume(volume hyperv1.Volume, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	if volume.Size < 8 {
+		errs = append(errs, field.Invalid(fldPath.Child("size"), volume.Size, "must be at least 8"))
+	}
+	switch volume.Type {
+	case "io1", "io2":
+		if volume.IOPS <= 0 {
+			errs = append(errs, field.Required(fldPath.Child("iops"), fmt.Sprintf("%s volumes require provisioned IOPS", volume.Type)))
+		}
+	case "gp3":
+	case "", "standard", "gp2", "sc1", "st1":
+		if volume.IOPS != 0 {
+			errs = append(errs, field.Forbidden(fldPath.Child("iops"), "IOPS can only be provisioned for io1, io2 and gp3 volumes"))
+		}
+	default:
+		errs = append(errs, field.NotSupported(fldPath.Child("type"), volume.Type,
+			[]string{"standard", "gp2", "gp3", "io1", "io2", "sc1", "st1"}))
+	}
+	if volume.IOPS < 0 {
+		errs = append(errs, field.Invalid(fldPath.Child("iops"), volume.IOPS, "must not be negative"))
+	}
+	if volume.EncryptionKey != "" && !volume.Encrypted {
+		errs = append(errs, field.Forbidden(fldPath.Child("encryptionKey"), "an encryption key requires encrypted to be set"))
+	}
+	return errs
+}
+
+func validateAWSTenancy(tenancy string, fldPath *field.Path) field.ErrorList {
+	switch tenancy {
+	case "", "default", "dedicated", "host":
+		return nil
+	}
+	return field.ErrorList{field.NotSupported(fldPath, tenancy, []string{"default", "dedicated", "host"})}
+}
+
+// validateTaints rejects taints the kubelet would refuse to register nodes
+// with.
+func validateTaints(taints []hyperv1.Taint, fldPath *field.Path) field.ErrorList {
+	var errs field.ErrorList
+	seen := map[string]bool{}
+	for i, taint := range taints {
+		idxPath := fldPath.Index(i)
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			errs = append(errs, field.Invalid(idxPath.Child("key"), taint.Key, msg))
+		}
+		if taint.Value != "" {
+			for _, msg := range validation.IsValidLabelValue(taint.Value) {
+				errs = append(errs, field.Invalid(idxPath.Child("value"), taint.Value, msg))
+			}
+		}
+		switch taint.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			errs = append(errs, field.NotSupported(idxPath.Child("effect"), taint.Effect,
+				[]string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}))
+		}
+		key := fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+		if seen[key] {
+			errs = append(errs, field.Duplicate(idxPath, key))
+		}
+		seen[key] = true
+	}
+	return errs
+}
+
+// validateNodePoolUpdate rejects changes to a NodePool which can't be
+// reconciled. Replace NodePools are backed by a MachineDeployment and InPlace
+// NodePools by a MachineSet, so the upgrade type can't change.
+func validateNodePoolUpdate(oldNodePool, nodePool *hyperv1.NodePool) field.ErrorList {
+	var errs field.ErrorList
+	if oldNodePool.Spec.Management.UpgradeType != nodePool.Spec.Management.UpgradeType {
+		errs = append(errs, field.Forbidden(field.NewPath("spec", "management", "upgradeType"), "the upgrade type is immutable"))
+	}
+	return errs
+}
@@ -0,0 +1,402 @@
+package nodepool
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	hyperapi "github.com/openshift/hypershift/api"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func validNodePool() *hyperv1.NodePool {
+	nodePool := &hyperv1.NodePool{
+		Spec: hyperv1.NodePoolSpec{
+			ClusterName: "cluster",
+			NodeCount:   pointer.Int32Ptr(2),
+			Release: hyperv1.Release{
+				Image: "quay.io/openshift-release-dev/ocp-release:4.8.6-x86_64",
+			},
+			Platform: hyperv1.NodePoolPlatform{
+				Type: hyperv1.AWSPlatform,
+				AWS:  &hyperv1.AWSNodePoolPlatform{InstanceType: "m4.large"},
+			},
+		},
+	}
+	defaultNodePool(nodePool)
+	return nodePool
+}
+
+func TestDefaultNodePool(t *testing.T) {
+	g := NewWithT(t)
+	nodePool := &hyperv1.NodePool{}
+	defaultNodePool(nodePool)
+
+	maxSurge := intstr.FromInt(1)
+	maxUnavailable := intstr.FromInt(0)
+	g.Expect(nodePool.Spec.Management).To(Equal(hyperv1.NodePoolManagement{
+		UpgradeType: hyperv1.UpgradeTypeReplace,
+		Replace: &hyperv1.ReplaceUpgrade{
+			Strategy: hyperv1.UpgradeStrategyRollingUpdate,
+			RollingUpdate: &hyperv1.RollingUpdate{
+				MaxSurge:       &maxSurge,
+				MaxUnavailable: &maxUnavailable,
+			},
+		},
+	}))
+
+	// Explicit settings are preserved.
+	nodePool = &hyperv1.NodePool{
+		Spec: hyperv1.NodePoolSpec{
+			Management: hyperv1.NodePoolManagement{
+				UpgradeType: hyperv1.UpgradeTypeReplace,
+				Replace:     &hyperv1.ReplaceUpgrade{Strategy: hyperv1.UpgradeStrategyOnDelete},
+			},
+		},
+	}
+	defaultNodePool(nodePool)
+	g.Expect(nodePool.Spec.Management.Replace).To(Equal(&hyperv1.ReplaceUpgrade{Strategy: hyperv1.UpgradeStrategyOnDelete}))
+
+	// InPlace NodePools update a single node at a time by default.
+	nodePool = &hyperv1.NodePool{
+		Spec: hyperv1.NodePoolSpec{
+			Management: hyperv1.NodePoolManagement{UpgradeType: hyperv1.UpgradeTypeInPlace},
+		},
+	}
+	defaultNodePool(nodePool)
+	oneUnavailable := intstr.FromInt(1)
+	g.Expect(nodePool.Spec.Management.InPlace).To(Equal(&hyperv1.InPlaceUpgrade{MaxUnavailable: &oneUnavailable}))
+	g.Expect(nodePool.Spec.Management.Replace).To(BeNil())
+}
+
+func TestValidateNodePool(t *testing.T) {
+	testCases := []struct {
+		name   string
+		mutate func(*hyperv1.NodePool)
+		error  bool
+	}{
+		{
+			name:   "it passes with a valid nodePool",
+			mutate: func(*hyperv1.NodePool) {},
+			error:  false,
+		},
+		{
+			name: "it fails with no cluster name",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.ClusterName = ""
+			},
+			error: true,
+		},
+		{
+			name: "it fails with no release image",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Release.Image = ""
+			},
+			error: true,
+		},
+		{
+			name: "it fails with both nodeCount and autoScaling",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.AutoScaling = &hyperv1.NodePoolAutoScaling{Min: 1, Max: 2}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an unsupported upgradeType",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Management.UpgradeType = "bad"
+			},
+			error: true,
+		},
+		{
+			name: "it passes with InPlace upgradeType",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				maxUnavailable := intstr.FromString("25%")
+				nodePool.Spec.Management.UpgradeType = hyperv1.UpgradeTypeInPlace
+				nodePool.Spec.Management.InPlace = &hyperv1.InPlaceUpgrade{MaxUnavailable: &maxUnavailable}
+			},
+			error: false,
+		},
+		{
+			name: "it fails with InPlace upgradeType and no unavailable nodes",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				maxUnavailable := intstr.FromInt(0)
+				nodePool.Spec.Management.UpgradeType = hyperv1.UpgradeTypeInPlace
+				nodePool.Spec.Management.InPlace = &hyperv1.InPlaceUpgrade{MaxUnavailable: &maxUnavailable}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an unknown strategy",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Management.Replace.Strategy = "bad"
+			},
+			error: true,
+		},
+		{
+			name: "it fails with AWS platform and no AWS settings",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS = nil
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an unknown platform",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.Type = "bad"
+			},
+			error: true,
+		},
+		{
+			name: "it passes with zones",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.Zones = []hyperv1.AWSNodePoolZone{
+					{Name: "us-east-1a", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-a")}},
+					{Name: "us-east-1b", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-b")}},
+				}
+			},
+		},
+		{
+			name: "it fails with zones and a subnet",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.Subnet = &hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-a")}
+				nodePool.Spec.Platform.AWS.Zones = []hyperv1.AWSNodePoolZone{
+					{Name: "us-east-1a", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-a")}},
+				}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with duplicate zones",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.Zones = []hyperv1.AWSNodePoolZone{
+					{Name: "us-east-1a", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-a")}},
+					{Name: "us-east-1a", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-b")}},
+				}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a zone without subnet",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.Zones = []hyperv1.AWSNodePoolZone{{Name: "us-east-1a"}}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with zones and autoscaling min below the number of zones",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.NodeCount = nil
+				nodePool.Spec.AutoScaling = &hyperv1.NodePoolAutoScaling{Min: 1, Max: 3}
+				nodePool.Spec.Platform.AWS.Zones = []hyperv1.AWSNodePoolZone{
+					{Name: "us-east-1a", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-a")}},
+					{Name: "us-east-1b", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-b")}},
+				}
+			},
+			error: true,
+		},
+		{
+			name: "it passes with zones and autoscaling min equal to the number of zones",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.NodeCount = nil
+				nodePool.Spec.AutoScaling = &hyperv1.NodePoolAutoScaling{Min: 2, Max: 3}
+				nodePool.Spec.Platform.AWS.Zones = []hyperv1.AWSNodePoolZone{
+					{Name: "us-east-1a", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-a")}},
+					{Name: "us-east-1b", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-b")}},
+				}
+			},
+		},
+		{
+			name: "it fails with zones and InPlace upgradeType",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				maxUnavailable := intstr.FromInt(1)
+				nodePool.Spec.Management.UpgradeType = hyperv1.UpgradeTypeInPlace
+				nodePool.Spec.Management.InPlace = &hyperv1.InPlaceUpgrade{MaxUnavailable: &maxUnavailable}
+				nodePool.Spec.Platform.AWS.Zones = []hyperv1.AWSNodePoolZone{
+					{Name: "us-east-1a", Subnet: hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet-a")}},
+				}
+			},
+			error: true,
+		},
+		{
+			name: "it passes with node labels the kubelet can't set and taints",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.NodeLabels = map[string]string{"node-role.kubernetes.io/infra": "", "team": "a"}
+				nodePool.Spec.Taints = []hyperv1.Taint{
+					{Key: "node-role.kubernetes.io/infra", Effect: corev1.TaintEffectNoSchedule},
+					{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoExecute},
+				}
+			},
+		},
+		{
+			name: "it fails with an invalid node label",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.NodeLabels = map[string]string{"team": "a b"}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an invalid taint key",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Taints = []hyperv1.Taint{{Key: "bad key", Effect: corev1.TaintEffectNoSchedule}}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a duplicate taint",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Taints = []hyperv1.Taint{
+					{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule},
+					{Key: "dedicated", Value: "b", Effect: corev1.TaintEffectNoSchedule},
+				}
+			},
+			error: true,
+		},
+		{
+			name: "it passes with volumes and tenancy",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.RootVolume = &hyperv1.Volume{Size: 120, Type: "io1", IOPS: 3000, Encrypted: true, EncryptionKey: "key"}
+				nodePool.Spec.Platform.AWS.AdditionalVolumes = []hyperv1.Volume{
+					{DeviceName: "/dev/sdb", Size: 200, Type: "gp3"},
+					{DeviceName: "/dev/sdc", Size: 500, Type: "st1"},
+				}
+				nodePool.Spec.Platform.AWS.Tenancy = "dedicated"
+			},
+		},
+		{
+			name: "it fails with a root volume below the minimum size",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.RootVolume = &hyperv1.Volume{Size: 4}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with a root volume device name",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.RootVolume = &hyperv1.Volume{DeviceName: "/dev/sda1", Size: 120}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an io1 volume without IOPS",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.RootVolume = &hyperv1.Volume{Size: 120, Type: "io1"}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with IOPS on a gp2 volume",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.RootVolume = &hyperv1.Volume{Size: 120, Type: "gp2", IOPS: 3000}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an encryption key on an unencrypted volume",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.RootVolume = &hyperv1.Volume{Size: 120, EncryptionKey: "key"}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an additional volume without device name",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.AdditionalVolumes = []hyperv1.Volume{{Size: 200}}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with duplicate additional volume device names",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.AdditionalVolumes = []hyperv1.Volume{
+					{DeviceName: "/dev/sdb", Size: 200},
+					{DeviceName: "/dev/sdb", Size: 300},
+				}
+			},
+			error: true,
+		},
+		{
+			name: "it fails with an unknown tenancy",
+			mutate: func(nodePool *hyperv1.NodePool) {
+				nodePool.Spec.Platform.AWS.Tenancy = "bad"
+			},
+			error: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			nodePool := validNodePool()
+			tc.mutate(nodePool)
+			errs := validateNodePool(nodePool)
+			if tc.error {
+				g.Expect(errs).ToNot(BeEmpty())
+				return
+			}
+			g.Expect(errs).To(BeEmpty())
+		})
+	}
+}
+
+func TestValidateNodePoolUpdate(t *testing.T) {
+	g := NewWithT(t)
+	oldNodePool := validNodePool()
+
+	nodePool := validNodePool()
+	nodePool.Spec.NodeCount = pointer.Int32Ptr(3)
+	g.Expect(validateNodePoolUpdate(oldNodePool, nodePool)).To(BeEmpty())
+
+	nodePool.Spec.Management.UpgradeType = hyperv1.UpgradeTypeInPlace
+	g.Expect(validateNodePoolUpdate(oldNodePool, nodePool)).ToNot(BeEmpty())
+}
+
+func TestNodePoolValidatorAllowsDeletion(t *testing.T) {
+	g := NewWithT(t)
+	decoder, err := admission.NewDecoder(hyperapi.Scheme)
+	g.Expect(err).ToNot(HaveOccurred())
+	validator := &nodePoolValidator{decoder: decoder}
+
+	// A NodePool admitted before a validation rule was added.
+	oldNodePool := validNodePool()
+	oldNodePool.Spec.ClusterName = ""
+	updateRequest := func(nodePool *hyperv1.NodePool) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Update,
+			Object:    runtime.RawExtension{Raw: mustMarshal(t, nodePool)},
+			OldObject: runtime.RawExtension{Raw: mustMarshal(t, oldNodePool)},
+		}}
+	}
+
+	// Removing its finalizers while it is deleted is allowed.
+	deleting := oldNodePool.DeepCopy()
+	deleting.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	g.Expect(validator.Handle(context.Background(), updateRequest(deleting)).Allowed).To(BeTrue())
+
+	// So are other metadata only changes.
+	relabeled := oldNodePool.DeepCopy()
+	relabeled.Labels = map[string]string{"team": "a"}
+	g.Expect(validator.Handle(context.Background(), updateRequest(relabeled)).Allowed).To(BeTrue())
+
+	// Spec changes are still validated.
+	scaled := oldNodePool.DeepCopy()
+	scaled.Spec.NodeCount = pointer.Int32Ptr(3)
+	g.Expect(validator.Handle(context.Background(), updateRequest(scaled)).Allowed).To(BeFalse())
+}
+
+func mustMarshal(t *testing.T, obj interface{}) []byte {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to marshal %T: %v", obj, err)
+	}
+	return raw
+}
@@ -0,0 +1,134 @@
+package nodepool
+
+import (
+	"context"
+	"fmt"
+
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8sutilspointer "k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// nodePoolZoneLabel is set on the Machines of NodePools spread across
+// availability zones and selects the Machines of a single zone.
+const nodePoolZoneLabel = "hypershift.openshift.io/nodePoolZone"
+
+// nodePoolZone is a part of a NodePool backed by its own MachineDeployment.
+type nodePoolZone struct {
+	// name is the availability zone, empty for NodePools which are not
+	// spread across zones.
+	name   string
+	subnet *hyperv1.AWSResourceReference
+	// index and count place the zone among the zones of the NodePool.
+	index int
+	count int
+}
+
+// nodePoolZones returns the zones of a NodePool. NodePools which are not
+// spread across availability zones have a single unnamed zone.
+func nodePoolZones(nodePool *hyperv1.NodePool) []nodePoolZone {
+	aws := nodePool.Spec.Platform.AWS
+	if nodePool.Spec.Platform.Type != hyperv1.AWSPlatform || aws == nil || len(aws.Zones) == 0 {
+		zone := nodePoolZone{count: 1}
+		if aws != nil {
+			zone.subnet = aws.Subnet
+		}
+		return []nodePoolZone{zone}
+	}
+	zones := make([]nodePoolZone, 0, len(aws.Zones))
+	for i := range aws.Zones {
+		zones = append(zones, nodePoolZone{
+			name:   aws.Zones[i].Name,
+			subnet: &aws.Zones[i].Subnet,
+			index:  i,
+			count:  len(aws.Zones),
+		})
+	}
+	return zones
+}
+
+// share returns the part of total the zone gets when total is distributed
+// evenly across the zones of a NodePool. The remainder goes to the first
+// zones.
+func (z nodePoolZone) share(total int32) int32 {
+	count := int32(z.count)
+	share := total / count
+	if int32(z.index) < total%count {
+		share++
+	}
+	return share
+}
+
+// listMachineDeployments returns the MachineDeployments of all the zones of a
+// NodePool, including the ones of zones which were removed from it.
+func (r *NodePoolReconciler) listMachineDeployments(ctx context.Context, nodePool *hyperv1.NodePool, controlPlaneNamespace string) ([]capiv1.MachineDeployment, error) {
+	machineDeploymentList := &capiv1.MachineDeploymentList{}
+	if err := r.List(ctx, machineDeploymentList, client.InNamespace(controlPlaneNamespace)); err != nil {
+		return nil, fmt.Errorf("failed to list MachineDeployments: %w", err)
+	}
+	filtered := []capiv1.MachineDeployment{}
+	for i := range machineDeploymentList.Items {
+		if machineDeploymentList.Items[i].GetAnnotations()[nodePoolAnnotation] == client.ObjectKeyFromObject(nodePool).String() {
+			filtered = append(filtered, machineDeploymentList.Items[i])
+		}
+	}
+	return filtered, nil
+}
+
+// deleteStaleMachineDeployments removes the MachineDeployments of zones which
+// were removed from a NodePool, including the one of a NodePool which started
+// to be spread across zones. They are only scaled down once the
+// MachineDeployments of the current zones have all their replicas available,
+// and deleted once they have no replicas left, so the capacity of the
+// NodePool is moved to the current zones before it is removed.
+func (r *NodePoolReconciler) deleteStaleMachineDeployments(ctx context.Context, nodePool *hyperv1.NodePool, controlPlaneNamespace string, current []*capiv1.MachineDeployment) error {
+	machineDeployments, err := r.listMachineDeployments(ctx, nodePool, controlPlaneNamespace)
+	if err != nil {
+		return err
+	}
+	names := map[string]bool{}
+	for _, md := range current {
+		names[md.Name] = true
+	}
+	for i := range machineDeployments {
+		md := &machineDeployments[i]
+		if names[md.Name] || !md.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if k8sutilspointer.Int32PtrDerefOr(md.Spec.Replicas, 0) == 0 && md.Status.Replicas == 0 {
+			if err := r.Delete(ctx, md); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("failed to delete MachineDeployment %q: %w", client.ObjectKeyFromObject(md).String(), err)
+			}
+			continue
+		}
+		if !machineDeploymentsAvailable(current) {
+			continue
+		}
+		// Disable autoscaling so the autoscaler doesn't scale it back up.
+		original := md.DeepCopy()
+		if md.Annotations == nil {
+			md.Annotations = map[string]string{}
+		}
+		md.Annotations[autoscalerMaxAnnotation] = "0"
+		md.Annotations[autoscalerMinAnnotation] = "0"
+		md.Spec.Replicas = k8sutilspointer.Int32Ptr(0)
+		if err := r.Patch(ctx, md, client.MergeFrom(original)); err != nil {
+			return fmt.Errorf("failed to scale down MachineDeployment %q: %w", client.ObjectKeyFromObject(md).String(), err)
+		}
+	}
+	return nil
+}
+
+// machineDeploymentsAvailable returns whether the MachineDeployments are
+// up to date and have all their replicas available.
+func machineDeploymentsAvailable(machineDeployments []*capiv1.MachineDeployment) bool {
+	for _, md := range machineDeployments {
+		if md.Status.ObservedGeneration < md.Generation ||
+			md.Status.AvailableReplicas < k8sutilspointer.Int32PtrDerefOr(md.Spec.Replicas, 0) {
+			return false
+		}
+	}
+	return true
+}
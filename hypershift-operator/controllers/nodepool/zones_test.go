@@ -0,0 +1,199 @@
+package nodepool
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	api "github.com/openshift/hypershift/api"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
+	capiv1 "github.com/openshift/hypershift/api/v1alpha1/thirdparty/clusterapi/api/v1alpha4"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNodePoolZones(t *testing.T) {
+	g := NewWithT(t)
+
+	subnet := &hyperv1.AWSResourceReference{ID: pointer.StringPtr("subnet")}
+	nodePool := &hyperv1.NodePool{
+		Spec: hyperv1.NodePoolSpec{
+			Platform: hyperv1.NodePoolPlatform{
+				Type: hyperv1.AWSPlatform,
+				AWS:  &hyperv1.AWSNodePoolPlatform{Subnet: subnet},
+			},
+		},
+	}
+	g.Expect(nodePoolZones(nodePool)).To(Equal([]nodePoolZone{{subnet: subnet, count: 1}}))
+
+	nodePool = zonedNodePool(nil, nil)
+	zones := nodePoolZones(nodePool)
+	g.Expect(zones).To(HaveLen(3))
+	g.Expect(zones[1]).To(Equal(nodePoolZone{
+		name:   "us-east-1b",
+		subnet: &nodePool.Spec.Platform.AWS.Zones[1].Subnet,
+		index:  1,
+		count:  3,
+	}))
+}
+
+func TestReconcileMachineDeploymentsStatus(t *testing.T) {
+	zoneMachineDeployment := func(dataSecretName string, replicas, available int32) *capiv1.MachineDeployment {
+		return &capiv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 1},
+			Spec: capiv1.MachineDeploymentSpec{
+				Replicas: pointer.Int32Ptr(replicas),
+				Template: capiv1.MachineTemplateSpec{
+					Spec: capiv1.MachineSpec{
+						Bootstrap: capiv1.Bootstrap{DataSecretName: pointer.StringPtr(dataSecretName)},
+					},
+				},
+			},
+			Status: capiv1.MachineDeploymentStatus{
+				ObservedGeneration: 1,
+				Replicas:           replicas,
+				UpdatedReplicas:    replicas,
+				AvailableReplicas:  available,
+			},
+		}
+	}
+	userDataSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "user-data-target"}}
+
+	testCases := []struct {
+		name               string
+		machineDeployments []*capiv1.MachineDeployment
+		expectNodeCount    int32
+		expectComplete     bool
+	}{
+		{
+			name: "it completes the update once every zone completed it",
+			machineDeployments: []*capiv1.MachineDeployment{
+				zoneMachineDeployment("user-data-target", 2, 2),
+				zoneMachineDeployment("user-data-target", 1, 1),
+			},
+			expectNodeCount: 3,
+			expectComplete:  true,
+		},
+		{
+			name: "it does not complete the update while a zone is unavailable",
+			machineDeployments: []*capiv1.MachineDeployment{
+				zoneMachineDeployment("user-data-target", 2, 2),
+				zoneMachineDeployment("user-data-target", 2, 1),
+			},
+			expectNodeCount: 3,
+		},
+		{
+			name: "it does not complete the update while a zone uses other user data",
+			machineDeployments: []*capiv1.MachineDeployment{
+				zoneMachineDeployment("user-data-target", 2, 2),
+				zoneMachineDeployment("user-data-old", 1, 1),
+			},
+			expectNodeCount: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			nodePool := &hyperv1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{}},
+				Status:     hyperv1.NodePoolStatus{Version: "old"},
+			}
+			reconcileMachineDeploymentsStatus(ctrl.Log, nodePool, tc.machineDeployments, userDataSecret, "target", "config", "config-version")
+			g.Expect(nodePool.Status.NodeCount).To(Equal(tc.expectNodeCount))
+			if tc.expectComplete {
+				g.Expect(nodePool.Status.Version).To(Equal("target"))
+				g.Expect(nodePool.Annotations[nodePoolAnnotationCurrentConfig]).To(Equal("config"))
+				g.Expect(nodePool.Annotations[nodePoolAnnotationCurrentConfigVersion]).To(Equal("config-version"))
+				return
+			}
+			g.Expect(nodePool.Status.Version).To(Equal("old"))
+			g.Expect(nodePool.Annotations).To(BeEmpty())
+		})
+	}
+}
+
+func TestDeleteStaleMachineDeployments(t *testing.T) {
+	nodePool := &hyperv1.NodePool{ObjectMeta: metav1.ObjectMeta{Namespace: "clusters", Name: "pool"}}
+	zoneMachineDeployment := func(name string, replicas, statusReplicas, available int32) *capiv1.MachineDeployment {
+		return &capiv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "clusters-hc",
+				Name:        name,
+				Generation:  1,
+				Annotations: map[string]string{nodePoolAnnotation: "clusters/pool"},
+			},
+			Spec: capiv1.MachineDeploymentSpec{Replicas: pointer.Int32Ptr(replicas)},
+			Status: capiv1.MachineDeploymentStatus{
+				ObservedGeneration: 1,
+				Replicas:           statusReplicas,
+				AvailableReplicas:  available,
+			},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		current          []*capiv1.MachineDeployment
+		stale            *capiv1.MachineDeployment
+		expectReplicas   int32
+		expectNotDeleted bool
+	}{
+		{
+			name:             "it keeps a stale MachineDeployment until the current ones are available",
+			current:          []*capiv1.MachineDeployment{zoneMachineDeployment("pool-us-east-1a", 2, 2, 1)},
+			stale:            zoneMachineDeployment("pool", 2, 2, 2),
+			expectReplicas:   2,
+			expectNotDeleted: true,
+		},
+		{
+			name:             "it scales down a stale MachineDeployment once the current ones are available",
+			current:          []*capiv1.MachineDeployment{zoneMachineDeployment("pool-us-east-1a", 2, 2, 2)},
+			stale:            zoneMachineDeployment("pool", 2, 2, 2),
+			expectReplicas:   0,
+			expectNotDeleted: true,
+		},
+		{
+			name:             "it keeps a scaled down MachineDeployment until its replicas are gone",
+			current:          []*capiv1.MachineDeployment{zoneMachineDeployment("pool-us-east-1a", 2, 2, 2)},
+			stale:            zoneMachineDeployment("pool", 0, 1, 0),
+			expectReplicas:   0,
+			expectNotDeleted: true,
+		},
+		{
+			name:    "it deletes a stale MachineDeployment without replicas",
+			current: []*capiv1.MachineDeployment{zoneMachineDeployment("pool-us-east-1a", 2, 2, 1)},
+			stale:   zoneMachineDeployment("pool", 0, 0, 0),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			objects := []client.Object{tc.stale}
+			for _, md := range tc.current {
+				objects = append(objects, md)
+			}
+			r := NodePoolReconciler{
+				Client: fake.NewClientBuilder().WithScheme(api.Scheme).WithObjects(objects...).Build(),
+			}
+			g.Expect(r.deleteStaleMachineDeployments(context.Background(), nodePool, "clusters-hc", tc.current)).To(Succeed())
+
+			for _, md := range tc.current {
+				g.Expect(r.Get(context.Background(), client.ObjectKeyFromObject(md), &capiv1.MachineDeployment{})).To(Succeed())
+			}
+			stale := &capiv1.MachineDeployment{}
+			err := r.Get(context.Background(), client.ObjectKeyFromObject(tc.stale), stale)
+			if !tc.expectNotDeleted {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(*stale.Spec.Replicas).To(Equal(tc.expectReplicas))
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/go-logr/logr"
 	hyperapi "github.com/openshift/hypershift/api"
+	hyperv1 "github.com/openshift/hypershift/api/v1alpha1"
 	"github.com/openshift/hypershift/hypershift-operator/controllers/hostedcluster"
 	"github.com/openshift/hypershift/hypershift-operator/controllers/nodepool"
 	"github.com/openshift/hypershift/support/releaseinfo"
@@ -69,7 +70,9 @@ type StartOptions struct {
 	EnableLeaderElection  bool
 	OperatorImage         string
 	IgnitionServerImage   string
+	KonnectivityImage     string
 	OpenTelemetryEndpoint string
+	WebhookCertDir        string
 }
 
 func NewStartCommand() *cobra.Command {
@@ -87,7 +90,9 @@ func NewStartCommand() *cobra.Command {
 		EnableLeaderElection:  false,
 		OperatorImage:         "",
 		IgnitionServerImage:   "",
+		KonnectivityImage:     hyperv1.DefaultKonnectivityImage,
 		OpenTelemetryEndpoint: "",
+		WebhookCertDir:        "",
 	}
 
 	cmd.Flags().StringVar(&opts.Namespace, "namespace", opts.Namespace, "The namespace this operator lives in")
@@ -98,6 +103,8 @@ func NewStartCommand() *cobra.Command {
 			"Enabling this will ensure there is only one active controller manager.")
 	cmd.Flags().StringVar(&opts.OperatorImage, "operator-image", opts.OperatorImage, "A control plane operator image to use (defaults to match this operator if running in a deployment)")
 	cmd.Flags().StringVar(&opts.IgnitionServerImage, "ignition-server-image", opts.IgnitionServerImage, "An ignition server image to use (defaults to match this operator if running in a deployment)")
+	cmd.Flags().StringVar(&opts.KonnectivityImage, "konnectivity-image", opts.KonnectivityImage, "The konnectivity image control plane operators use for release images that don't include one. It must be pinned by digest.")
+	cmd.Flags().StringVar(&opts.WebhookCertDir, "webhook-cert-dir", opts.WebhookCertDir, "The directory containing the serving certificate and key for the admission webhooks. If specified, the HostedCluster and NodePool webhooks are served.")
 	cmd.Flags().StringVar(&opts.OpenTelemetryEndpoint, "otlp-endpoint", opts.OpenTelemetryEndpoint, "An OpenTelemetry collector endpoint (e.g. localhost:4317). If specified, OTLP traces will be exported to this endpoint.")
 
 	cmd.Run = func(cmd *cobra.Command, args []string) {
@@ -117,6 +124,7 @@ func run(ctx context.Context, opts *StartOptions, log logr.Logger) error {
 		Scheme:             hyperapi.Scheme,
 		MetricsBindAddress: opts.MetricsAddr,
 		Port:               9443,
+		CertDir:            opts.WebhookCertDir,
 		LeaderElection:     opts.EnableLeaderElection,
 		LeaderElectionID:   "b2ed43ca.hypershift.openshift.io",
 		// Use a non-caching client everywhere. The default split client does not
@@ -174,6 +182,13 @@ func run(ctx context.Context, opts *StartOptions, log logr.Logger) error {
 		Client:                          mgr.GetClient(),
 		HostedControlPlaneOperatorImage: operatorImage,
 		IgnitionServerImage:             ignitionServerImage,
+		KonnectivityImage:               opts.KonnectivityImage,
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create controller: %w", err)
+	}
+
+	if err := (&hostedcluster.UnmanagedEtcdReconciler{
+		Client: mgr.GetClient(),
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("unable to create controller: %w", err)
 	}
@@ -188,6 +203,15 @@ func run(ctx context.Context, opts *StartOptions, log logr.Logger) error {
 		return fmt.Errorf("unable to create controller: %w", err)
 	}
 
+	if len(opts.WebhookCertDir) > 0 {
+		if err := hostedcluster.SetupWebhookWithManager(mgr); err != nil {
+			return fmt.Errorf("unable to create webhook: %w", err)
+		}
+		if err := nodepool.SetupWebhookWithManager(mgr); err != nil {
+			return fmt.Errorf("unable to create webhook: %w", err)
+		}
+	}
+
 	// Configure OpenTelemetry
 	var tracerOpts []sdktrace.TracerProviderOption
 	tracerOpts = append(tracerOpts, sdktrace.WithResource(resource.NewWithAttributes(
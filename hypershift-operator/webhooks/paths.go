@@ -0,0 +1,17 @@
+// Package webhooks holds the paths the hypershift operator serves its
+// admission webhooks on, so the install manifests can reference them without
+// depending on the controllers.
+package webhooks
+
+const (
+	// HostedClusterMutatingPath is the path the HostedCluster defaulting webhook is served on.
+	HostedClusterMutatingPath = "/mutate-hypershift-openshift-io-v1alpha1-hostedcluster"
+	// HostedClusterValidatingPath is the path the HostedCluster validating webhook is served on.
+	HostedClusterValidatingPath = "/validate-hypershift-openshift-io-v1alpha1-hostedcluster"
+	// NodePoolMutatingPath is the path the NodePool defaulting webhook is served on.
+	NodePoolMutatingPath = "/mutate-hypershift-openshift-io-v1alpha1-nodepool"
+	// NodePoolValidatingPath is the path the NodePool validating webhook is served on.
+	NodePoolValidatingPath = "/validate-hypershift-openshift-io-v1alpha1-nodepool"
+	// ClusterSizingConfigurationValidatingPath is the path the ClusterSizingConfiguration validating webhook is served on.
+	ClusterSizingConfigurationValidatingPath = "/validate-hypershift-openshift-io-v1alpha1-clustersizingconfiguration"
+)
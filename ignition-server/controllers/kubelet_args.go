@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	kubeletUnitName = "kubelet.service"
+
+	// kubeletConfigArg is passed to the kubelet by the kubelet unit which
+	// the machine config operator renders for every node.
+	kubeletConfigArg = "--config=/etc/kubernetes/kubelet.conf"
+)
+
+// addKubeletArgs appends args to the command line of the kubelet unit in an
+// ignition payload so nodes register with e.g. the labels and taints of
+// their NodePool.
+func addKubeletArgs(payload []byte, args string) ([]byte, error) {
+	config := map[string]interface{}{}
+	if err := json.Unmarshal(payload, &config); err != nil {
+		return nil, fmt.Errorf("failed to decode ignition payload: %w", err)
+	}
+	systemd, _ := config["systemd"].(map[string]interface{})
+	units, _ := systemd["units"].([]interface{})
+	for _, u := range units {
+		unit, ok := u.(map[string]interface{})
+		if !ok || unit["name"] != kubeletUnitName {
+			continue
+		}
+		contents, _ := unit["contents"].(string)
+		if !strings.Contains(contents, kubeletConfigArg) {
+			return nil, fmt.Errorf("%s does not run the kubelet with %s", kubeletUnitName, kubeletConfigArg)
+		}
+		unit["contents"] = strings.Replace(contents, kubeletConfigArg, kubeletConfigArg+" "+args, 1)
+		return json.Marshal(config)
+	}
+	return nil, fmt.Errorf("ignition payload has no %s unit", kubeletUnitName)
+}
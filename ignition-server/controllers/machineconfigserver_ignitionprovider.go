@@ -29,7 +29,8 @@ const (
 
 // MCSIgnitionProvider is an IgnitionProvider that uses
 // MachineConfigServer pods to build ignition payload contents
-// out of a given releaseImage and a config string containing 0..N MachineConfig yaml definitions.
+// out of a given releaseImage and a config string containing 0..N MachineConfig, KubeletConfig
+// and ContainerRuntimeConfig yaml definitions.
 type MCSIgnitionProvider struct {
 	Client          client.Client
 	ReleaseProvider releaseinfo.Provider
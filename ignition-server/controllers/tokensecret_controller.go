@@ -21,10 +21,11 @@ import (
 )
 
 const (
-	TokenSecretReleaseKey = "release"
-	TokenSecretConfigKey  = "config"
-	TokenSecretTokenKey   = "token"
-	TokenSecretAnnotation = "hypershift.openshift.io/ignition-config"
+	TokenSecretReleaseKey     = "release"
+	TokenSecretConfigKey      = "config"
+	TokenSecretTokenKey       = "token"
+	TokenSecretKubeletArgsKey = "kubeletArgs"
+	TokenSecretAnnotation     = "hypershift.openshift.io/ignition-config"
 )
 
 func NewPayloadStore() *ExpiringCache {
@@ -43,7 +44,8 @@ func NewPayloadStore() *ExpiringCache {
 // for a given release image.
 type IgnitionProvider interface {
 	// GetPayload returns the ignition payload content for
-	// the provided release image and a config string containing 0..N MachineConfig yaml definitions.
+	// the provided release image and a config string containing 0..N MachineConfig,
+	// KubeletConfig and ContainerRuntimeConfig yaml definitions.
 	GetPayload(ctx context.Context, payloadImage, config string) ([]byte, error)
 }
 
@@ -59,6 +61,7 @@ type IgnitionProvider interface {
 //     token: <authz token>
 //     release: <release image string>
 //     config: |-
+//     kubeletArgs: <optional kubelet arguments>
 type TokenSecretReconciler struct {
 	client.Client
 	IgnitionProvider IgnitionProvider
@@ -143,6 +146,12 @@ func (r *TokenSecretReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("error getting ignition payload: %v", err)
 	}
+	if kubeletArgs := string(tokenSecret.Data[TokenSecretKubeletArgsKey]); kubeletArgs != "" {
+		payload, err = addKubeletArgs(payload, kubeletArgs)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("error adding kubelet arguments to ignition payload: %v", err)
+		}
+	}
 
 	log.Info("IgnitionProvider generated payload")
 	r.PayloadStore.Set(token, payload)
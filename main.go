@@ -22,10 +22,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	auditcmd "github.com/openshift/hypershift/cmd/audit"
 	createcmd "github.com/openshift/hypershift/cmd/create"
 	destroycmd "github.com/openshift/hypershift/cmd/destroy"
 	dumpcmd "github.com/openshift/hypershift/cmd/dump"
+	etcdcmd "github.com/openshift/hypershift/cmd/etcd"
 	installcmd "github.com/openshift/hypershift/cmd/install"
+	kmscmd "github.com/openshift/hypershift/cmd/kms"
 )
 
 func main() {
@@ -41,6 +44,9 @@ func main() {
 	cmd.AddCommand(createcmd.NewCommand())
 	cmd.AddCommand(destroycmd.NewCommand())
 	cmd.AddCommand(dumpcmd.NewCommand())
+	cmd.AddCommand(etcdcmd.NewCommand())
+	cmd.AddCommand(kmscmd.NewCommand())
+	cmd.AddCommand(auditcmd.NewCommand())
 
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
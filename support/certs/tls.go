@@ -23,11 +23,25 @@ import (
 const (
 	keySize = 2048
 
+	// KeyAlgorithmRSA generates 2048 bit RSA keys.
+	KeyAlgorithmRSA KeyAlgorithm = "RSA"
+	// KeyAlgorithmECDSAP256 generates ECDSA keys on the P-256 curve.
+	KeyAlgorithmECDSAP256 KeyAlgorithm = "ECDSAP256"
+	// KeyAlgorithmECDSAP384 generates ECDSA keys on the P-384 curve.
+	KeyAlgorithmECDSAP384 KeyAlgorithm = "ECDSAP384"
+
 	ValidityOneDay   = 24 * time.Hour
 	ValidityOneYear  = 365 * ValidityOneDay
 	ValidityTenYears = 10 * ValidityOneYear
+
+	// notBeforeSkew backdates the start of generated certificates, so that
+	// they are valid right away on hosts whose clocks are slightly behind.
+	notBeforeSkew = 5 * time.Minute
 )
 
+// KeyAlgorithm is the algorithm of a private key.
+type KeyAlgorithm string
+
 // CertCfg contains all needed fields to configure a new certificate
 type CertCfg struct {
 	DNSNames     []string
@@ -37,6 +51,8 @@ type CertCfg struct {
 	Subject      pkix.Name
 	Validity     time.Duration
 	IsCA         bool
+	// KeyAlgorithm is the algorithm of the generated key. Defaults to RSA.
+	KeyAlgorithm KeyAlgorithm
 }
 
 // rsaPublicKey reflects the ASN.1 structure of a PKCS#1 public key.
@@ -46,8 +62,8 @@ type rsaPublicKey struct {
 }
 
 // GenerateSelfSignedCertificate generates a key/cert pair defined by CertCfg.
-func GenerateSelfSignedCertificate(cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
-	key, err := PrivateKey()
+func GenerateSelfSignedCertificate(cfg *CertCfg) (crypto.Signer, *x509.Certificate, error) {
+	key, err := GenerateKey(cfg.KeyAlgorithm)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to generate private key")
 	}
@@ -60,11 +76,11 @@ func GenerateSelfSignedCertificate(cfg *CertCfg) (*rsa.PrivateKey, *x509.Certifi
 }
 
 // GenerateSignedCertificate generate a key and cert defined by CertCfg and signed by CA.
-func GenerateSignedCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate,
-	cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
+func GenerateSignedCertificate(caKey crypto.Signer, caCert *x509.Certificate,
+	cfg *CertCfg) (crypto.Signer, *x509.Certificate, error) {
 
 	// create a private key
-	key, err := PrivateKey()
+	key, err := GenerateKey(cfg.KeyAlgorithm)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to generate private key")
 	}
@@ -98,8 +114,41 @@ func PrivateKey() (*rsa.PrivateKey, error) {
 	return rsaKey, nil
 }
 
+// GenerateKey generates a private key of the given algorithm. RSA keys are
+// generated if no algorithm is given.
+func GenerateKey(algorithm KeyAlgorithm) (crypto.Signer, error) {
+	switch algorithm {
+	case "", KeyAlgorithmRSA:
+		return PrivateKey()
+	case KeyAlgorithmECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyAlgorithmECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	default:
+		return nil, errors.Errorf("unsupported key algorithm %q", algorithm)
+	}
+}
+
+// PublicKeyAlgorithm returns the algorithm of a public key.
+func PublicKeyAlgorithm(pub crypto.PublicKey) (KeyAlgorithm, error) {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return KeyAlgorithmRSA, nil
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return KeyAlgorithmECDSAP256, nil
+		case elliptic.P384():
+			return KeyAlgorithmECDSAP384, nil
+		}
+		return "", errors.Errorf("unsupported ECDSA curve %s", pub.Curve.Params().Name)
+	default:
+		return "", errors.Errorf("unsupported public key type %T", pub)
+	}
+}
+
 // SelfSignedCertificate creates a self signed certificate
-func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate, error) {
+func SelfSignedCertificate(cfg *CertCfg, key crypto.Signer) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
@@ -109,7 +158,7 @@ func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate
 		IsCA:                  cfg.IsCA,
 		KeyUsage:              cfg.KeyUsages,
 		NotAfter:              time.Now().Add(cfg.Validity),
-		NotBefore:             time.Now(),
+		NotBefore:             time.Now().Add(-notBeforeSkew),
 		SerialNumber:          serial,
 		Subject:               cfg.Subject,
 	}
@@ -133,30 +182,30 @@ func SelfSignedCertificate(cfg *CertCfg, key *rsa.PrivateKey) (*x509.Certificate
 func SignedCertificate(
 	cfg *CertCfg,
 	csr *x509.CertificateRequest,
-	key *rsa.PrivateKey,
+	key crypto.Signer,
 	caCert *x509.Certificate,
-	caKey *rsa.PrivateKey,
+	caKey crypto.Signer,
 ) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
 	certTmpl := x509.Certificate{
 		DNSNames:              csr.DNSNames,
 		ExtKeyUsage:           cfg.ExtKeyUsages,
 		IPAddresses:           csr.IPAddresses,
 		KeyUsage:              cfg.KeyUsages,
-		NotAfter:              time.Now().Add(cfg.Validity),
-		NotBefore:             caCert.NotBefore,
+		NotAfter:              now.Add(cfg.Validity),
+		NotBefore:             now.Add(-notBeforeSkew),
 		SerialNumber:          serial,
 		Subject:               csr.Subject,
 		IsCA:                  cfg.IsCA,
 		Version:               3,
 		BasicConstraintsValid: true,
 	}
-	pub := caCert.PublicKey.(*rsa.PublicKey)
-	certTmpl.SubjectKeyId, err = generateSubjectKeyID(pub)
+	certTmpl.SubjectKeyId, err = generateSubjectKeyID(key.Public())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to set subject key identifier")
 	}
@@ -200,6 +249,22 @@ func PrivateKeyToPem(key *rsa.PrivateKey) []byte {
 	return keyinPem
 }
 
+// SignerToPem converts an RSA or ECDSA private key to a pem string
+func SignerToPem(key crypto.Signer) ([]byte, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return PrivateKeyToPem(key), nil
+	case *ecdsa.PrivateKey:
+		keyInBytes, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal EC private key")
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyInBytes}), nil
+	default:
+		return nil, errors.Errorf("unsupported private key type %T", key)
+	}
+}
+
 // CertToPem converts an x509.Certificate object to a pem string
 func CertToPem(cert *x509.Certificate) []byte {
 	certInPem := pem.EncodeToMemory(
@@ -246,6 +311,33 @@ func PemToPrivateKey(data []byte) (*rsa.PrivateKey, error) {
 	return x509.ParsePKCS1PrivateKey(block.Bytes)
 }
 
+// PemToSigner converts a data block to an RSA or ECDSA private key. PKCS#1,
+// SEC 1 and PKCS#8 encodings are supported.
+func PemToSigner(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.Errorf("could not find a PEM block in the private key")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	default:
+		return nil, errors.Errorf("unsupported PEM block type %q in the private key", block.Type)
+	}
+}
+
 // PemToCertificate converts a data block to x509.Certificate.
 func PemToCertificate(data []byte) (*x509.Certificate, error) {
 	block, _ := pem.Decode(data)
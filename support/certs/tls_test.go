@@ -0,0 +1,54 @@
+package certs
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+)
+
+func TestKeyAlgorithms(t *testing.T) {
+	for _, algorithm := range []KeyAlgorithm{KeyAlgorithmRSA, KeyAlgorithmECDSAP256, KeyAlgorithmECDSAP384} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			caKey, caCert, err := GenerateSelfSignedCertificate(&CertCfg{
+				Subject:      pkix.Name{CommonName: "ca", OrganizationalUnit: []string{"test"}},
+				KeyUsages:    x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+				Validity:     ValidityOneDay,
+				IsCA:         true,
+				KeyAlgorithm: algorithm,
+			})
+			if err != nil {
+				t.Fatalf("failed to generate CA: %v", err)
+			}
+			if got, err := PublicKeyAlgorithm(caCert.PublicKey); err != nil || got != algorithm {
+				t.Fatalf("expected CA key algorithm %s, got %s (%v)", algorithm, got, err)
+			}
+
+			// Keys survive a round trip through PEM
+			caKeyPem, err := SignerToPem(caKey)
+			if err != nil {
+				t.Fatalf("failed to encode CA key: %v", err)
+			}
+			parsedCAKey, err := PemToSigner(caKeyPem)
+			if err != nil {
+				t.Fatalf("failed to parse CA key: %v", err)
+			}
+
+			_, crt, err := GenerateSignedCertificate(parsedCAKey, caCert, &CertCfg{
+				Subject:      pkix.Name{CommonName: "leaf"},
+				KeyUsages:    x509.KeyUsageDigitalSignature,
+				ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+				Validity:     ValidityOneDay,
+				KeyAlgorithm: algorithm,
+			})
+			if err != nil {
+				t.Fatalf("failed to sign certificate: %v", err)
+			}
+			if err := crt.CheckSignatureFrom(caCert); err != nil {
+				t.Fatalf("certificate is not signed by the CA: %v", err)
+			}
+			if got, err := PublicKeyAlgorithm(crt.PublicKey); err != nil || got != algorithm {
+				t.Fatalf("expected key algorithm %s, got %s (%v)", algorithm, got, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+// Package etcd is a minimal client for the etcd v3 JSON gateway. It covers the
+// cluster membership and maintenance calls HyperShift needs to manage and probe
+// etcd clusters without depending on the etcd client libraries.
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+const (
+	// DefaultTimeout is the timeout of a single request to an etcd endpoint.
+	DefaultTimeout = 10 * time.Second
+
+	// DefragmentTimeout is the timeout of a defragmentation, which takes
+	// longer than other requests on large databases.
+	DefragmentTimeout = 5 * time.Minute
+
+	// handshakeAlertTimeout is how long Handshake waits for the server to
+	// reject the client certificate once a TLS 1.3 handshake has completed.
+	handshakeAlertTimeout = time.Second
+)
+
+// Member is a member of an etcd cluster. Members which have been added to the
+// cluster but have not started yet have no name and no client URLs.
+type Member struct {
+	ID         uint64   `json:"ID,string"`
+	Name       string   `json:"name,omitempty"`
+	PeerURLs   []string `json:"peerURLs,omitempty"`
+	ClientURLs []string `json:"clientURLs,omitempty"`
+	IsLearner  bool     `json:"isLearner,omitempty"`
+}
+
+// Started returns true if the member has joined the cluster.
+func (m *Member) Started() bool {
+	return len(m.ClientURLs) > 0
+}
+
+// Status is the status of a single etcd member.
+type Status struct {
+	Version     string   `json:"version"`
+	DBSize      int64    `json:"dbSize,string"`
+	DBSizeInUse int64    `json:"dbSizeInUse,string"`
+	Leader      uint64   `json:"leader,string"`
+	RaftIndex   uint64   `json:"raftIndex,string"`
+	RaftTerm    uint64   `json:"raftTerm,string"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+// Client talks to an etcd cluster through the JSON gateway served on the
+// client URLs of every member.
+type Client struct {
+	endpoints    []string
+	tlsConfig    *tls.Config
+	transport    *http.Transport
+	httpClient   *http.Client
+	defragClient *http.Client
+}
+
+// NewClient returns a client for the given endpoints which authenticates with
+// tlsConfig. Cluster wide calls are sent to the first endpoint which answers.
+// The client keeps connections to the endpoints open until it is closed.
+func NewClient(endpoints []string, tlsConfig *tls.Config) *Client {
+	transport := &http.Transport{
+		TLSClientConfig: tlsConfig,
+	}
+	return &Client{
+		endpoints: endpoints,
+		tlsConfig: tlsConfig,
+		transport: transport,
+		httpClient: &http.Client{
+			Timeout:   DefaultTimeout,
+			Transport: transport,
+		},
+		defragClient: &http.Client{
+			Timeout:   DefragmentTimeout,
+			Transport: transport,
+		},
+	}
+}
+
+// Close closes the connections the client keeps open to the endpoints.
+func (c *Client) Close() {
+	c.transport.CloseIdleConnections()
+}
+
+// NewTLSConfig returns a TLS configuration which presents the given client
+// certificate and trusts the given CA bundle.
+func NewTLSConfig(certPEM, keyPEM, caPEM []byte) (*tls.Config, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no valid CA certificates found")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+	}, nil
+}
+
+// MemberList returns the members of the cluster.
+func (c *Client) MemberList(ctx context.Context) ([]Member, error) {
+	var resp struct {
+		Members []Member `json:"members"`
+	}
+	if err := c.clusterCall(ctx, "/v3/cluster/member/list", struct{}{}, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list etcd members: %w", err)
+	}
+	return resp.Members, nil
+}
+
+// MemberAdd adds a member with the given peer URLs to the cluster.
+func (c *Client) MemberAdd(ctx context.Context, peerURLs []string) (*Member, error) {
+	req := struct {
+		PeerURLs []string `json:"peerURLs"`
+	}{PeerURLs: peerURLs}
+	var resp struct {
+		Member Member `json:"member"`
+	}
+	if err := c.clusterCall(ctx, "/v3/cluster/member/add", req, &resp); err != nil {
+		return nil, fmt.Errorf("failed to add etcd member: %w", err)
+	}
+	return &resp.Member, nil
+}
+
+// MemberRemove removes the member with the given ID from the cluster.
+func (c *Client) MemberRemove(ctx context.Context, id uint64) error {
+	req := struct {
+		ID uint64 `json:"ID,string"`
+	}{ID: id}
+	if err := c.clusterCall(ctx, "/v3/cluster/member/remove", req, nil); err != nil {
+		return fmt.Errorf("failed to remove etcd member %x: %w", id, err)
+	}
+	return nil
+}
+
+// Status returns the status of the member serving endpoint.
+func (c *Client) Status(ctx context.Context, endpoint string) (*Status, error) {
+	status := &Status{}
+	if err := c.call(ctx, endpoint, "/v3/maintenance/status", struct{}{}, status); err != nil {
+		return nil, fmt.Errorf("failed to get status of etcd member %s: %w", endpoint, err)
+	}
+	return status, nil
+}
+
+// Defragment defragments the backend database of the member serving endpoint.
+// The member doesn't serve requests while it is being defragmented.
+func (c *Client) Defragment(ctx context.Context, endpoint string) error {
+	if err := c.do(ctx, c.defragClient, endpoint, "/v3/maintenance/defragment", struct{}{}, nil); err != nil {
+		return fmt.Errorf("failed to defragment etcd member %s: %w", endpoint, err)
+	}
+	return nil
+}
+
+// Health returns an error if the member serving endpoint is not healthy, for
+// example because the cluster has no leader.
+func (c *Client) Health(ctx context.Context, endpoint string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/health", nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var health struct {
+		Health string `json:"health"`
+		Reason string `json:"reason,omitempty"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return fmt.Errorf("invalid health response from %s: %w", endpoint, err)
+	}
+	if health.Health != "true" {
+		if len(health.Reason) > 0 {
+			return fmt.Errorf("etcd member %s is unhealthy: %s", endpoint, health.Reason)
+		}
+		return fmt.Errorf("etcd member %s is unhealthy", endpoint)
+	}
+	return nil
+}
+
+// HandshakeError is returned by Handshake when the endpoint accepted the
+// connection but the TLS handshake failed, for example because the server
+// certificate isn't trusted or the client certificate was rejected.
+type HandshakeError struct {
+	Endpoint string
+	Err      error
+}
+
+func (e *HandshakeError) Error() string {
+	return fmt.Sprintf("TLS handshake with %s failed: %v", e.Endpoint, e.Err)
+}
+
+func (e *HandshakeError) Unwrap() error {
+	return e.Err
+}
+
+// Handshake connects to endpoint and completes a TLS handshake with it. It
+// returns a *HandshakeError if the endpoint is reachable but the handshake
+// fails. With TLS 1.3 the server verifies the client certificate after the
+// client has completed the handshake and reports a rejection on the first read,
+// so Handshake briefly waits for that alert as well.
+func (c *Client) Handshake(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid etcd endpoint %s: %w", endpoint, err)
+	}
+	address := u.Host
+	if len(u.Port()) == 0 {
+		address = net.JoinHostPort(u.Hostname(), "443")
+	}
+	ctx, cancel := context.WithTimeout(ctx, DefaultTimeout)
+	defer cancel()
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+	tlsConfig := &tls.Config{}
+	if c.tlsConfig != nil {
+		tlsConfig = c.tlsConfig.Clone()
+	}
+	if len(tlsConfig.ServerName) == 0 {
+		tlsConfig.ServerName = u.Hostname()
+	}
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.Handshake(); err != nil {
+		return &HandshakeError{Endpoint: endpoint, Err: err}
+	}
+	if tlsConn.ConnectionState().Version < tls.VersionTLS13 {
+		return nil
+	}
+	if err := tlsConn.SetReadDeadline(time.Now().Add(handshakeAlertTimeout)); err != nil {
+		return err
+	}
+	if _, err := tlsConn.Read(make([]byte, 1)); err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return nil
+		}
+		return &HandshakeError{Endpoint: endpoint, Err: err}
+	}
+	return nil
+}
+
+func (c *Client) clusterCall(ctx context.Context, path string, in, out interface{}) error {
+	var errs []error
+	for _, endpoint := range c.endpoints {
+		err := c.call(ctx, endpoint, path, in, out)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("no etcd endpoints")
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+func (c *Client) call(ctx context.Context, endpoint, path string, in, out interface{}) error {
+	return c.do(ctx, c.httpClient, endpoint, path, in, out)
+}
+
+func (c *Client) do(ctx context.Context, httpClient *http.Client, endpoint, path string, in, out interface{}) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response from %s: %w", endpoint, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var gatewayErr struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		}
+		if err := json.Unmarshal(data, &gatewayErr); err == nil && len(gatewayErr.Message) > 0 {
+			return fmt.Errorf("%s: %s", endpoint, gatewayErr.Message)
+		}
+		return fmt.Errorf("%s: unexpected status %s", endpoint, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", endpoint, err)
+	}
+	return nil
+}
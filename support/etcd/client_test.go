@@ -0,0 +1,81 @@
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient(t *testing.T) {
+	var removed string
+	var defragmented bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v3/cluster/member/list", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"header":{"cluster_id":"1","member_id":"2"},"members":[` +
+			`{"ID":"10276657743932975437","name":"etcd-0","peerURLs":["https://etcd-0.etcd.test.svc:2380"],"clientURLs":["https://etcd-0.etcd.test.svc:2379"]},` +
+			`{"ID":"1","peerURLs":["https://etcd-1.etcd.test.svc:2380"]}]}`))
+	})
+	mux.HandleFunc("/v3/cluster/member/remove", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		removed = string(body)
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/v3/maintenance/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"version":"3.4.9","dbSize":"24576","dbSizeInUse":"16384","leader":"10276657743932975437","raftIndex":"9","raftTerm":"2"}`))
+	})
+	mux.HandleFunc("/v3/cluster/member/add", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": "etcdserver: unhealthy cluster", "message": "etcdserver: unhealthy cluster", "code": 14})
+	})
+	mux.HandleFunc("/v3/maintenance/defragment", func(w http.ResponseWriter, r *http.Request) {
+		defragmented = true
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"health":"false"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	c := NewClient([]string{"http://127.0.0.1:1", server.URL}, nil)
+	defer c.Close()
+
+	members, err := c.MemberList(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(members) != 2 || members[0].ID != 10276657743932975437 || !members[0].Started() || members[1].Started() {
+		t.Errorf("unexpected members: %+v", members)
+	}
+
+	if err := c.MemberRemove(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if removed != `{"ID":"1"}` {
+		t.Errorf("unexpected remove request: %s", removed)
+	}
+
+	status, err := c.Status(ctx, server.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if status.DBSize != 24576 || status.DBSizeInUse != 16384 || status.Leader != 10276657743932975437 {
+		t.Errorf("unexpected status: %+v", status)
+	}
+
+	if _, err := c.MemberAdd(ctx, []string{"https://etcd-2.etcd.test.svc:2380"}); err == nil {
+		t.Errorf("expected an error adding a member to an unhealthy cluster")
+	}
+
+	if err := c.Defragment(ctx, server.URL); err != nil || !defragmented {
+		t.Errorf("expected the member to be defragmented: %v", err)
+	}
+
+	if err := c.Health(ctx, server.URL); err == nil {
+		t.Errorf("expected an unhealthy member")
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/blang/semver"
 	imageapi "github.com/openshift/api/image/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -75,6 +76,41 @@ func (i *ReleaseImage) ComponentImages() map[string]string {
 	return images
 }
 
+// WithComponentImages returns a copy of the release image whose components use
+// the given images instead. Components which are not part of the release image
+// are added to it. The release image itself is left untouched, since it may be
+// shared through a cache.
+func (i *ReleaseImage) WithComponentImages(images map[string]string) *ReleaseImage {
+	if len(images) == 0 {
+		return i
+	}
+	out := &ReleaseImage{
+		ImageStream:    i.ImageStream.DeepCopy(),
+		StreamMetadata: i.StreamMetadata,
+	}
+	replaced := make(map[string]bool)
+	for idx, tag := range out.Spec.Tags {
+		if image, ok := images[tag.Name]; ok {
+			out.Spec.Tags[idx].From = &corev1.ObjectReference{Kind: "DockerImage", Name: image}
+			replaced[tag.Name] = true
+		}
+	}
+	names := make([]string, 0, len(images))
+	for name := range images {
+		if !replaced[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out.Spec.Tags = append(out.Spec.Tags, imageapi.TagReference{
+			Name: name,
+			From: &corev1.ObjectReference{Kind: "DockerImage", Name: images[name]},
+		})
+	}
+	return out
+}
+
 func (i *ReleaseImage) ComponentVersions() (map[string]string, error) {
 	componentVersions, err := readComponentVersions(i.ImageStream)
 	if err := errors.NewAggregate(err); err != nil {
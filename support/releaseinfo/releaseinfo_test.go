@@ -0,0 +1,38 @@
+package releaseinfo
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/hypershift/support/releaseinfo/fixtures"
+)
+
+func TestWithComponentImages(t *testing.T) {
+	imageStream, err := DeserializeImageStream(fixtures.ImageReferencesJSON_4_8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	releaseImage := &ReleaseImage{ImageStream: imageStream}
+	original := releaseImage.ComponentImages()
+
+	overridden := releaseImage.WithComponentImages(map[string]string{
+		"hyperkube":          "quay.io/example/hyperkube:fix",
+		"konnectivity-agent": "quay.io/example/konnectivity:fix",
+	})
+	images := overridden.ComponentImages()
+	if images["hyperkube"] != "quay.io/example/hyperkube:fix" {
+		t.Errorf("expected the hyperkube image to be replaced, got %s", images["hyperkube"])
+	}
+	if images["konnectivity-agent"] != "quay.io/example/konnectivity:fix" {
+		t.Errorf("expected the konnectivity-agent image to be added, got %s", images["konnectivity-agent"])
+	}
+	if images["etcd"] != original["etcd"] {
+		t.Errorf("expected the etcd image to be kept, got %s", images["etcd"])
+	}
+	if len(images) != len(original)+1 {
+		t.Errorf("expected %d images, got %d", len(original)+1, len(images))
+	}
+	if !reflect.DeepEqual(releaseImage.ComponentImages(), original) {
+		t.Errorf("expected the original release image to be left untouched")
+	}
+}